@@ -0,0 +1,130 @@
+// Command migrate-uploads copies files from a local uploads directory into the configured storage
+// backend, so problem statement PDFs and submission attachments written before STORAGE_BACKEND=s3
+// survive redeploys. Files already present with the same size are skipped, so it is safe to rerun.
+//
+//	go run ./cmd/migrate-uploads -from ./uploads [-dry-run]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rift26/backend/internal/config"
+	"github.com/rift26/backend/pkg/storage"
+)
+
+func main() {
+	from := flag.String("from", filepath.Join(".", "uploads"), "local uploads directory to copy from")
+	dryRun := flag.Bool("dry-run", false, "list what would be copied without writing")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	store, err := storage.New(storageConfig(cfg))
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+	if local, ok := store.(*storage.Local); ok && samePath(local.Dir(), *from) {
+		log.Fatalf("Storage backend is already local %s; set STORAGE_BACKEND (and its settings) to the destination", local.Dir())
+	}
+	log.Printf("Copying %s to %s", *from, store.Name())
+
+	ctx := context.Background()
+	var copied, skipped, failed int
+	err = filepath.WalkDir(*from, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(*from, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if strings.HasPrefix(d.Name(), ".") || !storage.ValidKey(key) {
+			log.Printf("skip %s: not a storage key", key)
+			skipped++
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if obj, err := store.Stat(ctx, key); err == nil && obj.Size == info.Size() {
+			skipped++
+			return nil
+		} else if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("FAIL %s: %v", key, err)
+			failed++
+			return nil
+		}
+		if *dryRun {
+			log.Printf("would copy %s (%d bytes)", key, info.Size())
+			copied++
+			return nil
+		}
+		if err := copyFile(ctx, store, path, key, info.Size()); err != nil {
+			log.Printf("FAIL %s: %v", key, err)
+			failed++
+			return nil
+		}
+		log.Printf("copied %s (%d bytes)", key, info.Size())
+		copied++
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", *from, err)
+	}
+
+	verb := "Copied"
+	if *dryRun {
+		verb = "Would copy"
+	}
+	log.Printf("%s %d files, skipped %d, failed %d", verb, copied, skipped, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func copyFile(ctx context.Context, store storage.Store, path, key string, size int64) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return store.Put(ctx, key, f, size, storage.TypeByKey(key))
+}
+
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// storageConfig maps the environment settings onto storage.New's config.
+func storageConfig(cfg *config.Config) storage.Config {
+	return storage.Config{
+		Backend:       cfg.StorageBackend,
+		LocalDir:      cfg.StorageDir,
+		PublicURL:     cfg.APIPublicURL,
+		SigningSecret: cfg.StorageSigningSecret,
+		S3: storage.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			PathStyle:       cfg.S3PathStyle,
+		},
+	}
+}
@@ -0,0 +1,654 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/config"
+	"github.com/rift26/backend/internal/database"
+	"github.com/rift26/backend/internal/handlers"
+	"github.com/rift26/backend/internal/middleware"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+	"github.com/rift26/backend/internal/rsvppin"
+	"github.com/rift26/backend/internal/services"
+	"github.com/rift26/backend/pkg/email"
+	"github.com/rift26/backend/pkg/storage"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func main() {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Connect to PostgreSQL
+	db, err := database.NewPostgresDB(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+	log.Println("✅ Connected to PostgreSQL")
+
+	// Initialize Email Service
+	emailService := email.NewEmailService(
+		cfg.SMTPHost,
+		cfg.SMTPPort,
+		cfg.SMTPUsername,
+		cfg.SMTPPassword,
+		cfg.SMTPFromEmail,
+		cfg.SMTPFromName,
+	)
+	log.Println("✅ Email service initialized")
+
+	// Rate limits are shared across instances through Postgres unless RATE_LIMIT_STORE=memory
+	if cfg.RateLimitStore != "memory" {
+		middleware.SetRateLimitStore(repository.NewRateLimitRepository(db))
+	}
+	log.Printf("✅ Rate limit store: %s", cfg.RateLimitStore)
+
+	// Initialize repositories
+	eventRepo := repository.NewEventRepository(db)
+	cityRepo := repository.NewCityRepository(db)
+	teamRepo := repository.NewTeamRepository(db)
+	announcementRepo := repository.NewAnnouncementRepository(db)
+	otpRepo := repository.NewOTPRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	volunteerRepo := repository.NewVolunteerRepository(db)
+	eventTableRepo := repository.NewEventTableRepository(db.DB)
+	problemStatementRepo := repository.NewProblemStatementRepository(db)
+	eventPhaseRepo := repository.NewEventPhaseRepository(db)
+	psSelectionRepo := repository.NewPSSelectionRepository(db)
+	psSubmissionRepo := repository.NewPSSubmissionRepository(db)
+
+	// Blob storage for problem statement PDFs and submission attachments
+	store, err := storage.New(storageConfig(cfg))
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+	log.Printf("Uploads stored in %s", store.Name())
+
+	// Initialize services
+	eventService := services.NewEventService(eventRepo)
+	emailService.SetEventNameSource(eventService.CurrentName)
+	cityService := services.NewCityService(cityRepo)
+	phaseService := services.NewPhaseService(eventPhaseRepo)
+	// Seed RSVP phases from legacy RSVP_OPEN / FINAL_OPEN on first boot; afterwards event_phases is the source of truth
+	if err := phaseService.SeedDefaults(context.Background(), map[models.EventPhaseKey]models.PhaseMode{
+		models.PhaseRSVP1: models.PhaseModeFromLegacyFlag(cfg.RSVPOpen),
+		models.PhaseRSVP2: models.PhaseModeFromLegacyFlag(cfg.FinalOpen),
+	}); err != nil {
+		log.Printf("Warning: could not seed event phases: %v", err)
+	}
+	teamService := services.NewTeamService(teamRepo, announcementRepo, cityService)
+	checkinService := services.NewCheckinService(teamRepo)
+	sessionService := services.NewSessionService(repository.NewSessionRepository(db), cfg.JWTSecret, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	permissionService := services.NewPermissionService(repository.NewAdminRoleRepository(db), userRepo)
+	sessionService.SetPermissionSource(permissionService.EffectivePermissions)
+	enforce2FA := make([]models.UserRole, 0, len(cfg.Enforce2FARoles))
+	for _, role := range cfg.Enforce2FARoles {
+		enforce2FA = append(enforce2FA, models.UserRole(role))
+	}
+	twoFactorService := services.NewTwoFactorService(repository.NewTwoFactorRepository(db), sessionService, cfg.TwoFactorKey, enforce2FA)
+	emailOTPService := services.NewEmailOTPService(otpRepo, repository.NewOTPLockoutRepository(db), teamRepo, emailService, sessionService, services.OTPLockoutPolicy{
+		MaxAttempts:  cfg.OTPMaxAttempts,
+		BaseDuration: cfg.OTPLockoutBase,
+		MaxDuration:  cfg.OTPLockoutMax,
+	}, cfg.EnableEmailOTP)
+	magicLinkService := services.NewMagicLinkService(repository.NewMagicLinkRepository(db), teamRepo, emailService, sessionService, cfg.JWTSecret, cfg.FrontendURL, cfg.EnableEmailOTP)
+	ticketService := services.NewTicketService(db.DB, emailService)
+	announcementService := services.NewAnnouncementService(db.DB)
+	volunteerService := services.NewVolunteerService(volunteerRepo, sessionService)
+	eventTableService := services.NewEventTableService(eventTableRepo, cityService)
+	registrationDeskAllocService := services.NewRegistrationDeskAllocationService(teamRepo, eventTableRepo, cityService)
+	problemStatementService := services.NewProblemStatementService(problemStatementRepo, phaseService, cityService, store, cfg.StorageURLTTL)
+	psSelectionService := services.NewPSSelectionService(psSelectionRepo, teamRepo, problemStatementRepo, phaseService, services.PSSwitchPolicy{
+		Grace:       cfg.PSSwitchGrace,
+		MaxSwitches: cfg.PSMaxSwitches,
+	})
+	psSubmissionService := services.NewPSSubmissionService(psSubmissionRepo, psSelectionRepo, teamRepo, problemStatementRepo, phaseService, services.LateSubmissionPolicy{
+		Accept: cfg.LateSubmissions == "flag",
+		Window: cfg.LateSubmissionWindow,
+	}, services.SubmissionUploadConfig{
+		Store:    store,
+		MaxBytes: cfg.SubmissionUploadMaxBytes,
+		BaseURL:  cfg.APIPublicURL,
+		URLTTL:   cfg.StorageURLTTL,
+	})
+
+	// Initialize participant check-in repository
+	participantCheckinRepo := repository.NewParticipantCheckInRepository(db.DB)
+	volunteerAdminRepo := repository.NewVolunteerAdminRepository(db)
+	// GORM for seat allocation (blocks/rooms/seats of cities with seat allocation)
+	gormDB, err := gorm.Open(postgres.Open(cfg.DatabaseURL), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to connect GORM for seat allocation: %v", err)
+	}
+	seatAllocationService := services.NewSeatAllocationService(gormDB, cityService)
+	volunteerAdminService := services.NewVolunteerAdminService(volunteerAdminRepo, sessionService, twoFactorService)
+	rosterExportService := services.NewRosterExportService(teamRepo)
+	teamWithdrawalService := services.NewTeamWithdrawalService(teamRepo, emailOTPService, seatAllocationService, cityService, phaseService)
+
+	// Initialize handlers
+	dashboardTokenService := services.NewDashboardTokenService(repository.NewDashboardTokenRepository(db), cfg.DashboardTokenTTL, cfg.FrontendURL)
+	dashboardTokenHandler := handlers.NewDashboardTokenHandler(dashboardTokenService)
+	teamHandler := handlers.NewTeamHandler(teamService, cfg.JWTSecret, cfg.AllowCityChange, seatAllocationService, psSelectionService, problemStatementService, phaseService, dashboardTokenService)
+	emailOTPHandler := handlers.NewEmailOTPHandler(emailOTPService, cfg.EnableEmailOTP)
+	magicLinkHandler := handlers.NewMagicLinkHandler(magicLinkService)
+	scannerHandler := handlers.NewVolunteerHandler(checkinService, participantCheckinRepo, teamRepo, volunteerRepo, seatAllocationService, cityService)
+	seatAllocatorHandler := handlers.NewSeatAllocatorHandler(gormDB, cityService)
+	volunteerAuthHandler := handlers.NewVolunteerAuthHandler(volunteerService)
+	volunteerAdminHandler := handlers.NewVolunteerAdminHandler(volunteerAdminService, volunteerRepo, participantCheckinRepo, seatAllocationService, eventTableService, teamRepo, cityService, gormDB)
+	adminHandler := handlers.NewAdminHandler(teamRepo, announcementRepo, teamService, userRepo, twoFactorService, registrationDeskAllocService, participantCheckinRepo, cityService, rosterExportService)
+	auditService := services.NewAuditService(repository.NewAuditLogRepository(db))
+	auditHandler := handlers.NewAuditHandler(auditService)
+	apiKeyService := services.NewAPIKeyService(repository.NewAPIKeyRepository(db), cityService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	integrationHandler := handlers.NewIntegrationHandler(teamRepo, announcementService, seatAllocationService, cityService)
+	rsvpPinHandler := handlers.NewRSVPPinHandler(rsvppin.New(cfg.RSVPPinSecret, cfg.FinalPinSecret, cfg.PINRotation), phaseService, eventTableRepo, cityService)
+	ticketHandler := handlers.NewTicketHandler(ticketService)
+	announcementHandler := handlers.NewAnnouncementHandler(announcementService)
+	bulkEmailHandler := handlers.NewBulkEmailHandler(db.DB, emailService, announcementService)
+	certificateHandler := handlers.NewCertificateHandler(db.DB, emailService, cfg.APIPublicURL, cfg.FrontendURL)
+
+	eventTableHandler := handlers.NewEventTableHandler(eventTableService)
+	problemStatementHandler := handlers.NewProblemStatementHandler(problemStatementService)
+	fileHandler := handlers.NewFileHandler(store)
+	checkPSHandler := handlers.NewCheckPSHandler(psSelectionService)
+	psSubmissionHandler := handlers.NewPSSubmissionHandler(psSubmissionService)
+	judgingHandler := handlers.NewJudgingHandler(psSubmissionRepo, psSubmissionService)
+	judgeRepo := repository.NewJudgeRepository(db)
+	judgingService := services.NewJudgingService(repository.NewJudgingRepository(db), judgeRepo, cityService)
+	judgeHandler := handlers.NewJudgeHandler(services.NewJudgeService(judgeRepo, userRepo, sessionService), psSubmissionService, judgingService)
+	judgingRoundHandler := handlers.NewJudgingRoundHandler(judgingService)
+	phaseHandler := handlers.NewPhaseHandler(phaseService)
+	eventHandler := handlers.NewEventHandler(eventService)
+	cityHandler := handlers.NewCityHandler(cityService)
+	teamWithdrawalHandler := handlers.NewTeamWithdrawalHandler(teamWithdrawalService)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
+	roleHandler := handlers.NewRoleHandler(permissionService)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorService)
+	adminUserHandler := handlers.NewAdminUserHandler(services.NewAdminUserService(userRepo, permissionService, sessionService, emailService, cfg.FrontendURL))
+
+	// Setup Gin router
+	if cfg.Environment == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	router := gin.Default()
+
+	// Increase max multipart memory for file uploads (32MB)
+	router.MaxMultipartMemory = 32 << 20
+
+	// Global middleware
+	router.Use(middleware.CORSMiddleware(cfg.AllowedOrigins))
+
+	// Serve static font files (used by SVG certificates — same-origin avoids CORS issues)
+	router.Static("/static", "./static")
+
+	// Health check endpoint
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"status":  "healthy",
+			"service": eventService.CurrentName() + " API",
+			"version": "1.0.0",
+			"time":    time.Now().Format(time.RFC3339),
+		})
+	})
+
+	// API v1 routes
+	v1 := router.Group("/api/v1")
+	{
+		// Public routes
+		v1.GET("/ping", func(c *gin.Context) {
+			c.JSON(200, gin.H{"message": "pong"})
+		})
+
+		// Feature flags endpoint (phases resolved from event_phases; optional ?city=)
+		v1.GET("/config", func(c *gin.Context) {
+			phases, err := phaseService.Snapshot(c.Request.Context(), c.Query("city"))
+			if err != nil {
+				c.JSON(500, gin.H{"error": "Failed to load event phases"})
+				return
+			}
+			c.JSON(200, gin.H{
+				"otp_enabled":         cfg.EnableEmailOTP,
+				"magic_link_enabled":  magicLinkService.Enabled(),
+				"city_change_enabled": cfg.AllowCityChange,
+				"rsvp_open":           phases[models.PhaseRSVP1].Mode.LegacyFlag(),
+				"final_open":          phases[models.PhaseRSVP2].Mode.LegacyFlag(),
+				"phases":              phases,
+			})
+		})
+
+		// Current event (name and dates)
+		v1.GET("/event", eventHandler.GetCurrent)
+		// Active cities with venue info
+		v1.GET("/cities", cityHandler.ListActiveCities)
+
+		// Team routes (public search, public dashboard)
+		teams := v1.Group("/teams")
+		{
+			teams.GET("/search", middleware.RateLimitMiddleware(30, 1*time.Minute), teamHandler.SearchTeams)
+			teams.GET("/:id", middleware.AuthMiddleware(sessionService), teamHandler.GetTeam)
+			teams.PUT("/:id/rsvp", middleware.AuthMiddleware(sessionService), teamHandler.SubmitRSVP)
+			teams.PUT("/:id/rsvp2", middleware.AuthMiddleware(sessionService), teamHandler.SubmitRSVP2)
+			// Dashboard actions need the team's session (dashboard token or team JWT) for the team in the path
+			teamSession := middleware.TeamSessionMiddleware(sessionService, dashboardTokenService)
+			teams.POST("/:id/lock-ps", teamSession, teamHandler.LockPS)
+			// Final project submission portal (backend also enforces checked_in + locked PS)
+			teams.GET("/:id/submission", teamSession, psSubmissionHandler.GetTeamForm)
+			teams.POST("/:id/submission", teamSession, psSubmissionHandler.Submit)
+			teams.GET("/:id/submission/attachments/:attachment_id", teamSession, psSubmissionHandler.GetTeamAttachment)
+			// Withdrawal from the dashboard (leader email + OTP confirmation)
+			teams.POST("/:id/withdraw/request-otp", middleware.RateLimitMiddleware(5, 1*time.Minute), middleware.RateLimitByParam(5, 15*time.Minute, "id"), teamWithdrawalHandler.RequestWithdrawalOTP)
+			teams.POST("/:id/withdraw", middleware.RateLimitMiddleware(5, 1*time.Minute), middleware.RateLimitByParam(10, 15*time.Minute, "id"), teamWithdrawalHandler.Withdraw)
+			// Dashboard links: leaders signed in by OTP or magic link regenerate, revoke and audit them
+			dashboardTokens := teams.Group("/:id/dashboard-tokens", middleware.AuthMiddleware(sessionService), middleware.RequireTeamParam())
+			dashboardTokens.GET("", dashboardTokenHandler.ListDashboardTokens)
+			dashboardTokens.POST("", dashboardTokenHandler.IssueDashboardToken)
+			dashboardTokens.DELETE("/:token_id", dashboardTokenHandler.RevokeDashboardToken)
+			dashboardTokens.GET("/:token_id/access-logs", dashboardTokenHandler.GetDashboardAccessLogs)
+			// Team announcements (filtered by team) - must come after specific routes
+			teams.GET("/:id/announcements", announcementHandler.GetTeamAnnouncements)
+		}
+
+		// Dashboard route (public via token)
+		v1.GET("/dashboard/:token", teamHandler.GetDashboard)
+
+		// Problem statements (public; returns list only if released)
+		v1.GET("/problem-statements", problemStatementHandler.GetPublic)
+		// Uploaded PS PDFs by their old links: redirects to a signed URL (no auth; filename is UUID-based)
+		v1.GET("/uploads/problem-statements/:filename", problemStatementHandler.ServePDF)
+		// Signed, expiring download links of the local storage backend
+		v1.GET("/files/*key", fileHandler.ServeSigned)
+		// Check PS selections (public; shows checked_in teams and their PS choices)
+		v1.GET("/checkps", checkPSHandler.GetPSSelections)
+		// Judge portal: judges see only their assigned submissions, without participant PII
+		v1.POST("/judge/login", middleware.RateLimitMiddleware(10, 1*time.Minute), judgeHandler.Login)
+		judgeRoutes := v1.Group("/judge")
+		judgeRoutes.Use(middleware.AuthMiddleware(sessionService))
+		judgeRoutes.Use(middleware.RoleMiddleware(models.UserRoleJudge))
+		{
+			judgeRoutes.GET("/submissions", judgeHandler.GetSubmissions)
+			judgeRoutes.GET("/submissions/:id", judgeHandler.GetSubmission)
+			judgeRoutes.GET("/submissions/:id/attachments/:attachment_id", judgeHandler.GetAttachment)
+			judgeRoutes.PUT("/submissions/:id/note", judgeHandler.SaveNote)
+			judgeRoutes.PUT("/submissions/:id/score", judgeHandler.SaveScore)
+			judgeRoutes.GET("/rounds", judgeHandler.GetRounds)
+		}
+		// Public certificate verification (no auth)
+		v1.GET("/certificates/verify/:cert_id", certificateHandler.VerifyCertificate)
+		// SVG certificate image (for display in browser/email)
+		v1.GET("/certificates/:cert_id/image.svg", certificateHandler.GetCertificateImageSVG)
+		// JPEG certificate image (for LinkedIn OG scraping — LinkedIn requires raster images)
+		v1.GET("/certificates/:cert_id/image.jpg", certificateHandler.GetCertificateImageJPEG)
+
+		// Public room view (seating layout + allocations by city and room name) — no auth
+		publicRoutes := v1.Group("/public")
+		{
+			publicRoutes.GET("/viewroom/:city/:roomname", seatAllocatorHandler.GetPublicRoomView)
+		}
+
+		// Ticket creation from the dashboard (team session; the ticket is filed for the session's team)
+		v1.POST("/tickets", middleware.TeamSessionMiddleware(sessionService, dashboardTokenService), ticketHandler.CreateTicket)
+
+		// Auth routes (email OTP + RSVP PIN)
+		authRoutes := v1.Group("/auth")
+		{
+			authRoutes.POST("/send-email-otp", middleware.RateLimitMiddleware(5, 1*time.Minute), middleware.RateLimitByBodyField(5, 15*time.Minute, "email"), emailOTPHandler.SendEmailOTP)
+			authRoutes.POST("/verify-email-otp", middleware.RateLimitMiddleware(5, 1*time.Minute), middleware.RateLimitByBodyField(10, 15*time.Minute, "team_id"), emailOTPHandler.VerifyEmailOTP)
+			authRoutes.POST("/send-magic-link", middleware.RateLimitMiddleware(3, 1*time.Minute), middleware.RateLimitByBodyField(5, 15*time.Minute, "email"), magicLinkHandler.SendMagicLink)
+			authRoutes.POST("/verify-magic-link", middleware.RateLimitMiddleware(10, 1*time.Minute), magicLinkHandler.VerifyMagicLink)
+			authRoutes.POST("/validate-rsvp-pin", middleware.RateLimitMiddleware(10, 1*time.Minute), rsvpPinHandler.ValidatePIN)
+			// Sessions (all roles)
+			authRoutes.POST("/refresh", middleware.RateLimitMiddleware(30, 1*time.Minute), sessionHandler.Refresh)
+			authRoutes.POST("/logout", sessionHandler.Logout)
+			authRoutes.POST("/logout-all", middleware.AuthMiddleware(sessionService), sessionHandler.LogoutAll)
+			// Admin invite / password reset links
+			authRoutes.POST("/set-password", middleware.RateLimitMiddleware(10, 1*time.Minute), adminUserHandler.SetPassword)
+			// Two-factor: second login step (public) and self-service enrollment (admins, volunteer admins)
+			authRoutes.POST("/2fa/setup", middleware.RateLimitMiddleware(10, 1*time.Minute), twoFactorHandler.Setup)
+			authRoutes.POST("/2fa/verify", middleware.RateLimitMiddleware(10, 1*time.Minute), twoFactorHandler.Verify)
+			twoFactorRoutes := authRoutes.Group("/2fa")
+			twoFactorRoutes.Use(middleware.AuthMiddleware(sessionService))
+			twoFactorRoutes.Use(middleware.RoleMiddleware(models.UserRoleAdmin, models.UserRoleVolunteerAdmin))
+			twoFactorRoutes.Use(middleware.Audit(auditService))
+			{
+				twoFactorRoutes.GET("", twoFactorHandler.GetStatus)
+				twoFactorRoutes.POST("/enroll", twoFactorHandler.Enroll)
+				twoFactorRoutes.POST("/enable", middleware.RateLimitMiddleware(10, 1*time.Minute), twoFactorHandler.Enable)
+				twoFactorRoutes.POST("/disable", middleware.RateLimitMiddleware(10, 1*time.Minute), twoFactorHandler.Disable)
+				twoFactorRoutes.POST("/recovery-codes", middleware.RateLimitMiddleware(10, 1*time.Minute), twoFactorHandler.RegenerateRecoveryCodes)
+			}
+		}
+
+		// Volunteer routes (public login + table list)
+		v1.POST("/volunteer/login", volunteerAuthHandler.Login)
+		// Volunteer Admin (city-scoped) — public login
+		v1.POST("/volunteer-admin/login", volunteerAdminHandler.Login)
+		v1.GET("/volunteer/tables", func(c *gin.Context) {
+			// Public endpoint to get active tables for volunteer login selection.
+			// uuid.Nil = current event on purpose: volunteers only ever log in to the running edition.
+			isActive := true
+			tables, err := eventTableRepo.GetAll(uuid.Nil, nil, &isActive)
+			if err != nil {
+				c.JSON(500, gin.H{"error": "Failed to fetch tables"})
+				return
+			}
+			c.JSON(200, gin.H{"tables": tables})
+		})
+
+		// Volunteer routes (protected by volunteer auth)
+		volunteerRoutes := v1.Group("/volunteer")
+		volunteerRoutes.Use(middleware.VolunteerAuthMiddleware(volunteerService))
+		{
+			volunteerRoutes.GET("/verify", volunteerAuthHandler.VerifyToken)
+		}
+
+		// Check-in routes (protected - enhanced with participant selection)
+		checkinRoutes := v1.Group("/checkin")
+		checkinRoutes.Use(middleware.AuthMiddleware(sessionService))
+		checkinRoutes.Use(middleware.RoleMiddleware("volunteer", "admin"))
+		checkinRoutes.Use(middleware.Audit(auditService))
+		// Admins reaching the scanner routes still need the check-in permission to mutate
+		checkinManage := middleware.RequireAdminPermission(models.PermCheckinManage)
+		{
+			checkinRoutes.POST("/scan", scannerHandler.ScanQR)                                     // Scan QR and get team details
+			checkinRoutes.POST("/participants", checkinManage, scannerHandler.CheckInParticipants) // Check in selected participants
+			checkinRoutes.GET("/history", scannerHandler.GetCheckInHistory)                        // Get check-in history
+			checkinRoutes.DELETE("/:team_id", checkinManage, scannerHandler.UndoCheckIn)           // Undo a check-in
+		}
+
+		// Table routes (protected) - renamed but kept for backward compatibility
+		// These are now used by scanner page for pending teams and actions
+		tableRoutes := v1.Group("/table")
+		tableRoutes.Use(middleware.AuthMiddleware(sessionService))
+		tableRoutes.Use(middleware.RoleMiddleware("volunteer", "admin"))
+		tableRoutes.Use(middleware.Audit(auditService))
+		{
+			tableRoutes.POST("/confirm", checkinManage, scannerHandler.ConfirmTable)       // Mark team as done
+			tableRoutes.POST("/allocate-seat", checkinManage, scannerHandler.AllocateSeat) // Allocate seat for team (cities with seat allocation)
+			tableRoutes.GET("/pending", scannerHandler.GetPendingTeams)                    // Get pending teams checked in by volunteer
+		}
+
+		// Volunteer Admin dashboard (protected — role volunteer_admin, city from JWT)
+		volunteerAdminRoutes := v1.Group("/volunteer-admin")
+		volunteerAdminRoutes.Use(middleware.AuthMiddleware(sessionService))
+		volunteerAdminRoutes.Use(middleware.RoleMiddleware(models.UserRoleVolunteerAdmin))
+		volunteerAdminRoutes.Use(middleware.Audit(auditService))
+		{
+			volunteerAdminRoutes.GET("/volunteers", volunteerAdminHandler.GetVolunteers)
+			volunteerAdminRoutes.GET("/check-ins", volunteerAdminHandler.GetCheckIns)
+			volunteerAdminRoutes.GET("/check-in-teams", volunteerAdminHandler.GetCheckInTeams)
+			volunteerAdminRoutes.GET("/tables", volunteerAdminHandler.GetTables)
+			volunteerAdminRoutes.GET("/teams/:team_id", volunteerAdminHandler.GetTeamDetails)
+			volunteerAdminRoutes.GET("/seat-summary", volunteerAdminHandler.GetSeatSummary)
+		}
+
+		// Read-only integrations (display screens, bots) authenticate with a scoped API key
+		integrationRoutes := v1.Group("/integrations")
+		integrationRoutes.Use(middleware.RateLimitMiddleware(120, 1*time.Minute))
+		{
+			integrationRoutes.GET("/stats", middleware.APIKeyMiddleware(apiKeyService, models.ScopeStatsRead), integrationHandler.GetStats)
+			integrationRoutes.GET("/announcements", middleware.APIKeyMiddleware(apiKeyService, models.ScopeAnnouncementsRead), integrationHandler.GetAnnouncements)
+			integrationRoutes.GET("/rooms", middleware.APIKeyMiddleware(apiKeyService, models.ScopeRoomsRead), integrationHandler.GetRooms)
+		}
+
+		// Admin login (public)
+		v1.POST("/admin/login", adminHandler.AdminLogin)
+
+		// Admin routes (protected)
+		adminRoutes := v1.Group("/admin")
+		adminRoutes.Use(middleware.AuthMiddleware(sessionService))
+		adminRoutes.Use(middleware.RoleMiddleware("admin"))
+		// Admin APIs operate on ?event_id= / X-Event-ID, defaulting to the current event
+		adminRoutes.Use(middleware.EventScopeMiddleware(eventService))
+		// Every admin mutation lands in the audit log
+		adminRoutes.Use(middleware.Audit(auditService))
+		// Every admin route declares the permission it needs
+		perm := middleware.RequirePermission
+		{
+			// Roles and permissions
+			adminRoutes.GET("/permissions", roleHandler.ListPermissions)
+			adminRoutes.GET("/me/permissions", roleHandler.GetMyPermissions)
+			adminRoutes.GET("/roles", perm(models.PermRolesManage), roleHandler.ListRoles)
+			adminRoutes.POST("/roles", perm(models.PermRolesManage), roleHandler.CreateRole)
+			adminRoutes.GET("/roles/users", perm(models.PermRolesManage), roleHandler.ListAdminUsers)
+			adminRoutes.PUT("/roles/users/:user_id", perm(models.PermRolesManage), roleHandler.SetUserRoles)
+			adminRoutes.PUT("/roles/:key", perm(models.PermRolesManage), roleHandler.UpdateRole)
+			adminRoutes.DELETE("/roles/:key", perm(models.PermRolesManage), roleHandler.DeleteRole)
+
+			// Admin accounts
+			adminRoutes.GET("/users", perm(models.PermUsersManage), adminUserHandler.ListUsers)
+			adminRoutes.POST("/users", perm(models.PermUsersManage), adminUserHandler.InviteUser)
+			adminRoutes.GET("/users/:id", perm(models.PermUsersManage), adminUserHandler.GetUser)
+			adminRoutes.PUT("/users/:id", perm(models.PermUsersManage), adminUserHandler.UpdateUser)
+			adminRoutes.POST("/users/:id/disable", perm(models.PermUsersManage), adminUserHandler.DisableUser)
+			adminRoutes.POST("/users/:id/enable", perm(models.PermUsersManage), adminUserHandler.EnableUser)
+			adminRoutes.POST("/users/:id/reset-password", perm(models.PermUsersManage), adminUserHandler.ResetPassword)
+
+			// Two-factor reset for locked-out admins and volunteer admins
+			adminRoutes.POST("/2fa/reset", perm(models.PermUsersManage), twoFactorHandler.Reset)
+
+			// Integration API keys
+			adminRoutes.GET("/api-keys", perm(models.PermAPIKeysManage), apiKeyHandler.ListAPIKeys)
+			adminRoutes.POST("/api-keys", perm(models.PermAPIKeysManage), apiKeyHandler.CreateAPIKey)
+			adminRoutes.DELETE("/api-keys/:id", perm(models.PermAPIKeysManage), apiKeyHandler.RevokeAPIKey)
+
+			// Audit log
+			adminRoutes.GET("/audit-logs", perm(models.PermAuditRead), auditHandler.ListAuditLogs)
+			adminRoutes.GET("/audit-logs/export", perm(models.PermAuditRead), auditHandler.ExportAuditLogs)
+
+			// OTP brute-force lockouts
+			adminRoutes.GET("/otp-lockouts", perm(models.PermTeamsWrite), emailOTPHandler.ListOTPLockouts)
+			adminRoutes.DELETE("/otp-lockouts/:team_id", perm(models.PermTeamsWrite), emailOTPHandler.ClearOTPLockout)
+			adminRoutes.GET("/sessions", perm(models.PermSessionsManage), sessionHandler.ListSessions)
+			adminRoutes.POST("/sessions/revoke-all", perm(models.PermSessionsManage), sessionHandler.RevokeAllSessions)
+			adminRoutes.DELETE("/sessions/:id", perm(models.PermSessionsManage), sessionHandler.RevokeSession)
+
+			// Events (editions)
+			adminRoutes.GET("/events", perm(models.PermEventsManage), eventHandler.ListEvents)
+			adminRoutes.POST("/events", perm(models.PermEventsManage), eventHandler.CreateEvent)
+			adminRoutes.GET("/events/:id", perm(models.PermEventsManage), eventHandler.GetEvent)
+			adminRoutes.PUT("/events/:id", perm(models.PermEventsManage), eventHandler.UpdateEvent)
+			adminRoutes.POST("/events/:id/activate", perm(models.PermEventsManage), eventHandler.ActivateEvent)
+
+			// Cities (codes, aliases, timezone, venue)
+			adminRoutes.GET("/cities", perm(models.PermEventsManage), cityHandler.ListCities)
+			adminRoutes.POST("/cities", perm(models.PermEventsManage), cityHandler.CreateCity)
+			adminRoutes.PUT("/cities/:code", perm(models.PermEventsManage), cityHandler.UpdateCity)
+			adminRoutes.DELETE("/cities/:code", perm(models.PermEventsManage), cityHandler.DeleteCity)
+
+			// Teams
+			adminRoutes.POST("/teams/create", perm(models.PermTeamsWrite), adminHandler.CreateTeamManually)
+			adminRoutes.POST("/teams/bulk-upload", perm(models.PermTeamsWrite), adminHandler.BulkUploadTeams)
+			adminRoutes.GET("/teams", perm(models.PermTeamsRead), adminHandler.GetAllTeams)
+			adminRoutes.GET("/teams/export", perm(models.PermTeamsRead), adminHandler.ExportTeams)
+			adminRoutes.GET("/teams/export/columns", perm(models.PermTeamsRead), adminHandler.GetExportColumns)
+			adminRoutes.POST("/teams/no-shows", perm(models.PermTeamsWrite), teamWithdrawalHandler.MarkNoShows)
+			adminRoutes.POST("/teams/:team_id/reinstate", perm(models.PermTeamsWrite), teamWithdrawalHandler.Reinstate)
+			adminRoutes.GET("/teams/:team_id/dashboard-tokens", perm(models.PermTeamsRead), dashboardTokenHandler.ListDashboardTokens)
+			adminRoutes.POST("/teams/:team_id/dashboard-tokens", perm(models.PermTeamsWrite), dashboardTokenHandler.IssueDashboardToken)
+			adminRoutes.DELETE("/teams/:team_id/dashboard-tokens/:token_id", perm(models.PermTeamsWrite), dashboardTokenHandler.RevokeDashboardToken)
+			adminRoutes.GET("/teams/:team_id/dashboard-tokens/:token_id/access-logs", perm(models.PermTeamsRead), dashboardTokenHandler.GetDashboardAccessLogs)
+			adminRoutes.DELETE("/data/clear", perm(models.PermDataClear), adminHandler.ClearAllData)
+
+			// Tickets Management
+			adminRoutes.GET("/tickets", perm(models.PermTicketsManage), ticketHandler.GetAllTickets)
+			adminRoutes.GET("/tickets/:id", perm(models.PermTicketsManage), ticketHandler.GetTicket)
+			adminRoutes.POST("/tickets/:id/resolve", perm(models.PermTicketsManage), ticketHandler.ResolveTicket)
+			adminRoutes.PATCH("/tickets/:id/status", perm(models.PermTicketsManage), ticketHandler.UpdateTicketStatus)
+
+			// Announcements Management
+			adminRoutes.POST("/announcements", perm(models.PermAnnouncementsManage), announcementHandler.CreateAnnouncement)
+			adminRoutes.GET("/announcements", perm(models.PermAnnouncementsManage), announcementHandler.GetAllAnnouncements)
+			adminRoutes.DELETE("/announcements/:id", perm(models.PermAnnouncementsManage), announcementHandler.DeleteAnnouncement)
+
+			// Bulk Email
+			adminRoutes.POST("/send-bulk-email", perm(models.PermEmailSend), bulkEmailHandler.SendBulkEmail)
+			adminRoutes.GET("/email-logs", perm(models.PermEmailSend), bulkEmailHandler.GetEmailLogs)
+
+			// Certificates
+			adminRoutes.POST("/certificates/send", perm(models.PermCertificatesSend), certificateHandler.SendCertificates)
+			adminRoutes.POST("/certificates/send-manual", perm(models.PermCertificatesSend), certificateHandler.SendManualCertificate)
+
+			// Stats
+			adminRoutes.GET("/stats/checkin", perm(models.PermTeamsRead), adminHandler.GetCheckInStats)
+			adminRoutes.DELETE("/checkin/:team_id", perm(models.PermCheckinManage), adminHandler.UndoCheckIn)
+			adminRoutes.DELETE("/checkin/:team_id/member/:member_id", perm(models.PermCheckinManage), adminHandler.UndoCheckInMember)
+
+			// Judging (submissions with contact details, judges and their assignments)
+			adminRoutes.GET("/judging/submissions", perm(models.PermJudgingManage), judgingHandler.GetSubmissions)
+			adminRoutes.GET("/judging/submissions/:id/notes", perm(models.PermJudgingManage), judgeHandler.ListNotes)
+			adminRoutes.GET("/judging/submissions/:id/versions", perm(models.PermJudgingManage), psSubmissionHandler.GetVersions)
+			adminRoutes.GET("/judging/submissions/:id/attachments/:attachment_id", perm(models.PermJudgingManage), psSubmissionHandler.GetAttachment)
+			adminRoutes.GET("/judging/submissions/:id/scores", perm(models.PermJudgingManage), judgingRoundHandler.ListScores)
+			// Rubric judging: rounds with a rubric per track; finalizing a round locks its scores
+			adminRoutes.GET("/judging/rounds", perm(models.PermJudgingManage), judgingRoundHandler.ListRounds)
+			adminRoutes.POST("/judging/rounds", perm(models.PermJudgingManage), judgingRoundHandler.CreateRound)
+			adminRoutes.PUT("/judging/rounds/:id", perm(models.PermJudgingManage), judgingRoundHandler.UpdateRound)
+			adminRoutes.POST("/judging/rounds/:id/finalize", perm(models.PermJudgingManage), judgingRoundHandler.FinalizeRound)
+			adminRoutes.PUT("/judging/rounds/:id/rubrics", perm(models.PermJudgingManage), judgingRoundHandler.SetRubric)
+			adminRoutes.DELETE("/judging/rounds/:id/rubrics/:rubric_id", perm(models.PermJudgingManage), judgingRoundHandler.DeleteRubric)
+			adminRoutes.GET("/judging/rounds/:id/leaderboard", perm(models.PermJudgingManage), judgingRoundHandler.GetLeaderboard)
+			adminRoutes.GET("/judges", perm(models.PermJudgingManage), judgeHandler.ListJudges)
+			adminRoutes.POST("/judges", perm(models.PermJudgingManage), judgeHandler.CreateJudge)
+			adminRoutes.DELETE("/judges/:id", perm(models.PermJudgingManage), judgeHandler.DeleteJudge)
+			adminRoutes.GET("/judges/:id/assignments", perm(models.PermJudgingManage), judgeHandler.ListAssignments)
+			adminRoutes.POST("/judges/:id/assignments", perm(models.PermJudgingManage), judgeHandler.CreateAssignment)
+			adminRoutes.DELETE("/judges/:id/assignments/:assignment_id", perm(models.PermJudgingManage), judgeHandler.DeleteAssignment)
+
+			// Semi-finalists (PS selections)
+			adminRoutes.GET("/semi-finalists", perm(models.PermJudgingManage), checkPSHandler.GetSemiFinalists)
+			adminRoutes.POST("/semi-finalists/:team_id", perm(models.PermJudgingManage), checkPSHandler.MarkSemiFinalist)
+			adminRoutes.DELETE("/semi-finalists/:team_id", perm(models.PermJudgingManage), checkPSHandler.UnmarkSemiFinalist)
+			adminRoutes.POST("/semi-finalists/:team_id/awards", perm(models.PermJudgingManage), checkPSHandler.SetAwards)
+			// PS selection overrides and history
+			adminRoutes.POST("/teams/:team_id/ps-selection", perm(models.PermJudgingManage), checkPSHandler.ReassignPS)
+			adminRoutes.GET("/ps-selections/history", perm(models.PermJudgingManage), checkPSHandler.GetPSHistory)
+
+			// RSVP PIN (when the rsvp1 phase is in pin mode)
+			adminRoutes.GET("/rsvp-pin", perm(models.PermPhasesManage), rsvpPinHandler.GetRSVPPin)
+
+			// Event phase schedule (RSVP I/II, PS release, PS lock, final submission)
+			adminRoutes.GET("/phases", perm(models.PermPhasesManage), phaseHandler.ListPhases)
+			adminRoutes.PUT("/phases/:phase", perm(models.PermPhasesManage), phaseHandler.UpsertPhase)
+			adminRoutes.POST("/phases/:phase/override", perm(models.PermPhasesManage), phaseHandler.SetOverride)
+			adminRoutes.DELETE("/phases/:phase", perm(models.PermPhasesManage), phaseHandler.DeleteCityPhase)
+
+			// Volunteer Management
+			adminRoutes.POST("/volunteers", perm(models.PermVolunteersManage), volunteerAuthHandler.CreateVolunteer)
+			adminRoutes.GET("/volunteers", perm(models.PermVolunteersManage), volunteerAuthHandler.GetAllVolunteers)
+			adminRoutes.GET("/volunteers/:id", perm(models.PermVolunteersManage), volunteerAuthHandler.GetVolunteerByID)
+			adminRoutes.GET("/volunteers/:id/logs", perm(models.PermVolunteersManage), scannerHandler.GetVolunteerLogs)
+			adminRoutes.PUT("/volunteers/:id", perm(models.PermVolunteersManage), volunteerAuthHandler.UpdateVolunteer)
+			adminRoutes.DELETE("/volunteers/:id", perm(models.PermVolunteersManage), volunteerAuthHandler.DeleteVolunteer)
+
+			// Event Table Management
+			adminRoutes.POST("/registration-desks/allocate", perm(models.PermCheckinManage), adminHandler.AllocateRegistrationDesks)
+			adminRoutes.POST("/registration-desks/clear", perm(models.PermCheckinManage), adminHandler.ClearAllRegistrationDesks)
+			adminRoutes.GET("/problem-statements", perm(models.PermJudgingManage), problemStatementHandler.ListAdmin)
+			adminRoutes.POST("/problem-statements", perm(models.PermJudgingManage), problemStatementHandler.CreateAdmin)
+			adminRoutes.DELETE("/problem-statements/:id", perm(models.PermJudgingManage), problemStatementHandler.DeleteAdmin)
+			adminRoutes.PUT("/problem-statements/:id/capacity", perm(models.PermJudgingManage), problemStatementHandler.SetCapacity)
+			adminRoutes.PUT("/problem-statements/:id/submission-fields", perm(models.PermJudgingManage), problemStatementHandler.SetSubmissionFields)
+			adminRoutes.POST("/problem-statements/release-early", perm(models.PermJudgingManage), problemStatementHandler.ReleaseEarly)
+			adminRoutes.POST("/problem-statements/reset-release", perm(models.PermJudgingManage), problemStatementHandler.ResetRelease)
+			adminRoutes.GET("/problem-statements/submission-status", perm(models.PermJudgingManage), problemStatementHandler.GetSubmissionStatus)
+			adminRoutes.POST("/problem-statements/toggle-submission", perm(models.PermJudgingManage), problemStatementHandler.ToggleSubmissionWindow)
+			adminRoutes.GET("/problem-statements/final-submission-status", perm(models.PermJudgingManage), problemStatementHandler.GetFinalSubmissionStatus)
+			adminRoutes.POST("/problem-statements/toggle-final-submission", perm(models.PermJudgingManage), problemStatementHandler.ToggleFinalSubmissionPortal)
+			adminRoutes.POST("/tables", perm(models.PermCheckinManage), eventTableHandler.CreateEventTable)
+			adminRoutes.GET("/tables", perm(models.PermCheckinManage), eventTableHandler.GetAllEventTables)
+			adminRoutes.GET("/tables/:id", perm(models.PermCheckinManage), eventTableHandler.GetEventTable)
+			adminRoutes.PUT("/tables/:id", perm(models.PermCheckinManage), eventTableHandler.UpdateEventTable)
+			adminRoutes.DELETE("/tables/:id", perm(models.PermCheckinManage), eventTableHandler.DeleteEventTable)
+
+			// Seat Allocation (cities with seat allocation) - blocks, rooms, seats
+			adminRoutes.GET("/seat-allocation/blocks", perm(models.PermSeatingManage), seatAllocatorHandler.GetAllBlocks)
+			adminRoutes.POST("/seat-allocation/blocks", perm(models.PermSeatingManage), seatAllocatorHandler.CreateBlock)
+			adminRoutes.PUT("/seat-allocation/blocks/:id", perm(models.PermSeatingManage), seatAllocatorHandler.UpdateBlock)
+			adminRoutes.DELETE("/seat-allocation/blocks/:id", perm(models.PermSeatingManage), seatAllocatorHandler.DeleteBlock)
+			adminRoutes.GET("/seat-allocation/rooms", perm(models.PermSeatingManage), seatAllocatorHandler.GetRoomsByBlock)
+			adminRoutes.POST("/seat-allocation/rooms", perm(models.PermSeatingManage), seatAllocatorHandler.CreateRoom)
+			adminRoutes.POST("/seat-allocation/seats/grid", perm(models.PermSeatingManage), seatAllocatorHandler.CreateSeatsGrid)
+			adminRoutes.POST("/seat-allocation/seats/layout", perm(models.PermSeatingManage), seatAllocatorHandler.CreateSeatsLayout)
+			adminRoutes.GET("/seat-allocation/rooms/:room_id/layout", perm(models.PermSeatingManage), seatAllocatorHandler.GetRoomLayout)
+			adminRoutes.GET("/seat-allocation/rooms/:room_id/room-view", perm(models.PermSeatingManage), seatAllocatorHandler.GetRoomView)
+			adminRoutes.GET("/seat-allocation/rooms/:room_id/seats", perm(models.PermSeatingManage), seatAllocatorHandler.GetSeatsByRoom)
+			adminRoutes.PUT("/seat-allocation/seats/mark-team-size", perm(models.PermSeatingManage), seatAllocatorHandler.MarkSeatsForTeamSize)
+			adminRoutes.GET("/seat-allocation/allocations", perm(models.PermSeatingManage), seatAllocatorHandler.GetAllAllocations)
+			adminRoutes.GET("/seat-allocation/stats", perm(models.PermSeatingManage), seatAllocatorHandler.GetAllocationStats)
+
+			// Volunteer Admins (create/list/delete city-scoped volunteer admins)
+			adminRoutes.POST("/volunteer-admins", perm(models.PermVolunteersManage), volunteerAdminHandler.CreateVolunteerAdmin)
+			adminRoutes.GET("/volunteer-admins", perm(models.PermVolunteersManage), volunteerAdminHandler.GetAllVolunteerAdmins)
+			adminRoutes.DELETE("/volunteer-admins/:id", perm(models.PermVolunteersManage), volunteerAdminHandler.DeleteVolunteerAdmin)
+		}
+	}
+
+	// Start server
+	serverAddr := fmt.Sprintf(":%s", cfg.Port)
+	log.Printf("🚀 Server starting on %s", serverAddr)
+	log.Printf("📋 Environment: %s", cfg.Environment)
+	log.Printf("🔐 CORS allowed origins: %s", cfg.AllowedOrigins)
+	log.Println("📡 API Endpoints:")
+	log.Println("   GET  /health")
+	log.Println("   GET  /api/v1/teams/search")
+	log.Println("   GET  /api/v1/teams/:id (auth)")
+	log.Println("   PUT  /api/v1/teams/:id/rsvp (auth)")
+	log.Println("   GET  /api/v1/teams/:id/announcements")
+	log.Println("   GET  /api/v1/dashboard/:token")
+	log.Println("   GET  /api/v1/teams/:id/dashboard-tokens (team leader)")
+	log.Println("   POST /api/v1/teams/:id/dashboard-tokens (team leader)")
+	log.Println("   DELETE /api/v1/teams/:id/dashboard-tokens/:token_id (team leader)")
+	log.Println("   POST /api/v1/tickets (team session)")
+	log.Println("   POST /api/v1/teams/:id/lock-ps (team session)")
+	log.Println("   POST /api/v1/teams/:id/submission (team session)")
+	log.Println("   GET  /api/v1/teams/:id/submission/attachments/:attachment_id (team session)")
+	log.Println("   POST /api/v1/auth/send-email-otp")
+	log.Println("   POST /api/v1/auth/verify-email-otp")
+	log.Println("   POST /api/v1/auth/send-magic-link")
+	log.Println("   POST /api/v1/auth/verify-magic-link")
+	log.Println("   POST /api/v1/checkin/scan (volunteer)")
+	log.Println("   POST /api/v1/checkin/confirm (volunteer)")
+	log.Println("   POST /api/v1/admin/teams/bulk-upload (admin)")
+	log.Println("   GET  /api/v1/admin/teams (admin)")
+	log.Println("   GET  /api/v1/admin/tickets (admin)")
+	log.Println("   POST /api/v1/admin/tickets/:id/resolve (admin)")
+	log.Println("   POST /api/v1/admin/announcements (admin)")
+	log.Println("   GET  /api/v1/admin/announcements (admin)")
+	log.Println("   DELETE /api/v1/admin/announcements/:id (admin)")
+	log.Println("   POST /api/v1/admin/send-bulk-email (admin)")
+	log.Println("   GET  /api/v1/admin/email-logs (admin)")
+	log.Println("   GET  /api/v1/admin/stats/checkin (admin)")
+
+	if err := router.Run(serverAddr); err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
+}
+
+// storageConfig maps the environment settings onto storage.New's config.
+func storageConfig(cfg *config.Config) storage.Config {
+	return storage.Config{
+		Backend:       cfg.StorageBackend,
+		LocalDir:      cfg.StorageDir,
+		PublicURL:     cfg.APIPublicURL,
+		SigningSecret: cfg.StorageSigningSecret,
+		S3: storage.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			PathStyle:       cfg.S3PathStyle,
+		},
+	}
+}
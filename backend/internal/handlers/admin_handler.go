@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
 	"log"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/middleware"
 	"github.com/rift26/backend/internal/models"
 	"github.com/rift26/backend/internal/repository"
 	"github.com/rift26/backend/internal/services"
@@ -19,9 +21,11 @@ type AdminHandler struct {
 	announcementRepo              *repository.AnnouncementRepository
 	teamService                   *services.TeamService
 	userRepo                      *repository.UserRepository
-	jwtSecret                     string
+	twoFactorService              *services.TwoFactorService
 	registrationDeskAllocService  *services.RegistrationDeskAllocationService
 	participantCheckinRepo        *repository.ParticipantCheckInRepository
+	cityService                   *services.CityService
+	rosterExportService           *services.RosterExportService
 }
 
 func NewAdminHandler(
@@ -29,18 +33,22 @@ func NewAdminHandler(
 	announcementRepo *repository.AnnouncementRepository,
 	teamService *services.TeamService,
 	userRepo *repository.UserRepository,
-	jwtSecret string,
+	twoFactorService *services.TwoFactorService,
 	registrationDeskAllocService *services.RegistrationDeskAllocationService,
 	participantCheckinRepo *repository.ParticipantCheckInRepository,
+	cityService *services.CityService,
+	rosterExportService *services.RosterExportService,
 ) *AdminHandler {
 	return &AdminHandler{
 		teamRepo:                     teamRepo,
 		announcementRepo:             announcementRepo,
 		teamService:                  teamService,
 		userRepo:                     userRepo,
-		jwtSecret:                    jwtSecret,
+		twoFactorService:             twoFactorService,
 		registrationDeskAllocService: registrationDeskAllocService,
 		participantCheckinRepo:       participantCheckinRepo,
+		cityService:                  cityService,
+		rosterExportService:          rosterExportService,
 	}
 }
 
@@ -59,7 +67,7 @@ func (h *AdminHandler) AdminLogin(c *gin.Context) {
 
 	// Get user by email
 	user, err := h.userRepo.GetByEmail(c.Request.Context(), req.Email)
-	if err != nil {
+	if err != nil || user == nil {
 		c.JSON(401, gin.H{"error": "Invalid credentials"})
 		return
 	}
@@ -76,15 +84,32 @@ func (h *AdminHandler) AdminLogin(c *gin.Context) {
 		return
 	}
 
-	// Generate JWT token
-	token, err := user.GenerateJWT(h.jwtSecret)
+	if !user.IsActive {
+		c.JSON(403, gin.H{"error": "Account disabled"})
+		return
+	}
+
+	// Start a session, or ask for the second factor first
+	result, err := h.twoFactorService.Login(c.Request.Context(), models.SessionSubject{
+		SubjectID: user.ID,
+		Role:      user.Role,
+		Email:     user.Email,
+	}, sessionClient(c))
 	if err != nil {
 		c.JSON(500, gin.H{"error": "Failed to generate token"})
 		return
 	}
+	if result.Tokens == nil {
+		c.JSON(200, result)
+		return
+	}
+	tokens := result.Tokens
 
 	c.JSON(200, gin.H{
-		"token": token,
+		"token":         tokens.Token,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"permissions":   tokens.Permissions,
 		"user": gin.H{
 			"id":    user.ID,
 			"email": user.Email,
@@ -94,28 +119,14 @@ func (h *AdminHandler) AdminLogin(c *gin.Context) {
 	})
 }
 
-// mapCity converts city name from CSV to City enum
-func mapCity(cityName string) *models.City {
-	cityLower := strings.ToLower(strings.TrimSpace(cityName))
-
-	if strings.Contains(cityLower, "lucknow") || cityLower == "lko" {
-		city := models.CityLKO
-		return &city
-	}
-	if strings.Contains(cityLower, "pune") {
-		city := models.CityPUNE
-		return &city
+// mapCity converts a city name from CSV to a city code using the configured names and aliases
+func (h *AdminHandler) mapCity(ctx context.Context, cityName string) (*models.City, error) {
+	code, ok, err := h.cityService.Normalize(ctx, cityName)
+	if err != nil || !ok {
+		return nil, err // Unknown city
 	}
-	if strings.Contains(cityLower, "bengaluru") || strings.Contains(cityLower, "bangalore") || cityLower == "blr" {
-		city := models.CityBLR
-		return &city
-	}
-	if strings.Contains(cityLower, "noida") {
-		city := models.CityNOIDA
-		return &city
-	}
-
-	return nil // Unknown city
+	city := models.City(code)
+	return &city, nil
 }
 
 // BulkUploadTeams handles CSV upload for team creation with optimizations
@@ -219,6 +230,7 @@ func (h *AdminHandler) BulkUploadTeams(c *gin.Context) {
 
 	// Use transaction for batch insert
 	ctx := c.Request.Context()
+	eventID := middleware.GetEventID(c) // teams go into the selected event
 
 	// Prepare all teams and members first
 	type TeamWithMembers struct {
@@ -251,7 +263,7 @@ func (h *AdminHandler) BulkUploadTeams(c *gin.Context) {
 		leaderEmail := candidates[leaderIndex].Email
 
 		// VALIDATION 1: Check if team with same name and leader already exists
-		existingTeam, err := h.teamRepo.CheckTeamExistsByNameAndLeader(ctx, teamName, leaderEmail)
+		existingTeam, err := h.teamRepo.CheckTeamExistsByNameAndLeader(ctx, eventID, teamName, leaderEmail)
 		if err != nil {
 			errorCount++
 			errors = append(errors, fmt.Sprintf("Team %s: Failed to validate - %v", teamName, err))
@@ -283,7 +295,7 @@ func (h *AdminHandler) BulkUploadTeams(c *gin.Context) {
 			}
 
 			// Check if phone exists in database
-			phoneExists, existingTeamName, err := h.teamRepo.CheckPhoneExists(ctx, phone)
+			phoneExists, existingTeamName, err := h.teamRepo.CheckPhoneExists(ctx, eventID, phone)
 			if err != nil {
 				validationFailed = true
 				validationErrors = append(validationErrors, fmt.Sprintf("Failed to validate phone %s: %v", phone, err))
@@ -296,7 +308,7 @@ func (h *AdminHandler) BulkUploadTeams(c *gin.Context) {
 			}
 
 			// Check if email exists in database
-			emailExists, existingTeamName, err := h.teamRepo.CheckEmailExists(ctx, candidate.Email)
+			emailExists, existingTeamName, err := h.teamRepo.CheckEmailExists(ctx, eventID, candidate.Email)
 			if err != nil {
 				validationFailed = true
 				validationErrors = append(validationErrors, fmt.Sprintf("Failed to validate email %s: %v", candidate.Email, err))
@@ -332,7 +344,12 @@ func (h *AdminHandler) BulkUploadTeams(c *gin.Context) {
 
 		// Map city from CSV
 		cityName := teamCities[teamID]
-		city := mapCity(cityName)
+		city, err := h.mapCity(ctx, cityName)
+		if err != nil {
+			errorCount++
+			errors = append(errors, fmt.Sprintf("Team %s: Failed to resolve city - %v", teamName, err))
+			continue
+		}
 
 		// Create team
 		teamUUID := uuid.New()
@@ -342,6 +359,7 @@ func (h *AdminHandler) BulkUploadTeams(c *gin.Context) {
 			City:        city,
 			Status:      "shortlisted",
 			MemberCount: len(candidates),
+			EventID:     eventID,
 		}
 
 		// Prepare team members
@@ -396,16 +414,24 @@ func (h *AdminHandler) BulkUploadTeams(c *gin.Context) {
 	})
 }
 
-// ClearAllData deletes all teams and members - for testing only
+// ClearAllData deletes the selected event's teams and members - for testing only
 // DELETE /api/v1/admin/data/clear
 func (h *AdminHandler) ClearAllData(c *gin.Context) {
-	err := h.teamRepo.ClearAllData(c.Request.Context())
+	eventID := middleware.GetEventID(c)
+	middleware.SetAuditAction(c, "data.clear", "event", eventID.String())
+	before, err := h.teamRepo.GetCheckInStats(c.Request.Context(), eventID)
+	if err != nil {
+		log.Printf("ClearAllData: failed to snapshot stats for audit: %v", err)
+	}
+
+	err = h.teamRepo.ClearAllData(c.Request.Context(), eventID)
 	if err != nil {
 		c.JSON(500, gin.H{"error": "Failed to clear data"})
 		return
 	}
+	middleware.SetAuditChange(c, before, nil)
 
-	c.JSON(200, gin.H{"message": "All teams and members deleted successfully"})
+	c.JSON(200, gin.H{"message": "All teams and members of this event deleted successfully"})
 }
 
 // CreateAnnouncement creates a new announcement
@@ -442,7 +468,7 @@ func (h *AdminHandler) CreateAnnouncement(c *gin.Context) {
 // GetAllAnnouncements returns all announcements (including inactive)
 // GET /api/v1/admin/announcements
 func (h *AdminHandler) GetAllAnnouncements(c *gin.Context) {
-	announcements, err := h.announcementRepo.GetAll(c.Request.Context())
+	announcements, err := h.announcementRepo.GetAll(c.Request.Context(), middleware.GetEventID(c))
 	if err != nil {
 		c.JSON(500, gin.H{"error": "Failed to fetch announcements"})
 		return
@@ -484,7 +510,7 @@ func (h *AdminHandler) UpdateAnnouncement(c *gin.Context) {
 // GetCheckInStats returns check-in statistics
 // GET /api/v1/admin/stats/checkin
 func (h *AdminHandler) GetCheckInStats(c *gin.Context) {
-	stats, err := h.teamRepo.GetCheckInStats(c.Request.Context())
+	stats, err := h.teamRepo.GetCheckInStats(c.Request.Context(), middleware.GetEventID(c))
 	if err != nil {
 		c.JSON(500, gin.H{"error": "Failed to fetch stats"})
 		return
@@ -502,15 +528,22 @@ func (h *AdminHandler) UndoCheckIn(c *gin.Context) {
 		c.JSON(400, gin.H{"error": "Invalid team ID"})
 		return
 	}
+	middleware.SetAuditAction(c, "checkin.undo", "team", teamID.String())
+	team, ok := h.eventTeam(c, teamID)
+	if !ok {
+		return
+	}
 	if err := h.participantCheckinRepo.DeleteByTeamIDForAdmin(teamID); err != nil {
 		log.Printf("UndoCheckIn: %v", err)
 		c.JSON(500, gin.H{"error": "Failed to undo check-in"})
 		return
 	}
+	middleware.SetAuditChange(c, gin.H{"checked_in_at": team.CheckedInAt}, gin.H{"checked_in_at": nil})
 	c.JSON(200, gin.H{"message": "Check-in undone successfully"})
 }
 
-// UndoCheckInMember removes one member's check-in for a team (admin only). If no members remain checked in, clears team check-in.
+// UndoCheckInMember removes one member's check-in for a team (admin only). The team itself stays checked in;
+// use UndoCheckIn to fully undo.
 // Register: DELETE /api/v1/admin/checkin/:team_id/member/:member_id
 func (h *AdminHandler) UndoCheckInMember(c *gin.Context) {
 	teamID, err := uuid.Parse(c.Param("team_id"))
@@ -523,21 +556,120 @@ func (h *AdminHandler) UndoCheckInMember(c *gin.Context) {
 		c.JSON(400, gin.H{"error": "Invalid member ID"})
 		return
 	}
+	middleware.SetAuditAction(c, "checkin.undo_member", "team_member", memberID.String())
+	team, ok := h.eventTeam(c, teamID)
+	if !ok {
+		return
+	}
+	before, err := h.memberCheckIn(team, memberID)
+	if err != nil {
+		log.Printf("UndoCheckInMember: %v", err)
+		c.JSON(500, gin.H{"error": "Failed to fetch member check-in"})
+		return
+	}
 	if err := h.participantCheckinRepo.DeleteByTeamAndMemberForAdmin(teamID, memberID); err != nil {
 		log.Printf("UndoCheckInMember: %v", err)
 		c.JSON(500, gin.H{"error": "Failed to remove member check-in"})
 		return
 	}
+	// Re-read the team so the audit shows the team check-in as it is after the delete
+	teamCheckedInAt := team.CheckedInAt
+	if after, err := h.teamRepo.GetByID(c.Request.Context(), teamID); err != nil {
+		log.Printf("UndoCheckInMember: reload team: %v", err)
+	} else if after != nil {
+		teamCheckedInAt = after.CheckedInAt
+	}
+	middleware.SetAuditChange(c,
+		gin.H{"team_id": teamID, "member_check_in": before, "team_checked_in_at": team.CheckedInAt},
+		gin.H{"team_id": teamID, "member_check_in": nil, "team_checked_in_at": teamCheckedInAt})
 	c.JSON(200, gin.H{"message": "Member check-in removed"})
 }
 
+// memberCheckIn returns the member's check-in row, or nil if the member is not checked in.
+// Legacy rows without team_member_id are matched by name and role, as DeleteByTeamAndMemberForAdmin does.
+func (h *AdminHandler) memberCheckIn(team *models.Team, memberID uuid.UUID) (*models.ParticipantCheckIn, error) {
+	checkIns, err := h.participantCheckinRepo.GetByTeamID(team.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range checkIns {
+		if checkIns[i].TeamMemberID != nil && *checkIns[i].TeamMemberID == memberID {
+			return &checkIns[i], nil
+		}
+	}
+	for _, m := range team.Members {
+		if m.ID != memberID {
+			continue
+		}
+		for i := range checkIns {
+			if checkIns[i].TeamMemberID == nil && checkIns[i].ParticipantName == m.Name && checkIns[i].ParticipantRole == string(m.Role) {
+				return &checkIns[i], nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// eventTeam loads a team of the selected event and writes the error response otherwise;
+// teams of other editions are reported as not found.
+func (h *AdminHandler) eventTeam(c *gin.Context, teamID uuid.UUID) (*models.Team, bool) {
+	team, err := h.teamRepo.GetByID(c.Request.Context(), teamID)
+	if err != nil {
+		log.Printf("eventTeam: %v", err)
+		c.JSON(500, gin.H{"error": "Failed to fetch team"})
+		return nil, false
+	}
+	if team == nil || team.EventID != middleware.GetEventID(c) {
+		c.JSON(404, gin.H{"error": "Team not found"})
+		return nil, false
+	}
+	return team, true
+}
+
+// ExportTeams streams the full roster (one row per participant) as CSV or XLSX.
+// Takes the same filters as GetAllTeams; columns is a comma-separated list of keys (default: all).
+// GET /api/v1/admin/teams/export?format=csv|xlsx&status=&city=&columns=
+func (h *AdminHandler) ExportTeams(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", "csv"))
+	if format != "csv" && format != "xlsx" {
+		c.JSON(400, gin.H{"error": "format must be csv or xlsx"})
+		return
+	}
+	cols, err := h.rosterExportService.ResolveColumns(c.Query("columns"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("roster-%s.%s", time.Now().Format("20060102-150405"), format)
+	contentType := "text/csv; charset=utf-8"
+	if format == "xlsx" {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(200)
+
+	// Headers are already sent once rows stream, so failures can only be logged
+	err = h.rosterExportService.Export(c.Request.Context(), c.Writer, format, middleware.GetEventID(c), c.Query("status"), c.Query("city"), cols)
+	if err != nil {
+		log.Printf("❌ Error exporting roster: %v", err)
+	}
+}
+
+// GetExportColumns lists the columns available to ExportTeams.
+// GET /api/v1/admin/teams/export/columns
+func (h *AdminHandler) GetExportColumns(c *gin.Context) {
+	c.JSON(200, gin.H{"columns": h.rosterExportService.Columns()})
+}
+
 // GetAllTeams returns all teams with filters
 // GET /api/v1/admin/teams?status=&city=
 func (h *AdminHandler) GetAllTeams(c *gin.Context) {
 	status := c.Query("status")
 	city := c.Query("city")
 
-	teams, err := h.teamRepo.GetAllWithFilters(c.Request.Context(), status, city)
+	teams, err := h.teamRepo.GetAllWithFilters(c.Request.Context(), middleware.GetEventID(c), status, city)
 	if err != nil {
 		log.Printf("❌ Error fetching teams: %v", err)
 		c.JSON(500, gin.H{"error": "Failed to fetch teams", "details": err.Error()})
@@ -624,6 +756,7 @@ func (h *AdminHandler) CreateTeamManually(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
+	eventID := middleware.GetEventID(c) // the team goes into the selected event
 
 	// Validate exactly one leader
 	leaderCount := 0
@@ -661,7 +794,7 @@ func (h *AdminHandler) CreateTeamManually(c *gin.Context) {
 
 	// Check if any email already exists in database
 	for _, member := range req.Members {
-		exists, existingTeam, err := h.teamRepo.CheckEmailExists(ctx, member.Email)
+		exists, existingTeam, err := h.teamRepo.CheckEmailExists(ctx, eventID, member.Email)
 		if err != nil {
 			c.JSON(500, gin.H{"error": "Failed to validate email"})
 			return
@@ -675,7 +808,7 @@ func (h *AdminHandler) CreateTeamManually(c *gin.Context) {
 	// Check if any phone already exists in database
 	for _, member := range req.Members {
 		phone := strings.TrimSpace(strings.TrimPrefix(member.Phone, "+91"))
-		exists, existingTeam, err := h.teamRepo.CheckPhoneExists(ctx, phone)
+		exists, existingTeam, err := h.teamRepo.CheckPhoneExists(ctx, eventID, phone)
 		if err != nil {
 			c.JSON(500, gin.H{"error": "Failed to validate phone"})
 			return
@@ -689,7 +822,11 @@ func (h *AdminHandler) CreateTeamManually(c *gin.Context) {
 	// Map city
 	var city *models.City
 	if req.City != "" {
-		mappedCity := mapCity(req.City)
+		mappedCity, err := h.mapCity(ctx, req.City)
+		if err != nil {
+			c.JSON(500, gin.H{"error": "Failed to resolve city"})
+			return
+		}
 		city = mappedCity
 	}
 
@@ -701,6 +838,7 @@ func (h *AdminHandler) CreateTeamManually(c *gin.Context) {
 		City:        city,
 		Status:      "shortlisted",
 		MemberCount: len(req.Members),
+		EventID:     eventID,
 	}
 
 	// Generate dashboard token
@@ -774,7 +912,7 @@ func (h *AdminHandler) AllocateRegistrationDesks(c *gin.Context) {
 		c.JSON(503, gin.H{"error": "Registration desk allocation not configured"})
 		return
 	}
-	result, err := h.registrationDeskAllocService.AllocateRegistrationDesks(c.Request.Context())
+	result, err := h.registrationDeskAllocService.AllocateRegistrationDesks(c.Request.Context(), middleware.GetEventID(c))
 	if err != nil {
 		log.Printf("[Admin] AllocateRegistrationDesks: %v", err)
 		c.JSON(500, gin.H{"error": "Failed to allocate registration desks", "detail": err.Error()})
@@ -786,21 +924,21 @@ func (h *AdminHandler) AllocateRegistrationDesks(c *gin.Context) {
 	})
 }
 
-// ClearAllRegistrationDesks sets registration_desk_id = NULL for all teams that have a desk allocated.
+// ClearAllRegistrationDesks sets registration_desk_id = NULL for the event's teams that have a desk allocated.
 // POST /api/v1/admin/registration-desks/clear
 func (h *AdminHandler) ClearAllRegistrationDesks(c *gin.Context) {
 	if h.registrationDeskAllocService == nil {
 		c.JSON(503, gin.H{"error": "Registration desk allocation not configured"})
 		return
 	}
-	cleared, err := h.registrationDeskAllocService.ClearAllRegistrationDesks(c.Request.Context())
+	cleared, err := h.registrationDeskAllocService.ClearAllRegistrationDesks(c.Request.Context(), middleware.GetEventID(c))
 	if err != nil {
 		log.Printf("[Admin] ClearAllRegistrationDesks: %v", err)
 		c.JSON(500, gin.H{"error": "Failed to clear registration desks", "detail": err.Error()})
 		return
 	}
 	c.JSON(200, gin.H{
-		"message": "All registration desk allocations cleared",
+		"message": "All registration desk allocations of this event cleared",
 		"cleared": cleared,
 	})
 }
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/middleware"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+	"github.com/rift26/backend/internal/services"
+)
+
+type AdminUserHandler struct {
+	adminUserService *services.AdminUserService
+}
+
+func NewAdminUserHandler(adminUserService *services.AdminUserService) *AdminUserHandler {
+	return &AdminUserHandler{adminUserService: adminUserService}
+}
+
+// ListUsers returns admin accounts with roles, status and last login times (admin).
+// GET /api/v1/admin/users
+func (h *AdminUserHandler) ListUsers(c *gin.Context) {
+	users, err := h.adminUserService.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"users": users})
+}
+
+// GetUser returns one admin account (admin).
+// GET /api/v1/admin/users/:id
+func (h *AdminUserHandler) GetUser(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	user, err := h.adminUserService.Get(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Admin user not found"})
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// InviteUser creates an admin account and emails a set-password link (admin).
+// Granting roles in the same call also needs roles.manage.
+// POST /api/v1/admin/users
+func (h *AdminUserHandler) InviteUser(c *gin.Context) {
+	var req models.InviteAdminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Roles) > 0 && !models.HasPermission(middleware.GetPermissions(c), models.PermRolesManage) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: missing permission " + string(models.PermRolesManage)})
+		return
+	}
+	actor, _ := middleware.GetUserID(c)
+	user, sent, err := h.adminUserService.Invite(c.Request.Context(), req, actor)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"user": user, "email_sent": sent})
+}
+
+// UpdateUser changes an admin's display name (admin).
+// PUT /api/v1/admin/users/:id
+func (h *AdminUserHandler) UpdateUser(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	var req models.UpdateAdminUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	user, err := h.adminUserService.Update(c.Request.Context(), id, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// DisableUser blocks an admin from logging in and ends their sessions (admin).
+// POST /api/v1/admin/users/:id/disable
+func (h *AdminUserHandler) DisableUser(c *gin.Context) {
+	h.setActive(c, false)
+}
+
+// EnableUser lets a disabled admin log in again (admin).
+// POST /api/v1/admin/users/:id/enable
+func (h *AdminUserHandler) EnableUser(c *gin.Context) {
+	h.setActive(c, true)
+}
+
+func (h *AdminUserHandler) setActive(c *gin.Context, active bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	actor, _ := middleware.GetUserID(c)
+	if err := h.adminUserService.SetActive(c.Request.Context(), id, active, actor); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, repository.ErrNoRoleManager) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if active {
+		c.JSON(http.StatusOK, gin.H{"message": "User enabled"})
+	} else {
+		c.JSON(http.StatusOK, gin.H{"message": "User disabled"})
+	}
+}
+
+// ResetPassword emails the admin a one-time link to set a new password; resends pending invites (admin).
+// POST /api/v1/admin/users/:id/reset-password
+func (h *AdminUserHandler) ResetPassword(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	actor, _ := middleware.GetUserID(c)
+	if err := h.adminUserService.SendPasswordReset(c.Request.Context(), id, actor); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Password link sent"})
+}
+
+// SetPassword redeems an invite or reset link (public).
+// POST /api/v1/auth/set-password
+func (h *AdminUserHandler) SetPassword(c *gin.Context) {
+	var req models.SetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.adminUserService.SetPassword(c.Request.Context(), req.Token, req.Password); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Password set. You can now log in."})
+}
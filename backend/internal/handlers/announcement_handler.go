@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rift26/backend/internal/middleware"
 	"github.com/rift26/backend/internal/models"
 	"github.com/rift26/backend/internal/services"
 )
@@ -30,7 +31,7 @@ func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
 		adminEmail = "admin@rift.com"
 	}
 
-	announcement, err := h.announcementService.CreateAnnouncement(req, adminEmail)
+	announcement, err := h.announcementService.CreateAnnouncement(middleware.GetEventID(c), req, adminEmail)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -44,7 +45,7 @@ func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
 
 // GET /api/v1/admin/announcements
 func (h *AnnouncementHandler) GetAllAnnouncements(c *gin.Context) {
-	announcements, err := h.announcementService.GetAllAnnouncements()
+	announcements, err := h.announcementService.GetAllAnnouncements(middleware.GetEventID(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/middleware"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/services"
+)
+
+type APIKeyHandler struct {
+	apiKeyService *services.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService *services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// ListAPIKeys returns all API keys (without secrets) and the scope catalog (admin).
+// GET /api/v1/admin/api-keys
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load API keys"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"api_keys": keys, "scopes": models.APIKeyScopes})
+}
+
+// CreateAPIKey issues a key. The plaintext key is in the response and cannot be retrieved again (admin).
+// POST /api/v1/admin/api-keys
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	createdBy, _ := middleware.GetUserID(c)
+	key, err := h.apiKeyService.Create(c.Request.Context(), req, createdBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	middleware.SetAuditAction(c, "api_key.create", "api_key", key.ID.String())
+	middleware.SetAuditChange(c, nil, key.APIKey)
+	c.JSON(http.StatusCreated, key)
+}
+
+// RevokeAPIKey disables a key immediately (admin).
+// DELETE /api/v1/admin/api-keys/:id
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return
+	}
+	middleware.SetAuditAction(c, "api_key.revoke", "api_key", id.String())
+	revoked, err := h.apiKeyService.Revoke(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke API key"})
+		return
+	}
+	if !revoked {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found or already revoked"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked"})
+}
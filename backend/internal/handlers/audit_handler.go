@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/services"
+)
+
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// ListAuditLogs returns a filtered page of the audit log, newest first (admin).
+// GET /api/v1/admin/audit-logs?actor_email=&role=&action=&target_type=&target_id=&status=&from=&to=&page=&page_size=
+func (h *AuditHandler) ListAuditLogs(c *gin.Context) {
+	var filter models.AuditLogFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	page, err := h.auditService.List(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// ExportAuditLogs streams every matching entry as CSV (admin). Takes the same filters as ListAuditLogs.
+// GET /api/v1/admin/audit-logs/export
+func (h *AuditHandler) ExportAuditLogs(c *gin.Context) {
+	var filter models.AuditLogFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.auditService.ValidateFilter(filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("audit-log-%s.csv", time.Now().Format("20060102-150405"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	// Headers are already sent once rows stream, so failures can only be logged
+	if err := h.auditService.ExportCSV(c.Request.Context(), c.Writer, filter); err != nil {
+		log.Printf("❌ Error exporting audit log: %v", err)
+	}
+}
@@ -23,7 +23,7 @@ func (h *AuthHandler) VerifyFirebase(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.VerifyFirebaseToken(c.Request.Context(), req.IDToken, req.TeamID)
+	response, err := h.authService.VerifyFirebaseToken(c.Request.Context(), req.IDToken, req.TeamID, sessionClient(c))
 	if err != nil {
 		c.JSON(401, gin.H{"error": err.Error()})
 		return
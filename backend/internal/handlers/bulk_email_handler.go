@@ -10,7 +10,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/middleware"
 	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
 	"github.com/rift26/backend/internal/services"
 )
 
@@ -50,7 +52,7 @@ func (h *BulkEmailHandler) SendBulkEmail(c *gin.Context) {
 	}
 
 	// Get matching teams based on filters
-	matchingTeams, err := h.announcementService.GetTeamsMatchingFilters(req.Filters)
+	matchingTeams, err := h.announcementService.GetTeamsMatchingFilters(middleware.GetEventID(c), req.Filters)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -96,9 +98,9 @@ func (h *BulkEmailHandler) SendBulkEmail(c *gin.Context) {
 	}
 
 	_, err = h.db.Exec(`
-		INSERT INTO email_logs (id, subject, recipients, html_content, filters, sent_count, created_by, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	`, uuid.New(), req.Subject, recipientsJSON, req.HTMLContent, filtersJSON, len(recipients), adminIDPtr, time.Now())
+		INSERT INTO email_logs (id, subject, recipients, html_content, filters, sent_count, created_by, created_at, event_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9, current_event_id()))
+	`, uuid.New(), req.Subject, recipientsJSON, req.HTMLContent, filtersJSON, len(recipients), adminIDPtr, time.Now(), repository.EventArg(middleware.GetEventID(c)))
 
 	c.JSON(http.StatusOK, gin.H{
 		"message":          "Emails sent successfully",
@@ -169,9 +171,10 @@ func (h *BulkEmailHandler) GetEmailLogs(c *gin.Context) {
 	rows, err := h.db.Query(`
 		SELECT id, subject, sent_count, created_by, created_at
 		FROM email_logs
+		WHERE event_id = COALESCE($1, current_event_id())
 		ORDER BY created_at DESC
 		LIMIT 50
-	`)
+	`, repository.EventArg(middleware.GetEventID(c)))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
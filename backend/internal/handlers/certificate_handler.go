@@ -14,7 +14,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/middleware"
 	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
 )
 
 // certificateEmailSender matches the email service interface
@@ -81,7 +83,7 @@ func (h *CertificateHandler) SendCertificates(c *gin.Context) {
 		SELECT tm.id, tm.name, tm.email, t.id, t.team_name
 		FROM team_members tm
 		JOIN teams t ON t.id = tm.team_id
-		WHERE tm.team_id IN (%s)
+		WHERE tm.team_id IN (%s) AND t.status NOT IN ('withdrawn', 'no_show')
 		ORDER BY t.team_name, tm.role
 	`, placeholders)
 
@@ -125,9 +127,9 @@ func (h *CertificateHandler) SendCertificates(c *gin.Context) {
 	for _, m := range members {
 		var certID string
 		err := h.db.QueryRow(`
-			INSERT INTO certificates (id, participant_name, participant_email, team_id, team_name, cert_type, position, issued_at)
-			VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7)
-			ON CONFLICT (participant_email, cert_type)
+			INSERT INTO certificates (id, participant_name, participant_email, team_id, team_name, cert_type, position, issued_at, event_id)
+			VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, (SELECT event_id FROM teams WHERE id = $3))
+			ON CONFLICT (event_id, participant_email, cert_type)
 			DO UPDATE SET participant_name = EXCLUDED.participant_name,
 			              team_id = EXCLUDED.team_id,
 			              team_name = EXCLUDED.team_name,
@@ -189,15 +191,15 @@ func (h *CertificateHandler) SendManualCertificate(c *gin.Context) {
 
 	var certID string
 	err := h.db.QueryRow(`
-		INSERT INTO certificates (id, participant_name, participant_email, team_name, cert_type, position, issued_at)
-		VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6)
-		ON CONFLICT (participant_email, cert_type)
+		INSERT INTO certificates (id, participant_name, participant_email, team_name, cert_type, position, issued_at, event_id)
+		VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, COALESCE($7, current_event_id()))
+		ON CONFLICT (event_id, participant_email, cert_type)
 		DO UPDATE SET participant_name = EXCLUDED.participant_name,
 		              team_name = EXCLUDED.team_name,
 		              position = EXCLUDED.position,
 		              issued_at = EXCLUDED.issued_at
 		RETURNING id
-	`, req.Name, req.Email, req.TeamName, req.CertType, req.Position, issuedAt).Scan(&certID)
+	`, req.Name, req.Email, req.TeamName, req.CertType, req.Position, issuedAt, repository.EventArg(middleware.GetEventID(c))).Scan(&certID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create certificate: " + err.Error()})
 		return
@@ -236,12 +238,17 @@ func (h *CertificateHandler) VerifyCertificate(c *gin.Context) {
 
 	var cert models.Certificate
 	var issuedAt time.Time
+	var ev certEvent
 	err := h.db.QueryRow(`
-		SELECT id, participant_name, participant_email, team_id, team_name, cert_type, position, issued_at
-		FROM certificates WHERE id = $1
+		SELECT c.id, c.participant_name, c.participant_email, c.team_id, c.team_name, c.cert_type, c.position, c.issued_at,
+		       e.name, e.slug, e.starts_at, e.ends_at
+		FROM certificates c
+		JOIN events e ON e.id = c.event_id
+		WHERE c.id = $1
 	`, certIDStr).Scan(
 		&cert.ID, &cert.ParticipantName, &cert.ParticipantEmail,
 		&cert.TeamID, &cert.TeamName, &cert.CertType, &cert.Position, &issuedAt,
+		&ev.Name, &ev.Slug, &ev.StartsAt, &ev.EndsAt,
 	)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"valid": false, "error": "Certificate not found"})
@@ -257,7 +264,8 @@ func (h *CertificateHandler) VerifyCertificate(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"valid":       true,
 		"certificate": cert,
-		"label":       certTypeFullLabel(cert.CertType, cert.Position),
+		"label":       certTypeFullLabel(cert.CertType, cert.Position, ev.Name),
+		"event":       gin.H{"name": ev.Name, "slug": ev.Slug},
 		"issued_at":   issuedAt.Format("02 January 2006"),
 		"image_url":   fmt.Sprintf("%s/api/v1/certificates/%s/image.jpg", h.apiPublicURL, cert.ID),
 		"verify_url":  fmt.Sprintf("%s/verify/%s", h.frontendURL, cert.ID),
@@ -274,8 +282,14 @@ func (h *CertificateHandler) GetCertificateImageSVG(c *gin.Context) {
 
 	var cert models.Certificate
 	var issuedAt time.Time
-	err := h.db.QueryRow(`SELECT id, participant_name, team_name, cert_type, position, issued_at FROM certificates WHERE id = $1`, certIDStr).
-		Scan(&cert.ID, &cert.ParticipantName, &cert.TeamName, &cert.CertType, &cert.Position, &issuedAt)
+	var ev certEvent
+	err := h.db.QueryRow(`
+		SELECT c.id, c.participant_name, c.team_name, c.cert_type, c.position, c.issued_at, e.name, e.slug, e.starts_at, e.ends_at
+		FROM certificates c
+		JOIN events e ON e.id = c.event_id
+		WHERE c.id = $1
+	`, certIDStr).
+		Scan(&cert.ID, &cert.ParticipantName, &cert.TeamName, &cert.CertType, &cert.Position, &issuedAt, &ev.Name, &ev.Slug, &ev.StartsAt, &ev.EndsAt)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Certificate not found"})
 		return
@@ -291,7 +305,7 @@ func (h *CertificateHandler) GetCertificateImageSVG(c *gin.Context) {
 	}
 
 	svg := generateCertificateSVG(cert.ParticipantName, teamName, cert.CertType,
-		issuedAt.Format("02 January 2006"), cert.ID, cert.Position, h.apiPublicURL)
+		issuedAt.Format("02 January 2006"), cert.ID, cert.Position, h.apiPublicURL, ev)
 
 	c.Header("Content-Type", "image/svg+xml")
 	c.Header("Cache-Control", "no-cache, no-store, must-revalidate")
@@ -310,8 +324,14 @@ func (h *CertificateHandler) GetCertificateImageJPEG(c *gin.Context) {
 
 	var cert models.Certificate
 	var issuedAt time.Time
-	err := h.db.QueryRow(`SELECT id, participant_name, team_name, cert_type, position, issued_at FROM certificates WHERE id = $1`, certIDStr).
-		Scan(&cert.ID, &cert.ParticipantName, &cert.TeamName, &cert.CertType, &cert.Position, &issuedAt)
+	var ev certEvent
+	err := h.db.QueryRow(`
+		SELECT c.id, c.participant_name, c.team_name, c.cert_type, c.position, c.issued_at, e.name, e.slug, e.starts_at, e.ends_at
+		FROM certificates c
+		JOIN events e ON e.id = c.event_id
+		WHERE c.id = $1
+	`, certIDStr).
+		Scan(&cert.ID, &cert.ParticipantName, &cert.TeamName, &cert.CertType, &cert.Position, &issuedAt, &ev.Name, &ev.Slug, &ev.StartsAt, &ev.EndsAt)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Certificate not found"})
 		return
@@ -331,8 +351,8 @@ func (h *CertificateHandler) GetCertificateImageJPEG(c *gin.Context) {
 
 	c.Header("Content-Type", "image/jpeg")
 	c.Header("Cache-Control", "public, max-age=86400")
-	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="RIFT26-Certificate-%s.jpg"`,
-		strings.ReplaceAll(cert.ParticipantName, " ", "-")))
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s-Certificate-%s.jpg"`,
+		strings.ToUpper(ev.Slug), strings.ReplaceAll(cert.ParticipantName, " ", "-")))
 
 	if err := jpeg.Encode(c.Writer, img, &jpeg.Options{Quality: 92}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode image"})
@@ -341,29 +361,76 @@ func (h *CertificateHandler) GetCertificateImageJPEG(c *gin.Context) {
 
 // ── Label helpers ────────────────────────────────────────────────────────────
 
-func certTypeFullLabel(certType string, position *string) string {
+// certEvent is the edition a certificate was issued for (certificates of past events stay verifiable).
+type certEvent struct {
+	Name     string
+	Slug     string
+	StartsAt sql.NullTime
+	EndsAt   sql.NullTime
+}
+
+// dateLabel renders the event dates as e.g. "19th-20th February 2026".
+func (e certEvent) dateLabel() string {
+	if !e.StartsAt.Valid {
+		return ""
+	}
+	loc, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		loc = time.UTC
+	}
+	start := e.StartsAt.Time.In(loc)
+	if !e.EndsAt.Valid {
+		return fmt.Sprintf("%s %s", ordinal(start.Day()), start.Format("January 2006"))
+	}
+	end := e.EndsAt.Time.In(loc)
+	switch {
+	case start.Year() == end.Year() && start.Month() == end.Month() && start.Day() == end.Day():
+		return fmt.Sprintf("%s %s", ordinal(start.Day()), start.Format("January 2006"))
+	case start.Year() == end.Year() && start.Month() == end.Month():
+		return fmt.Sprintf("%s-%s %s", ordinal(start.Day()), ordinal(end.Day()), end.Format("January 2006"))
+	default:
+		return fmt.Sprintf("%s %s - %s %s", ordinal(start.Day()), start.Format("January 2006"), ordinal(end.Day()), end.Format("January 2006"))
+	}
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+func certTypeFullLabel(certType string, position *string, eventName string) string {
 	switch certType {
 	case "winner":
 		if position != nil && *position != "" {
-			return fmt.Sprintf("Winner (%s) – RIFT '26 Hackathon", *position)
+			return fmt.Sprintf("Winner (%s) – %s Hackathon", *position, eventName)
 		}
-		return "Winner – RIFT '26 Hackathon"
+		return fmt.Sprintf("Winner – %s Hackathon", eventName)
 	case "semi_finalist":
-		return "Semi-Finalist – RIFT '26 Hackathon"
+		return fmt.Sprintf("Semi-Finalist – %s Hackathon", eventName)
 	case "volunteer":
-		return "Volunteer – RIFT '26 Hackathon"
+		return fmt.Sprintf("Volunteer – %s Hackathon", eventName)
 	case "hod":
 		if position != nil && *position != "" {
-			return fmt.Sprintf("Head of Department (%s) – RIFT '26", *position)
+			return fmt.Sprintf("Head of Department (%s) – %s", *position, eventName)
 		}
-		return "Head of Department – RIFT '26"
+		return fmt.Sprintf("Head of Department – %s", eventName)
 	case "custom":
 		if position != nil && *position != "" {
-			return fmt.Sprintf("%s – RIFT '26", *position)
+			return fmt.Sprintf("%s – %s", *position, eventName)
 		}
-		return "Certificate – RIFT '26"
+		return fmt.Sprintf("Certificate – %s", eventName)
 	default:
-		return "Participant – RIFT '26 Hackathon"
+		return fmt.Sprintf("Participant – %s Hackathon", eventName)
 	}
 }
 
@@ -435,8 +502,13 @@ func certTypeBadge(certType string, position *string) string {
 
 // ── SVG generation ──────────────────────────────────────────────────────────
 
-func generateCertificateSVG(participantName, teamName, certType, issuedDate, certID string, position *string, apiPublicURL string) string {
+func generateCertificateSVG(participantName, teamName, certType, issuedDate, certID string, position *string, apiPublicURL string, ev certEvent) string {
 	safeName := html.EscapeString(participantName)
+	safeEvent := html.EscapeString(ev.Name)
+	eventLine := safeEvent + " Hackathon"
+	if dates := ev.dateLabel(); dates != "" {
+		eventLine += " held on " + dates
+	}
 	safeTeam := html.EscapeString(teamName)
 
 	safeID := html.EscapeString(certID)
@@ -529,7 +601,7 @@ func generateCertificateSVG(participantName, teamName, certType, issuedDate, cer
 
 
   <!-- Purpose + date -->
-  <text x="600" y="478" font-family="Arial,sans-serif" font-size="17" fill="#aaaaaa" text-anchor="middle">%s RIFT '26 Hackathon held on 19th-20th February 2026.</text>
+  <text x="600" y="478" font-family="Arial,sans-serif" font-size="17" fill="#aaaaaa" text-anchor="middle">%s %s.</text>
 
   <!-- Team name line -->
   %s
@@ -549,12 +621,12 @@ func generateCertificateSVG(participantName, teamName, certType, issuedDate, cer
   <text x="908" y="682" font-family="Arial,sans-serif" font-size="11" font-weight="700" fill="#666666" text-anchor="middle" letter-spacing="2">VP – PHYSICS WALLAH</text>
 
   <!-- Watermark (Tan Buster) -->
-  <text x="600" y="702" font-family="TanBuster,Arial Black,sans-serif" font-size="80" font-weight="bold" fill="#c0211f" opacity="0.05" text-anchor="middle" letter-spacing="6">RIFT '26</text>
+  <text x="600" y="702" font-family="TanBuster,Arial Black,sans-serif" font-size="80" font-weight="bold" fill="#c0211f" opacity="0.05" text-anchor="middle" letter-spacing="6">%s</text>
 
   <!-- Seal -->
   <circle cx="600" cy="664" r="48" fill="none" stroke="#c0211f" stroke-width="1.2" opacity="0.25"/>
   <circle cx="600" cy="664" r="40" fill="none" stroke="#c0211f" stroke-width="0.6" opacity="0.15"/>
-  <text x="600" y="661" font-family="TanBuster,Arial,sans-serif" font-size="9" font-weight="bold" fill="#c0211f" opacity="0.4" text-anchor="middle" letter-spacing="1.5">RIFT '26</text>
+  <text x="600" y="661" font-family="TanBuster,Arial,sans-serif" font-size="9" font-weight="bold" fill="#c0211f" opacity="0.4" text-anchor="middle" letter-spacing="1.5">%s</text>
   <text x="600" y="673" font-family="Arial,sans-serif" font-size="7" fill="#c0211f" opacity="0.4" text-anchor="middle" letter-spacing="1">HACKATHON</text>
 
   <!-- Bottom bar -->
@@ -565,9 +637,10 @@ func generateCertificateSVG(participantName, teamName, certType, issuedDate, cer
 </svg>`,
 		brittanyDataURI, tanBusterDataURI, pwioiLogoDataURI,
 		subtitle, safeName,
-		purpose,
+		purpose, eventLine,
 		teamLine,
 		badgeX, badgeW, badge,
+		safeEvent, safeEvent,
 		safeID,
 	)
 }
@@ -1,10 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/middleware"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
 	"github.com/rift26/backend/internal/services"
 )
 
@@ -40,7 +45,7 @@ func (h *CheckPSHandler) GetSemiFinalists(c *gin.Context) {
 	if city != "" {
 		cityPtr = &city
 	}
-	list, err := h.psSelectionService.GetSemiFinalistsWithDetails(c.Request.Context(), cityPtr)
+	list, err := h.psSelectionService.GetSemiFinalistsWithDetails(c.Request.Context(), middleware.GetEventID(c), cityPtr)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -56,10 +61,13 @@ func (h *CheckPSHandler) MarkSemiFinalist(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
 		return
 	}
+	middleware.SetAuditAction(c, "semi_finalist.mark", "team", teamID.String())
+	before := h.auditSelection(c, teamID)
 	if err := h.psSelectionService.SetSemiFinalist(c.Request.Context(), teamID, true); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	middleware.SetAuditChange(c, before, h.auditSelection(c, teamID))
 	c.JSON(http.StatusOK, gin.H{"message": "Team marked as semi-finalist"})
 }
 
@@ -71,10 +79,13 @@ func (h *CheckPSHandler) UnmarkSemiFinalist(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
 		return
 	}
+	middleware.SetAuditAction(c, "semi_finalist.unmark", "team", teamID.String())
+	before := h.auditSelection(c, teamID)
 	if err := h.psSelectionService.SetSemiFinalist(c.Request.Context(), teamID, false); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	middleware.SetAuditChange(c, before, h.auditSelection(c, teamID))
 	c.JSON(http.StatusOK, gin.H{"message": "Team removed from semi-finalists"})
 }
 
@@ -94,9 +105,80 @@ func (h *CheckPSHandler) SetAwards(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
+	middleware.SetAuditAction(c, "awards.set", "team", teamID.String())
+	before := h.auditSelection(c, teamID)
 	if err := h.psSelectionService.SetAwards(c.Request.Context(), teamID, req.Position, req.BestWeb3); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	middleware.SetAuditChange(c, before, h.auditSelection(c, teamID))
 	c.JSON(http.StatusOK, gin.H{"message": "Awards updated"})
 }
+
+// ReassignPS sets a team's problem statement as an admin override, ignoring the lock window and
+// switch limit (and capacity with force). Recorded in the selection history with the reason (admin only).
+// POST /api/v1/admin/teams/:team_id/ps-selection
+func (h *CheckPSHandler) ReassignPS(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("team_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	var req models.ReassignPSRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	psID, err := uuid.Parse(req.ProblemStatementID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid problem statement ID"})
+		return
+	}
+	middleware.SetAuditAction(c, "ps_selection.reassign", "team", teamID.String())
+	var before gin.H
+	if sel, err := h.psSelectionService.GetByTeamID(c.Request.Context(), teamID); err == nil && sel != nil {
+		before = gin.H{"problem_statement_id": sel.ProblemStatementID}
+	}
+	err = h.psSelectionService.Reassign(c.Request.Context(), teamID, psID, c.GetString("user_email"), req.Reason, req.Force)
+	if err != nil {
+		if errors.Is(err, repository.ErrPSFull) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "full": true})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	middleware.SetAuditChange(c, before, gin.H{"problem_statement_id": psID, "reason": req.Reason, "force": req.Force})
+	c.JSON(http.StatusOK, gin.H{"message": "Problem statement reassigned"})
+}
+
+// GetPSHistory returns who changed which team's problem statement and when, newest first
+// (admin only, optional ?team_id= and ?limit=, default 200).
+// GET /api/v1/admin/ps-selections/history
+func (h *CheckPSHandler) GetPSHistory(c *gin.Context) {
+	teamID := uuid.Nil
+	if v := c.Query("team_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+			return
+		}
+		teamID = id
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	history, err := h.psSelectionService.History(c.Request.Context(), middleware.GetEventID(c), teamID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"history": history, "count": len(history)})
+}
+
+// auditSelection snapshots the award fields of a team's PS selection for the audit log
+func (h *CheckPSHandler) auditSelection(c *gin.Context, teamID uuid.UUID) gin.H {
+	sel, err := h.psSelectionService.GetByTeamID(c.Request.Context(), teamID)
+	if err != nil || sel == nil {
+		return nil
+	}
+	return gin.H{"is_semi_finalist": sel.IsSemiFinalist, "position": sel.Position, "best_web3": sel.BestWeb3}
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/services"
+)
+
+type CityHandler struct {
+	cityService *services.CityService
+}
+
+func NewCityHandler(cityService *services.CityService) *CityHandler {
+	return &CityHandler{cityService: cityService}
+}
+
+// ListActiveCities returns active cities with their venue info (public).
+// GET /api/v1/cities
+func (h *CityHandler) ListActiveCities(c *gin.Context) {
+	cities, err := h.cityService.Active(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"cities": cities})
+}
+
+// ListCities returns every city including inactive ones (admin).
+// GET /api/v1/admin/cities
+func (h *CityHandler) ListCities(c *gin.Context) {
+	cities, err := h.cityService.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"cities": cities})
+}
+
+// CreateCity adds a new city (admin).
+// POST /api/v1/admin/cities
+func (h *CityHandler) CreateCity(c *gin.Context) {
+	var req models.CreateCityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	city, err := h.cityService.Create(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, city)
+}
+
+// UpdateCity updates name, aliases, timezone, venue info and flags of a city (admin).
+// PUT /api/v1/admin/cities/:code
+func (h *CityHandler) UpdateCity(c *gin.Context) {
+	var req models.UpdateCityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	city, err := h.cityService.Update(c.Request.Context(), c.Param("code"), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, city)
+}
+
+// DeleteCity removes a city that no team or venue references (admin).
+// DELETE /api/v1/admin/cities/:code
+func (h *CityHandler) DeleteCity(c *gin.Context) {
+	if err := h.cityService.Delete(c.Request.Context(), c.Param("code")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "City deleted"})
+}
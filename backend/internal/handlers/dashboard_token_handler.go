@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/middleware"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/services"
+)
+
+// DashboardTokenHandler lets team leaders (signed in by OTP or magic link) and admins manage a
+// team's dashboard links. Leader routes use :id, admin routes :team_id.
+type DashboardTokenHandler struct {
+	dashboardTokenService *services.DashboardTokenService
+}
+
+func NewDashboardTokenHandler(dashboardTokenService *services.DashboardTokenService) *DashboardTokenHandler {
+	return &DashboardTokenHandler{dashboardTokenService: dashboardTokenService}
+}
+
+func (h *DashboardTokenHandler) teamID(c *gin.Context) (uuid.UUID, bool) {
+	param := c.Param("team_id")
+	if param == "" {
+		param = c.Param("id")
+	}
+	id, err := uuid.Parse(param)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *DashboardTokenHandler) tokenID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("token_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dashboard token ID"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// ListDashboardTokens returns the team's dashboard links with expiry and last use (leader or admin).
+// GET /api/v1/teams/:id/dashboard-tokens
+// GET /api/v1/admin/teams/:team_id/dashboard-tokens
+func (h *DashboardTokenHandler) ListDashboardTokens(c *gin.Context) {
+	teamID, ok := h.teamID(c)
+	if !ok {
+		return
+	}
+	tokens, err := h.dashboardTokenService.List(c.Request.Context(), teamID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load dashboard links"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"dashboard_tokens": tokens, "count": len(tokens)})
+}
+
+// IssueDashboardToken generates a new dashboard link and revokes the team's previous link with the
+// same access. access is "full" (default) or "read_only"; the link is only returned here (leader or admin).
+// POST /api/v1/teams/:id/dashboard-tokens
+// POST /api/v1/admin/teams/:team_id/dashboard-tokens
+func (h *DashboardTokenHandler) IssueDashboardToken(c *gin.Context) {
+	teamID, ok := h.teamID(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Access models.DashboardAccess `json:"access"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Access == "" {
+		req.Access = models.DashboardAccessFull
+	}
+
+	createdBy := "leader:" + c.GetString("user_email")
+	if role, _ := middleware.GetRole(c); role == models.UserRoleAdmin {
+		createdBy = "admin:" + c.GetString("user_email")
+	}
+	issued, err := h.dashboardTokenService.Issue(c.Request.Context(), teamID, req.Access, createdBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	middleware.SetAuditAction(c, "dashboard_token.issue", "team", teamID.String())
+	middleware.SetAuditChange(c, nil, issued.DashboardToken)
+	c.JSON(http.StatusCreated, issued)
+}
+
+// RevokeDashboardToken disables one of the team's dashboard links immediately (leader or admin).
+// DELETE /api/v1/teams/:id/dashboard-tokens/:token_id
+// DELETE /api/v1/admin/teams/:team_id/dashboard-tokens/:token_id
+func (h *DashboardTokenHandler) RevokeDashboardToken(c *gin.Context) {
+	teamID, ok := h.teamID(c)
+	if !ok {
+		return
+	}
+	tokenID, ok := h.tokenID(c)
+	if !ok {
+		return
+	}
+	middleware.SetAuditAction(c, "dashboard_token.revoke", "team", teamID.String())
+	revoked, err := h.dashboardTokenService.Revoke(c.Request.Context(), teamID, tokenID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke dashboard link"})
+		return
+	}
+	if !revoked {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dashboard link not found or already revoked"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Dashboard link revoked"})
+}
+
+// GetDashboardAccessLogs returns recent requests made with one of the team's dashboard links
+// (leader or admin, optional ?limit=, default 100).
+// GET /api/v1/teams/:id/dashboard-tokens/:token_id/access-logs
+// GET /api/v1/admin/teams/:team_id/dashboard-tokens/:token_id/access-logs
+func (h *DashboardTokenHandler) GetDashboardAccessLogs(c *gin.Context) {
+	teamID, ok := h.teamID(c)
+	if !ok {
+		return
+	}
+	tokenID, ok := h.tokenID(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	logs, err := h.dashboardTokenService.AccessLogs(c.Request.Context(), teamID, tokenID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load access logs"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"access_logs": logs, "count": len(logs)})
+}
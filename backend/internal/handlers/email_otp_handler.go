@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/rift26/backend/internal/services"
@@ -84,11 +86,55 @@ func (h *EmailOTPHandler) VerifyEmailOTP(c *gin.Context) {
 	}
 
 	// Verify OTP and get response
-	response, err := h.emailOTPService.VerifyOTP(c.Request.Context(), teamID, req.Email, req.OTPCode)
+	response, err := h.emailOTPService.VerifyOTP(c.Request.Context(), teamID, req.Email, req.OTPCode, sessionClient(c))
 	if err != nil {
+		if respondOTPLocked(c, err) {
+			return
+		}
 		c.JSON(401, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(200, response)
 }
+
+// respondOTPLocked writes a 429 if err is an OTP lockout and reports whether it did
+func respondOTPLocked(c *gin.Context, err error) bool {
+	var locked *services.OTPLockedError
+	if !errors.As(err, &locked) {
+		return false
+	}
+	c.JSON(429, gin.H{"error": err.Error(), "locked_until": locked.Until})
+	return true
+}
+
+// ListOTPLockouts lists teams currently locked out of OTP verification (admin)
+// GET /api/v1/admin/otp-lockouts
+func (h *EmailOTPHandler) ListOTPLockouts(c *gin.Context) {
+	lockouts, err := h.emailOTPService.ListLockouts(c.Request.Context())
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load OTP lockouts"})
+		return
+	}
+	c.JSON(200, gin.H{"lockouts": lockouts, "count": len(lockouts)})
+}
+
+// ClearOTPLockout lifts a team's OTP lockout and resets its failed attempts (admin)
+// DELETE /api/v1/admin/otp-lockouts/:team_id
+func (h *EmailOTPHandler) ClearOTPLockout(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("team_id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	cleared, err := h.emailOTPService.ClearLockout(c.Request.Context(), teamID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to clear OTP lockout"})
+		return
+	}
+	if !cleared {
+		c.JSON(404, gin.H{"error": "Team has no OTP lockout"})
+		return
+	}
+	c.JSON(200, gin.H{"message": "OTP lockout cleared"})
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/services"
+)
+
+type EventHandler struct {
+	eventService *services.EventService
+}
+
+func NewEventHandler(eventService *services.EventService) *EventHandler {
+	return &EventHandler{eventService: eventService}
+}
+
+// GetCurrent returns the current event (public).
+// GET /api/v1/event
+func (h *EventHandler) GetCurrent(c *gin.Context) {
+	event, err := h.eventService.Current(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
+
+// ListEvents returns every edition, newest first (admin).
+// GET /api/v1/admin/events
+func (h *EventHandler) ListEvents(c *gin.Context) {
+	events, err := h.eventService.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"events": events})
+}
+
+// GetEvent returns a single event (admin).
+// GET /api/v1/admin/events/:id
+func (h *EventHandler) GetEvent(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+	event, err := h.eventService.Get(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if event == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
+
+// CreateEvent creates a new edition; it becomes current only once activated (admin).
+// POST /api/v1/admin/events
+func (h *EventHandler) CreateEvent(c *gin.Context) {
+	var req models.CreateEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	event, err := h.eventService.Create(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, event)
+}
+
+// UpdateEvent updates the name and dates of an event (admin).
+// PUT /api/v1/admin/events/:id
+func (h *EventHandler) UpdateEvent(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+	var req models.UpdateEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	event, err := h.eventService.Update(c.Request.Context(), id, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
+
+// ActivateEvent makes an event the current one (admin).
+// POST /api/v1/admin/events/:id/activate
+func (h *EventHandler) ActivateEvent(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+	if err := h.eventService.Activate(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Event activated"})
+}
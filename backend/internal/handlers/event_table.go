@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/middleware"
 	"github.com/rift26/backend/internal/models"
 	"github.com/rift26/backend/internal/services"
 )
@@ -69,7 +70,7 @@ func (h *EventTableHandler) GetAllEventTables(c *gin.Context) {
 		isActivePtr = &isActive
 	}
 
-	tables, err := h.service.GetAllEventTables(cityPtr, isActivePtr)
+	tables, err := h.service.GetAllEventTables(middleware.GetEventID(c), cityPtr, isActivePtr)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rift26/backend/pkg/storage"
+)
+
+// FileHandler serves signed download URLs of the local storage backend; S3 serves its own.
+type FileHandler struct {
+	store storage.Store
+}
+
+func NewFileHandler(store storage.Store) *FileHandler {
+	return &FileHandler{store: store}
+}
+
+// ServeSigned streams a stored file when the URL's signature is valid and unexpired (public).
+// GET /api/v1/files/*key?expires=&signature=
+func (h *FileHandler) ServeSigned(c *gin.Context) {
+	local, ok := h.store.(*storage.Local)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	d, err := local.VerifySignedURL(key, c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	body, obj, err := local.Open(c.Request.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer body.Close()
+	if d.Filename == "" {
+		d.Filename = path.Base(key)
+	}
+	if d.ContentType == "" {
+		d.ContentType = obj.ContentType
+	}
+	c.DataFromReader(http.StatusOK, obj.Size, d.ContentType, body, map[string]string{
+		"Content-Disposition":    storage.ContentDisposition(d.Filename),
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, no-store",
+	})
+}
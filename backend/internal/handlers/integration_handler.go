@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/repository"
+	"github.com/rift26/backend/internal/services"
+)
+
+// IntegrationHandler serves read-only data to API-key clients (display screens, bots).
+// Everything is for the current event; city-restricted keys only see their city.
+type IntegrationHandler struct {
+	teamRepo              *repository.TeamRepository
+	announcementService   *services.AnnouncementService
+	seatAllocationService *services.SeatAllocationService
+	cityService           *services.CityService
+}
+
+func NewIntegrationHandler(teamRepo *repository.TeamRepository, announcementService *services.AnnouncementService, seatAllocationService *services.SeatAllocationService, cityService *services.CityService) *IntegrationHandler {
+	return &IntegrationHandler{
+		teamRepo:              teamRepo,
+		announcementService:   announcementService,
+		seatAllocationService: seatAllocationService,
+		cityService:           cityService,
+	}
+}
+
+// city returns the key's city, else the optional ?city= (as a city code). When ok is false the
+// error response (unknown city or failed lookup) has been written.
+func (h *IntegrationHandler) city(c *gin.Context) (string, bool) {
+	if city := c.GetString("city"); city != "" {
+		return city, true
+	}
+	q := c.Query("city")
+	if q == "" {
+		return "", true
+	}
+	code, ok, err := h.cityService.Normalize(c.Request.Context(), q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve city"})
+		return "", false
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown city"})
+		return "", false
+	}
+	return code, true
+}
+
+// GetStats returns team, RSVP and check-in counts (scope stats:read, optional ?city=).
+// GET /api/v1/integrations/stats
+func (h *IntegrationHandler) GetStats(c *gin.Context) {
+	city, ok := h.city(c)
+	if !ok {
+		return
+	}
+	var stats map[string]interface{}
+	var err error
+	if city != "" {
+		stats, err = h.teamRepo.GetCityCheckInStats(c.Request.Context(), uuid.Nil, city)
+	} else {
+		stats, err = h.teamRepo.GetCheckInStats(c.Request.Context(), uuid.Nil)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stats"})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetAnnouncements returns active announcements shown to all teams (scope announcements:read, optional ?city=).
+// GET /api/v1/integrations/announcements
+func (h *IntegrationHandler) GetAnnouncements(c *gin.Context) {
+	city, ok := h.city(c)
+	if !ok {
+		return
+	}
+	var cityNames []string
+	if city != "" {
+		cityNames = h.cityService.Variations(city)
+	}
+	announcements, err := h.announcementService.GetBroadcastAnnouncements(cityNames)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch announcements"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"announcements": announcements, "count": len(announcements)})
+}
+
+// GetRooms returns room capacity and live occupancy (scope rooms:read, optional ?city=).
+// GET /api/v1/integrations/rooms
+func (h *IntegrationHandler) GetRooms(c *gin.Context) {
+	city, ok := h.city(c)
+	if !ok {
+		return
+	}
+	rooms, err := h.seatAllocationService.GetRoomOccupancy(city)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rooms"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"rooms": rooms, "count": len(rooms)})
+}
@@ -0,0 +1,365 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/middleware"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+	"github.com/rift26/backend/internal/services"
+)
+
+type JudgeHandler struct {
+	judgeService *services.JudgeService
+	submissions  *services.PSSubmissionService
+	judging      *services.JudgingService
+}
+
+func NewJudgeHandler(judgeService *services.JudgeService, submissions *services.PSSubmissionService, judging *services.JudgingService) *JudgeHandler {
+	return &JudgeHandler{judgeService: judgeService, submissions: submissions, judging: judging}
+}
+
+func judgeSubmission(row repository.JudgeSubmissionRow) models.JudgeSubmission {
+	sub := row.JudgeSubmission
+	sub.CustomFields = parseCustomFields(row.CustomFieldsJSON)
+	return sub
+}
+
+// judgeFieldLabels is customFieldLabels without the fields judges must not see.
+func judgeFieldLabels(psFields []sql.NullString) map[string]string {
+	labels := customFieldLabels(psFields)
+	for _, raw := range psFields {
+		for key := range services.JudgeHiddenFields(raw) {
+			delete(labels, key)
+		}
+	}
+	return labels
+}
+
+// judgeSubmissions converts rows for the judge API, with attachment links under /judge/submissions.
+func (h *JudgeHandler) judgeSubmissions(ctx context.Context, rows []repository.JudgeSubmissionRow) ([]models.JudgeSubmission, error) {
+	files := make([]services.SubmissionFiles, 0, len(rows))
+	for _, row := range rows {
+		files = append(files, services.SubmissionFiles{SubmissionID: row.SubmissionID, PSFields: row.PSFieldsJSON, CustomFields: row.CustomFieldsJSON})
+	}
+	attachments, err := h.submissions.ListAttachments(ctx, files, "/api/v1/judge/submissions/")
+	if err != nil {
+		return nil, err
+	}
+	subs := make([]models.JudgeSubmission, 0, len(rows))
+	for _, row := range rows {
+		sub := judgeSubmission(row)
+		sub.Attachments = attachments[sub.SubmissionID]
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+// Login authenticates a judge (public).
+// POST /api/v1/judge/login
+func (h *JudgeHandler) Login(c *gin.Context) {
+	var req models.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	tokens, user, err := h.judgeService.Login(c.Request.Context(), req.Email, req.Password, sessionClient(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"token":         tokens.Token,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"user": gin.H{
+			"id":    user.ID,
+			"email": user.Email,
+			"name":  user.Name,
+			"role":  user.Role,
+		},
+	})
+}
+
+// GetSubmissions returns the submissions assigned to the judge, without participant PII (judge).
+// GET /api/v1/judge/submissions?problem_statement_id=uuid
+func (h *JudgeHandler) GetSubmissions(c *gin.Context) {
+	judgeID, _ := middleware.GetUserID(c)
+	var psID *uuid.UUID
+	if v := c.Query("problem_statement_id"); v != "" {
+		parsed, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid problem statement ID"})
+			return
+		}
+		psID = &parsed
+	}
+	rows, err := h.judgeService.ListSubmissions(c.Request.Context(), judgeID, uuid.Nil, psID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	subs, err := h.judgeSubmissions(c.Request.Context(), rows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	psFields := make([]sql.NullString, 0, len(rows))
+	for _, row := range rows {
+		psFields = append(psFields, row.PSFieldsJSON)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"count":        len(subs),
+		"submissions":  subs,
+		"field_labels": judgeFieldLabels(psFields),
+	})
+}
+
+// GetSubmission returns one assigned submission with the judge's note, score sheets and any late edits (judge).
+// GET /api/v1/judge/submissions/:id
+func (h *JudgeHandler) GetSubmission(c *gin.Context) {
+	judgeID, _ := middleware.GetUserID(c)
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return
+	}
+	row, err := h.judgeService.GetSubmission(c.Request.Context(), judgeID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if row == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+		return
+	}
+	subs, err := h.judgeSubmissions(c.Request.Context(), []repository.JudgeSubmissionRow{*row})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	scores, err := h.judging.ListJudgeScores(c.Request.Context(), judgeID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"submission":   subs[0],
+		"field_labels": judgeFieldLabels([]sql.NullString{row.PSFieldsJSON}),
+		"scores":       scores,
+	})
+}
+
+// GetAttachment downloads a file of an assigned submission (judge).
+// GET /api/v1/judge/submissions/:id/attachments/:attachment_id
+func (h *JudgeHandler) GetAttachment(c *gin.Context) {
+	judgeID, _ := middleware.GetUserID(c)
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return
+	}
+	attachmentID, err := uuid.Parse(c.Param("attachment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return
+	}
+	row, err := h.judgeService.GetSubmission(c.Request.Context(), judgeID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if row == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+		return
+	}
+	a, err := h.submissions.SubmissionAttachment(c.Request.Context(), id, attachmentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load attachment"})
+		return
+	}
+	// Files of private fields are for admins only
+	if a != nil && services.JudgeHiddenFields(row.PSFieldsJSON)[a.FieldKey] {
+		a = nil
+	}
+	serveAttachment(c, h.submissions, a)
+}
+
+// SaveNote creates or replaces the judge's note on an assigned submission (judge).
+// PUT /api/v1/judge/submissions/:id/note
+func (h *JudgeHandler) SaveNote(c *gin.Context) {
+	judgeID, _ := middleware.GetUserID(c)
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return
+	}
+	var req models.JudgeNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	note, err := h.judgeService.SaveNote(c.Request.Context(), judgeID, id, req.Note)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, note)
+}
+
+// GetRounds returns the event's judging rounds with the rubric of each track (judge).
+// GET /api/v1/judge/rounds
+func (h *JudgeHandler) GetRounds(c *gin.Context) {
+	rounds, err := h.judging.ListRounds(c.Request.Context(), uuid.Nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"rounds": rounds})
+}
+
+// SaveScore creates or replaces the judge's score sheet for an assigned submission in an open
+// round (judge). Every criterion of the rubric for the submission's track needs a score.
+// PUT /api/v1/judge/submissions/:id/score
+func (h *JudgeHandler) SaveScore(c *gin.Context) {
+	judgeID, _ := middleware.GetUserID(c)
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return
+	}
+	var req models.JudgeScoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	score, err := h.judging.SaveScore(c.Request.Context(), judgeID, id, req)
+	if err != nil {
+		respondJudgingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, score)
+}
+
+// ListJudges returns judge accounts with their assignment counts (admin).
+// GET /api/v1/admin/judges
+func (h *JudgeHandler) ListJudges(c *gin.Context) {
+	judges, err := h.judgeService.ListJudges(c.Request.Context(), middleware.GetEventID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"judges": judges})
+}
+
+// CreateJudge adds a judge account (admin).
+// POST /api/v1/admin/judges
+func (h *JudgeHandler) CreateJudge(c *gin.Context) {
+	var req models.CreateJudgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	user, err := h.judgeService.CreateJudge(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, user)
+}
+
+// DeleteJudge removes a judge with their assignments and notes (admin).
+// DELETE /api/v1/admin/judges/:id
+func (h *JudgeHandler) DeleteJudge(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid judge ID"})
+		return
+	}
+	if err := h.judgeService.DeleteJudge(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Judge deleted"})
+}
+
+// ListAssignments returns a judge's tracks and submissions (admin).
+// GET /api/v1/admin/judges/:id/assignments
+func (h *JudgeHandler) ListAssignments(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid judge ID"})
+		return
+	}
+	list, err := h.judgeService.ListAssignments(c.Request.Context(), id, middleware.GetEventID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"assignments": list})
+}
+
+// CreateAssignment assigns a track or a single submission to a judge (admin).
+// POST /api/v1/admin/judges/:id/assignments
+func (h *JudgeHandler) CreateAssignment(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid judge ID"})
+		return
+	}
+	var req models.CreateJudgeAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var createdBy *uuid.UUID
+	if adminID, ok := middleware.GetUserID(c); ok {
+		createdBy = &adminID
+	}
+	a, err := h.judgeService.Assign(c.Request.Context(), id, middleware.GetEventID(c), req, createdBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, a)
+}
+
+// DeleteAssignment removes one of a judge's assignments (admin).
+// DELETE /api/v1/admin/judges/:id/assignments/:assignment_id
+func (h *JudgeHandler) DeleteAssignment(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid judge ID"})
+		return
+	}
+	assignmentID, err := uuid.Parse(c.Param("assignment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+	if err := h.judgeService.Unassign(c.Request.Context(), id, assignmentID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Assignment removed"})
+}
+
+// ListNotes returns every judge's notes on a submission (admin).
+// GET /api/v1/admin/judging/submissions/:id/notes
+func (h *JudgeHandler) ListNotes(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return
+	}
+	notes, err := h.judgeService.ListNotes(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"notes": notes})
+}
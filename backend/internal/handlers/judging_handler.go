@@ -1,25 +1,31 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/middleware"
+	"github.com/rift26/backend/internal/models"
 	"github.com/rift26/backend/internal/repository"
+	"github.com/rift26/backend/internal/services"
 )
 
 type JudgingHandler struct {
-	subRepo *repository.PSSubmissionRepository
+	subRepo     *repository.PSSubmissionRepository
+	submissions *services.PSSubmissionService
 }
 
-func NewJudgingHandler(subRepo *repository.PSSubmissionRepository) *JudgingHandler {
-	return &JudgingHandler{subRepo: subRepo}
+func NewJudgingHandler(subRepo *repository.PSSubmissionRepository, submissions *services.PSSubmissionService) *JudgingHandler {
+	return &JudgingHandler{subRepo: subRepo, submissions: submissions}
 }
 
 // JudgingRowResponse is the API response row (custom_fields as map).
 type JudgingRowResponse struct {
+	SubmissionID       string            `json:"submission_id"`
 	TeamID             string            `json:"team_id"`
 	TeamName           string            `json:"team_name"`
 	City               string            `json:"city"`
@@ -35,10 +41,16 @@ type JudgingRowResponse struct {
 	ExtraNotes         string            `json:"extra_notes"`
 	CustomFields       map[string]string  `json:"custom_fields,omitempty"`
 	SubmittedAt        string            `json:"submitted_at"`
+	Version            int               `json:"version"`
+	IsLate             bool              `json:"is_late"`
+	LatestVersion      int               `json:"latest_version"`
+	LateEdits          int               `json:"late_edits"`
+	Attachments        []models.SubmissionAttachment `json:"attachments,omitempty"`
 }
 
-// GetSubmissions returns all submitted projects with optional city and PS filters.
-// GET /api/v1/judging/submissions?city=BLR&problem_statement_id=uuid
+// GetSubmissions returns all submitted projects, including leader contact details, with optional
+// city and PS filters (admin). Each row shows the final on-time revision and counts later late edits. Judges use the assignment-scoped /judge/submissions instead.
+// GET /api/v1/admin/judging/submissions?city=BLR&problem_statement_id=uuid
 func (h *JudgingHandler) GetSubmissions(c *gin.Context) {
 	var city *string
 	if v := c.Query("city"); v != "" {
@@ -50,15 +62,26 @@ func (h *JudgingHandler) GetSubmissions(c *gin.Context) {
 			psID = &parsed
 		}
 	}
-	list, err := h.subRepo.GetAllForJudging(c.Request.Context(), city, psID)
+	list, err := h.subRepo.GetAllForJudging(c.Request.Context(), middleware.GetEventID(c), city, psID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	files := make([]services.SubmissionFiles, 0, len(list))
+	for _, row := range list {
+		files = append(files, services.SubmissionFiles{SubmissionID: row.SubmissionID, PSFields: row.PSFieldsJSON, CustomFields: row.CustomFieldsJSON})
+	}
+	attachments, err := h.submissions.ListAttachments(c.Request.Context(), files, "/api/v1/admin/judging/submissions/")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	// Convert to response with parsed custom_fields
 	resp := make([]JudgingRowResponse, 0, len(list))
+	psFields := make([]sql.NullString, 0, len(list))
 	for _, row := range list {
 		r := JudgingRowResponse{
+			SubmissionID:       row.SubmissionID.String(),
 			TeamID:             row.TeamID.String(),
 			TeamName:           row.TeamName,
 			LeaderName:         row.LeaderName,
@@ -71,39 +94,62 @@ func (h *JudgingHandler) GetSubmissions(c *gin.Context) {
 			GithubURL:          row.GithubURL,
 			LiveURL:            row.LiveURL,
 			ExtraNotes:         row.ExtraNotes,
+			CustomFields:       parseCustomFields(row.CustomFieldsJSON),
 			SubmittedAt:        row.SubmittedAt,
+			Version:            row.Version,
+			IsLate:             row.IsLate,
+			LatestVersion:      row.LatestVersion,
+			LateEdits:          row.LateEdits,
+			Attachments:        attachments[row.SubmissionID],
 		}
 		if row.City != nil {
 			r.City = *row.City
 		}
-		if row.CustomFieldsJSON.Valid && row.CustomFieldsJSON.String != "" {
-			var raw map[string]interface{}
-			if json.Unmarshal([]byte(row.CustomFieldsJSON.String), &raw) == nil {
-				r.CustomFields = make(map[string]string)
-				for k, v := range raw {
-					if v == nil {
-						r.CustomFields[k] = ""
-					} else if s, ok := v.(string); ok {
-						r.CustomFields[k] = s
-					} else {
-						r.CustomFields[k] = fmt.Sprint(v)
-					}
-				}
-			}
-		}
 		resp = append(resp, r)
+		psFields = append(psFields, row.PSFieldsJSON)
 	}
-	// Build custom field labels map from all PS submission_fields configs
+
+	c.JSON(http.StatusOK, gin.H{
+		"count":          len(resp),
+		"submissions":    resp,
+		"field_labels":   customFieldLabels(psFields), // Map of custom field key -> label
+	})
+}
+
+// parseCustomFields flattens a submission's custom_fields JSON into strings.
+func parseCustomFields(raw sql.NullString) map[string]string {
+	if !raw.Valid || raw.String == "" {
+		return nil
+	}
+	var values map[string]interface{}
+	if json.Unmarshal([]byte(raw.String), &values) != nil {
+		return nil
+	}
+	fields := make(map[string]string)
+	for k, v := range values {
+		if v == nil {
+			fields[k] = ""
+		} else if s, ok := v.(string); ok {
+			fields[k] = s
+		} else {
+			fields[k] = fmt.Sprint(v)
+		}
+	}
+	return fields
+}
+
+// customFieldLabels builds the custom field key -> label map from PS submission_fields configs.
+func customFieldLabels(psFields []sql.NullString) map[string]string {
 	fieldLabels := make(map[string]string)
-	for _, row := range list {
-		if row.PSFieldsJSON.Valid && row.PSFieldsJSON.String != "" {
+	for _, raw := range psFields {
+		if raw.Valid && raw.String != "" {
 			var psConfig struct {
 				CustomFields []struct {
 					Key   string `json:"key"`
 					Label string `json:"label"`
 				} `json:"custom_fields"`
 			}
-			if json.Unmarshal([]byte(row.PSFieldsJSON.String), &psConfig) == nil {
+			if json.Unmarshal([]byte(raw.String), &psConfig) == nil {
 				for _, cf := range psConfig.CustomFields {
 					if _, exists := fieldLabels[cf.Key]; !exists {
 						fieldLabels[cf.Key] = cf.Label
@@ -112,10 +158,5 @@ func (h *JudgingHandler) GetSubmissions(c *gin.Context) {
 			}
 		}
 	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"count":          len(resp),
-		"submissions":    resp,
-		"field_labels":   fieldLabels, // Map of custom field key -> label
-	})
+	return fieldLabels
 }
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/middleware"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+	"github.com/rift26/backend/internal/services"
+)
+
+// JudgingRoundHandler manages rubric judging rounds and their leaderboards (admin).
+type JudgingRoundHandler struct {
+	judging *services.JudgingService
+}
+
+func NewJudgingRoundHandler(judging *services.JudgingService) *JudgingRoundHandler {
+	return &JudgingRoundHandler{judging: judging}
+}
+
+// respondJudgingError maps judging errors: missing round or submission 404, finalized round 409,
+// invalid scores 400 with an error per criterion.
+func respondJudgingError(c *gin.Context, err error) {
+	var notFound *services.JudgingNotFoundError
+	var invalid *services.ScoreValidationError
+	switch {
+	case errors.As(err, &notFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrRoundFinalized):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.As(err, &invalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Please fix the highlighted scores", "criteria_errors": invalid.Criteria})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+func roundID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid round ID"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// ListRounds returns the event's judging rounds with their rubrics (admin).
+// GET /api/v1/admin/judging/rounds
+func (h *JudgingRoundHandler) ListRounds(c *gin.Context) {
+	rounds, err := h.judging.ListRounds(c.Request.Context(), middleware.GetEventID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"rounds": rounds})
+}
+
+// CreateRound adds a judging round (admin).
+// POST /api/v1/admin/judging/rounds
+func (h *JudgingRoundHandler) CreateRound(c *gin.Context) {
+	var req models.CreateJudgingRoundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var createdBy *uuid.UUID
+	if adminID, ok := middleware.GetUserID(c); ok {
+		createdBy = &adminID
+	}
+	round, err := h.judging.CreateRound(c.Request.Context(), middleware.GetEventID(c), req, createdBy)
+	if err != nil {
+		respondJudgingError(c, err)
+		return
+	}
+	middleware.SetAuditAction(c, "judging_round.create", "judging_round", round.ID.String())
+	c.JSON(http.StatusCreated, round)
+}
+
+// UpdateRound renames an open round or sets whether its leaderboard normalizes by default (admin).
+// PUT /api/v1/admin/judging/rounds/:id
+func (h *JudgingRoundHandler) UpdateRound(c *gin.Context) {
+	id, ok := roundID(c)
+	if !ok {
+		return
+	}
+	var req models.UpdateJudgingRoundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	middleware.SetAuditAction(c, "judging_round.update", "judging_round", id.String())
+	round, err := h.judging.UpdateRound(c.Request.Context(), id, req)
+	if err != nil {
+		respondJudgingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, round)
+}
+
+// FinalizeRound locks a round's rubrics and scores; it cannot be reopened (admin).
+// POST /api/v1/admin/judging/rounds/:id/finalize
+func (h *JudgingRoundHandler) FinalizeRound(c *gin.Context) {
+	id, ok := roundID(c)
+	if !ok {
+		return
+	}
+	var finalizedBy *uuid.UUID
+	if adminID, ok := middleware.GetUserID(c); ok {
+		finalizedBy = &adminID
+	}
+	middleware.SetAuditAction(c, "judging_round.finalize", "judging_round", id.String())
+	round, err := h.judging.FinalizeRound(c.Request.Context(), id, finalizedBy)
+	if err != nil {
+		respondJudgingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Round finalized; scores are locked", "round": round})
+}
+
+// SetRubric creates or replaces a track's rubric in an open round (admin). Criteria have a key,
+// label, optional description, weight and max_score.
+// PUT /api/v1/admin/judging/rounds/:id/rubrics
+func (h *JudgingRoundHandler) SetRubric(c *gin.Context) {
+	id, ok := roundID(c)
+	if !ok {
+		return
+	}
+	var req models.JudgingRubricRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var updatedBy *uuid.UUID
+	if adminID, ok := middleware.GetUserID(c); ok {
+		updatedBy = &adminID
+	}
+	middleware.SetAuditAction(c, "judging_rubric.set", "judging_round", id.String())
+	rubric, err := h.judging.SetRubric(c.Request.Context(), id, req, updatedBy)
+	if err != nil {
+		respondJudgingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rubric)
+}
+
+// DeleteRubric removes a track's rubric from an open round (admin).
+// DELETE /api/v1/admin/judging/rounds/:id/rubrics/:rubric_id
+func (h *JudgingRoundHandler) DeleteRubric(c *gin.Context) {
+	id, ok := roundID(c)
+	if !ok {
+		return
+	}
+	rubricID, err := uuid.Parse(c.Param("rubric_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rubric ID"})
+		return
+	}
+	middleware.SetAuditAction(c, "judging_rubric.delete", "judging_rubric", rubricID.String())
+	if err := h.judging.DeleteRubric(c.Request.Context(), id, rubricID); err != nil {
+		respondJudgingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Rubric deleted"})
+}
+
+// GetLeaderboard ranks a round's scored submissions, optionally for one track and city (admin).
+// normalize overrides the round's default z-score normalization per judge.
+// GET /api/v1/admin/judging/rounds/:id/leaderboard?track=AI&city=BLR&normalize=true
+func (h *JudgingRoundHandler) GetLeaderboard(c *gin.Context) {
+	id, ok := roundID(c)
+	if !ok {
+		return
+	}
+	opts := services.LeaderboardOptions{Track: c.Query("track"), City: c.Query("city")}
+	if v := c.Query("normalize"); v != "" {
+		normalize, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "normalize must be true or false"})
+			return
+		}
+		opts.Normalize = &normalize
+	}
+	board, err := h.judging.Leaderboard(c.Request.Context(), id, opts)
+	if err != nil {
+		respondJudgingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, board)
+}
+
+// ListScores returns every judge's score sheets for a submission across rounds (admin).
+// GET /api/v1/admin/judging/submissions/:id/scores
+func (h *JudgingRoundHandler) ListScores(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return
+	}
+	scores, err := h.judging.ListSubmissionScores(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"scores": scores})
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/services"
+)
+
+type MagicLinkHandler struct {
+	magicLinkService *services.MagicLinkService
+}
+
+func NewMagicLinkHandler(magicLinkService *services.MagicLinkService) *MagicLinkHandler {
+	return &MagicLinkHandler{magicLinkService: magicLinkService}
+}
+
+// SendMagicLink emails the team leader a single-use sign-in link.
+// When ENABLE_EMAIL_OTP is off no link is sent; the client falls back to verify-email-otp.
+// POST /api/v1/auth/send-magic-link
+func (h *MagicLinkHandler) SendMagicLink(c *gin.Context) {
+	var req struct {
+		TeamID string `json:"team_id" binding:"required"`
+		Email  string `json:"email" binding:"required,email"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request. Please provide team_id and email"})
+		return
+	}
+
+	teamID, err := uuid.Parse(req.TeamID)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	if err := h.magicLinkService.SendLink(c.Request.Context(), teamID, req.Email, sessionClient(c)); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	message := "Sign-in link sent to your email"
+	if !h.magicLinkService.Enabled() {
+		message = "Email verified"
+	}
+	c.JSON(200, gin.H{
+		"message":            message,
+		"email":              req.Email,
+		"magic_link_enabled": h.magicLinkService.Enabled(),
+	})
+}
+
+// VerifyMagicLink exchanges a sign-in link token for a session
+// POST /api/v1/auth/verify-magic-link
+func (h *MagicLinkHandler) VerifyMagicLink(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request. Please provide token"})
+		return
+	}
+
+	response, err := h.magicLinkService.VerifyLink(c.Request.Context(), req.Token, sessionClient(c))
+	if err != nil {
+		c.JSON(401, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, response)
+}
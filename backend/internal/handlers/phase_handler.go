@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rift26/backend/internal/middleware"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/services"
+)
+
+type PhaseHandler struct {
+	phaseService *services.PhaseService
+}
+
+func NewPhaseHandler(phaseService *services.PhaseService) *PhaseHandler {
+	return &PhaseHandler{phaseService: phaseService}
+}
+
+// ListPhases returns the raw schedule rows plus the resolved default status of each phase (admin).
+// GET /api/v1/admin/phases
+func (h *PhaseHandler) ListPhases(c *gin.Context) {
+	schedule, err := h.phaseService.ListSchedule(c.Request.Context(), middleware.GetEventID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	effective, err := h.phaseService.EventSnapshot(c.Request.Context(), middleware.GetEventID(c), c.Query("city"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"schedule": schedule, "effective": effective})
+}
+
+// UpsertPhase sets the mode and open/close window of a phase for a city ("" = default for all cities).
+// PUT /api/v1/admin/phases/:phase
+func (h *PhaseHandler) UpsertPhase(c *gin.Context) {
+	var req models.UpsertEventPhaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	phase, err := h.phaseService.UpsertSchedule(c.Request.Context(), middleware.GetEventID(c), c.Param("phase"), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Phase schedule saved", "phase": phase})
+}
+
+// SetOverride forces a phase open/closed/pin for a city, or clears the override when mode is null.
+// POST /api/v1/admin/phases/:phase/override
+func (h *PhaseHandler) SetOverride(c *gin.Context) {
+	var req models.SetPhaseOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	phase := models.EventPhaseKey(c.Param("phase"))
+	if err := h.phaseService.SetOverride(c.Request.Context(), middleware.GetEventID(c), phase, req.City, req.Mode); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	status, err := h.phaseService.EventStatus(c.Request.Context(), middleware.GetEventID(c), phase, req.City)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Phase override updated", "status": status})
+}
+
+// DeleteCityPhase removes a city-specific schedule so the city falls back to the default.
+// DELETE /api/v1/admin/phases/:phase?city=BLR
+func (h *PhaseHandler) DeleteCityPhase(c *gin.Context) {
+	if err := h.phaseService.DeleteCitySchedule(c.Request.Context(), middleware.GetEventID(c), c.Param("phase"), c.Query("city")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "City phase schedule removed"})
+}
@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/middleware"
+	"github.com/rift26/backend/internal/models"
 	"github.com/rift26/backend/internal/services"
+	"github.com/rift26/backend/pkg/storage"
 )
 
 type ProblemStatementHandler struct {
@@ -21,37 +24,53 @@ func NewProblemStatementHandler(service *services.ProblemStatementService) *Prob
 	return &ProblemStatementHandler{service: service}
 }
 
-// GetPublic returns problem statements for the public only if released (11:30 AM 19 Feb or after early release).
+// GetPublic returns problem statements for the public only if the ps_release phase is open (optional ?city=).
 // GET /api/v1/problem-statements
 func (h *ProblemStatementHandler) GetPublic(c *gin.Context) {
-	list, released, err := h.service.ListPublic(c.Request.Context())
+	city := c.Query("city")
+	list, released, err := h.service.ListPublic(c.Request.Context(), city)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load problem statements"})
 		return
 	}
 	if !released {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Problem statements are not yet released", "released": false})
+		resp := gin.H{"error": "Problem statements are not yet released", "released": false}
+		if st, err := h.service.ReleaseStatus(c.Request.Context(), city); err == nil && st.OpensAt != nil {
+			resp["release_at"] = st.OpensAt
+		}
+		c.JSON(http.StatusForbidden, resp)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"released": true, "problem_statements": list})
 }
 
-// ServePDF serves a problem statement PDF by filename (only files under upload dir).
+// ServePDF redirects to a signed download URL for an uploaded problem statement PDF.
+// Kept for links issued before PDFs moved to blob storage; listings now carry signed URLs.
 // GET /api/v1/uploads/problem-statements/:filename
 func (h *ProblemStatementHandler) ServePDF(c *gin.Context) {
 	filename := c.Param("filename")
-	if filename == "" || strings.Contains(filename, "..") || strings.ContainsRune(filename, filepath.Separator) {
+	if !storage.ValidKey(filename) || strings.Contains(filename, "/") {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filename"})
 		return
 	}
-	fullPath := filepath.Join(h.service.UploadDir(), filename)
-	c.File(fullPath)
+	signed, err := h.service.SignedPDFURL(c.Request.Context(), filename)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to prepare download"})
+		return
+	}
+	c.Redirect(http.StatusFound, signed)
 }
 
-// ListAdmin returns all problem statements (admin).
+// ListAdmin returns all problem statements with their caps and lock counts (admin).
 // GET /api/v1/admin/problem-statements
 func (h *ProblemStatementHandler) ListAdmin(c *gin.Context) {
-	list, err := h.service.ListAdmin(c.Request.Context())
+	eventID := middleware.GetEventID(c)
+	list, err := h.service.ListAdmin(c.Request.Context(), eventID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	counts, err := h.service.LockCounts(c.Request.Context(), eventID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -63,16 +82,26 @@ func (h *ProblemStatementHandler) ListAdmin(c *gin.Context) {
 		Name             string                 `json:"name"`
 		FilePath         string                 `json:"file_path"`
 		SubmissionFields map[string]interface{} `json:"submission_fields,omitempty"`
+		MaxTeams         *int                   `json:"max_teams,omitempty"`
+		CityMaxTeams     map[string]int         `json:"city_max_teams,omitempty"`
+		LockedTeams      int                    `json:"locked_teams"`
+		LockedByCity     map[string]int         `json:"locked_by_city"`
 		CreatedAt        string                 `json:"created_at"`
 	}
 	response := make([]PSResponse, 0, len(list))
 	for _, ps := range list {
 		res := PSResponse{
-			ID:        ps.ID.String(),
-			Track:     ps.Track,
-			Name:      ps.Name,
-			FilePath:  ps.FilePath,
-			CreatedAt: ps.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			ID:           ps.ID.String(),
+			Track:        ps.Track,
+			Name:         ps.Name,
+			FilePath:     ps.FilePath,
+			MaxTeams:     ps.MaxTeams,
+			CityMaxTeams: ps.CityMaxTeams,
+			LockedByCity: map[string]int{},
+			CreatedAt:    ps.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+		if count := counts[ps.ID]; count != nil {
+			res.LockedTeams, res.LockedByCity = count.Total, count.ByCity
 		}
 		if ps.SubmissionFields.Valid && ps.SubmissionFields.String != "" {
 			var fields map[string]interface{}
@@ -85,8 +114,8 @@ func (h *ProblemStatementHandler) ListAdmin(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"problem_statements": response})
 }
 
-// CreateAdmin creates a problem statement with a Google Drive (or any) PDF link (admin).
-// POST /api/v1/admin/problem-statements (form: track, name, link)
+// CreateAdmin creates a problem statement with a Google Drive (or any) PDF link or an uploaded PDF (admin).
+// POST /api/v1/admin/problem-statements (form: track, name, link or file, optional max_teams)
 func (h *ProblemStatementHandler) CreateAdmin(c *gin.Context) {
 	track := strings.TrimSpace(c.PostForm("track"))
 	name := strings.TrimSpace(c.PostForm("name"))
@@ -96,21 +125,45 @@ func (h *ProblemStatementHandler) CreateAdmin(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "track and name are required"})
 		return
 	}
-	if link == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "link is required (e.g. Google Drive share link)"})
+	file, _ := c.FormFile("file")
+	if link == "" && file == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "link (e.g. Google Drive share link) or a PDF file is required"})
 		return
 	}
-	parsed, err := url.Parse(link)
-	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "link must be a valid URL"})
-		return
+	if file == nil {
+		parsed, err := url.Parse(link)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "link must be a valid URL"})
+			return
+		}
+		if parsed.Scheme != "http" && parsed.Scheme != "https" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "link must be http or https"})
+			return
+		}
+	}
+	var maxTeams *int
+	if v := strings.TrimSpace(c.PostForm("max_teams")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "max_teams must be a non-negative number"})
+			return
+		}
+		maxTeams = &n
 	}
-	if parsed.Scheme != "http" && parsed.Scheme != "https" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "link must be http or https"})
+	fields, err := services.ParseSubmissionFields(submissionFieldsJSON)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	// Store the full URL in file_path (used as download URL when it's a link)
-	ps, err := h.service.Create(c.Request.Context(), track, name, link, submissionFieldsJSON)
+	// file_path holds the full URL for links (used as the download URL) or the storage key of an uploaded PDF
+	filePath := link
+	if file != nil {
+		if filePath, err = h.service.UploadPDF(c.Request.Context(), file); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	ps, err := h.service.Create(c.Request.Context(), middleware.GetEventID(c), track, name, filePath, fields, maxTeams)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -122,6 +175,7 @@ func (h *ProblemStatementHandler) CreateAdmin(c *gin.Context) {
 		Name             string                 `json:"name"`
 		FilePath         string                 `json:"file_path"`
 		SubmissionFields map[string]interface{} `json:"submission_fields,omitempty"`
+		MaxTeams         *int                   `json:"max_teams,omitempty"`
 		CreatedAt        string                 `json:"created_at"`
 	}
 	res := PSResponse{
@@ -129,6 +183,7 @@ func (h *ProblemStatementHandler) CreateAdmin(c *gin.Context) {
 		Track:     ps.Track,
 		Name:      ps.Name,
 		FilePath:  ps.FilePath,
+		MaxTeams:  ps.MaxTeams,
 		CreatedAt: ps.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 	if ps.SubmissionFields.Valid && ps.SubmissionFields.String != "" {
@@ -156,30 +211,86 @@ func (h *ProblemStatementHandler) DeleteAdmin(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Deleted"})
 }
 
-// ReleaseEarly sets release time to now for testing (admin).
+// SetSubmissionFields replaces the final submission field schema of a problem statement (admin).
+// Custom fields have a type (url, github-repo, text, long-text, number, email, select, checkbox),
+// required flag, pattern, min_length/max_length, help_text and, for select, options.
+// PUT /api/v1/admin/problem-statements/:id/submission-fields
+func (h *ProblemStatementHandler) SetSubmissionFields(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req services.PSSubmissionFieldsConfig
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	middleware.SetAuditAction(c, "problem_statement.submission_fields", "problem_statement", id.String())
+	found, err := h.service.SetSubmissionFields(c.Request.Context(), id, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Problem statement not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Submission fields updated", "submission_fields": req})
+}
+
+// SetCapacity sets how many teams can lock a problem statement, overall and per city (admin).
+// max_teams null = unlimited; city_max_teams replaces all city caps ({} clears them).
+// PUT /api/v1/admin/problem-statements/:id/capacity
+func (h *ProblemStatementHandler) SetCapacity(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req models.PSCapacityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	middleware.SetAuditAction(c, "problem_statement.capacity", "problem_statement", id.String())
+	found, err := h.service.SetCapacity(c.Request.Context(), id, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Problem statement not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Capacity updated"})
+}
+
+// ReleaseEarly opens the ps_release phase for all cities (admin); see PhaseService.Toggle.
 // POST /api/v1/admin/problem-statements/release-early
 func (h *ProblemStatementHandler) ReleaseEarly(c *gin.Context) {
-	if err := h.service.ReleaseEarly(c.Request.Context()); err != nil {
+	st, err := h.service.ReleaseEarly(c.Request.Context())
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Problem statements released now (for testing)"})
+	c.JSON(http.StatusOK, gin.H{"message": "Problem statements released now" + overrideNote(st), "phase": st})
 }
 
-// ResetRelease clears the early-release flag so the timer/countdown shows again (admin, testing only).
+// ResetRelease clears the release override so the scheduled release time applies again (admin).
 // POST /api/v1/admin/problem-statements/reset-release
 func (h *ProblemStatementHandler) ResetRelease(c *gin.Context) {
 	if err := h.service.ResetRelease(c.Request.Context()); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Release reset. Timer will show until 11:30 AM 19 Feb or release early again."})
+	c.JSON(http.StatusOK, gin.H{"message": "Release reset. The scheduled ps_release phase applies again."})
 }
 
-// GetSubmissionStatus returns PS submission window status (admin).
+// GetSubmissionStatus returns PS submission window status (admin, optional ?city=).
 // GET /api/v1/admin/problem-statements/submission-status
 func (h *ProblemStatementHandler) GetSubmissionStatus(c *gin.Context) {
-	open, err := h.service.IsSubmissionOpen(c.Request.Context())
+	open, err := h.service.IsSubmissionOpen(c.Request.Context(), c.Query("city"))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -187,7 +298,7 @@ func (h *ProblemStatementHandler) GetSubmissionStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"submission_open": open})
 }
 
-// ToggleSubmissionWindow toggles PS submission window open/closed (admin).
+// ToggleSubmissionWindow opens/closes the PS submission window for all cities (admin); see PhaseService.Toggle.
 // POST /api/v1/admin/problem-statements/toggle-submission
 func (h *ProblemStatementHandler) ToggleSubmissionWindow(c *gin.Context) {
 	var req struct {
@@ -197,21 +308,25 @@ func (h *ProblemStatementHandler) ToggleSubmissionWindow(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
 		return
 	}
-	if err := h.service.SetSubmissionOpen(c.Request.Context(), req.Open); err != nil {
+	middleware.SetAuditAction(c, "ps_submission_window.toggle", "phase", string(models.PhasePSLock))
+	wasOpen, _ := h.service.IsSubmissionOpen(c.Request.Context(), "")
+	st, err := h.service.SetSubmissionOpen(c.Request.Context(), req.Open)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	middleware.SetAuditChange(c, gin.H{"open": wasOpen}, gin.H{"open": req.Open})
 	status := "locked"
 	if req.Open {
 		status = "unlocked"
 	}
-	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("PS submission window %s", status), "submission_open": req.Open})
+	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("PS submission window %s", status) + overrideNote(st), "submission_open": req.Open, "phase": st})
 }
 
-// GetFinalSubmissionStatus returns the final submission portal status (admin).
+// GetFinalSubmissionStatus returns the final submission portal status (admin, optional ?city=).
 // GET /api/v1/admin/problem-statements/final-submission-status
 func (h *ProblemStatementHandler) GetFinalSubmissionStatus(c *gin.Context) {
-	open, err := h.service.IsFinalSubmissionOpen(c.Request.Context())
+	open, err := h.service.IsFinalSubmissionOpen(c.Request.Context(), c.Query("city"))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -219,7 +334,7 @@ func (h *ProblemStatementHandler) GetFinalSubmissionStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"portal_open": open})
 }
 
-// ToggleFinalSubmissionPortal opens/closes the final submission portal (admin).
+// ToggleFinalSubmissionPortal opens/closes the final submission portal for all cities (admin); see PhaseService.Toggle.
 // POST /api/v1/admin/problem-statements/toggle-final-submission
 func (h *ProblemStatementHandler) ToggleFinalSubmissionPortal(c *gin.Context) {
 	var req struct {
@@ -229,13 +344,25 @@ func (h *ProblemStatementHandler) ToggleFinalSubmissionPortal(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
 		return
 	}
-	if err := h.service.SetFinalSubmissionOpen(c.Request.Context(), req.Open); err != nil {
+	middleware.SetAuditAction(c, "final_submission_portal.toggle", "phase", string(models.PhaseFinalSubmission))
+	wasOpen, _ := h.service.IsFinalSubmissionOpen(c.Request.Context(), "")
+	st, err := h.service.SetFinalSubmissionOpen(c.Request.Context(), req.Open)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	middleware.SetAuditChange(c, gin.H{"open": wasOpen}, gin.H{"open": req.Open})
 	status := "closed"
 	if req.Open {
 		status = "opened"
 	}
-	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Final submission portal %s", status), "portal_open": req.Open})
+	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Final submission portal %s", status) + overrideNote(st), "portal_open": req.Open, "phase": st})
+}
+
+// overrideNote tells the admin when a toggle left an override in force over the per-city schedules.
+func overrideNote(st *models.PhaseStatus) string {
+	if st.Source != "override" {
+		return ""
+	}
+	return ". An override is now in force for all cities and wins over the phase schedules; clear it in the phase settings to follow the schedule again."
 }
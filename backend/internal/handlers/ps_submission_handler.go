@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/middleware"
+	"github.com/rift26/backend/internal/models"
 	"github.com/rift26/backend/internal/services"
+	"github.com/rift26/backend/pkg/storage"
 )
 
 type PSSubmissionHandler struct {
@@ -33,24 +39,51 @@ func (h *PSSubmissionHandler) GetTeamForm(c *gin.Context) {
 	c.JSON(http.StatusOK, form)
 }
 
-// Submit handles project submission for a team.
-// POST /api/v1/teams/:id/submission
-func (h *PSSubmissionHandler) Submit(c *gin.Context) {
-	teamID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
-		return
-	}
-	var req struct {
-		LinkedinURL       string                 `json:"linkedin_url"`
-		GithubURL        string                 `json:"github_url"`
-		LiveURL          string                 `json:"live_url"`
-		ExtraNotes       string                 `json:"extra_notes"`
-		CustomFieldValues map[string]interface{} `json:"custom_field_values,omitempty"`
-	}
-	if err := c.ShouldBindJSON(&req); err != nil {
+// submissionRequest is the JSON submission body; multipart submissions send the same names as form
+// values, with custom_field_values as a JSON object and each file under its field key.
+type submissionRequest struct {
+	LinkedinURL       string                 `json:"linkedin_url"`
+	GithubURL         string                 `json:"github_url"`
+	LiveURL           string                 `json:"live_url"`
+	ExtraNotes        string                 `json:"extra_notes"`
+	CustomFieldValues map[string]interface{} `json:"custom_field_values,omitempty"`
+}
+
+// bindSubmission reads a JSON or multipart submission. It writes the error response itself and returns false on failure.
+func (h *PSSubmissionHandler) bindSubmission(c *gin.Context) (services.SubmissionInput, bool) {
+	var req submissionRequest
+	var files map[string]*multipart.FileHeader
+	if c.ContentType() == "multipart/form-data" {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.service.MaxUploadRequestBytes())
+		form, err := c.MultipartForm()
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Upload is too large"})
+				return services.SubmissionInput{}, false
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form: " + err.Error()})
+			return services.SubmissionInput{}, false
+		}
+		req.LinkedinURL = c.PostForm("linkedin_url")
+		req.GithubURL = c.PostForm("github_url")
+		req.LiveURL = c.PostForm("live_url")
+		req.ExtraNotes = c.PostForm("extra_notes")
+		if raw := c.PostForm("custom_field_values"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &req.CustomFieldValues); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "custom_field_values must be a JSON object"})
+				return services.SubmissionInput{}, false
+			}
+		}
+		files = make(map[string]*multipart.FileHeader, len(form.File))
+		for key, headers := range form.File {
+			if len(headers) > 0 {
+				files[key] = headers[0]
+			}
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
-		return
+		return services.SubmissionInput{}, false
 	}
 	// Coerce custom field values to string (client may send strings or other types)
 	customStrings := make(map[string]string)
@@ -66,10 +99,134 @@ func (h *PSSubmissionHandler) Submit(c *gin.Context) {
 			customStrings[k] = fmt.Sprint(val)
 		}
 	}
-	if err := h.service.Submit(c.Request.Context(), teamID, req.LinkedinURL, req.GithubURL, req.LiveURL, req.ExtraNotes, customStrings); err != nil {
+	return services.SubmissionInput{
+		LinkedinURL:       req.LinkedinURL,
+		GithubURL:         req.GithubURL,
+		LiveURL:           req.LiveURL,
+		ExtraNotes:        req.ExtraNotes,
+		CustomFieldValues: customStrings,
+		Files:             files,
+	}, true
+}
+
+// Submit saves a new revision of the team's project submission; earlier revisions are kept.
+// Accepts JSON, or multipart/form-data when file fields are uploaded; files are checked against
+// their field's size cap and allowed types (by content, not the client's Content-Type).
+// Submissions after the portal's scheduled close are flagged late or rejected, per LATE_SUBMISSIONS.
+// POST /api/v1/teams/:id/submission
+func (h *PSSubmissionHandler) Submit(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	in, ok := h.bindSubmission(c)
+	if !ok {
+		return
+	}
+	sub, err := h.service.Submit(c.Request.Context(), teamID, in, middleware.TeamActor(c))
+	if err != nil {
+		var invalid *services.SubmissionValidationError
+		if errors.As(err, &invalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Please fix the highlighted fields", "field_errors": invalid.Fields})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Submission saved"})
+	message := "Submission saved"
+	if sub.IsLate {
+		message = "Submission saved after the deadline and marked late"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":      message,
+		"version":      sub.Version,
+		"late":         sub.IsLate,
+		"submitted_at": sub.SubmittedAt,
+	})
+}
+
+// GetVersions returns every saved revision of a submission with who saved it and whether it was late (admin).
+// GET /api/v1/admin/judging/submissions/:id/versions
+func (h *PSSubmissionHandler) GetVersions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return
+	}
+	versions, err := h.service.ListVersions(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load submission versions"})
+		return
+	}
+	if len(versions) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"versions": versions, "count": len(versions)})
+}
+
+// GetTeamAttachment downloads a file the team uploaded with its submission.
+// GET /api/v1/teams/:id/submission/attachments/:attachment_id
+func (h *PSSubmissionHandler) GetTeamAttachment(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	attachmentID, err := uuid.Parse(c.Param("attachment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return
+	}
+	a, err := h.service.TeamAttachment(c.Request.Context(), teamID, attachmentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load attachment"})
+		return
+	}
+	serveAttachment(c, h.service, a)
+}
+
+// GetAttachment downloads a file from any revision of a submission (admin).
+// GET /api/v1/admin/judging/submissions/:id/attachments/:attachment_id
+func (h *PSSubmissionHandler) GetAttachment(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return
+	}
+	attachmentID, err := uuid.Parse(c.Param("attachment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return
+	}
+	a, err := h.service.SubmissionAttachment(c.Request.Context(), id, attachmentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load attachment"})
+		return
+	}
+	serveAttachment(c, h.service, a)
+}
+
+// serveAttachment streams a stored attachment as a download with its sniffed content type.
+func serveAttachment(c *gin.Context, service *services.PSSubmissionService, a *models.SubmissionAttachment) {
+	if a == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+	body, err := service.OpenAttachment(c.Request.Context(), a)
+	if errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment file is missing from storage"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read attachment"})
+		return
+	}
+	defer body.Close()
+	c.DataFromReader(http.StatusOK, a.SizeBytes, a.ContentType, body, map[string]string{
+		"Content-Disposition":    storage.ContentDisposition(a.Filename),
+		"X-Content-Type-Options": "nosniff",
+	})
 }
 
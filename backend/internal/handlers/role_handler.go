@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/middleware"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+	"github.com/rift26/backend/internal/services"
+)
+
+type RoleHandler struct {
+	permissionService *services.PermissionService
+}
+
+func NewRoleHandler(permissionService *services.PermissionService) *RoleHandler {
+	return &RoleHandler{permissionService: permissionService}
+}
+
+// roleChangeStatus maps role changes that would lock everyone out to 409.
+func roleChangeStatus(err error) int {
+	if errors.Is(err, repository.ErrNoRoleManager) {
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
+
+// ListPermissions returns the permission catalog (admin).
+// GET /api/v1/admin/permissions
+func (h *RoleHandler) ListPermissions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"permissions": h.permissionService.Catalog()})
+}
+
+// GetMyPermissions returns the caller's effective permissions as carried by their token (admin).
+// GET /api/v1/admin/me/permissions
+func (h *RoleHandler) GetMyPermissions(c *gin.Context) {
+	perms := middleware.GetPermissions(c)
+	if perms == nil {
+		perms = []string{}
+	}
+	c.JSON(http.StatusOK, gin.H{"permissions": perms})
+}
+
+// ListRoles returns every role with its permissions and holder count (admin).
+// GET /api/v1/admin/roles
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.permissionService.ListRoles(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"roles": roles})
+}
+
+// CreateRole adds a custom role (admin).
+// POST /api/v1/admin/roles
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var req models.CreateAdminRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	role, err := h.permissionService.CreateRole(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, role)
+}
+
+// UpdateRole changes a role's name, description or permissions (admin).
+// PUT /api/v1/admin/roles/:key
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	var req models.UpdateAdminRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	role, err := h.permissionService.UpdateRole(c.Request.Context(), c.Param("key"), req)
+	if err != nil {
+		c.JSON(roleChangeStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, role)
+}
+
+// DeleteRole removes a custom role (admin).
+// DELETE /api/v1/admin/roles/:key
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	if err := h.permissionService.DeleteRole(c.Request.Context(), c.Param("key")); err != nil {
+		c.JSON(roleChangeStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Role deleted"})
+}
+
+// ListAdminUsers returns admin users with their roles and effective permissions (admin).
+// GET /api/v1/admin/roles/users
+func (h *RoleHandler) ListAdminUsers(c *gin.Context) {
+	users, err := h.permissionService.ListAdminUsers(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"users": users})
+}
+
+// SetUserRoles replaces the roles of an admin user. Takes effect on their next token refresh (admin).
+// PUT /api/v1/admin/roles/users/:user_id
+func (h *RoleHandler) SetUserRoles(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	var req models.SetUserRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var grantedBy *uuid.UUID
+	if id, ok := middleware.GetUserID(c); ok {
+		grantedBy = &id
+	}
+	if err := h.permissionService.SetUserRoles(c.Request.Context(), userID, req.Roles, grantedBy); err != nil {
+		c.JSON(roleChangeStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Roles updated"})
+}
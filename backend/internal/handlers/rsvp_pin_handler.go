@@ -1,72 +1,171 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rift26/backend/internal/middleware"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
 	"github.com/rift26/backend/internal/rsvppin"
+	"github.com/rift26/backend/internal/services"
 )
 
 type RSVPPinHandler struct {
-	pinSecret   string
-	rsvpOpenMode string
+	pins           *rsvppin.PINs
+	phaseService   *services.PhaseService
+	eventTableRepo *repository.EventTableRepository
+	cityService    *services.CityService
 }
 
-func NewRSVPPinHandler(pinSecret, rsvpOpenMode string) *RSVPPinHandler {
+func NewRSVPPinHandler(pins *rsvppin.PINs, phaseService *services.PhaseService, eventTableRepo *repository.EventTableRepository, cityService *services.CityService) *RSVPPinHandler {
 	return &RSVPPinHandler{
-		pinSecret:   pinSecret,
-		rsvpOpenMode: rsvpOpenMode,
+		pins:           pins,
+		phaseService:   phaseService,
+		eventTableRepo: eventTableRepo,
+		cityService:    cityService,
 	}
 }
 
-// ValidatePIN validates the 6-digit RSVP PIN (public, rate-limited).
-// POST /api/v1/auth/validate-rsvp-pin
-func (h *RSVPPinHandler) ValidatePIN(c *gin.Context) {
-	if h.rsvpOpenMode != "pin" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "RSVP is not in PIN mode"})
-		return
+// pinPhases maps the phase-gated PIN scopes to the phase whose PIN mode enables them.
+var pinPhases = map[rsvppin.Scope]models.EventPhaseKey{
+	rsvppin.ScopeRSVP:  models.PhaseRSVP1,
+	rsvppin.ScopeFinal: models.PhaseRSVP2,
+}
+
+// scopeEnabled reports whether a PIN scope is in use. RSVP and final PINs follow their phase's
+// PIN mode; a desk PIN is in use while the desk is active.
+func (h *RSVPPinHandler) scopeEnabled(ctx context.Context, scope rsvppin.Scope, city string) (bool, string, error) {
+	if phase, ok := pinPhases[scope]; ok {
+		mode, err := h.phaseService.Mode(ctx, phase, city)
+		if err != nil {
+			return false, "", err
+		}
+		if mode != models.PhaseModePIN {
+			return false, "Phase is not in PIN mode", nil
+		}
+		return true, "", nil
+	}
+	deskID, _ := rsvppin.DeskID(scope)
+	// GetByID reports a missing desk as an error
+	desk, err := h.eventTableRepo.GetByID(deskID)
+	if err != nil || !desk.IsActive {
+		return false, "Registration desk not found or inactive", nil
 	}
+	return true, "", nil
+}
 
+// ValidatePIN validates a 6-digit PIN (public, rate-limited).
+// scope is "rsvp" (default), "final" or "desk:<desk id>"; the previous and next window's PIN are also accepted.
+// POST /api/v1/auth/validate-rsvp-pin
+func (h *RSVPPinHandler) ValidatePIN(c *gin.Context) {
 	var req struct {
-		Pin string `json:"pin" binding:"required"`
+		Pin   string `json:"pin" binding:"required"`
+		City  string `json:"city"`
+		Scope string `json:"scope"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "PIN is required"})
 		return
 	}
+	if req.Scope == "" {
+		req.Scope = string(rsvppin.ScopeRSVP)
+	}
+	scope, ok := rsvppin.ParseScope(req.Scope)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid PIN scope"})
+		return
+	}
+
+	enabled, reason, err := h.scopeEnabled(c.Request.Context(), scope, req.City)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check PIN status"})
+		return
+	}
+	if !enabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": reason})
+		return
+	}
 
-	valid := rsvppin.ValidatePIN(h.pinSecret, req.Pin)
+	valid := h.pins.Validate(scope, req.Pin)
 	if !valid {
 		c.JSON(http.StatusUnauthorized, gin.H{"valid": false, "error": "Invalid PIN"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"valid": true})
+	c.JSON(http.StatusOK, gin.H{"valid": true, "scope": scope})
 }
 
-// GetRSVPPin returns the current 6-digit PIN and next rotation time (admin only).
+// activePIN is one PIN currently in use, as shown on the admin PIN board.
+type activePIN struct {
+	Scope       rsvppin.Scope `json:"scope"`
+	Label       string        `json:"label"`
+	City        string        `json:"city,omitempty"`
+	PIN         string        `json:"pin"`
+	TableNumber string        `json:"table_number,omitempty"`
+}
+
+// GetRSVPPin returns every active PIN (RSVP, final and one per active registration desk)
+// with the next rotation time (admin only, optional ?city=).
+// The top-level pin field is the RSVP PIN, kept for older clients.
 // GET /api/v1/admin/rsvp-pin
 func (h *RSVPPinHandler) GetRSVPPin(c *gin.Context) {
-	if h.rsvpOpenMode != "pin" {
-		c.JSON(http.StatusOK, gin.H{
-			"enabled":   false,
-			"pin":       "",
-			"message":   "RSVP is not in PIN mode",
-			"next_rotation_at": nil,
-			"seconds_until_rotation": 0,
-		})
-		return
+	ctx := c.Request.Context()
+	city := c.Query("city")
+
+	pins := []activePIN{}
+	rsvpEnabled := false
+	for _, scope := range []rsvppin.Scope{rsvppin.ScopeRSVP, rsvppin.ScopeFinal} {
+		enabled, _, err := h.scopeEnabled(ctx, scope, city)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check RSVP status"})
+			return
+		}
+		if !enabled {
+			continue
+		}
+		label := "RSVP"
+		if scope == rsvppin.ScopeFinal {
+			label = "Final confirmation"
+		} else {
+			rsvpEnabled = true
+		}
+		pins = append(pins, activePIN{Scope: scope, Label: label, PIN: h.pins.Current(scope)})
 	}
 
-	pin := rsvppin.GetCurrentPIN(h.pinSecret)
-	nextAt := rsvppin.GetNextRotationTime()
-	secondsUntil := rsvppin.GetSecondsUntilRotation()
+	var cities []string
+	if city != "" {
+		cities = h.cityService.Variations(city)
+	}
+	isActive := true
+	desks, err := h.eventTableRepo.GetAll(middleware.GetEventID(c), cities, &isActive)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load registration desks"})
+		return
+	}
+	for _, desk := range desks {
+		scope := rsvppin.DeskScope(desk.ID)
+		pins = append(pins, activePIN{
+			Scope:       scope,
+			Label:       desk.TableName,
+			City:        desk.City,
+			TableNumber: desk.TableNumber,
+			PIN:         h.pins.Current(scope),
+		})
+	}
 
+	rsvpPIN := ""
+	if rsvpEnabled {
+		rsvpPIN = h.pins.Current(rsvppin.ScopeRSVP)
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"enabled":                  true,
-		"pin":                      pin,
-		"next_rotation_at":         nextAt.Format(time.RFC3339),
-		"seconds_until_rotation":   secondsUntil,
+		"enabled":                rsvpEnabled,
+		"pin":                    rsvpPIN,
+		"pins":                   pins,
+		"rotation_seconds":       int64(h.pins.Rotation() / time.Second),
+		"next_rotation_at":       h.pins.NextRotation().Format(time.RFC3339),
+		"seconds_until_rotation": h.pins.SecondsUntilRotation(),
 	})
 }
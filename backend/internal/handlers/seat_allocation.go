@@ -16,11 +16,12 @@ import (
 )
 
 type SeatAllocatorHandler struct {
-	db *gorm.DB
+	db          *gorm.DB
+	cityService *services.CityService
 }
 
-func NewSeatAllocatorHandler(db *gorm.DB) *SeatAllocatorHandler {
-	return &SeatAllocatorHandler{db: db}
+func NewSeatAllocatorHandler(db *gorm.DB, cityService *services.CityService) *SeatAllocatorHandler {
+	return &SeatAllocatorHandler{db: db, cityService: cityService}
 }
 
 // Blocks
@@ -32,7 +33,25 @@ func (h *SeatAllocatorHandler) CreateBlock(c *gin.Context) {
 		return
 	}
 
-	block.City = "bengaluru" // Enforce Bengaluru for now as per requirements
+	// Blocks belong to a city that uses seat allocation; default to the first such city
+	if strings.TrimSpace(block.City) == "" {
+		seatCity, err := h.cityService.SeatAllocationCity(c.Request.Context())
+		if err != nil || seatCity == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No city is configured for seat allocation"})
+			return
+		}
+		block.City = seatCity.Code
+	}
+	code, ok, err := h.cityService.Normalize(c.Request.Context(), block.City)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve city"})
+		return
+	}
+	if !ok || !h.cityService.HasSeatAllocation(code) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "City does not use seat allocation"})
+		return
+	}
+	block.City = code
 
 	// Get max display order
 	var maxOrder int
@@ -575,7 +594,7 @@ func slug(s string) string {
 }
 
 // GetPublicRoomView returns layout and allocations for a room by city and room name (slug).
-// Public, no auth. For now only city "bengaluru" is supported.
+// Public, no auth. Only cities with seat allocation enabled are supported.
 // GET /api/v1/public/viewroom/:city/:roomname
 func (h *SeatAllocatorHandler) GetPublicRoomView(c *gin.Context) {
 	cityParam := strings.ToLower(strings.TrimSpace(c.Param("city")))
@@ -584,19 +603,14 @@ func (h *SeatAllocatorHandler) GetPublicRoomView(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "room name required"})
 		return
 	}
-	// Only Bengaluru supported for now
-	if cityParam != "bengaluru" && cityParam != "blr" && cityParam != "bangalore" {
+	if !h.cityService.HasSeatAllocation(cityParam) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "city not found or not supported"})
 		return
 	}
 
-	// Match Bengaluru blocks: exact match or city containing bengaluru/bangalore
+	// Match the city's blocks by code, name or any alias
 	var blocks []models.Block
-	if err := h.db.Where(
-		"LOWER(TRIM(city)) IN ? OR LOWER(TRIM(city)) LIKE ? OR LOWER(TRIM(city)) LIKE ?",
-		[]string{"bengaluru", "blr", "bangalore"},
-		"%bengaluru%", "%bangalore%",
-	).Find(&blocks).Error; err != nil {
+	if err := h.db.Where("LOWER(TRIM(city)) IN ?", h.cityService.Variations(cityParam)).Find(&blocks).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to find blocks"})
 		return
 	}
@@ -756,7 +770,7 @@ func (h *SeatAllocatorHandler) GetAllAllocations(c *gin.Context) {
 
 // GetAllocationStats returns seat allocation statistics (for admin dashboard)
 func (h *SeatAllocatorHandler) GetAllocationStats(c *gin.Context) {
-	stats, err := services.NewSeatAllocationService(h.db).GetAllocationStats()
+	stats, err := services.NewSeatAllocationService(h.db, h.cityService).GetAllocationStats()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stats"})
 		return
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/middleware"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/services"
+)
+
+type SessionHandler struct {
+	sessions *services.SessionService
+}
+
+func NewSessionHandler(sessions *services.SessionService) *SessionHandler {
+	return &SessionHandler{sessions: sessions}
+}
+
+// sessionClient describes the requesting device for a new session.
+func sessionClient(c *gin.Context) models.SessionClient {
+	return models.SessionClient{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh token (public).
+// The old refresh token stops working; presenting it again revokes the session.
+// POST /api/v1/auth/refresh
+func (h *SessionHandler) Refresh(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tokens, err := h.sessions.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout ends the session of a refresh token (public; works for every role).
+// POST /api/v1/auth/logout
+func (h *SessionHandler) Logout(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.sessions.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// LogoutAll ends every session of the caller, on all devices (auth).
+// POST /api/v1/auth/logout-all
+func (h *SessionHandler) LogoutAll(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	n, err := h.sessions.RevokeAll(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "All sessions revoked", "revoked": n})
+}
+
+// ListSessions returns the active sessions of a user, volunteer, volunteer admin or team (admin).
+// GET /api/v1/admin/sessions?subject_id=
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	subjectID, err := uuid.Parse(c.Query("subject_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid subject_id"})
+		return
+	}
+	list, err := h.sessions.ListActive(c.Request.Context(), subjectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"sessions": list})
+}
+
+// RevokeAllSessions signs a user, volunteer, volunteer admin or team out everywhere,
+// e.g. when a volunteer's phone is lost (admin).
+// POST /api/v1/admin/sessions/revoke-all
+func (h *SessionHandler) RevokeAllSessions(c *gin.Context) {
+	var req models.RevokeSessionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	n, err := h.sessions.RevokeAll(c.Request.Context(), req.SubjectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "All sessions revoked", "revoked": n})
+}
+
+// RevokeSession ends a single session by ID (admin).
+// DELETE /api/v1/admin/sessions/:id
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+	if err := h.sessions.RevokeSession(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
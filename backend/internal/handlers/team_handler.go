@@ -1,13 +1,13 @@
 package handlers
 
 import (
-	"os"
-	"strings"
+	"errors"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/rift26/backend/internal/middleware"
 	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
 	"github.com/rift26/backend/internal/services"
 )
 
@@ -18,9 +18,11 @@ type TeamHandler struct {
 	seatAllocationService  *services.SeatAllocationService
 	psSelectionService    *services.PSSelectionService
 	problemStatementService *services.ProblemStatementService
+	phaseService           *services.PhaseService
+	dashboardTokenService  *services.DashboardTokenService
 }
 
-func NewTeamHandler(teamService *services.TeamService, jwtSecret string, allowCityChange bool, seatAllocationService *services.SeatAllocationService, psSelectionService *services.PSSelectionService, problemStatementService *services.ProblemStatementService) *TeamHandler {
+func NewTeamHandler(teamService *services.TeamService, jwtSecret string, allowCityChange bool, seatAllocationService *services.SeatAllocationService, psSelectionService *services.PSSelectionService, problemStatementService *services.ProblemStatementService, phaseService *services.PhaseService, dashboardTokenService *services.DashboardTokenService) *TeamHandler {
 	return &TeamHandler{
 		teamService:          teamService,
 		jwtSecret:            jwtSecret,
@@ -28,11 +30,22 @@ func NewTeamHandler(teamService *services.TeamService, jwtSecret string, allowCi
 		seatAllocationService: seatAllocationService,
 		psSelectionService:   psSelectionService,
 		problemStatementService: problemStatementService,
+		phaseService:           phaseService,
+		dashboardTokenService:  dashboardTokenService,
 	}
 }
 
-// SearchTeams handles team search requests
-// GET /api/v1/teams/search?name=query
+// phaseOpenForTeam reports whether a phase is open (or PIN-protected) for the team's city.
+func (h *TeamHandler) phaseOpenForTeam(c *gin.Context, phase models.EventPhaseKey, team *models.Team) (bool, error) {
+	city := ""
+	if team != nil && team.City != nil {
+		city = string(*team.City)
+	}
+	return h.phaseService.IsOpen(c.Request.Context(), phase, city)
+}
+
+// SearchTeams handles ranked, paginated team search requests (public)
+// GET /api/v1/teams/search?query=...&page=1&page_size=10
 func (h *TeamHandler) SearchTeams(c *gin.Context) {
 	var query models.TeamSearchRequest
 	if err := c.ShouldBindQuery(&query); err != nil {
@@ -40,16 +53,19 @@ func (h *TeamHandler) SearchTeams(c *gin.Context) {
 		return
 	}
 
-	results, err := h.teamService.SearchTeams(c.Request.Context(), query.Query)
+	q, err := services.NormalizeSearchQuery(query.Query)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := h.teamService.SearchTeams(c.Request.Context(), middleware.GetEventID(c), q, query.Page, query.PageSize)
 	if err != nil {
 		c.JSON(500, gin.H{"error": "Failed to search teams"})
 		return
 	}
 
-	c.JSON(200, gin.H{
-		"teams": results,
-		"count": len(results),
-	})
+	c.JSON(200, results)
 }
 
 // GetTeam retrieves team details by ID (requires auth)
@@ -94,17 +110,27 @@ func (h *TeamHandler) SubmitRSVP(c *gin.Context) {
 		return
 	}
 
-	// If city change is not allowed, get the existing team city
+	team, err := h.teamService.GetTeamByID(c.Request.Context(), teamID)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Failed to fetch team"})
+		return
+	}
+
+	// Enforce the rsvp1 phase for the team's city
+	open, err := h.phaseOpenForTeam(c, models.PhaseRSVP1, team)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to check RSVP status"})
+		return
+	}
+	if !open {
+		c.JSON(400, gin.H{"error": "RSVP is closed"})
+		return
+	}
+
+	// If city change is not allowed, use the existing team city
 	cityToUse := req.City
-	if !h.allowCityChange {
-		team, err := h.teamService.GetTeamByID(c.Request.Context(), teamID)
-		if err != nil {
-			c.JSON(400, gin.H{"error": "Failed to fetch team"})
-			return
-		}
-		if team.City != nil {
-			cityToUse = *team.City
-		}
+	if !h.allowCityChange && team.City != nil {
+		cityToUse = *team.City
 	}
 
 	err = h.teamService.SubmitRSVP(c.Request.Context(), teamID, cityToUse, req.Members)
@@ -119,16 +145,42 @@ func (h *TeamHandler) SubmitRSVP(c *gin.Context) {
 	})
 }
 
-// GetDashboard retrieves team dashboard data via token (no auth required)
+// GetDashboard retrieves team dashboard data via an expiring dashboard link (no auth required).
+// Read-only links get no QR code, member contact details or full-access token. Every use is logged.
 // GET /api/v1/dashboard/:token
 func (h *TeamHandler) GetDashboard(c *gin.Context) {
-	token := c.Param("token")
+	link, err := h.dashboardTokenService.Resolve(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		var linkErr *services.DashboardLinkError
+		if !errors.As(err, &linkErr) {
+			c.JSON(500, gin.H{"error": "Failed to load dashboard"})
+			return
+		}
+		status := 404
+		if linkErr.Gone {
+			status = 410
+		}
+		c.JSON(status, gin.H{"error": linkErr.Reason})
+		return
+	}
+	h.dashboardTokenService.RecordAccess(c.Request.Context(), link, c.ClientIP(), c.Request.UserAgent(), c.Request.Method, c.FullPath())
 
-	team, announcements, qrCode, err := h.teamService.GetDashboard(c.Request.Context(), token)
+	team, announcements, qrCode, err := h.teamService.GetDashboard(c.Request.Context(), link.TeamID)
 	if err != nil {
 		c.JSON(404, gin.H{"error": "Dashboard not found"})
 		return
 	}
+	readOnly := link.Access == models.DashboardAccessReadOnly
+	if readOnly {
+		qrCode = ""
+		team.QRCodeToken = nil
+		team.DashboardToken = nil
+		for i := range team.Members {
+			team.Members[i].Email = ""
+			team.Members[i].Phone = ""
+			team.Members[i].IndividualQRToken = nil
+		}
+	}
 
 	var seatAllocation interface{}
 	if h.seatAllocationService != nil {
@@ -150,14 +202,16 @@ func (h *TeamHandler) GetDashboard(c *gin.Context) {
 	var psSubmissionOpen bool
 	var currentPSSelection interface{}
 	if team.Status == models.StatusCheckedIn {
-		// Check if PS are released
-		if released, _ := h.problemStatementService.IsReleased(c.Request.Context()); released {
-			if list, _, err := h.problemStatementService.ListPublic(c.Request.Context()); err == nil {
-				problemStatements = list
-			}
+		city := ""
+		if team.City != nil {
+			city = string(*team.City)
+		}
+		// Check if PS are released for the team's city
+		if list, released, err := h.problemStatementService.ListPublic(c.Request.Context(), city); err == nil && released {
+			problemStatements = list
 		}
 		// Check submission window status
-		if open, _ := h.problemStatementService.IsSubmissionOpen(c.Request.Context()); open {
+		if open, _ := h.problemStatementService.IsSubmissionOpen(c.Request.Context(), city); open {
 			psSubmissionOpen = open
 		}
 		// Get current PS selection if any
@@ -165,6 +219,8 @@ func (h *TeamHandler) GetDashboard(c *gin.Context) {
 			currentPSSelection = gin.H{
 				"problem_statement_id": sel.ProblemStatementID.String(),
 				"locked_at": sel.LockedAt.Format("2006-01-02T15:04:05Z07:00"),
+				"switch_count": sel.SwitchCount,
+				"switch": h.psSelectionService.SwitchStatus(sel),
 			}
 		}
 	}
@@ -177,19 +233,15 @@ func (h *TeamHandler) GetDashboard(c *gin.Context) {
 		"problem_statements":  problemStatements,
 		"ps_submission_open":  psSubmissionOpen,
 		"ps_selection":        currentPSSelection,
+		"access":              link.Access,
+		"read_only":           readOnly,
+		"expires_at":          link.ExpiresAt,
 	})
 }
 
 // SubmitRSVP2 handles RSVP II submission (member selection)
 // PUT /api/v1/teams/:id/rsvp2
 func (h *TeamHandler) SubmitRSVP2(c *gin.Context) {
-	// Enforce FINAL_OPEN feature flag on backend as a hard gate
-	finalMode := strings.ToLower(strings.TrimSpace(os.Getenv("FINAL_OPEN")))
-	if finalMode != "true" && finalMode != "pin" {
-		c.JSON(400, gin.H{"error": "Final Confirmation is closed"})
-		return
-	}
-
 	teamIDStr := c.Param("id")
 	teamID, err := uuid.Parse(teamIDStr)
 	if err != nil {
@@ -197,6 +249,22 @@ func (h *TeamHandler) SubmitRSVP2(c *gin.Context) {
 		return
 	}
 
+	// Enforce the rsvp2 phase for the team's city on backend as a hard gate
+	team, err := h.teamService.GetTeamByID(c.Request.Context(), teamID)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "Team not found"})
+		return
+	}
+	open, err := h.phaseOpenForTeam(c, models.PhaseRSVP2, team)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to check Final Confirmation status"})
+		return
+	}
+	if !open {
+		c.JSON(400, gin.H{"error": "Final Confirmation is closed"})
+		return
+	}
+
 	var req models.RSVP2SubmissionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(400, gin.H{"error": err.Error()})
@@ -222,7 +290,8 @@ func (h *TeamHandler) SubmitRSVP2(c *gin.Context) {
 	})
 }
 
-// LockPS locks a problem statement for the team (requires rsvp2_done, submission window open, leader email).
+// LockPS locks a problem statement for the team, or switches it within the grace period
+// (team session; requires checked_in and the PS lock window open).
 // POST /api/v1/teams/:id/lock-ps
 func (h *TeamHandler) LockPS(c *gin.Context) {
 	teamIDStr := c.Param("id")
@@ -233,7 +302,6 @@ func (h *TeamHandler) LockPS(c *gin.Context) {
 	}
 	var req struct {
 		ProblemStatementID string `json:"problem_statement_id" binding:"required"`
-		LeaderEmail        string `json:"leader_email" binding:"required,email"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(400, gin.H{"error": err.Error()})
@@ -244,7 +312,11 @@ func (h *TeamHandler) LockPS(c *gin.Context) {
 		c.JSON(400, gin.H{"error": "Invalid problem statement ID"})
 		return
 	}
-	if err := h.psSelectionService.LockPS(c.Request.Context(), teamID, psID, req.LeaderEmail); err != nil {
+	if err := h.psSelectionService.LockPS(c.Request.Context(), teamID, psID, middleware.TeamActor(c)); err != nil {
+		if errors.Is(err, repository.ErrPSFull) {
+			c.JSON(409, gin.H{"error": err.Error(), "full": true})
+			return
+		}
 		c.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/middleware"
+	"github.com/rift26/backend/internal/services"
+)
+
+type TeamWithdrawalHandler struct {
+	withdrawalService *services.TeamWithdrawalService
+}
+
+func NewTeamWithdrawalHandler(withdrawalService *services.TeamWithdrawalService) *TeamWithdrawalHandler {
+	return &TeamWithdrawalHandler{withdrawalService: withdrawalService}
+}
+
+// RequestWithdrawalOTP sends the team leader an OTP to confirm withdrawal.
+// POST /api/v1/teams/:id/withdraw/request-otp
+func (h *TeamWithdrawalHandler) RequestWithdrawalOTP(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	var req struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Leader email is required"})
+		return
+	}
+	if err := h.withdrawalService.RequestWithdrawal(c.Request.Context(), teamID, req.Email); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "OTP sent to the team leader's email"})
+}
+
+// Withdraw withdraws the team after OTP confirmation (releases desk and seat).
+// POST /api/v1/teams/:id/withdraw
+func (h *TeamWithdrawalHandler) Withdraw(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	var req struct {
+		Email   string `json:"email" binding:"required,email"`
+		OTPCode string `json:"otp_code" binding:"required,len=6"`
+		Reason  string `json:"reason" binding:"max=500"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Please provide email and the 6-digit OTP"})
+		return
+	}
+	if err := h.withdrawalService.Withdraw(c.Request.Context(), teamID, req.Email, req.OTPCode, req.Reason); err != nil {
+		if respondOTPLocked(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Team withdrawn successfully", "status": "withdrawn"})
+}
+
+// MarkNoShows marks confirmed teams of a city that never checked in as no-shows (admin).
+// POST /api/v1/admin/teams/no-shows
+// Body: city (required), dry_run (optional) — dry_run returns the candidates without changing anything;
+// force (optional) marks even while the city's checkin phase is open.
+func (h *TeamWithdrawalHandler) MarkNoShows(c *gin.Context) {
+	var req struct {
+		City   string `json:"city" binding:"required"`
+		DryRun bool   `json:"dry_run"`
+		Force  bool   `json:"force"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "City is required"})
+		return
+	}
+	result, err := h.withdrawalService.MarkNoShows(c.Request.Context(), middleware.GetEventID(c), req.City, req.DryRun, req.Force, c.GetString("user_email"))
+	if errors.Is(err, services.ErrCheckinOpen) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "result": result})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "result": result})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// Reinstate reverses a withdrawal or no-show (admin). The registration desk must be re-allocated.
+// POST /api/v1/admin/teams/:team_id/reinstate
+func (h *TeamWithdrawalHandler) Reinstate(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("team_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	status, err := h.withdrawalService.Reinstate(c.Request.Context(), teamID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Team reinstated", "status": status})
+}
@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 
+	"github.com/rift26/backend/internal/middleware"
 	"github.com/rift26/backend/internal/models"
 	"github.com/rift26/backend/internal/services"
 
@@ -17,6 +19,7 @@ func NewTicketHandler(ticketService *services.TicketService) *TicketHandler {
 	return &TicketHandler{ticketService: ticketService}
 }
 
+// CreateTicket files a support ticket for the session's team (team session).
 // POST /api/v1/tickets
 func (h *TicketHandler) CreateTicket(c *gin.Context) {
 	var req models.CreateTicketRequest
@@ -25,6 +28,18 @@ func (h *TicketHandler) CreateTicket(c *gin.Context) {
 		return
 	}
 
+	// The ticket is always filed for the session's team
+	teamID, ok := middleware.GetTeamID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Team session required"})
+		return
+	}
+	if req.TeamID != "" && !strings.EqualFold(req.TeamID, teamID.String()) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "team_id does not match your session"})
+		return
+	}
+	req.TeamID = teamID.String()
+
 	ticket, err := h.ticketService.CreateTicket(req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -81,11 +96,24 @@ func (h *TicketHandler) ResolveTicket(c *gin.Context) {
 		return
 	}
 
+	middleware.SetAuditAction(c, "ticket.resolve", "ticket", ticketID)
+	var before gin.H
+	if ticket, err := h.ticketService.GetTicketByID(ticketID); err == nil && ticket != nil {
+		before = gin.H{"status": ticket.Status, "resolution": ticket.Resolution}
+	}
+
 	err := h.ticketService.ResolveTicket(ticketID, req, adminEmail)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	// Resolutions can reopen the team's RSVP for editing, so record the grant
+	middleware.SetAuditChange(c, before, gin.H{
+		"status":       "resolved",
+		"resolution":   req.Resolution,
+		"allow_edit":   req.AllowEdit,
+		"edit_minutes": req.EditMinutes,
+	})
 
 	c.JSON(http.StatusOK, gin.H{"message": "Ticket resolved successfully"})
 }
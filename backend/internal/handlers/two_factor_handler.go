@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rift26/backend/internal/middleware"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/services"
+)
+
+type TwoFactorHandler struct {
+	twoFactorService *services.TwoFactorService
+}
+
+func NewTwoFactorHandler(twoFactorService *services.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{twoFactorService: twoFactorService}
+}
+
+// Setup starts enrollment during login when the role requires 2FA and none is set up (public).
+// POST /api/v1/auth/2fa/setup
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	var req models.TwoFactorChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	enrollment, err := h.twoFactorService.SetupChallenge(c.Request.Context(), req.ChallengeToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// Verify completes the second login step with a TOTP or recovery code and returns the session (public).
+// After a setup challenge the response also carries the new recovery codes, shown only once.
+// POST /api/v1/auth/2fa/verify
+func (h *TwoFactorHandler) Verify(c *gin.Context) {
+	var req models.TwoFactorVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Code == "" && req.RecoveryCode == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code or recovery_code is required"})
+		return
+	}
+	tokens, recoveryCodes, err := h.twoFactorService.VerifyChallenge(c.Request.Context(), req, sessionClient(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	resp := gin.H{
+		"token":              tokens.Token,
+		"refresh_token":      tokens.RefreshToken,
+		"expires_in":         tokens.ExpiresIn,
+		"refresh_expires_at": tokens.RefreshExpiresAt,
+	}
+	if tokens.Permissions != nil {
+		resp["permissions"] = tokens.Permissions
+	}
+	if recoveryCodes != nil {
+		resp["recovery_codes"] = recoveryCodes
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetStatus returns whether the caller has 2FA enabled and whether their role requires it (admin, volunteer admin).
+// GET /api/v1/auth/2fa
+func (h *TwoFactorHandler) GetStatus(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetRole(c)
+	status, err := h.twoFactorService.Status(c.Request.Context(), userID, role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// Enroll generates a new TOTP secret with its QR code; confirm it with Enable (admin, volunteer admin).
+// POST /api/v1/auth/2fa/enroll
+func (h *TwoFactorHandler) Enroll(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetRole(c)
+	enrollment, err := h.twoFactorService.BeginEnrollment(c.Request.Context(), userID, role, c.GetString("email"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// Enable confirms enrollment with a code from the authenticator app and returns recovery codes (admin, volunteer admin).
+// POST /api/v1/auth/2fa/enable
+func (h *TwoFactorHandler) Enable(c *gin.Context) {
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	codes, err := h.twoFactorService.ConfirmEnrollment(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication enabled", "recovery_codes": codes})
+}
+
+// Disable turns 2FA off with a current code, unless the role requires it (admin, volunteer admin).
+// POST /api/v1/auth/2fa/disable
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetRole(c)
+	if err := h.twoFactorService.Disable(c.Request.Context(), userID, role, req.Code); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes replaces the caller's recovery codes (admin, volunteer admin).
+// POST /api/v1/auth/2fa/recovery-codes
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// Reset removes 2FA from a locked-out admin or volunteer admin and ends their sessions (super admin).
+// POST /api/v1/admin/2fa/reset
+func (h *TwoFactorHandler) Reset(c *gin.Context) {
+	var req models.TwoFactorResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.twoFactorService.Reset(c.Request.Context(), req.SubjectID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication reset"})
+}
@@ -22,6 +22,7 @@ type VolunteerAdminHandler struct {
 	seatAllocService      *services.SeatAllocationService
 	eventTableService     *services.EventTableService
 	teamRepo              *repository.TeamRepository
+	cityService           *services.CityService
 	gormDB                *gorm.DB
 }
 
@@ -32,6 +33,7 @@ func NewVolunteerAdminHandler(
 	seatAllocService *services.SeatAllocationService,
 	eventTableService *services.EventTableService,
 	teamRepo *repository.TeamRepository,
+	cityService *services.CityService,
 	gormDB *gorm.DB,
 ) *VolunteerAdminHandler {
 	return &VolunteerAdminHandler{
@@ -41,6 +43,7 @@ func NewVolunteerAdminHandler(
 		seatAllocService:     seatAllocService,
 		eventTableService:    eventTableService,
 		teamRepo:             teamRepo,
+		cityService:          cityService,
 		gormDB:               gormDB,
 	}
 }
@@ -52,7 +55,7 @@ func (h *VolunteerAdminHandler) Login(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	resp, err := h.volunteerAdminService.Login(req.Email, req.Password)
+	resp, err := h.volunteerAdminService.Login(req.Email, req.Password, sessionClient(c))
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
@@ -69,20 +72,17 @@ func getCityFromContext(c *gin.Context) string {
 	return ""
 }
 
-// normalizeCity for comparison (BLR, bengaluru, bangalore -> BLR).
-func normalizeCityForFilter(city string) string {
-	lower := strings.ToLower(strings.TrimSpace(city))
-	switch {
-	case lower == "bengaluru" || lower == "bangalore" || lower == "blr":
-		return "BLR"
-	case lower == "pune":
-		return "PUNE"
-	case lower == "noida":
-		return "NOIDA"
-	case lower == "lucknow" || lower == "lko":
-		return "LKO"
-	}
-	return strings.ToUpper(lower)
+// normalizeCityForFilter resolves a city name or alias to its code for comparison (bengaluru, bangalore -> BLR).
+// Unknown names, and names that could not be looked up, compare as their upper-cased input.
+func (h *VolunteerAdminHandler) normalizeCityForFilter(c *gin.Context, city string) string {
+	code, ok, err := h.cityService.Normalize(c.Request.Context(), city)
+	if err != nil {
+		log.Printf("[VolunteerAdmin] normalize city %q: %v", city, err)
+	}
+	if ok {
+		return code
+	}
+	return strings.ToUpper(strings.TrimSpace(city))
 }
 
 // GetVolunteers returns volunteers for the admin's city.
@@ -92,8 +92,8 @@ func (h *VolunteerAdminHandler) GetVolunteers(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "city not set"})
 		return
 	}
-	normalized := normalizeCityForFilter(city)
-	volunteers, err := h.volunteerRepo.GetAll(&normalized, nil)
+	normalized := h.normalizeCityForFilter(c, city)
+	volunteers, err := h.volunteerRepo.GetAll(uuid.Nil, &normalized, nil)
 	if err != nil {
 		log.Printf("[VolunteerAdmin] GetVolunteers: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch volunteers", "detail": err.Error()})
@@ -109,7 +109,7 @@ func (h *VolunteerAdminHandler) GetCheckIns(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "city not set"})
 		return
 	}
-	normalized := normalizeCityForFilter(city)
+	normalized := h.normalizeCityForFilter(c, city)
 	limit := 200
 	list, err := h.checkinRepo.GetRecentByCity(normalized, limit)
 	if err != nil {
@@ -127,7 +127,7 @@ func (h *VolunteerAdminHandler) GetCheckInTeams(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "city not set"})
 		return
 	}
-	normalized := normalizeCityForFilter(city)
+	normalized := h.normalizeCityForFilter(c, city)
 	limit := 200
 	if l := c.Query("limit"); l != "" {
 		if n, err := parseInt(l, 10, 1, 500); err == nil {
@@ -158,9 +158,9 @@ func (h *VolunteerAdminHandler) GetTables(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "city not set"})
 		return
 	}
-	normalized := normalizeCityForFilter(city)
+	normalized := h.normalizeCityForFilter(c, city)
 	isActive := true
-	tables, err := h.eventTableService.GetAllEventTables(&normalized, &isActive)
+	tables, err := h.eventTableService.GetAllEventTables(uuid.Nil, &normalized, &isActive)
 	if err != nil {
 		log.Printf("[VolunteerAdmin] GetTables: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tables", "detail": err.Error()})
@@ -197,7 +197,7 @@ func (h *VolunteerAdminHandler) GetTeamDetails(c *gin.Context) {
 	if team.City != nil {
 		teamCityStr = string(*team.City)
 	}
-	if normalizeCityForFilter(teamCityStr) != normalizeCityForFilter(city) {
+	if h.normalizeCityForFilter(c, teamCityStr) != h.normalizeCityForFilter(c, city) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Team not in your city"})
 		return
 	}
@@ -238,16 +238,15 @@ func parseInt(s string, base, min, max int) (int, error) {
 	return n, nil
 }
 
-// GetSeatSummary returns seat allocation summary for the city (Bengaluru only has seats).
+// GetSeatSummary returns seat allocation summary for the city (only cities with seat allocation have seats).
 func (h *VolunteerAdminHandler) GetSeatSummary(c *gin.Context) {
 	city := getCityFromContext(c)
 	if city == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "city not set"})
 		return
 	}
-	normalized := normalizeCityForFilter(city)
-	if normalized != "BLR" {
-		c.JSON(http.StatusOK, gin.H{"city": city, "seat_allocation_available": false, "message": "Seat allocation is only for Bengaluru"})
+	if !h.cityService.HasSeatAllocation(city) {
+		c.JSON(http.StatusOK, gin.H{"city": city, "seat_allocation_available": false, "message": "Seat allocation is not enabled for this city"})
 		return
 	}
 	if h.seatAllocService == nil {
@@ -287,7 +286,7 @@ func (h *VolunteerAdminHandler) CreateVolunteerAdmin(c *gin.Context) {
 
 // GetAllVolunteerAdmins (admin only) lists all volunteer admins.
 func (h *VolunteerAdminHandler) GetAllVolunteerAdmins(c *gin.Context) {
-	list, err := h.volunteerAdminService.GetAll()
+	list, err := h.volunteerAdminService.GetAll(middleware.GetEventID(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
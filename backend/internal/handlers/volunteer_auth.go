@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/rift26/backend/internal/middleware"
 	"github.com/rift26/backend/internal/models"
 	"github.com/rift26/backend/internal/services"
 
@@ -26,7 +27,7 @@ func (h *VolunteerAuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := h.service.Login(req.Email, req.Password)
+	response, err := h.service.Login(req.Email, req.Password, sessionClient(c))
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
@@ -97,7 +98,7 @@ func (h *VolunteerAuthHandler) GetAllVolunteers(c *gin.Context) {
 		}
 	}
 
-	volunteers, err := h.service.GetAllVolunteers(cityPtr, tableIDPtr)
+	volunteers, err := h.service.GetAllVolunteers(middleware.GetEventID(c), cityPtr, tableIDPtr)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
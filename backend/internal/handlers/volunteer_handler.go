@@ -21,6 +21,7 @@ type VolunteerHandler struct {
 	teamRepo               *repository.TeamRepository
 	volunteerRepo          *repository.VolunteerRepository
 	seatAllocationService  *services.SeatAllocationService
+	cityService            *services.CityService
 }
 
 func NewVolunteerHandler(
@@ -29,6 +30,7 @@ func NewVolunteerHandler(
 	teamRepo *repository.TeamRepository,
 	volunteerRepo *repository.VolunteerRepository,
 	seatAllocationService *services.SeatAllocationService,
+	cityService *services.CityService,
 ) *VolunteerHandler {
 	return &VolunteerHandler{
 		checkinService:         checkinService,
@@ -36,6 +38,7 @@ func NewVolunteerHandler(
 		teamRepo:               teamRepo,
 		volunteerRepo:          volunteerRepo,
 		seatAllocationService:  seatAllocationService,
+		cityService:            cityService,
 	}
 }
 
@@ -105,6 +108,7 @@ func (h *VolunteerHandler) CheckInParticipants(c *gin.Context) {
 	volunteerCity, _ := c.Get("city")
 	cityStr, _ := volunteerCity.(string)
 
+	middleware.SetAuditAction(c, "checkin.participants", "team", req.TeamID.String())
 	// Get team details early to validate location (city) and eligibility
 	team, err := h.teamRepo.GetByID(c.Request.Context(), req.TeamID)
 	if err != nil || team == nil {
@@ -241,6 +245,7 @@ func (h *VolunteerHandler) UndoCheckIn(c *gin.Context) {
 		return
 	}
 
+	middleware.SetAuditAction(c, "checkin.undo", "team", teamID.String())
 	err = h.participantCheckinRepo.DeleteByTeamID(teamID, volunteerID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -268,6 +273,7 @@ func (h *VolunteerHandler) ConfirmTable(c *gin.Context) {
 		return
 	}
 
+	middleware.SetAuditAction(c, "table.confirm", "team", req.TeamID.String())
 	// Update team status to checked_in
 	err := h.teamRepo.UpdateTeamStatus(c.Request.Context(), req.TeamID, models.StatusCheckedIn)
 	if err != nil {
@@ -291,7 +297,7 @@ func (h *VolunteerHandler) ConfirmTable(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Team confirmed successfully"})
 }
 
-// AllocateSeat allocates a Bengaluru seat to a team (table volunteer).
+// AllocateSeat allocates a seat to a team whose city uses seat allocation (table volunteer).
 // POST /api/v1/table/allocate-seat
 // Body: team_id (required), block_name (optional) — e.g. "A (17th Floor)" or "B (12th Floor)". If block is full, allocates in another block.
 func (h *VolunteerHandler) AllocateSeat(c *gin.Context) {
@@ -310,14 +316,15 @@ func (h *VolunteerHandler) AllocateSeat(c *gin.Context) {
 		return
 	}
 
-	// Verify team is from Bengaluru (seat allocation only available for BLR)
+	middleware.SetAuditAction(c, "table.allocate_seat", "team", req.TeamID.String())
+	// Verify the team's city uses seat allocation
 	team, err := h.teamRepo.GetByID(c.Request.Context(), req.TeamID)
 	if err != nil || team == nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Team not found"})
 		return
 	}
-	if team.City == nil || string(*team.City) != "BLR" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Seat allocation only available for Bengaluru teams"})
+	if team.City == nil || !h.cityService.HasSeatAllocation(string(*team.City)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Seat allocation is not available for this team's city"})
 		return
 	}
 
@@ -519,7 +526,7 @@ func (h *VolunteerHandler) GetPendingTeams(c *gin.Context) {
 			continue // Skip if team is nil
 		}
 
-		// Normalize city comparison (handle case differences and "Bangalore" vs "BLR")
+		// Normalize city comparison (handle case differences and aliases such as "Bangalore" vs "BLR")
 		teamCityStr := ""
 		if team.City != nil {
 			teamCityStr = string(*team.City)
@@ -527,12 +534,14 @@ func (h *VolunteerHandler) GetPendingTeams(c *gin.Context) {
 
 		// Normalize both cities for comparison (case-insensitive)
 		normalizeCity := func(city string) string {
-			cityLower := strings.ToLower(strings.TrimSpace(city))
-			if cityLower == "bangalore" || cityLower == "bengaluru" || cityLower == "blr" {
-				return "BLR"
+			code, ok, err := h.cityService.Normalize(c.Request.Context(), city)
+			if err != nil {
+				fmt.Printf("[GetPendingTeams] Error resolving city %q: %v\n", city, err)
 			}
-			// Return uppercase for other cities (PUNE, NOIDA, LKO)
-			return strings.ToUpper(cityLower)
+			if ok {
+				return code
+			}
+			return strings.ToUpper(strings.TrimSpace(city))
 		}
 
 		teamCityNormalized := normalizeCity(teamCityStr)
@@ -554,8 +563,8 @@ func (h *VolunteerHandler) GetPendingTeams(c *gin.Context) {
 			"participants_count": len(participants),
 		}
 
-		// Check if team already has a seat allocated (for BLR teams)
-		if teamCityNormalized == "BLR" && h.seatAllocationService != nil {
+		// Check if team already has a seat allocated (for cities with seat allocation)
+		if h.cityService.HasSeatAllocation(teamCityNormalized) && h.seatAllocationService != nil {
 			allocation, err := h.seatAllocationService.GetTeamAllocation(teamID)
 			if err == nil && allocation != nil {
 				teamData["seat_allocation"] = gin.H{
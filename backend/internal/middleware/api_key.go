@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/services"
+)
+
+// APIKeyMiddleware authenticates integrations by API key, taken from the X-API-Key header
+// or "Authorization: Bearer rk_...", and requires the given scope.
+// A city-restricted key sets "city" in the context like a volunteer token does.
+func APIKeyMiddleware(keys *services.APIKeyService, scope models.APIKeyScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		credential := c.GetHeader("X-API-Key")
+		if credential == "" {
+			credential = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		}
+		if !services.IsAPIKey(credential) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "API key required"})
+			c.Abort()
+			return
+		}
+
+		key, err := keys.Authenticate(c.Request.Context(), credential, c.ClientIP())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify API key"})
+			c.Abort()
+			return
+		}
+		if key == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or revoked API key"})
+			c.Abort()
+			return
+		}
+		if !key.HasScope(scope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "API key lacks scope " + string(scope)})
+			c.Abort()
+			return
+		}
+
+		c.Set("api_key_id", key.ID)
+		if key.City != nil {
+			c.Set("city", *key.City)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+)
+
+// auditBodyLimit caps how much of a request body is kept as the after snapshot
+const auditBodyLimit = 64 << 10
+
+// AuditRecorder stores audit log entries (implemented by services.AuditService)
+type AuditRecorder interface {
+	Record(ctx context.Context, entry *models.AuditLog) error
+}
+
+// auditState is what a handler adds to the automatic entry
+type auditState struct {
+	action     string
+	targetType string
+	targetID   string
+	before     interface{}
+	after      interface{}
+	hasChange  bool
+}
+
+// Audit records every mutating request (anything but GET/HEAD/OPTIONS) on the group.
+// Register it after AuthMiddleware and RoleMiddleware so only authorized actors are logged.
+// By default the action is the handler name, the target is the route's path parameter and
+// the after snapshot is the JSON request body with secrets redacted; handlers refine this
+// with SetAuditAction and SetAuditChange.
+func Audit(recorder AuditRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		body := readAuditBody(c)
+		state := &auditState{}
+		c.Set("audit", state)
+
+		c.Next()
+
+		entry := &models.AuditLog{
+			ActorEmail: c.GetString("user_email"),
+			IP:         c.ClientIP(),
+			UserAgent:  c.Request.UserAgent(),
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			Action:     state.action,
+			TargetType: state.targetType,
+			TargetID:   state.targetID,
+			StatusCode: c.Writer.Status(),
+		}
+		if id, ok := GetUserID(c); ok {
+			entry.ActorID = &id
+		}
+		if role, ok := GetRole(c); ok {
+			entry.ActorRole = string(role)
+		}
+		if eventID := GetEventID(c); eventID != uuid.Nil {
+			entry.EventID = &eventID
+		}
+		if entry.Action == "" {
+			entry.Action = auditHandlerName(c.HandlerName())
+		}
+		if entry.TargetType == "" && len(c.Params) > 0 {
+			entry.TargetType, entry.TargetID = c.Params[0].Key, c.Params[0].Value
+		}
+		if state.hasChange {
+			entry.Before = auditJSON(state.before)
+			entry.After = auditJSON(state.after)
+		} else {
+			entry.After = body
+		}
+
+		if err := recorder.Record(c.Request.Context(), entry); err != nil {
+			log.Printf("[Audit] Failed to record %s %s by %s: %v", entry.Method, entry.Path, entry.ActorEmail, err)
+		}
+	}
+}
+
+// SetAuditAction names the action and its target for the current request's audit entry
+func SetAuditAction(c *gin.Context, action, targetType, targetID string) {
+	if state, ok := c.Get("audit"); ok {
+		s := state.(*auditState)
+		s.action, s.targetType, s.targetID = action, targetType, targetID
+	}
+}
+
+// SetAuditChange records the target's state before and after the action (either may be nil)
+func SetAuditChange(c *gin.Context, before, after interface{}) {
+	if state, ok := c.Get("audit"); ok {
+		s := state.(*auditState)
+		s.before, s.after, s.hasChange = before, after, true
+	}
+}
+
+// auditHandlerName shortens "github.com/.../handlers.(*AdminHandler).ClearAllData-fm" to "AdminHandler.ClearAllData"
+func auditHandlerName(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSuffix(name, "-fm")
+	return strings.NewReplacer("(*", "", ")", "").Replace(name)
+}
+
+// readAuditBody returns the redacted JSON request body and restores it for the handler.
+// Non-JSON bodies (uploads) are not kept.
+func readAuditBody(c *gin.Context) json.RawMessage {
+	if c.Request.Body == nil || !strings.HasPrefix(c.ContentType(), "application/json") {
+		return nil
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if len(body) == 0 || len(body) > auditBodyLimit {
+		return nil
+	}
+
+	var v interface{}
+	if json.Unmarshal(body, &v) != nil {
+		return nil
+	}
+	return auditJSON(v)
+}
+
+// auditJSON marshals a snapshot with secrets redacted
+func auditJSON(v interface{}) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var generic interface{}
+	if json.Unmarshal(raw, &generic) != nil {
+		return nil
+	}
+	out, err := json.Marshal(redactAudit(generic))
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+// redactAudit blanks values of keys that look like credentials
+func redactAudit(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			key := strings.ToLower(k)
+			switch {
+			case strings.Contains(key, "password"), strings.Contains(key, "secret"), strings.Contains(key, "token"),
+				key == "otp", key == "otp_code", key == "pin", key == "code", key == "recovery_code":
+				t[k] = "[redacted]"
+			default:
+				t[k] = redactAudit(val)
+			}
+		}
+	case []interface{}:
+		for i, val := range t {
+			t[i] = redactAudit(val)
+		}
+	}
+	return v
+}
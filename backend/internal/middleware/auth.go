@@ -2,17 +2,17 @@ package middleware
 
 import (
 	"fmt"
+	"log"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/rift26/backend/internal/models"
-	"github.com/rift26/backend/internal/utils"
+	"github.com/rift26/backend/internal/services"
 )
 
-// AuthMiddleware validates JWT tokens
-func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+// AuthMiddleware validates JWT tokens and rejects tokens of revoked sessions
+func AuthMiddleware(sessions *services.SessionService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -31,8 +31,8 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 
 		token := parts[1]
 
-		// Validate JWT
-		claims, err := utils.ValidateJWT(token, jwtSecret)
+		// Validate JWT (signature, expiry and revocation)
+		claims, err := sessions.ParseAccessToken(token)
 		if err != nil {
 			fmt.Printf("[AuthMiddleware] Token Validation Failed: %v\n", err)
 			c.JSON(401, gin.H{"error": "Invalid or expired token"})
@@ -51,19 +51,11 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 	}
 	c.Set("email", claims.Email)
 	c.Set("user_email", claims.Email) // Also set as user_email for compatibility
+	c.Set("permissions", claims.Permissions)
 
-	// Extract additional claims from raw token for volunteers (city)
-	// Re-parse as MapClaims to get volunteer-specific fields
-	rawToken := parts[1]
-	if mapToken, err := jwt.Parse(rawToken, func(token *jwt.Token) (interface{}, error) {
-		return []byte(jwtSecret), nil
-	}); err == nil {
-		if mapClaims, ok := mapToken.Claims.(jwt.MapClaims); ok {
-			// Extract city
-			if city, ok := mapClaims["city"].(string); ok {
-				c.Set("city", city)
-			}
-		}
+	// Volunteer and volunteer admin tokens carry their city
+	if claims.City != "" {
+		c.Set("city", claims.City)
 	}
 
 	c.Next()
@@ -97,6 +89,32 @@ func RoleMiddleware(allowedRoles ...models.UserRole) gin.HandlerFunc {
 	}
 }
 
+// RequirePermission checks that the admin holds a permission (or "*"). Use after RoleMiddleware("admin").
+func RequirePermission(perm models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !models.HasPermission(GetPermissions(c), perm) {
+			log.Printf("[RequirePermission] %s %s denied: missing %s", c.Request.Method, c.FullPath(), perm)
+			c.JSON(403, gin.H{"error": "Forbidden: missing permission " + string(perm)})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAdminPermission checks the permission for admins only; other roles let through by
+// RoleMiddleware (e.g. volunteers on the scanner routes) pass unchanged.
+func RequireAdminPermission(perm models.Permission) gin.HandlerFunc {
+	check := RequirePermission(perm)
+	return func(c *gin.Context) {
+		if role, _ := GetRole(c); role != models.UserRoleAdmin {
+			c.Next()
+			return
+		}
+		check(c)
+	}
+}
+
 // Helper functions to get claims from context
 func GetUserID(c *gin.Context) (uuid.UUID, bool) {
 	userIDValue, exists := c.Get("user_id")
@@ -124,3 +142,12 @@ func GetRole(c *gin.Context) (models.UserRole, bool) {
 	role, ok := roleValue.(models.UserRole)
 	return role, ok
 }
+
+func GetPermissions(c *gin.Context) []string {
+	permsValue, exists := c.Get("permissions")
+	if !exists {
+		return nil
+	}
+	perms, _ := permsValue.([]string)
+	return perms
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/services"
+)
+
+// EventScopeMiddleware resolves the event an admin request operates on.
+// The event is taken from the event_id query parameter or the X-Event-ID header and
+// defaults to the current event, so existing clients keep working unchanged.
+func EventScopeMiddleware(eventService *services.EventService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := strings.TrimSpace(c.Query("event_id"))
+		if raw == "" {
+			raw = strings.TrimSpace(c.GetHeader("X-Event-ID"))
+		}
+
+		if raw == "" {
+			event, err := eventService.Current(c.Request.Context())
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve current event"})
+				c.Abort()
+				return
+			}
+			c.Set("event_id", event.ID)
+			c.Next()
+			return
+		}
+
+		eventID, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+			c.Abort()
+			return
+		}
+		event, err := eventService.Get(c.Request.Context(), eventID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve event"})
+			c.Abort()
+			return
+		}
+		if event == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			c.Abort()
+			return
+		}
+		c.Set("event_id", event.ID)
+		c.Next()
+	}
+}
+
+// GetEventID returns the event resolved by EventScopeMiddleware, or uuid.Nil (current event).
+func GetEventID(c *gin.Context) uuid.UUID {
+	v, exists := c.Get("event_id")
+	if !exists {
+		return uuid.Nil
+	}
+	id, _ := v.(uuid.UUID)
+	return id
+}
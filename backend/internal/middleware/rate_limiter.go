@@ -1,64 +1,110 @@
 package middleware
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-type rateLimiter struct {
-	requests    map[string][]time.Time
-	mu          sync.RWMutex
-	maxRequests int
-	window      time.Duration
+// RateLimitStore counts hits per key in a sliding window.
+// Allow records a hit and returns true while key is under limit.
+type RateLimitStore interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error)
 }
 
-func newRateLimiter(maxRequests int, window time.Duration) *rateLimiter {
-	return &rateLimiter{
-		requests:    make(map[string][]time.Time),
-		maxRequests: maxRequests,
-		window:      window,
-	}
+// rateLimitStore backs every rate limit middleware. Defaults to in-process memory;
+// main swaps in the Postgres store so limits are shared across replicas and deploys.
+var rateLimitStore RateLimitStore = NewMemoryRateLimitStore()
+
+// SetRateLimitStore replaces the store used by all rate limit middleware
+func SetRateLimitStore(store RateLimitStore) {
+	rateLimitStore = store
+}
+
+// memoryBucket holds the counts of the current and previous fixed window for one key
+type memoryBucket struct {
+	window   time.Duration
+	start    time.Time
+	current  int
+	previous int
+}
+
+// MemoryRateLimitStore is a single-instance store. Idle keys are evicted.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: make(map[string]*memoryBucket), lastSweep: time.Now()}
 }
 
-func (rl *rateLimiter) allow(key string) bool {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
+func (s *MemoryRateLimitStore) Allow(_ context.Context, key string, limit int, window time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	now := time.Now()
-	cutoff := now.Add(-rl.window)
-
-	// Clean up old requests
-	times := rl.requests[key]
-	validTimes := []time.Time{}
-	for _, t := range times {
-		if t.After(cutoff) {
-			validTimes = append(validTimes, t)
-		}
-	}
+	start := now.Truncate(window)
+	s.sweep(now)
 
-	// Check if under limit
-	if len(validTimes) >= rl.maxRequests {
-		rl.requests[key] = validTimes
-		return false
+	b := s.buckets[key]
+	switch {
+	case b == nil:
+		b = &memoryBucket{window: window, start: start}
+		s.buckets[key] = b
+	case b.start.Equal(start.Add(-window)):
+		b.start, b.previous, b.current = start, b.current, 0
+	case !b.start.Equal(start):
+		b.start, b.previous, b.current = start, 0, 0
 	}
 
-	// Add current request
-	validTimes = append(validTimes, now)
-	rl.requests[key] = validTimes
-	return true
+	weight := 1 - float64(now.Sub(start))/float64(window)
+	if float64(b.previous)*weight+float64(b.current) >= float64(limit) {
+		return false, nil
+	}
+	b.current++
+	return true, nil
 }
 
-// RateLimitMiddleware limits requests per IP address
-func RateLimitMiddleware(maxRequests int, window time.Duration) gin.HandlerFunc {
-	limiter := newRateLimiter(maxRequests, window)
+// sweep evicts keys with no hits in their last two windows, at most once a minute
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if now.Sub(b.start) > 2*b.window {
+			delete(s.buckets, key)
+		}
+	}
+}
 
+// rateLimit builds a middleware that limits hits per key. keyFn returns "" to skip limiting.
+// Keys are namespaced by route so separate limits don't share counters.
+// Store errors are logged and the request is let through.
+func rateLimit(maxRequests int, window time.Duration, kind string, keyFn func(c *gin.Context) string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Use IP address as key
-		key := c.ClientIP()
+		value := keyFn(c)
+		if value == "" {
+			c.Next()
+			return
+		}
+		key := c.Request.Method + " " + c.FullPath() + "|" + kind + ":" + value
 
-		if !limiter.allow(key) {
+		allowed, err := rateLimitStore.Allow(c.Request.Context(), key, maxRequests, window)
+		if err != nil {
+			log.Printf("[RateLimit] Store error for %s: %v", key, err)
+			allowed = true
+		}
+		if !allowed {
 			c.JSON(429, gin.H{
 				"error": "Rate limit exceeded. Please try again later.",
 			})
@@ -69,3 +115,39 @@ func RateLimitMiddleware(maxRequests int, window time.Duration) gin.HandlerFunc
 		c.Next()
 	}
 }
+
+// RateLimitMiddleware limits requests per IP address
+func RateLimitMiddleware(maxRequests int, window time.Duration) gin.HandlerFunc {
+	return rateLimit(maxRequests, window, "ip", func(c *gin.Context) string {
+		return c.ClientIP()
+	})
+}
+
+// RateLimitByParam limits requests per value of a path parameter (e.g. a team ID)
+func RateLimitByParam(maxRequests int, window time.Duration, param string) gin.HandlerFunc {
+	return rateLimit(maxRequests, window, param, func(c *gin.Context) string {
+		return c.Param(param)
+	})
+}
+
+// RateLimitByBodyField limits requests per value of a top-level JSON body field
+// (e.g. "team_id" or "email"). The body is restored for the handler.
+func RateLimitByBodyField(maxRequests int, window time.Duration, field string) gin.HandlerFunc {
+	return rateLimit(maxRequests, window, field, func(c *gin.Context) string {
+		if c.Request.Body == nil {
+			return ""
+		}
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, 1<<20))
+		if err != nil {
+			return ""
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		var fields map[string]interface{}
+		if json.Unmarshal(body, &fields) != nil {
+			return ""
+		}
+		value, _ := fields[field].(string)
+		return strings.ToLower(strings.TrimSpace(value))
+	})
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/services"
+)
+
+// TeamSessionMiddleware requires a team-scoped session for dashboard actions: either the team's
+// full-access dashboard link token in the X-Dashboard-Token header, or a team access token (email OTP
+// or magic link) as "Authorization: Bearer <jwt>". The team is set as "team_id" in the context. On
+// routes with an :id path parameter the session's team must be that team. Dashboard link use is logged.
+func TeamSessionMiddleware(sessions *services.SessionService, dashboards *services.DashboardTokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var teamID uuid.UUID
+		if token := strings.TrimSpace(c.GetHeader("X-Dashboard-Token")); token != "" {
+			link, err := dashboards.Resolve(c.Request.Context(), token)
+			if err != nil {
+				var linkErr *services.DashboardLinkError
+				if errors.As(err, &linkErr) {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": linkErr.Reason})
+				} else {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dashboard token"})
+				}
+				c.Abort()
+				return
+			}
+			if link.Access != models.DashboardAccessFull {
+				c.JSON(http.StatusForbidden, gin.H{"error": "This dashboard link is read-only"})
+				c.Abort()
+				return
+			}
+			dashboards.RecordAccess(c.Request.Context(), link, c.ClientIP(), c.Request.UserAgent(), c.Request.Method, c.FullPath())
+			teamID = link.TeamID
+			c.Set("team_auth", "dashboard")
+		} else {
+			token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Team session required: sign in or open your dashboard link"})
+				c.Abort()
+				return
+			}
+			claims, err := sessions.ParseAccessToken(token)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+				c.Abort()
+				return
+			}
+			if claims.TeamID == nil {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Token is not a team session"})
+				c.Abort()
+				return
+			}
+			teamID = *claims.TeamID
+			c.Set("user_id", claims.UserID)
+			c.Set("role", claims.Role)
+			c.Set("email", claims.Email)
+			c.Set("user_email", claims.Email)
+			c.Set("team_auth", "session")
+		}
+		c.Set("team_id", teamID)
+
+		if !matchTeamParam(c, teamID) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// TeamActor names who is acting in a team session: the signed-in email, or "dashboard link"
+func TeamActor(c *gin.Context) string {
+	if email := c.GetString("user_email"); email != "" {
+		return email
+	}
+	return "dashboard link"
+}
+
+// RequireTeamParam requires the team access token set by AuthMiddleware to be for the team in
+// the :id path parameter. Use after AuthMiddleware on leader-only team routes.
+func RequireTeamParam() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		teamID, ok := GetTeamID(c)
+		if !ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Team leader sign-in required"})
+			c.Abort()
+			return
+		}
+		if !matchTeamParam(c, teamID) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// matchTeamParam aborts and returns false unless the :id path parameter (when present) is teamID
+func matchTeamParam(c *gin.Context, teamID uuid.UUID) bool {
+	param := c.Param("id")
+	if param == "" {
+		return true
+	}
+	pathTeamID, err := uuid.Parse(param)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		c.Abort()
+		return false
+	}
+	if pathTeamID != teamID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Your session belongs to a different team"})
+		c.Abort()
+		return false
+	}
+	return true
+}
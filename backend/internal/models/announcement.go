@@ -98,7 +98,7 @@ type Ticket struct {
 }
 
 type CreateTicketRequest struct {
-	TeamID  string `json:"team_id" binding:"required"`
+	TeamID  string `json:"team_id"` // optional; must match the team session
 	Subject string `json:"subject" binding:"required,min=5,max=255"`
 	Message string `json:"message" binding:"required,min=10"`
 }
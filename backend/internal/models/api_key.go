@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyScope is a read capability granted to an API key.
+type APIKeyScope string
+
+const (
+	ScopeStatsRead         APIKeyScope = "stats:read"         // team and check-in counts
+	ScopeAnnouncementsRead APIKeyScope = "announcements:read" // active general announcements
+	ScopeRoomsRead         APIKeyScope = "rooms:read"         // room capacity and occupancy
+)
+
+// APIKeyScopeInfo describes a scope for the key editor.
+type APIKeyScopeInfo struct {
+	Key         APIKeyScope `json:"key"`
+	Description string      `json:"description"`
+}
+
+// APIKeyScopes is the catalog of assignable scopes.
+var APIKeyScopes = []APIKeyScopeInfo{
+	{ScopeStatsRead, "Read team, RSVP and check-in counts"},
+	{ScopeAnnouncementsRead, "Read active announcements shown to all teams"},
+	{ScopeRoomsRead, "Read room capacity and live occupancy"},
+}
+
+// IsValidAPIKeyScope reports whether s is in the catalog.
+func IsValidAPIKeyScope(s APIKeyScope) bool {
+	for _, info := range APIKeyScopes {
+		if info.Key == s {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey is an integration credential. The key itself is only shown once, on creation.
+type APIKey struct {
+	ID         uuid.UUID     `json:"id"`
+	Name       string        `json:"name"`
+	Prefix     string        `json:"prefix"`
+	Scopes     []APIKeyScope `json:"scopes"`
+	City       *string       `json:"city,omitempty"` // restricts data to one city when set
+	CreatedBy  *uuid.UUID    `json:"created_by,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	ExpiresAt  *time.Time    `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time    `json:"last_used_at,omitempty"`
+	LastUsedIP *string       `json:"last_used_ip,omitempty"`
+	RevokedAt  *time.Time    `json:"revoked_at,omitempty"`
+}
+
+// HasScope reports whether the key grants scope.
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAPIKeyRequest creates a key. City is a city code or alias; empty means all cities.
+type CreateAPIKeyRequest struct {
+	Name      string        `json:"name" binding:"required,max=100"`
+	Scopes    []APIKeyScope `json:"scopes" binding:"required,min=1"`
+	City      string        `json:"city"`
+	ExpiresAt *time.Time    `json:"expires_at"`
+}
+
+// CreatedAPIKey is returned once on creation and carries the plaintext key.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
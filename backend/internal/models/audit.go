@@ -0,0 +1,54 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLog is one recorded admin or volunteer-admin mutation.
+// Before and After are JSON snapshots; Diff lists the top-level fields that changed.
+type AuditLog struct {
+	ID         uuid.UUID       `json:"id"`
+	EventID    *uuid.UUID      `json:"event_id,omitempty"`
+	ActorID    *uuid.UUID      `json:"actor_id,omitempty"`
+	ActorEmail string          `json:"actor_email"`
+	ActorRole  string          `json:"actor_role"`
+	IP         string          `json:"ip"`
+	UserAgent  string          `json:"user_agent,omitempty"`
+	Method     string          `json:"method"`
+	Path       string          `json:"path"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type,omitempty"`
+	TargetID   string          `json:"target_id,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	Diff       json.RawMessage `json:"diff,omitempty"`
+	StatusCode int             `json:"status_code"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditLogFilter narrows the audit log. Empty fields match everything.
+// Status is "success" (2xx/3xx) or "failed".
+type AuditLogFilter struct {
+	ActorID    string `form:"actor_id"`
+	ActorEmail string `form:"actor_email"`
+	Role       string `form:"role"`
+	Action     string `form:"action"`
+	TargetType string `form:"target_type"`
+	TargetID   string `form:"target_id"`
+	Status     string `form:"status" binding:"omitempty,oneof=success failed"`
+	From       string `form:"from"` // RFC3339 or YYYY-MM-DD
+	To         string `form:"to"`
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	PageSize   int    `form:"page_size" binding:"omitempty,min=1,max=200"`
+}
+
+// AuditLogPage is one page of audit log entries, newest first.
+type AuditLogPage struct {
+	Entries  []AuditLog `json:"entries"`
+	Total    int        `json:"total"`
+	Page     int        `json:"page"`
+	PageSize int        `json:"page_size"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// CityConfig is a row of the cities table. Code is what teams, tables and volunteers store (e.g. "BLR").
+type CityConfig struct {
+	Code           string    `json:"code" db:"code"`
+	Name           string    `json:"name" db:"name"`
+	Aliases        []string  `json:"aliases" db:"aliases"` // lower-case names matched on import, e.g. "bangalore"
+	Timezone       string    `json:"timezone" db:"timezone"`
+	VenueName      *string   `json:"venue_name,omitempty" db:"venue_name"`
+	VenueAddress   *string   `json:"venue_address,omitempty" db:"venue_address"`
+	VenueMapsURL   *string   `json:"venue_maps_url,omitempty" db:"venue_maps_url"`
+	SeatAllocation bool      `json:"seat_allocation" db:"seat_allocation"` // city uses block/room seat allocation
+	IsActive       bool      `json:"is_active" db:"is_active"`
+	SortOrder      int       `json:"sort_order" db:"sort_order"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Request DTOs
+type CreateCityRequest struct {
+	Code           string   `json:"code" binding:"required,max=20"`
+	Name           string   `json:"name" binding:"required,max=100"`
+	Aliases        []string `json:"aliases"`
+	Timezone       string   `json:"timezone"`
+	VenueName      *string  `json:"venue_name"`
+	VenueAddress   *string  `json:"venue_address"`
+	VenueMapsURL   *string  `json:"venue_maps_url"`
+	SeatAllocation bool     `json:"seat_allocation"`
+	SortOrder      int      `json:"sort_order"`
+}
+
+type UpdateCityRequest struct {
+	Name           *string   `json:"name" binding:"omitempty,max=100"`
+	Aliases        *[]string `json:"aliases"`
+	Timezone       *string   `json:"timezone"`
+	VenueName      *string   `json:"venue_name"`
+	VenueAddress   *string   `json:"venue_address"`
+	VenueMapsURL   *string   `json:"venue_maps_url"`
+	SeatAllocation *bool     `json:"seat_allocation"`
+	IsActive       *bool     `json:"is_active"`
+	SortOrder      *int      `json:"sort_order"`
+}
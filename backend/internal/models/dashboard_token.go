@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DashboardAccess is what a dashboard link allows.
+type DashboardAccess string
+
+const (
+	DashboardAccessFull     DashboardAccess = "full"      // leader's link: dashboard, QR code and team actions
+	DashboardAccessReadOnly DashboardAccess = "read_only" // member link: view only, no QR code or contact details
+)
+
+// DashboardToken is one dashboard link of a team. The token itself is only stored hashed.
+type DashboardToken struct {
+	ID         uuid.UUID       `json:"id"`
+	TeamID     uuid.UUID       `json:"team_id"`
+	Prefix     string          `json:"prefix"`
+	Access     DashboardAccess `json:"access"`
+	CreatedBy  *string         `json:"created_by,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	ExpiresAt  *time.Time      `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time      `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time      `json:"revoked_at,omitempty"`
+}
+
+// IsExpired reports whether the link is past its expiry.
+func (t *DashboardToken) IsExpired() bool {
+	return t.ExpiresAt != nil && !t.ExpiresAt.After(time.Now())
+}
+
+// IssuedDashboardToken is returned once when a link is generated and carries the plaintext token.
+type IssuedDashboardToken struct {
+	DashboardToken
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// DashboardAccessLog is one request made with a dashboard link.
+type DashboardAccessLog struct {
+	ID         int64     `json:"id"`
+	TokenID    uuid.UUID `json:"token_id"`
+	TeamID     uuid.UUID `json:"team_id"`
+	IP         *string   `json:"ip,omitempty"`
+	UserAgent  *string   `json:"user_agent,omitempty"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	AccessedAt time.Time `json:"accessed_at"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is one edition of the hackathon (e.g. RIFT '26) or a side event.
+// Teams, volunteers, tables, problem statements, submissions, announcements, certificates,
+// phases and settings all belong to an event. Exactly one event is current.
+type Event struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	Slug      string     `json:"slug" db:"slug"`
+	Name      string     `json:"name" db:"name"`
+	StartsAt  *time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at" db:"ends_at"`
+	IsCurrent bool       `json:"is_current" db:"is_current"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Request DTOs
+type CreateEventRequest struct {
+	Slug     string     `json:"slug" binding:"required,max=50"`
+	Name     string     `json:"name" binding:"required,max=255"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
+
+type UpdateEventRequest struct {
+	Name     *string    `json:"name" binding:"omitempty,max=255"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
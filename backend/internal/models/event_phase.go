@@ -0,0 +1,113 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventPhaseKey identifies a gated phase of the event
+type EventPhaseKey string
+
+const (
+	PhaseRSVP1           EventPhaseKey = "rsvp1"            // RSVP I form
+	PhaseRSVP2           EventPhaseKey = "rsvp2"            // RSVP II / Final Confirmation form
+	PhasePSRelease       EventPhaseKey = "ps_release"       // Problem statements visible
+	PhasePSLock          EventPhaseKey = "ps_lock"          // Teams can lock a problem statement
+	PhaseFinalSubmission EventPhaseKey = "final_submission" // Project submission portal
+	PhaseCheckin         EventPhaseKey = "checkin"          // Venue check-in; no-shows are only marked once it closes
+)
+
+// AllEventPhases lists every phase in event order
+var AllEventPhases = []EventPhaseKey{PhaseRSVP1, PhaseRSVP2, PhasePSRelease, PhasePSLock, PhaseCheckin, PhaseFinalSubmission}
+
+// IsValidEventPhase reports whether key is a known phase
+func IsValidEventPhase(key string) bool {
+	for _, p := range AllEventPhases {
+		if string(p) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// PhaseMode is the access mode of a phase
+type PhaseMode string
+
+const (
+	PhaseModeOpen   PhaseMode = "open"
+	PhaseModeClosed PhaseMode = "closed"
+	PhaseModePIN    PhaseMode = "pin"
+)
+
+// IsValidPhaseMode reports whether mode is open, closed or pin
+func IsValidPhaseMode(mode string) bool {
+	switch PhaseMode(mode) {
+	case PhaseModeOpen, PhaseModeClosed, PhaseModePIN:
+		return true
+	}
+	return false
+}
+
+// LegacyFlag returns the "true" / "false" / "pin" value the frontend used to get from RSVP_OPEN / FINAL_OPEN
+func (m PhaseMode) LegacyFlag() string {
+	switch m {
+	case PhaseModeOpen:
+		return "true"
+	case PhaseModePIN:
+		return "pin"
+	}
+	return "false"
+}
+
+// PhaseModeFromLegacyFlag maps a legacy RSVP_OPEN / FINAL_OPEN value to a mode
+func PhaseModeFromLegacyFlag(v string) PhaseMode {
+	switch v {
+	case "true":
+		return PhaseModeOpen
+	case "pin":
+		return PhaseModePIN
+	}
+	return PhaseModeClosed
+}
+
+// EventPhase is one row of the phase schedule. City "" is the default for all cities.
+type EventPhase struct {
+	ID           uuid.UUID     `json:"id" db:"id"`
+	Phase        EventPhaseKey `json:"phase" db:"phase"`
+	City         string        `json:"city" db:"city"`
+	Mode         PhaseMode     `json:"mode" db:"mode"`
+	OpensAt      *time.Time    `json:"opens_at" db:"opens_at"`
+	ClosesAt     *time.Time    `json:"closes_at" db:"closes_at"`
+	OverrideMode *PhaseMode    `json:"override_mode" db:"override_mode"`
+	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// PhaseStatus is the resolved state of a phase for a city at a point in time
+type PhaseStatus struct {
+	Phase    EventPhaseKey `json:"phase"`
+	City     string        `json:"city,omitempty"`
+	Mode     PhaseMode     `json:"mode"`
+	Source   string        `json:"source"` // "override", "schedule" or "default"
+	OpensAt  *time.Time    `json:"opens_at,omitempty"`
+	ClosesAt *time.Time    `json:"closes_at,omitempty"`
+}
+
+// IsOpen reports whether the phase accepts requests (open or PIN-protected)
+func (s *PhaseStatus) IsOpen() bool {
+	return s.Mode == PhaseModeOpen || s.Mode == PhaseModePIN
+}
+
+// Request DTOs
+type UpsertEventPhaseRequest struct {
+	City     string     `json:"city"`
+	Mode     string     `json:"mode" binding:"required"`
+	OpensAt  *time.Time `json:"opens_at"`
+	ClosesAt *time.Time `json:"closes_at"`
+}
+
+type SetPhaseOverrideRequest struct {
+	City string  `json:"city"`
+	Mode *string `json:"mode"` // null clears the override
+}
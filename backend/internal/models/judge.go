@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Judge is a user with the judge role, with assignment counts for the admin list.
+type Judge struct {
+	ID              uuid.UUID `json:"id"`
+	Email           string    `json:"email"`
+	Name            string    `json:"name"`
+	AssignmentCount int       `json:"assignment_count"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// JudgeAssignment gives a judge either every submission of a track or a single submission.
+type JudgeAssignment struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	EventID      uuid.UUID  `json:"event_id" db:"event_id"`
+	JudgeID      uuid.UUID  `json:"judge_id" db:"judge_id"`
+	Track        *string    `json:"track,omitempty" db:"track"`
+	SubmissionID *uuid.UUID `json:"submission_id,omitempty" db:"submission_id"`
+	TeamName     *string    `json:"team_name,omitempty"` // of the assigned submission
+	CreatedBy    *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// JudgeSubmission is a submission as judges see it: project details without participant PII.
+type JudgeSubmission struct {
+	SubmissionID       uuid.UUID         `json:"submission_id"`
+	TeamName           string            `json:"team_name"`
+	City               *string           `json:"city,omitempty"`
+	MemberCount        int               `json:"member_count"`
+	ProblemStatementID uuid.UUID         `json:"problem_statement_id"`
+	PSTrack            string            `json:"ps_track"`
+	PSName             string            `json:"ps_name"`
+	GithubURL          string            `json:"github_url"`
+	LiveURL            string            `json:"live_url"`
+	ExtraNotes         string            `json:"extra_notes"`
+	CustomFields       map[string]string `json:"custom_fields,omitempty"`
+	SubmittedAt        time.Time         `json:"submitted_at"`
+	Note               *string           `json:"note,omitempty"` // the judge's own note
+	// Judges score the final on-time revision; edits after the scheduled close are listed separately
+	Version       int                   `json:"version"`
+	IsLate        bool                  `json:"is_late"` // no on-time revision exists, so this one is late
+	LateEditCount int                   `json:"late_edit_count"`
+	LateEdits     []PSSubmissionVersion `json:"late_edits,omitempty"`
+	// Files named by the judged revision's file fields, downloaded through the judge API
+	Attachments []SubmissionAttachment `json:"attachments,omitempty"`
+}
+
+// JudgeNote is a judge's private note on a submission.
+type JudgeNote struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	JudgeID      uuid.UUID `json:"judge_id" db:"judge_id"`
+	JudgeName    string    `json:"judge_name,omitempty"`
+	SubmissionID uuid.UUID `json:"submission_id" db:"submission_id"`
+	Note         string    `json:"note" db:"note"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Request DTOs
+type CreateJudgeRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Name     string `json:"name" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+type CreateJudgeAssignmentRequest struct {
+	Track        *string    `json:"track"`
+	SubmissionID *uuid.UUID `json:"submission_id"`
+}
+
+type JudgeNoteRequest struct {
+	Note string `json:"note" binding:"required,max=10000"`
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JudgingRound groups the rubrics and score sheets of one pass over the submissions
+// (e.g. "Prelims", "Finals"). Finalizing a round locks its rubrics and scores.
+type JudgingRound struct {
+	ID          uuid.UUID       `json:"id" db:"id"`
+	EventID     uuid.UUID       `json:"event_id" db:"event_id"`
+	Name        string          `json:"name" db:"name"`
+	Normalize   bool            `json:"normalize" db:"normalize"` // leaderboard default: z-score normalize per judge
+	FinalizedAt *time.Time      `json:"finalized_at,omitempty" db:"finalized_at"`
+	FinalizedBy *uuid.UUID      `json:"finalized_by,omitempty" db:"finalized_by"`
+	CreatedBy   *uuid.UUID      `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	Rubrics     []JudgingRubric `json:"rubrics"`
+}
+
+// Finalized reports whether the round's scores are locked.
+func (r *JudgingRound) Finalized() bool {
+	return r.FinalizedAt != nil
+}
+
+// RubricCriterion is one scored aspect of a rubric. A sheet's total is the weighted mean of
+// score/max_score over the criteria, scaled to 0-100.
+type RubricCriterion struct {
+	Key         string  `json:"key" binding:"required,max=64"`
+	Label       string  `json:"label" binding:"required,max=255"`
+	Description string  `json:"description,omitempty" binding:"max=2000"`
+	Weight      float64 `json:"weight" binding:"gt=0"`
+	MaxScore    float64 `json:"max_score" binding:"gt=0"`
+}
+
+// JudgingRubric is the criteria a round scores a track's submissions against.
+type JudgingRubric struct {
+	ID        uuid.UUID         `json:"id" db:"id"`
+	RoundID   uuid.UUID         `json:"round_id" db:"round_id"`
+	Track     string            `json:"track" db:"track"`
+	Criteria  []RubricCriterion `json:"criteria" db:"criteria"`
+	UpdatedBy *uuid.UUID        `json:"updated_by,omitempty" db:"updated_by"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// JudgeScore is a judge's score sheet for a submission in a round.
+type JudgeScore struct {
+	ID           uuid.UUID          `json:"id" db:"id"`
+	RoundID      uuid.UUID          `json:"round_id" db:"round_id"`
+	JudgeID      uuid.UUID          `json:"judge_id" db:"judge_id"`
+	JudgeName    string             `json:"judge_name,omitempty"`
+	SubmissionID uuid.UUID          `json:"submission_id" db:"submission_id"`
+	Scores       map[string]float64 `json:"scores" db:"scores"`
+	Comments     map[string]string  `json:"comments,omitempty" db:"comments"` // per criterion
+	Comment      string             `json:"comment" db:"comment"`
+	// Weighted 0-100 total under the round's current rubric; nil when the rubric has criteria the sheet doesn't score
+	Total     *float64  `json:"total"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// LeaderboardEntry is a submission's aggregated score in a round.
+type LeaderboardEntry struct {
+	Rank         int                `json:"rank"`
+	SubmissionID uuid.UUID          `json:"submission_id"`
+	TeamID       uuid.UUID          `json:"team_id"`
+	TeamName     string             `json:"team_name"`
+	City         *string            `json:"city,omitempty"`
+	Track        string             `json:"track"`
+	PSName       string             `json:"ps_name"`
+	JudgeCount   int                `json:"judge_count"`
+	Score        float64            `json:"score"`             // ranked on: normalized score, or raw_score when not normalized
+	RawScore     float64            `json:"raw_score"`         // mean of the judges' weighted totals (0-100)
+	ZScore       *float64           `json:"z_score,omitempty"` // mean of the judges' z-scores when normalized
+	Criteria     map[string]float64 `json:"criteria"`          // mean score per criterion key
+}
+
+// Leaderboard ranks a round's scored submissions, optionally filtered to a track and city.
+type Leaderboard struct {
+	Round      JudgingRound       `json:"round"`
+	Track      string             `json:"track,omitempty"`
+	City       string             `json:"city,omitempty"`
+	Normalized bool               `json:"normalized"`
+	Entries    []LeaderboardEntry `json:"entries"`
+}
+
+// Request DTOs
+type CreateJudgingRoundRequest struct {
+	Name      string `json:"name" binding:"required,max=255"`
+	Normalize bool   `json:"normalize"`
+}
+
+type UpdateJudgingRoundRequest struct {
+	Name      *string `json:"name" binding:"omitempty,max=255"`
+	Normalize *bool   `json:"normalize"`
+}
+
+type JudgingRubricRequest struct {
+	Track    string            `json:"track" binding:"required,max=255"`
+	Criteria []RubricCriterion `json:"criteria" binding:"required,min=1,max=50,dive"`
+}
+
+type JudgeScoreRequest struct {
+	RoundID  uuid.UUID          `json:"round_id" binding:"required"`
+	Scores   map[string]float64 `json:"scores" binding:"required"`
+	Comments map[string]string  `json:"comments"`
+	Comment  string             `json:"comment" binding:"max=10000"`
+}
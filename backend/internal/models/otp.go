@@ -14,19 +14,45 @@ type FirebaseAuthRequest struct {
 
 // FirebaseAuthResponse represents the response after Firebase verification
 type FirebaseAuthResponse struct {
-	Token       string `json:"token"`
-	Team        Team   `json:"team"`
-	PhoneNumber string `json:"phone_number"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	Team         Team   `json:"team"`
+	PhoneNumber  string `json:"phone_number"`
 }
 
+// OTPPurpose is what an OTP was issued for; a code is only accepted for its own purpose
+type OTPPurpose string
+
+const (
+	OTPPurposeLogin      OTPPurpose = "login"
+	OTPPurposeWithdrawal OTPPurpose = "withdrawal"
+)
+
 // OTP model for email-based OTP authentication
 type OTP struct {
 	ID        uuid.UUID  `json:"id" db:"id"`
 	Phone     *string    `json:"phone,omitempty" db:"phone"` // Optional, for backward compatibility
 	Email     *string    `json:"email,omitempty" db:"email"` // Email for email-based OTP
 	OTPCode   string     `json:"otp_code" db:"otp_code"`
+	Purpose   OTPPurpose `json:"purpose" db:"purpose"`
 	TeamID    *uuid.UUID `json:"team_id" db:"team_id"`
 	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
 	Verified  bool       `json:"verified" db:"verified"`
 	CreatedAt time.Time  `json:"created_at" db:"created_at"`
 }
+
+// OTPLockout tracks failed OTP verifications for a team
+type OTPLockout struct {
+	TeamID         uuid.UUID  `json:"team_id"`
+	TeamName       string     `json:"team_name,omitempty"`
+	FailedAttempts int        `json:"failed_attempts"`
+	LockoutCount   int        `json:"lockout_count"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`
+	LastFailedAt   *time.Time `json:"last_failed_at,omitempty"`
+}
+
+// IsLocked reports whether the team is currently locked out
+func (l *OTPLockout) IsLocked() bool {
+	return l != nil && l.LockedUntil != nil && time.Now().Before(*l.LockedUntil)
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permission is a single admin capability. Every admin route declares the one it needs.
+type Permission string
+
+const (
+	PermAll                 Permission = "*"                    // super admin
+	PermEventsManage        Permission = "events.manage"        // events (editions) and cities
+	PermPhasesManage        Permission = "phases.manage"        // phase schedule, overrides, RSVP PIN
+	PermTeamsRead           Permission = "teams.read"           // team list, export, check-in stats
+	PermTeamsWrite          Permission = "teams.write"          // create, bulk upload, no-shows, reinstate
+	PermDataClear           Permission = "data.clear"           // wipe all event data
+	PermCheckinManage       Permission = "checkin.manage"       // undo check-ins, registration desks, tables
+	PermSeatingManage       Permission = "seating.manage"       // blocks, rooms, seats
+	PermVolunteersManage    Permission = "volunteers.manage"    // volunteers and volunteer admins
+	PermTicketsManage       Permission = "tickets.manage"       // support tickets
+	PermAnnouncementsManage Permission = "announcements.manage" // announcements
+	PermEmailSend           Permission = "email.send"           // bulk email and email logs
+	PermCertificatesSend    Permission = "certificates.send"    // certificates
+	PermJudgingManage       Permission = "judging.manage"       // problem statements, submissions, semi-finalists
+	PermSessionsManage      Permission = "sessions.manage"      // list and revoke sessions
+	PermRolesManage         Permission = "roles.manage"         // admin roles and assignments
+	PermUsersManage         Permission = "users.manage"         // invite, disable and reset admin accounts (password and 2FA)
+	PermAuditRead           Permission = "audit.read"           // audit log and export
+	PermAPIKeysManage       Permission = "api_keys.manage"      // integration API keys
+)
+
+// PermissionInfo describes a permission for the role editor.
+type PermissionInfo struct {
+	Key         Permission `json:"key"`
+	Description string     `json:"description"`
+}
+
+// Permissions is the catalog of assignable permissions.
+var Permissions = []PermissionInfo{
+	{PermAll, "Everything, including permissions added later"},
+	{PermEventsManage, "Manage events (editions) and cities"},
+	{PermPhasesManage, "Manage the phase schedule and view the RSVP PIN"},
+	{PermTeamsRead, "View and export teams and check-in stats"},
+	{PermTeamsWrite, "Create and import teams, mark no-shows, reinstate teams, clear OTP lockouts"},
+	{PermDataClear, "Clear all event data"},
+	{PermCheckinManage, "Undo check-ins, allocate registration desks, manage tables"},
+	{PermSeatingManage, "Manage seat allocation blocks, rooms and seats"},
+	{PermVolunteersManage, "Manage volunteers and volunteer admins"},
+	{PermTicketsManage, "View and resolve support tickets"},
+	{PermAnnouncementsManage, "Create and delete announcements"},
+	{PermEmailSend, "Send bulk email and view email logs"},
+	{PermCertificatesSend, "Send certificates"},
+	{PermJudgingManage, "Manage problem statements, submission windows and semi-finalists"},
+	{PermSessionsManage, "List and revoke login sessions"},
+	{PermRolesManage, "Manage admin roles and assign them to staff"},
+	{PermUsersManage, "Invite, disable and re-enable admin accounts and reset their passwords and two-factor auth"},
+	{PermAuditRead, "View and export the audit log of admin actions"},
+	{PermAPIKeysManage, "Create and revoke API keys for display screens and bots"},
+}
+
+// IsValidPermission reports whether p is in the catalog.
+func IsValidPermission(p Permission) bool {
+	for _, info := range Permissions {
+		if info.Key == p {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether granted includes p, directly or through "*".
+func HasPermission(granted []string, p Permission) bool {
+	for _, g := range granted {
+		if Permission(g) == p || Permission(g) == PermAll {
+			return true
+		}
+	}
+	return false
+}
+
+// AdminRole is a named set of permissions assigned to admin users.
+type AdminRole struct {
+	Key         string       `json:"key" db:"key"`
+	Name        string       `json:"name" db:"name"`
+	Description *string      `json:"description,omitempty" db:"description"`
+	Permissions []Permission `json:"permissions" db:"permissions"`
+	IsSystem    bool         `json:"is_system" db:"is_system"`
+	UserCount   int          `json:"user_count" db:"user_count"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// AdminUserRoles is an admin user with their roles and effective permissions.
+type AdminUserRoles struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Email       string    `json:"email"`
+	Name        string    `json:"name"`
+	Roles       []string  `json:"roles"`
+	Permissions []string  `json:"permissions"`
+}
+
+// Request DTOs
+type CreateAdminRoleRequest struct {
+	Key         string       `json:"key" binding:"required,max=50"`
+	Name        string       `json:"name" binding:"required,max=100"`
+	Description *string      `json:"description"`
+	Permissions []Permission `json:"permissions" binding:"required"`
+}
+
+type UpdateAdminRoleRequest struct {
+	Name        *string       `json:"name" binding:"omitempty,max=100"`
+	Description *string       `json:"description"`
+	Permissions *[]Permission `json:"permissions"`
+}
+
+type SetUserRolesRequest struct {
+	Roles []string `json:"roles" binding:"required"`
+}
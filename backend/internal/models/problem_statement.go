@@ -14,10 +14,33 @@ type PSItem struct {
 	Name             string         `json:"name" db:"title"` // DB column is "title"
 	FilePath         string         `json:"file_path" db:"file_path"`
 	SubmissionFields sql.NullString `json:"submission_fields,omitempty" db:"submission_fields"`
+	MaxTeams         *int           `json:"max_teams,omitempty" db:"max_teams"`           // nil = unlimited
+	CityMaxTeams     map[string]int `json:"city_max_teams,omitempty" db:"city_max_teams"` // by city code
 	CreatedAt        time.Time      `json:"created_at" db:"created_at"`
 	UpdatedAt        time.Time      `json:"updated_at" db:"updated_at"`
 }
 
+// CityLimit returns the cap for a city code, or nil when the city has no cap.
+func (ps *PSItem) CityLimit(city string) *int {
+	if max, ok := ps.CityMaxTeams[city]; ok {
+		return &max
+	}
+	return nil
+}
+
+// PSLockCount is how many teams have locked a problem statement, overall and by city code.
+type PSLockCount struct {
+	Total  int            `json:"total"`
+	ByCity map[string]int `json:"by_city"`
+}
+
+// PSCapacityRequest sets a problem statement's caps. Omitted/null max_teams means unlimited;
+// city_max_teams replaces all per-city caps (keys are city codes or names).
+type PSCapacityRequest struct {
+	MaxTeams     *int           `json:"max_teams" binding:"omitempty,min=0"`
+	CityMaxTeams map[string]int `json:"city_max_teams"`
+}
+
 // PublicResponse omits internal file_path and exposes download URL
 type ProblemStatementPublic struct {
 	ID         string `json:"id"`
@@ -25,4 +48,8 @@ type ProblemStatementPublic struct {
 	Name       string `json:"name"`
 	DownloadURL string `json:"download_url"`
 	CreatedAt  string `json:"created_at"`
+	// Availability for the requested city (overall cap and the city's cap, whichever is tighter)
+	MaxTeams       *int `json:"max_teams,omitempty"`
+	RemainingSlots *int `json:"remaining_slots,omitempty"` // omitted when unlimited
+	Full           bool `json:"full"`
 }
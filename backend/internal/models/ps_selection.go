@@ -18,6 +18,7 @@ type PSSelection struct {
 	IsSemiFinalist    bool      `json:"is_semi_finalist" db:"is_semi_finalist"`
 	Position          *int      `json:"position,omitempty" db:"position"`
 	BestWeb3          bool      `json:"best_web3" db:"best_web3"`
+	SwitchCount       int       `json:"switch_count" db:"switch_count"` // switches made by the team (not admin reassignments)
 }
 
 // PSSelectionWithDetails includes PS name/track for display
@@ -30,3 +31,42 @@ type PSSelectionWithDetails struct {
 	LeaderName        string `json:"leader_name"`
 	LeaderEmail       string `json:"leader_email"`
 }
+
+// PSSelectionAction is how a team's problem statement changed.
+type PSSelectionAction string
+
+const (
+	PSActionLock     PSSelectionAction = "lock"     // team's first lock
+	PSActionSwitch   PSSelectionAction = "switch"   // team changed its PS within the grace period
+	PSActionReassign PSSelectionAction = "reassign" // admin override
+)
+
+// PSSelectionHistory is one change of a team's problem statement.
+type PSSelectionHistory struct {
+	ID                     uuid.UUID         `json:"id"`
+	TeamID                 uuid.UUID         `json:"team_id"`
+	TeamName               string            `json:"team_name"`
+	FromProblemStatementID *uuid.UUID        `json:"from_problem_statement_id,omitempty"`
+	FromPSName             *string           `json:"from_ps_name,omitempty"`
+	ToProblemStatementID   *uuid.UUID        `json:"to_problem_statement_id,omitempty"`
+	ToPSName               *string           `json:"to_ps_name,omitempty"`
+	Action                 PSSelectionAction `json:"action"`
+	ActorType              string            `json:"actor_type"` // team or admin
+	Actor                  *string           `json:"actor,omitempty"`
+	Reason                 *string           `json:"reason,omitempty"`
+	CreatedAt              time.Time         `json:"created_at"`
+}
+
+// PSSwitchStatus tells a team whether it can still change its problem statement.
+type PSSwitchStatus struct {
+	CanSwitch      bool       `json:"can_switch"`
+	SwitchesLeft   int        `json:"switches_left"`
+	SwitchDeadline *time.Time `json:"switch_deadline,omitempty"`
+}
+
+// ReassignPSRequest is an admin override of a team's problem statement. Force also ignores capacity caps.
+type ReassignPSRequest struct {
+	ProblemStatementID string `json:"problem_statement_id" binding:"required"`
+	Reason             string `json:"reason" binding:"required,min=3"`
+	Force              bool   `json:"force"`
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,13 +12,53 @@ type PSSubmission struct {
 	ID                 uuid.UUID      `json:"id" db:"id"`
 	TeamID             uuid.UUID      `json:"team_id" db:"team_id"`
 	ProblemStatementID uuid.UUID      `json:"problem_statement_id" db:"problem_statement_id"`
-	LinkedinURL        string          `json:"linkedin_url" db:"linkedin_url"`
-	GithubURL          string          `json:"github_url" db:"github_url"`
-	LiveURL            string          `json:"live_url" db:"live_url"`
-	ExtraNotes         string          `json:"extra_notes" db:"extra_notes"`
+	LinkedinURL        string         `json:"linkedin_url" db:"linkedin_url"`
+	GithubURL          string         `json:"github_url" db:"github_url"`
+	LiveURL            string         `json:"live_url" db:"live_url"`
+	ExtraNotes         string         `json:"extra_notes" db:"extra_notes"`
 	CustomFields       sql.NullString `json:"custom_fields,omitempty" db:"custom_fields"`
+	Version            int            `json:"version" db:"version"` // number of the latest revision
+	IsLate             bool           `json:"is_late" db:"is_late"` // latest revision came after the scheduled close
+	SubmittedBy        *string        `json:"submitted_by,omitempty" db:"submitted_by"`
 	SubmittedAt        time.Time      `json:"submitted_at" db:"submitted_at"`
 	CreatedAt          time.Time      `json:"created_at" db:"created_at"`
 	UpdatedAt          time.Time      `json:"updated_at" db:"updated_at"`
 }
 
+// PSSubmissionVersion is one saved revision of a team's submission.
+type PSSubmissionVersion struct {
+	ID                 uuid.UUID       `json:"id" db:"id"`
+	SubmissionID       uuid.UUID       `json:"submission_id" db:"submission_id"`
+	TeamID             uuid.UUID       `json:"team_id" db:"team_id"`
+	ProblemStatementID uuid.UUID       `json:"problem_statement_id" db:"problem_statement_id"`
+	Version            int             `json:"version" db:"version"`
+	LinkedinURL        string          `json:"linkedin_url" db:"linkedin_url"`
+	GithubURL          string          `json:"github_url" db:"github_url"`
+	LiveURL            string          `json:"live_url" db:"live_url"`
+	ExtraNotes         string          `json:"extra_notes" db:"extra_notes"`
+	CustomFields       json.RawMessage `json:"custom_fields,omitempty" db:"custom_fields"`
+	SubmittedBy        *string         `json:"submitted_by,omitempty" db:"submitted_by"`
+	IsLate             bool            `json:"is_late" db:"is_late"`
+	Deadline           *time.Time      `json:"deadline,omitempty" db:"deadline"`
+	SubmittedAt        time.Time       `json:"submitted_at" db:"submitted_at"`
+}
+
+// SubmissionAttachment is a file uploaded for a "file" submission field. Submissions reference it by
+// ID from custom_fields; the file itself is never changed, so older versions keep their files.
+type SubmissionAttachment struct {
+	ID                 uuid.UUID `json:"id" db:"id"`
+	TeamID             uuid.UUID `json:"team_id" db:"team_id"`
+	ProblemStatementID uuid.UUID `json:"problem_statement_id" db:"problem_statement_id"`
+	FieldKey           string    `json:"field_key" db:"field_key"`
+	Filename           string    `json:"filename" db:"filename"`
+	ContentType        string    `json:"content_type" db:"content_type"` // sniffed from the content
+	SizeBytes          int64     `json:"size_bytes" db:"size_bytes"`
+	SHA256             string    `json:"sha256" db:"sha256"`
+	StorageKey         string    `json:"-" db:"storage_key"`
+	UploadedBy         *string   `json:"uploaded_by,omitempty" db:"uploaded_by"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+	DownloadURL        string    `json:"download_url,omitempty"` // authenticated API route
+	// Direct link that needs no credentials, valid until SignedURLExpiresAt
+	SignedURL          string     `json:"signed_url,omitempty"`
+	SignedURLExpiresAt *time.Time `json:"signed_url_expires_at,omitempty"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RosterRow is one participant of a team in the full roster export. Teams without members
+// produce a single row with empty member fields.
+type RosterRow struct {
+	TeamID           uuid.UUID
+	TeamName         string
+	City             *string
+	Status           string
+	MemberCount      int
+	RSVP1At          *time.Time
+	RSVP2At          *time.Time
+	TeamCheckedInAt  *time.Time
+	RegistrationDesk *string
+	SeatBlock        *string
+	SeatRoom         *string
+	SeatLabel        *string
+	PSTrack          *string
+	PSName           *string
+	PSLockedAt       *time.Time
+	GithubURL        *string
+	LiveURL          *string
+	LinkedinURL      *string
+	SubmittedAt      *time.Time
+
+	MemberName    *string
+	MemberRole    *string
+	MemberEmail   *string
+	MemberPhone   *string
+	TShirtSize    *string
+	RSVP2Selected bool
+	CheckedInAt   *time.Time // this participant's check-in
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuthSession is a server-side login session. The refresh token is stored only as a hash.
+type AuthSession struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	SubjectID  uuid.UUID  `json:"subject_id" db:"subject_id"`
+	Role       UserRole   `json:"role" db:"role"`
+	Email      string     `json:"email" db:"email"`
+	TeamID     *uuid.UUID `json:"team_id,omitempty" db:"team_id"`
+	City       string     `json:"city,omitempty" db:"city"`
+	UserAgent  *string    `json:"user_agent,omitempty" db:"user_agent"`
+	IP         *string    `json:"ip,omitempty" db:"ip"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt time.Time  `json:"last_used_at" db:"last_used_at"`
+}
+
+// SessionSubject is who a session is issued for; it becomes the access token claims.
+type SessionSubject struct {
+	SubjectID uuid.UUID
+	Role      UserRole
+	Email     string
+	TeamID    *uuid.UUID
+	City      string
+	// Permissions of admin users, resolved from their roles on every login and refresh
+	Permissions []string
+}
+
+// SessionClient describes the device a session was issued to.
+type SessionClient struct {
+	IP        string
+	UserAgent string
+}
+
+// TokenPair is returned on login and refresh. Token is the short-lived access token.
+type TokenPair struct {
+	Token            string    `json:"token"`
+	RefreshToken     string    `json:"refresh_token"`
+	ExpiresIn        int       `json:"expires_in"` // access token lifetime in seconds
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	Permissions      []string  `json:"permissions,omitempty"` // admin users only
+}
+
+// Request DTOs
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type RevokeSessionsRequest struct {
+	SubjectID uuid.UUID `json:"subject_id" binding:"required"`
+}
@@ -13,8 +13,15 @@ const (
 	StatusRSVPDone    TeamStatus = "rsvp_done"
 	StatusRSVP2Done   TeamStatus = "rsvp2_done"
 	StatusCheckedIn   TeamStatus = "checked_in"
+	StatusWithdrawn   TeamStatus = "withdrawn" // Leader declined participation (OTP-confirmed)
+	StatusNoShow      TeamStatus = "no_show"   // Marked by admin after check-in closed
 )
 
+// IsInactive returns true for withdrawn and no-show teams (excluded from desks, seats, announcements and certificates)
+func (s TeamStatus) IsInactive() bool {
+	return s == StatusWithdrawn || s == StatusNoShow
+}
+
 type MemberRole string
 
 const (
@@ -22,15 +29,9 @@ const (
 	RoleMember MemberRole = "member"
 )
 
+// City is a code from the cities table (e.g. "BLR").
 type City string
 
-const (
-	CityBLR   City = "BLR"
-	CityPUNE  City = "PUNE"
-	CityNOIDA City = "NOIDA"
-	CityLKO   City = "LKO"
-)
-
 type Team struct {
 	ID               uuid.UUID    `json:"id" db:"id"`
 	TeamName         string       `json:"team_name" db:"team_name"`
@@ -49,6 +50,10 @@ type Team struct {
 	MemberCount         int          `json:"member_count" db:"member_count"`
 	EditAllowedUntil    *time.Time   `json:"edit_allowed_until,omitempty" db:"edit_allowed_until"`
 	RegistrationDeskID   *uuid.UUID  `json:"registration_desk_id,omitempty" db:"registration_desk_id"`
+	PreviousStatus      *TeamStatus  `json:"previous_status,omitempty" db:"previous_status"`
+	InactiveAt          *time.Time   `json:"inactive_at,omitempty" db:"inactive_at"`
+	InactiveReason      *string      `json:"inactive_reason,omitempty" db:"inactive_reason"`
+	EventID             uuid.UUID    `json:"event_id" db:"event_id"`
 	CreatedAt        time.Time    `json:"created_at" db:"created_at"`
 	UpdatedAt        time.Time    `json:"updated_at" db:"updated_at"`
 	Members          []TeamMember `json:"members,omitempty"`
@@ -77,8 +82,13 @@ type TeamMember struct {
 }
 
 // Request/Response DTOs
+
+// TeamSearchRequest is the /teams/search query. Queries need at least 3 characters (2 before ranked
+// search); shorter ones get a 400, so clients should wait for the third character before searching.
 type TeamSearchRequest struct {
-	Query string `form:"query" binding:"required,min=2"`
+	Query    string `form:"query" binding:"required,min=3,max=100"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1"`
 }
 
 type TeamSearchResponse struct {
@@ -90,6 +100,19 @@ type TeamSearchResponse struct {
 	Status      TeamStatus `json:"status"`
 	MemberCount int        `json:"member_count"`
 	RSVPLocked  bool       `json:"rsvp_locked"`
+	Score       float64    `json:"score"`
+}
+
+// TeamSearchPage is one page of ranked search results. Total is capped at the number of reachable results.
+// teams and count are the fields of the old unpaginated response (count is still the number of
+// teams returned), so existing clients keep working; page, page_size, total and has_more are new.
+type TeamSearchPage struct {
+	Teams    []TeamSearchResponse `json:"teams"`
+	Count    int                  `json:"count"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"page_size"`
+	Total    int                  `json:"total"`
+	HasMore  bool                 `json:"has_more"`
 }
 
 type RSVPRequest struct {
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactor is a TOTP enrollment. The secret is only held decrypted in memory.
+type TwoFactor struct {
+	SubjectID       uuid.UUID  `json:"subject_id" db:"subject_id"`
+	Role            UserRole   `json:"role" db:"role"`
+	SecretEncrypted string     `json:"-" db:"secret_encrypted"`
+	EnabledAt       *time.Time `json:"enabled_at,omitempty" db:"enabled_at"`
+	LastCounter     *int64     `json:"-" db:"last_counter"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// TwoFactorChallenge is the pending second login step.
+type TwoFactorChallenge struct {
+	ID         uuid.UUID  `db:"id"`
+	SubjectID  uuid.UUID  `db:"subject_id"`
+	Role       UserRole   `db:"role"`
+	Email      string     `db:"email"`
+	City       *string    `db:"city"`
+	Setup      bool       `db:"setup"`
+	Attempts   int        `db:"attempts"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	ConsumedAt *time.Time `db:"consumed_at"`
+}
+
+// TwoFactorStatus tells a user whether 2FA is on and whether it is required for them.
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	Enforced               bool       `json:"enforced"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorEnrollment is returned when enrollment starts; the secret is shown once.
+type TwoFactorEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+	QRCode          string `json:"qr_code"` // data:image/png;base64,...
+}
+
+// LoginResult is either a session or a second step the client must complete.
+type LoginResult struct {
+	Tokens                 *TokenPair `json:"-"`
+	TwoFactorRequired      bool       `json:"two_factor_required,omitempty"`
+	TwoFactorSetupRequired bool       `json:"two_factor_setup_required,omitempty"`
+	ChallengeToken         string     `json:"challenge_token,omitempty"`
+}
+
+// Request DTOs
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+type TwoFactorVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code"`          // TOTP code
+	RecoveryCode   string `json:"recovery_code"` // or a one-time recovery code
+}
+
+type TwoFactorResetRequest struct {
+	SubjectID uuid.UUID `json:"subject_id" binding:"required"`
+}
@@ -3,7 +3,6 @@ package models
 import (
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -15,6 +14,7 @@ const (
 	UserRoleVolunteer     UserRole = "volunteer"
 	UserRoleVolunteerAdmin UserRole = "volunteer_admin"
 	UserRoleAdmin         UserRole = "admin"
+	UserRoleJudge         UserRole = "judge"
 )
 
 type User struct {
@@ -23,6 +23,7 @@ type User struct {
 	PasswordHash string    `json:"-" db:"password_hash"`
 	Name         string    `json:"name" db:"name"`
 	Role         UserRole  `json:"role" db:"role"`
+	IsActive     bool      `json:"is_active" db:"is_active"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -33,29 +34,28 @@ func (u *User) ComparePassword(password string) bool {
 	return err == nil
 }
 
-// GenerateJWT generates a JWT token for the user
-func (u *User) GenerateJWT(jwtSecret string) (string, error) {
-	// Create claims
-	claims := jwt.MapClaims{
-		"user_id": u.ID.String(),
-		"email":   u.Email,
-		"role":    string(u.Role),
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
-		"iat":     time.Now().Unix(),
-		"nbf":     time.Now().Unix(),
-		"iss":     "rift26-api",
-	}
-
-	// Create token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(jwtSecret))
-	if err != nil {
-		return "", err
-	}
-
-	return tokenString, nil
+// AdminUser is an admin account as listed in user management.
+type AdminUser struct {
+	ID               uuid.UUID  `json:"id"`
+	Email            string     `json:"email"`
+	Name             string     `json:"name"`
+	IsActive         bool       `json:"is_active"`
+	PasswordSet      bool       `json:"password_set"` // false until an invite is accepted
+	Roles            []string   `json:"roles"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	LastLoginAt      *time.Time `json:"last_login_at,omitempty"`  // newest session
+	LastActiveAt     *time.Time `json:"last_active_at,omitempty"` // newest token refresh
+	DisabledAt       *time.Time `json:"disabled_at,omitempty"`
+	InvitedBy        *uuid.UUID `json:"invited_by,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
 }
 
+// Password link purposes
+const (
+	PasswordTokenInvite = "invite"
+	PasswordTokenReset  = "reset"
+)
+
 // Request DTOs
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
@@ -66,3 +66,18 @@ type LoginResponse struct {
 	Token string `json:"token"`
 	User  User   `json:"user"`
 }
+
+type InviteAdminRequest struct {
+	Email string   `json:"email" binding:"required,email"`
+	Name  string   `json:"name" binding:"required,max=255"`
+	Roles []string `json:"roles"` // admin roles to grant; needs roles.manage
+}
+
+type UpdateAdminUserRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+type SetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
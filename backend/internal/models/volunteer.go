@@ -40,8 +40,10 @@ type VolunteerLoginRequest struct {
 
 // VolunteerLoginResponse represents the login response
 type VolunteerLoginResponse struct {
-	Token     string    `json:"token"`
-	Volunteer Volunteer `json:"volunteer"`
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresIn    int       `json:"expires_in"`
+	Volunteer    Volunteer `json:"volunteer"`
 }
 
 // CreateVolunteerRequest represents the request to create a new volunteer
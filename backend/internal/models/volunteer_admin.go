@@ -26,10 +26,16 @@ type VolunteerAdminLoginRequest struct {
 
 // VolunteerAdminLoginResponse is the login response with token and city.
 type VolunteerAdminLoginResponse struct {
-	Token   string `json:"token"`
-	Email   string `json:"email"`
-	City    string `json:"city"`
-	Message string `json:"message,omitempty"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	Email        string `json:"email"`
+	City         string `json:"city"`
+	Message      string `json:"message,omitempty"`
+	// Set instead of tokens when a second factor is needed; complete via /auth/2fa/verify
+	TwoFactorRequired      bool   `json:"two_factor_required,omitempty"`
+	TwoFactorSetupRequired bool   `json:"two_factor_setup_required,omitempty"`
+	ChallengeToken         string `json:"challenge_token,omitempty"`
 }
 
 // CreateVolunteerAdminRequest is used by organiser admin to create a volunteer admin.
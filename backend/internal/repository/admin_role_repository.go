@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/rift26/backend/internal/database"
+	"github.com/rift26/backend/internal/models"
+)
+
+// ErrNoRoleManager is returned when a change would leave no admin able to manage roles.
+var ErrNoRoleManager = errors.New("change would leave no admin able to manage roles")
+
+type AdminRoleRepository struct {
+	db *database.DB
+}
+
+func NewAdminRoleRepository(db *database.DB) *AdminRoleRepository {
+	return &AdminRoleRepository{db: db}
+}
+
+const adminRoleColumns = `r.key, r.name, r.description, r.permissions, r.is_system,
+	(SELECT COUNT(*) FROM user_admin_roles uar WHERE uar.role_key = r.key), r.created_at, r.updated_at`
+
+func scanAdminRole(row rowScanner) (*models.AdminRole, error) {
+	var role models.AdminRole
+	var perms pq.StringArray
+	if err := row.Scan(&role.Key, &role.Name, &role.Description, &perms, &role.IsSystem,
+		&role.UserCount, &role.CreatedAt, &role.UpdatedAt); err != nil {
+		return nil, err
+	}
+	role.Permissions = make([]models.Permission, 0, len(perms))
+	for _, p := range perms {
+		role.Permissions = append(role.Permissions, models.Permission(p))
+	}
+	return &role, nil
+}
+
+func permissionArray(perms []models.Permission) pq.StringArray {
+	arr := make(pq.StringArray, 0, len(perms))
+	for _, p := range perms {
+		arr = append(arr, string(p))
+	}
+	return arr
+}
+
+// GetAll returns every role with the number of users holding it.
+func (r *AdminRoleRepository) GetAll(ctx context.Context) ([]models.AdminRole, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+adminRoleColumns+` FROM admin_roles r ORDER BY r.is_system DESC, r.key`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query admin roles: %w", err)
+	}
+	defer rows.Close()
+	list := make([]models.AdminRole, 0)
+	for rows.Next() {
+		role, err := scanAdminRole(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan admin role: %w", err)
+		}
+		list = append(list, *role)
+	}
+	return list, rows.Err()
+}
+
+// GetByKey returns a role, or nil if not found.
+func (r *AdminRoleRepository) GetByKey(ctx context.Context, key string) (*models.AdminRole, error) {
+	role, err := scanAdminRole(r.db.QueryRowContext(ctx, `SELECT `+adminRoleColumns+` FROM admin_roles r WHERE r.key = $1`, key))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admin role: %w", err)
+	}
+	return role, nil
+}
+
+// Create inserts a new custom role.
+func (r *AdminRoleRepository) Create(ctx context.Context, role *models.AdminRole) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO admin_roles (key, name, description, permissions, is_system, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, FALSE, NOW(), NOW())`,
+		role.Key, role.Name, role.Description, permissionArray(role.Permissions))
+	if err != nil {
+		return fmt.Errorf("failed to create admin role: %w", err)
+	}
+	saved, err := r.GetByKey(ctx, role.Key)
+	if err != nil {
+		return err
+	}
+	*role = *saved
+	return nil
+}
+
+// Update saves name, description and permissions of a role.
+func (r *AdminRoleRepository) Update(ctx context.Context, role *models.AdminRole) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE admin_roles SET name = $2, description = $3, permissions = $4, updated_at = NOW()
+		WHERE key = $1`,
+		role.Key, role.Name, role.Description, permissionArray(role.Permissions))
+	if err != nil {
+		return fmt.Errorf("failed to update admin role: %w", err)
+	}
+	if err := ensureRoleManager(ctx, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit admin role: %w", err)
+	}
+	saved, err := r.GetByKey(ctx, role.Key)
+	if err != nil {
+		return err
+	}
+	*role = *saved
+	return nil
+}
+
+// Delete removes a custom role and its assignments. Built-in roles are kept.
+func (r *AdminRoleRepository) Delete(ctx context.Context, key string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM admin_roles WHERE key = $1 AND NOT is_system`, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete admin role: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("role not found or built-in")
+	}
+	if err := ensureRoleManager(ctx, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit admin role: %w", err)
+	}
+	return nil
+}
+
+// GetUserPermissions returns the union of the permissions of a user's roles.
+func (r *AdminRoleRepository) GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	var perms pq.StringArray
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(ARRAY_AGG(DISTINCT p ORDER BY p), '{}')
+		FROM user_admin_roles uar
+		JOIN admin_roles r ON r.key = uar.role_key
+		CROSS JOIN LATERAL UNNEST(r.permissions) AS p
+		WHERE uar.user_id = $1`, userID).Scan(&perms)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user permissions: %w", err)
+	}
+	return []string(perms), nil
+}
+
+// ListAdminUsers returns every admin user with their roles and effective permissions.
+func (r *AdminRoleRepository) ListAdminUsers(ctx context.Context) ([]models.AdminUserRoles, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.id, u.email, u.name,
+		       COALESCE((SELECT ARRAY_AGG(uar.role_key ORDER BY uar.role_key) FROM user_admin_roles uar WHERE uar.user_id = u.id), '{}'),
+		       COALESCE((SELECT ARRAY_AGG(DISTINCT p ORDER BY p)
+		                 FROM user_admin_roles uar
+		                 JOIN admin_roles r ON r.key = uar.role_key
+		                 CROSS JOIN LATERAL UNNEST(r.permissions) AS p
+		                 WHERE uar.user_id = u.id), '{}')
+		FROM users u
+		WHERE u.role = 'admin'
+		ORDER BY u.email`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query admin users: %w", err)
+	}
+	defer rows.Close()
+	list := make([]models.AdminUserRoles, 0)
+	for rows.Next() {
+		var u models.AdminUserRoles
+		var roles, perms pq.StringArray
+		if err := rows.Scan(&u.UserID, &u.Email, &u.Name, &roles, &perms); err != nil {
+			return nil, fmt.Errorf("failed to scan admin user: %w", err)
+		}
+		u.Roles, u.Permissions = []string(roles), []string(perms)
+		list = append(list, u)
+	}
+	return list, rows.Err()
+}
+
+// SetUserRoles replaces the roles of a user.
+func (r *AdminRoleRepository) SetUserRoles(ctx context.Context, userID uuid.UUID, roleKeys []string, grantedBy *uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM user_admin_roles WHERE user_id = $1 AND NOT (role_key = ANY($2))`, userID, pq.Array(roleKeys))
+	if err != nil {
+		return fmt.Errorf("failed to remove user roles: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO user_admin_roles (user_id, role_key, granted_by, granted_at)
+		SELECT $1, k, $3, NOW() FROM UNNEST($2::text[]) AS k
+		ON CONFLICT (user_id, role_key) DO NOTHING`, userID, pq.Array(roleKeys), grantedBy)
+	if err != nil {
+		return fmt.Errorf("failed to assign user roles: %w", err)
+	}
+	if err := ensureRoleManager(ctx, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit user roles: %w", err)
+	}
+	return nil
+}
+
+// ensureRoleManager fails with ErrNoRoleManager unless some admin can still manage roles.
+func ensureRoleManager(ctx context.Context, tx *sql.Tx) error {
+	var ok bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM user_admin_roles uar
+			JOIN admin_roles r ON r.key = uar.role_key
+			JOIN users u ON u.id = uar.user_id AND u.role = 'admin' AND u.is_active
+			WHERE r.permissions && ARRAY['*', 'roles.manage']
+		)`).Scan(&ok)
+	if err != nil {
+		return fmt.Errorf("failed to check role managers: %w", err)
+	}
+	if !ok {
+		return ErrNoRoleManager
+	}
+	return nil
+}
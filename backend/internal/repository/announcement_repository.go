@@ -17,12 +17,12 @@ func NewAnnouncementRepository(db *database.DB) *AnnouncementRepository {
 	return &AnnouncementRepository{db: db}
 }
 
-// GetActiveAnnouncements retrieves all active announcements ordered by priority
+// GetActiveAnnouncements retrieves all active announcements of the current event ordered by priority
 func (r *AnnouncementRepository) GetActiveAnnouncements(ctx context.Context) ([]models.Announcement, error) {
 	query := `
 		SELECT id, title, content, priority, is_active, created_by, created_at, updated_at, button_text, button_url
 		FROM announcements
-		WHERE is_active = true
+		WHERE is_active = true AND event_id = current_event_id()
 		ORDER BY priority DESC, created_at DESC
 	`
 
@@ -62,15 +62,16 @@ func (r *AnnouncementRepository) Create(ctx context.Context, announcement models
 	return nil
 }
 
-// GetAll retrieves all announcements (active and inactive)
-func (r *AnnouncementRepository) GetAll(ctx context.Context) ([]models.Announcement, error) {
+// GetAll retrieves all announcements (active and inactive) of the event (uuid.Nil = current event)
+func (r *AnnouncementRepository) GetAll(ctx context.Context, eventID uuid.UUID) ([]models.Announcement, error) {
 	query := `
 		SELECT id, title, content, priority, is_active, created_by, created_at, updated_at, button_text, button_url
 		FROM announcements
+		WHERE event_id = COALESCE($1, current_event_id())
 		ORDER BY priority DESC, created_at DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, EventArg(eventID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all announcements: %w", err)
 	}
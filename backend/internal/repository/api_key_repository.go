@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/rift26/backend/internal/database"
+	"github.com/rift26/backend/internal/models"
+)
+
+type APIKeyRepository struct {
+	db *database.DB
+}
+
+func NewAPIKeyRepository(db *database.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+const apiKeyColumns = `id, name, prefix, scopes, city, created_by, created_at, expires_at, last_used_at, last_used_ip, revoked_at`
+
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	var k models.APIKey
+	var scopes []string
+	var city, lastIP sql.NullString
+	var createdBy uuid.NullUUID
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(&k.ID, &k.Name, &k.Prefix, pq.Array(&scopes), &city, &createdBy, &k.CreatedAt,
+		&expiresAt, &lastUsedAt, &lastIP, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+	k.Scopes = make([]models.APIKeyScope, len(scopes))
+	for i, s := range scopes {
+		k.Scopes[i] = models.APIKeyScope(s)
+	}
+	if city.Valid {
+		k.City = &city.String
+	}
+	if createdBy.Valid {
+		k.CreatedBy = &createdBy.UUID
+	}
+	if expiresAt.Valid {
+		k.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		k.LastUsedAt = &lastUsedAt.Time
+	}
+	if lastIP.Valid {
+		k.LastUsedIP = &lastIP.String
+	}
+	if revokedAt.Valid {
+		k.RevokedAt = &revokedAt.Time
+	}
+	return &k, nil
+}
+
+// Create stores a new key by its hash
+func (r *APIKeyRepository) Create(ctx context.Context, k *models.APIKey, keyHash string) error {
+	scopes := make([]string, len(k.Scopes))
+	for i, s := range k.Scopes {
+		scopes[i] = string(s)
+	}
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO api_keys (name, prefix, key_hash, scopes, city, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`, k.Name, k.Prefix, keyHash, pq.Array(scopes), k.City, k.CreatedBy, k.ExpiresAt).Scan(&k.ID, &k.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+	return nil
+}
+
+// List returns all keys, including revoked ones, newest first
+func (r *APIKeyRepository) List(ctx context.Context) ([]models.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan API key: %w", err)
+		}
+		keys = append(keys, *k)
+	}
+	return keys, rows.Err()
+}
+
+// GetActiveByHash returns the unrevoked, unexpired key with this hash, or nil
+func (r *APIKeyRepository) GetActiveByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	k, err := scanAPIKey(r.db.QueryRowContext(ctx, `
+		SELECT `+apiKeyColumns+` FROM api_keys
+		WHERE key_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+	`, keyHash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	return k, nil
+}
+
+// TouchLastUsed records a use, writing at most once a minute per key
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, ip string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE api_keys SET last_used_at = NOW(), last_used_ip = NULLIF($2, '')
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+	`, id, ip)
+	if err != nil {
+		return fmt.Errorf("failed to record API key use: %w", err)
+	}
+	return nil
+}
+
+// Revoke disables a key. Returns false if it does not exist or is already revoked.
+func (r *APIKeyRepository) Revoke(ctx context.Context, id uuid.UUID) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/database"
+	"github.com/rift26/backend/internal/models"
+)
+
+type AuditLogRepository struct {
+	db *database.DB
+}
+
+func NewAuditLogRepository(db *database.DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+// AuditLogQuery is a parsed AuditLogFilter
+type AuditLogQuery struct {
+	models.AuditLogFilter
+	From, To *time.Time
+}
+
+const auditLogColumns = `id, event_id, actor_id, COALESCE(actor_email, ''), actor_role, COALESCE(ip, ''), COALESCE(user_agent, ''),
+	method, path, action, COALESCE(target_type, ''), COALESCE(target_id, ''), before_state, after_state, diff, status_code, created_at`
+
+func scanAuditLog(row rowScanner) (*models.AuditLog, error) {
+	var l models.AuditLog
+	var eventID, actorID uuid.NullUUID
+	var before, after, diff []byte
+	err := row.Scan(&l.ID, &eventID, &actorID, &l.ActorEmail, &l.ActorRole, &l.IP, &l.UserAgent,
+		&l.Method, &l.Path, &l.Action, &l.TargetType, &l.TargetID, &before, &after, &diff, &l.StatusCode, &l.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if eventID.Valid {
+		l.EventID = &eventID.UUID
+	}
+	if actorID.Valid {
+		l.ActorID = &actorID.UUID
+	}
+	l.Before, l.After, l.Diff = before, after, diff
+	return &l, nil
+}
+
+// nullJSON stores an empty snapshot as NULL
+func nullJSON(b []byte) interface{} {
+	if len(b) == 0 {
+		return nil
+	}
+	return string(b)
+}
+
+// Create records an audit log entry
+func (r *AuditLogRepository) Create(ctx context.Context, l *models.AuditLog) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO audit_logs (event_id, actor_id, actor_email, actor_role, ip, user_agent, method, path,
+			action, target_type, target_id, before_state, after_state, diff, status_code)
+		VALUES ($1, $2, NULLIF($3, ''), $4, NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9, NULLIF($10, ''), NULLIF($11, ''),
+			$12::jsonb, $13::jsonb, $14::jsonb, $15)
+	`, l.EventID, l.ActorID, l.ActorEmail, l.ActorRole, l.IP, l.UserAgent, l.Method, l.Path,
+		l.Action, l.TargetType, l.TargetID, nullJSON(l.Before), nullJSON(l.After), nullJSON(l.Diff), l.StatusCode)
+	if err != nil {
+		return fmt.Errorf("failed to create audit log: %w", err)
+	}
+	return nil
+}
+
+// where builds the WHERE clause for a query
+func (q AuditLogQuery) where() (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if q.ActorID != "" {
+		add("actor_id::text = $%d", q.ActorID)
+	}
+	if q.ActorEmail != "" {
+		add("LOWER(actor_email) = LOWER($%d)", q.ActorEmail)
+	}
+	if q.Role != "" {
+		add("actor_role = $%d", q.Role)
+	}
+	if q.Action != "" {
+		add("action ILIKE '%%' || $%d || '%%'", q.Action)
+	}
+	if q.TargetType != "" {
+		add("target_type = $%d", q.TargetType)
+	}
+	if q.TargetID != "" {
+		add("target_id = $%d", q.TargetID)
+	}
+	switch q.Status {
+	case "success":
+		conds = append(conds, "status_code < 400")
+	case "failed":
+		conds = append(conds, "status_code >= 400")
+	}
+	if q.From != nil {
+		add("created_at >= $%d", *q.From)
+	}
+	if q.To != nil {
+		add("created_at < $%d", *q.To)
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
+
+// List returns one page of matching entries (newest first) and the total match count
+func (r *AuditLogRepository) List(ctx context.Context, q AuditLogQuery, limit, offset int) ([]models.AuditLog, int, error) {
+	where, args := q.where()
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_logs `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit logs: %w", err)
+	}
+
+	args = append(args, limit, offset)
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s FROM audit_logs %s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, auditLogColumns, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.AuditLog{}
+	for rows.Next() {
+		l, err := scanAuditLog(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan audit log: %w", err)
+		}
+		entries = append(entries, *l)
+	}
+	return entries, total, rows.Err()
+}
+
+// Each calls fn for every matching entry, newest first (for exports)
+func (r *AuditLogRepository) Each(ctx context.Context, q AuditLogQuery, fn func(*models.AuditLog) error) error {
+	where, args := q.where()
+	rows, err := r.db.QueryContext(ctx, `SELECT `+auditLogColumns+` FROM audit_logs `+where+` ORDER BY created_at DESC`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		l, err := scanAuditLog(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan audit log: %w", err)
+		}
+		if err := fn(l); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/rift26/backend/internal/database"
+	"github.com/rift26/backend/internal/models"
+)
+
+type CityRepository struct {
+	db *database.DB
+}
+
+func NewCityRepository(db *database.DB) *CityRepository {
+	return &CityRepository{db: db}
+}
+
+const cityColumns = `code, name, aliases, timezone, venue_name, venue_address, venue_maps_url, seat_allocation, is_active, sort_order, created_at, updated_at`
+
+func scanCity(row rowScanner) (*models.CityConfig, error) {
+	var c models.CityConfig
+	var aliases pq.StringArray
+	if err := row.Scan(&c.Code, &c.Name, &aliases, &c.Timezone, &c.VenueName, &c.VenueAddress, &c.VenueMapsURL,
+		&c.SeatAllocation, &c.IsActive, &c.SortOrder, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	c.Aliases = []string(aliases)
+	if c.Aliases == nil {
+		c.Aliases = []string{}
+	}
+	return &c, nil
+}
+
+// GetAll returns all cities (active and inactive) in display order.
+func (r *CityRepository) GetAll(ctx context.Context) ([]models.CityConfig, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+cityColumns+` FROM cities ORDER BY sort_order, code`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cities: %w", err)
+	}
+	defer rows.Close()
+	list := make([]models.CityConfig, 0)
+	for rows.Next() {
+		c, err := scanCity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan city: %w", err)
+		}
+		list = append(list, *c)
+	}
+	return list, rows.Err()
+}
+
+// GetByCode returns a city, or nil if not found.
+func (r *CityRepository) GetByCode(ctx context.Context, code string) (*models.CityConfig, error) {
+	c, err := scanCity(r.db.QueryRowContext(ctx, `SELECT `+cityColumns+` FROM cities WHERE code = $1`, code))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get city: %w", err)
+	}
+	return c, nil
+}
+
+// Create inserts a new city.
+func (r *CityRepository) Create(ctx context.Context, c *models.CityConfig) error {
+	saved, err := scanCity(r.db.QueryRowContext(ctx, `
+		INSERT INTO cities (code, name, aliases, timezone, venue_name, venue_address, venue_maps_url, seat_allocation, is_active, sort_order, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+		RETURNING `+cityColumns,
+		c.Code, c.Name, pq.Array(c.Aliases), c.Timezone, c.VenueName, c.VenueAddress, c.VenueMapsURL, c.SeatAllocation, c.IsActive, c.SortOrder))
+	if err != nil {
+		return fmt.Errorf("failed to create city: %w", err)
+	}
+	*c = *saved
+	return nil
+}
+
+// Update saves every editable field of a city.
+func (r *CityRepository) Update(ctx context.Context, c *models.CityConfig) error {
+	saved, err := scanCity(r.db.QueryRowContext(ctx, `
+		UPDATE cities SET name = $2, aliases = $3, timezone = $4, venue_name = $5, venue_address = $6, venue_maps_url = $7,
+		       seat_allocation = $8, is_active = $9, sort_order = $10, updated_at = NOW()
+		WHERE code = $1
+		RETURNING `+cityColumns,
+		c.Code, c.Name, pq.Array(c.Aliases), c.Timezone, c.VenueName, c.VenueAddress, c.VenueMapsURL, c.SeatAllocation, c.IsActive, c.SortOrder))
+	if err != nil {
+		return fmt.Errorf("failed to update city: %w", err)
+	}
+	*c = *saved
+	return nil
+}
+
+// Delete removes a city. It fails while teams or venues still reference it; deactivate instead.
+func (r *CityRepository) Delete(ctx context.Context, code string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM cities WHERE code = $1`, code)
+	if err != nil {
+		return fmt.Errorf("failed to delete city (still referenced? deactivate it instead): %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("city not found")
+	}
+	return nil
+}
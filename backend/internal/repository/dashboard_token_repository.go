@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/database"
+	"github.com/rift26/backend/internal/models"
+)
+
+type DashboardTokenRepository struct {
+	db *database.DB
+}
+
+func NewDashboardTokenRepository(db *database.DB) *DashboardTokenRepository {
+	return &DashboardTokenRepository{db: db}
+}
+
+const dashboardTokenColumns = `id, team_id, prefix, access, created_by, created_at, expires_at, last_used_at, revoked_at`
+
+func scanDashboardToken(row rowScanner) (*models.DashboardToken, error) {
+	var t models.DashboardToken
+	var createdBy sql.NullString
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(&t.ID, &t.TeamID, &t.Prefix, &t.Access, &createdBy, &t.CreatedAt, &expiresAt, &lastUsedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+	if createdBy.Valid {
+		t.CreatedBy = &createdBy.String
+	}
+	if expiresAt.Valid {
+		t.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		t.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		t.RevokedAt = &revokedAt.Time
+	}
+	return &t, nil
+}
+
+// GetByHash returns the link with this token hash (including expired and revoked ones), or nil
+func (r *DashboardTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*models.DashboardToken, error) {
+	t, err := scanDashboardToken(r.db.QueryRowContext(ctx,
+		`SELECT `+dashboardTokenColumns+` FROM dashboard_tokens WHERE token_hash = $1`, tokenHash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dashboard token: %w", err)
+	}
+	return t, nil
+}
+
+// RegisterCurrent adds a row for a team's teams.dashboard_token that has none yet (links set by
+// RSVP, manual team creation or bulk upload) and revokes the full-access links it replaced.
+// Returns nil if token is not a team's current link.
+func (r *DashboardTokenRepository) RegisterCurrent(ctx context.Context, token, tokenHash, prefix string, expiresAt time.Time) (*models.DashboardToken, error) {
+	t, err := scanDashboardToken(r.db.QueryRowContext(ctx, `
+		INSERT INTO dashboard_tokens (team_id, token_hash, prefix, access, created_by, expires_at)
+		SELECT id, $2, $3, 'full', 'system', $4 FROM teams WHERE dashboard_token = $1
+		ON CONFLICT (token_hash) DO NOTHING
+		RETURNING `+dashboardTokenColumns, token, tokenHash, prefix, expiresAt))
+	if err == sql.ErrNoRows {
+		// Not a current link, or registered concurrently
+		return r.GetByHash(ctx, tokenHash)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to register dashboard token: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE dashboard_tokens SET revoked_at = NOW()
+		WHERE team_id = $1 AND access = 'full' AND revoked_at IS NULL AND id <> $2
+	`, t.TeamID, t.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke replaced dashboard tokens: %w", err)
+	}
+	return t, nil
+}
+
+// Issue stores a new link and revokes the team's other active links with the same access.
+// A new full-access link also becomes the team's teams.dashboard_token.
+func (r *DashboardTokenRepository) Issue(ctx context.Context, t *models.DashboardToken, token, tokenHash string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE dashboard_tokens SET revoked_at = NOW()
+		WHERE team_id = $1 AND access = $2 AND revoked_at IS NULL
+	`, t.TeamID, t.Access)
+	if err != nil {
+		return fmt.Errorf("failed to revoke previous dashboard tokens: %w", err)
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO dashboard_tokens (team_id, token_hash, prefix, access, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, t.TeamID, tokenHash, t.Prefix, t.Access, t.CreatedBy, t.ExpiresAt).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create dashboard token: %w", err)
+	}
+	if t.Access == models.DashboardAccessFull {
+		_, err = tx.ExecContext(ctx, `UPDATE teams SET dashboard_token = $2, updated_at = NOW() WHERE id = $1`, t.TeamID, token)
+		if err != nil {
+			return fmt.Errorf("failed to update team dashboard token: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Revoke disables one of the team's links. Revoking the current full-access link clears
+// teams.dashboard_token. Returns false if the link does not exist or is already revoked.
+func (r *DashboardTokenRepository) Revoke(ctx context.Context, teamID, id uuid.UUID) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var tokenHash string
+	var access models.DashboardAccess
+	err = tx.QueryRowContext(ctx, `
+		UPDATE dashboard_tokens SET revoked_at = NOW()
+		WHERE id = $1 AND team_id = $2 AND revoked_at IS NULL
+		RETURNING token_hash, access
+	`, id, teamID).Scan(&tokenHash, &access)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke dashboard token: %w", err)
+	}
+	if access == models.DashboardAccessFull {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE teams SET dashboard_token = NULL, updated_at = NOW()
+			WHERE id = $1 AND encode(sha256(dashboard_token::bytea), 'hex') = $2
+		`, teamID, tokenHash)
+		if err != nil {
+			return false, fmt.Errorf("failed to clear team dashboard token: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to revoke dashboard token: %w", err)
+	}
+	return true, nil
+}
+
+// ListByTeam returns the team's links, newest first
+func (r *DashboardTokenRepository) ListByTeam(ctx context.Context, teamID uuid.UUID) ([]models.DashboardToken, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+dashboardTokenColumns+` FROM dashboard_tokens WHERE team_id = $1 ORDER BY created_at DESC`, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dashboard tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []models.DashboardToken{}
+	for rows.Next() {
+		t, err := scanDashboardToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dashboard token: %w", err)
+		}
+		tokens = append(tokens, *t)
+	}
+	return tokens, rows.Err()
+}
+
+// LogAccess records a request made with a link and updates its last use
+func (r *DashboardTokenRepository) LogAccess(ctx context.Context, l *models.DashboardAccessLog) error {
+	_, err := r.db.ExecContext(ctx, `
+		WITH touched AS (
+			UPDATE dashboard_tokens SET last_used_at = NOW() WHERE id = $1
+		)
+		INSERT INTO dashboard_access_logs (token_id, team_id, ip, user_agent, method, path)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, l.TokenID, l.TeamID, l.IP, l.UserAgent, l.Method, l.Path)
+	if err != nil {
+		return fmt.Errorf("failed to log dashboard access: %w", err)
+	}
+	return nil
+}
+
+// ListAccessLogs returns the most recent requests made with one of the team's links
+func (r *DashboardTokenRepository) ListAccessLogs(ctx context.Context, teamID, tokenID uuid.UUID, limit int) ([]models.DashboardAccessLog, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, token_id, team_id, ip, user_agent, method, path, accessed_at
+		FROM dashboard_access_logs
+		WHERE team_id = $1 AND token_id = $2
+		ORDER BY accessed_at DESC
+		LIMIT $3
+	`, teamID, tokenID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dashboard access logs: %w", err)
+	}
+	defer rows.Close()
+
+	logs := []models.DashboardAccessLog{}
+	for rows.Next() {
+		var l models.DashboardAccessLog
+		var ip, userAgent sql.NullString
+		if err := rows.Scan(&l.ID, &l.TokenID, &l.TeamID, &ip, &userAgent, &l.Method, &l.Path, &l.AccessedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan dashboard access log: %w", err)
+		}
+		if ip.Valid {
+			l.IP = &ip.String
+		}
+		if userAgent.Valid {
+			l.UserAgent = &userAgent.String
+		}
+		logs = append(logs, l)
+	}
+	return logs, rows.Err()
+}
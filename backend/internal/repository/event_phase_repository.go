@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/database"
+	"github.com/rift26/backend/internal/models"
+)
+
+type EventPhaseRepository struct {
+	db *database.DB
+}
+
+func NewEventPhaseRepository(db *database.DB) *EventPhaseRepository {
+	return &EventPhaseRepository{db: db}
+}
+
+const eventPhaseColumns = `id, phase, city, mode, opens_at, closes_at, override_mode, created_at, updated_at`
+
+func scanEventPhase(row rowScanner) (*models.EventPhase, error) {
+	var p models.EventPhase
+	var opensAt, closesAt sql.NullTime
+	var override sql.NullString
+	if err := row.Scan(&p.ID, &p.Phase, &p.City, &p.Mode, &opensAt, &closesAt, &override, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if opensAt.Valid {
+		p.OpensAt = &opensAt.Time
+	}
+	if closesAt.Valid {
+		p.ClosesAt = &closesAt.Time
+	}
+	if override.Valid {
+		m := models.PhaseMode(override.String)
+		p.OverrideMode = &m
+	}
+	return &p, nil
+}
+
+// Phase rows are scoped to an event; uuid.Nil means the current event.
+
+// GetAll returns every phase row of the event, default rows first.
+func (r *EventPhaseRepository) GetAll(ctx context.Context, eventID uuid.UUID) ([]models.EventPhase, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+eventPhaseColumns+` FROM event_phases WHERE event_id = COALESCE($1, current_event_id()) ORDER BY phase, city`, EventArg(eventID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event phases: %w", err)
+	}
+	defer rows.Close()
+	list := make([]models.EventPhase, 0)
+	for rows.Next() {
+		p, err := scanEventPhase(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event phase: %w", err)
+		}
+		list = append(list, *p)
+	}
+	return list, rows.Err()
+}
+
+// GetForCity returns the default row ("") and the city row for a phase. Either may be nil.
+func (r *EventPhaseRepository) GetForCity(ctx context.Context, eventID uuid.UUID, phase models.EventPhaseKey, city string) (def, cityRow *models.EventPhase, err error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+eventPhaseColumns+` FROM event_phases WHERE event_id = COALESCE($1, current_event_id()) AND phase = $2 AND city IN ('', $3)`, EventArg(eventID), phase, city)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query event phase: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		p, err := scanEventPhase(rows)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan event phase: %w", err)
+		}
+		if p.City == "" {
+			def = p
+		} else {
+			cityRow = p
+		}
+	}
+	return def, cityRow, rows.Err()
+}
+
+// Upsert creates or updates the schedule (mode and window) of a phase row. Overrides are left untouched.
+func (r *EventPhaseRepository) Upsert(ctx context.Context, eventID uuid.UUID, p *models.EventPhase) error {
+	query := `
+		INSERT INTO event_phases (event_id, phase, city, mode, opens_at, closes_at, created_at, updated_at)
+		VALUES (COALESCE($1, current_event_id()), $2, $3, $4, $5, $6, NOW(), NOW())
+		ON CONFLICT (event_id, phase, city) DO UPDATE SET
+			mode = EXCLUDED.mode,
+			opens_at = EXCLUDED.opens_at,
+			closes_at = EXCLUDED.closes_at,
+			updated_at = NOW()
+		RETURNING ` + eventPhaseColumns
+	saved, err := scanEventPhase(r.db.QueryRowContext(ctx, query, EventArg(eventID), p.Phase, p.City, p.Mode, p.OpensAt, p.ClosesAt))
+	if err != nil {
+		return fmt.Errorf("failed to upsert event phase: %w", err)
+	}
+	*p = *saved
+	return nil
+}
+
+// SetOverride sets (or clears when mode is nil) the admin override of a phase row, creating a closed row if missing.
+func (r *EventPhaseRepository) SetOverride(ctx context.Context, eventID uuid.UUID, phase models.EventPhaseKey, city string, mode *models.PhaseMode) error {
+	var val interface{}
+	if mode != nil {
+		val = string(*mode)
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO event_phases (event_id, phase, city, mode, override_mode, created_at, updated_at)
+		VALUES (COALESCE($1, current_event_id()), $2, $3, 'closed', $4, NOW(), NOW())
+		ON CONFLICT (event_id, phase, city) DO UPDATE SET override_mode = EXCLUDED.override_mode, updated_at = NOW()
+	`, EventArg(eventID), phase, city, val)
+	if err != nil {
+		return fmt.Errorf("failed to set phase override: %w", err)
+	}
+	return nil
+}
+
+// InsertIfMissing seeds a phase row without touching an existing one.
+func (r *EventPhaseRepository) InsertIfMissing(ctx context.Context, eventID uuid.UUID, phase models.EventPhaseKey, city string, mode models.PhaseMode) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO event_phases (event_id, phase, city, mode, created_at, updated_at)
+		VALUES (COALESCE($1, current_event_id()), $2, $3, $4, NOW(), NOW())
+		ON CONFLICT (event_id, phase, city) DO NOTHING
+	`, EventArg(eventID), phase, city, mode)
+	if err != nil {
+		return fmt.Errorf("failed to seed event phase: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a phase row (used to drop a city-specific schedule).
+func (r *EventPhaseRepository) Delete(ctx context.Context, eventID uuid.UUID, phase models.EventPhaseKey, city string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM event_phases WHERE event_id = COALESCE($1, current_event_id()) AND phase = $2 AND city = $3`, EventArg(eventID), phase, city)
+	if err != nil {
+		return fmt.Errorf("failed to delete event phase: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("event phase not found")
+	}
+	return nil
+}
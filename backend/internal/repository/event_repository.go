@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/database"
+	"github.com/rift26/backend/internal/models"
+)
+
+// EventArg converts an event ID into a query argument: uuid.Nil becomes NULL, which
+// queries resolve to the current event via COALESCE($n, current_event_id()).
+func EventArg(eventID uuid.UUID) interface{} {
+	if eventID == uuid.Nil {
+		return nil
+	}
+	return eventID
+}
+
+type EventRepository struct {
+	db *database.DB
+}
+
+func NewEventRepository(db *database.DB) *EventRepository {
+	return &EventRepository{db: db}
+}
+
+const eventColumns = `id, slug, name, starts_at, ends_at, is_current, created_at, updated_at`
+
+func scanEvent(row rowScanner) (*models.Event, error) {
+	var e models.Event
+	var startsAt, endsAt sql.NullTime
+	if err := row.Scan(&e.ID, &e.Slug, &e.Name, &startsAt, &endsAt, &e.IsCurrent, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if startsAt.Valid {
+		e.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		e.EndsAt = &endsAt.Time
+	}
+	return &e, nil
+}
+
+// GetAll returns all events, newest first.
+func (r *EventRepository) GetAll(ctx context.Context) ([]models.Event, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+eventColumns+` FROM events ORDER BY COALESCE(starts_at, created_at) DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+	list := make([]models.Event, 0)
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		list = append(list, *e)
+	}
+	return list, rows.Err()
+}
+
+// GetByID returns an event, or nil if not found.
+func (r *EventRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Event, error) {
+	e, err := scanEvent(r.db.QueryRowContext(ctx, `SELECT `+eventColumns+` FROM events WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	return e, nil
+}
+
+// GetCurrent returns the current event, or nil if none is marked current.
+func (r *EventRepository) GetCurrent(ctx context.Context) (*models.Event, error) {
+	e, err := scanEvent(r.db.QueryRowContext(ctx, `SELECT `+eventColumns+` FROM events WHERE is_current LIMIT 1`))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current event: %w", err)
+	}
+	return e, nil
+}
+
+// Create inserts a new (non-current) event.
+func (r *EventRepository) Create(ctx context.Context, e *models.Event) error {
+	saved, err := scanEvent(r.db.QueryRowContext(ctx, `
+		INSERT INTO events (slug, name, starts_at, ends_at, is_current, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, FALSE, NOW(), NOW())
+		RETURNING `+eventColumns, e.Slug, e.Name, e.StartsAt, e.EndsAt))
+	if err != nil {
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+	*e = *saved
+	return nil
+}
+
+// Update saves name and dates of an event.
+func (r *EventRepository) Update(ctx context.Context, e *models.Event) error {
+	saved, err := scanEvent(r.db.QueryRowContext(ctx, `
+		UPDATE events SET name = $1, starts_at = $2, ends_at = $3, updated_at = NOW()
+		WHERE id = $4
+		RETURNING `+eventColumns, e.Name, e.StartsAt, e.EndsAt, e.ID))
+	if err != nil {
+		return fmt.Errorf("failed to update event: %w", err)
+	}
+	*e = *saved
+	return nil
+}
+
+// SetCurrent makes the event current (and every other event non-current) in one transaction.
+func (r *EventRepository) SetCurrent(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE events SET is_current = FALSE, updated_at = NOW() WHERE is_current AND id <> $1`, id); err != nil {
+		return fmt.Errorf("failed to clear current event: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, `UPDATE events SET is_current = TRUE, updated_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to set current event: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("event not found")
+	}
+	return tx.Commit()
+}
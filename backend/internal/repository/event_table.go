@@ -71,8 +71,9 @@ func (r *EventTableRepository) GetByID(id uuid.UUID) (*models.EventTable, error)
 	return table, nil
 }
 
-// GetAll retrieves all event tables with optional filters
-func (r *EventTableRepository) GetAll(city *string, isActive *bool) ([]models.EventTable, error) {
+// GetAll retrieves all event tables of the event (uuid.Nil = current event) with optional filters.
+// cityNames are the lower-case spellings of one city (see CityService.Variations); nil means all cities.
+func (r *EventTableRepository) GetAll(eventID uuid.UUID, cityNames []string, isActive *bool) ([]models.EventTable, error) {
 	// Build query dynamically to avoid prepared statement cache issues
 	var queryParts []string
 	var conditions []string
@@ -80,20 +81,15 @@ func (r *EventTableRepository) GetAll(city *string, isActive *bool) ([]models.Ev
 	queryParts = append(queryParts, `
 		SELECT id, table_name, table_number, city, capacity, is_active, created_at, updated_at
 		FROM event_tables
-		WHERE 1=1
+		WHERE event_id = COALESCE($1, current_event_id())
 	`)
 
-	if city != nil {
-		// Handle city variations: BLR/Bengaluru/Bangalore, PUNE/Pune, etc.
-		// Normalize and get all variations
-		cityNormalized := normalizeCityForEventTables(*city)
-		cityVariations := getCityVariations(cityNormalized)
-		
-		// Build IN clause with quoted values to avoid parameter binding issues
-		// Use string interpolation for whitelisted city values (safe since they're from our function)
-		quotedVariations := make([]string, len(cityVariations))
-		for i, cityVar := range cityVariations {
-			quotedVariations[i] = fmt.Sprintf("'%s'", strings.ReplaceAll(cityVar, "'", "''"))
+	if cityNames != nil {
+		// Tables store free-text city names (BLR/Bengaluru/Bangalore, ...), so match every spelling.
+		// Build IN clause with quoted, escaped values to avoid parameter binding issues
+		quotedVariations := make([]string, len(cityNames))
+		for i, cityVar := range cityNames {
+			quotedVariations[i] = fmt.Sprintf("'%s'", strings.ReplaceAll(strings.ToLower(cityVar), "'", "''"))
 		}
 		conditions = append(conditions, fmt.Sprintf("LOWER(TRIM(city)) IN (%s)", strings.Join(quotedVariations, ",")))
 	}
@@ -114,7 +110,7 @@ func (r *EventTableRepository) GetAll(city *string, isActive *bool) ([]models.Ev
 
 	query := strings.Join(queryParts, "")
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.Query(query, EventArg(eventID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get event tables: %w", err)
 	}
@@ -142,39 +138,6 @@ func (r *EventTableRepository) GetAll(city *string, isActive *bool) ([]models.Ev
 	return tables, nil
 }
 
-// normalizeCityForEventTables normalizes city names to match database values
-// The database stores cities as: BLR, PUNE, NOIDA, LKO
-func normalizeCityForEventTables(city string) string {
-	cityUpper := strings.ToUpper(strings.TrimSpace(city))
-	switch {
-	case cityUpper == "BENGALURU" || cityUpper == "BANGALORE" || cityUpper == "BLR":
-		return "BLR"
-	case cityUpper == "PUNE":
-		return "PUNE"
-	case cityUpper == "NOIDA":
-		return "NOIDA"
-	case cityUpper == "LUCKNOW" || cityUpper == "LKO":
-		return "LKO"
-	}
-	return cityUpper
-}
-
-// getCityVariations returns all possible city name variations for a normalized city code
-func getCityVariations(normalizedCity string) []string {
-	switch normalizedCity {
-	case "BLR":
-		return []string{"BLR", "Bengaluru", "Bangalore", "bengaluru", "bangalore", "blr"}
-	case "PUNE":
-		return []string{"PUNE", "Pune", "pune"}
-	case "NOIDA":
-		return []string{"NOIDA", "Noida", "noida"}
-	case "LKO":
-		return []string{"LKO", "Lucknow", "lucknow", "lko"}
-	default:
-		return []string{normalizedCity}
-	}
-}
-
 // Update updates an event table
 func (r *EventTableRepository) Update(table *models.EventTable) error {
 	query := `
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/database"
+	"github.com/rift26/backend/internal/models"
+)
+
+type JudgeRepository struct {
+	db *database.DB
+}
+
+func NewJudgeRepository(db *database.DB) *JudgeRepository {
+	return &JudgeRepository{db: db}
+}
+
+// JudgeSubmissionRow is a submission visible to a judge, with raw custom field JSON for the handler.
+type JudgeSubmissionRow struct {
+	models.JudgeSubmission
+	CustomFieldsJSON sql.NullString
+	PSFieldsJSON     sql.NullString // submission_fields from problem_statements
+}
+
+// judgeVisible restricts ps_submissions s / problem_statements pst to what judge $1 is assigned.
+const judgeVisible = `EXISTS (
+	SELECT 1 FROM judge_assignments ja
+	WHERE ja.judge_id = $1 AND ja.event_id = s.event_id
+	  AND (ja.submission_id = s.id OR LOWER(ja.track) = LOWER(pst.track))
+)`
+
+// judgeSubmissionSelect reads project fields from the judged revision (the final on-time one).
+const judgeSubmissionSelect = `
+	SELECT s.id, t.team_name, t.city, COALESCE(t.member_count, 0),
+	       s.problem_statement_id, pst.track, pst.title,
+	       COALESCE(j.github_url, ''), COALESCE(j.live_url, ''), COALESCE(j.extra_notes, ''),
+	       j.custom_fields, pst.submission_fields, j.submitted_at, n.note,
+	       j.version, j.is_late,
+	       (SELECT COUNT(*) FROM ps_submission_versions lv WHERE lv.submission_id = s.id AND lv.is_late AND lv.version > j.version)
+	FROM ps_submissions s
+	JOIN ps_submission_judged j ON j.submission_id = s.id
+	JOIN teams t ON s.team_id = t.id
+	JOIN problem_statements pst ON s.problem_statement_id = pst.id
+	LEFT JOIN judge_notes n ON n.submission_id = s.id AND n.judge_id = $1
+`
+
+func scanJudgeSubmission(row rowScanner) (*JudgeSubmissionRow, error) {
+	var r JudgeSubmissionRow
+	err := row.Scan(&r.SubmissionID, &r.TeamName, &r.City, &r.MemberCount,
+		&r.ProblemStatementID, &r.PSTrack, &r.PSName,
+		&r.GithubURL, &r.LiveURL, &r.ExtraNotes,
+		&r.CustomFieldsJSON, &r.PSFieldsJSON, &r.SubmittedAt, &r.Note,
+		&r.Version, &r.IsLate, &r.LateEditCount)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ListSubmissionsForJudge returns the submissions of the event (uuid.Nil = current event) assigned to a judge.
+func (r *JudgeRepository) ListSubmissionsForJudge(ctx context.Context, judgeID, eventID uuid.UUID, psID *uuid.UUID) ([]JudgeSubmissionRow, error) {
+	query := judgeSubmissionSelect + `WHERE s.event_id = COALESCE($2, current_event_id()) AND ` + judgeVisible
+	args := []interface{}{judgeID, EventArg(eventID)}
+	if psID != nil && *psID != uuid.Nil {
+		query += ` AND s.problem_statement_id = $3`
+		args = append(args, *psID)
+	}
+	query += ` ORDER BY pst.track, s.submitted_at`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query judge submissions: %w", err)
+	}
+	defer rows.Close()
+	list := make([]JudgeSubmissionRow, 0)
+	for rows.Next() {
+		row, err := scanJudgeSubmission(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan judge submission: %w", err)
+		}
+		list = append(list, *row)
+	}
+	return list, rows.Err()
+}
+
+// GetSubmissionForJudge returns one submission if it is assigned to the judge, or nil.
+func (r *JudgeRepository) GetSubmissionForJudge(ctx context.Context, judgeID, submissionID uuid.UUID) (*JudgeSubmissionRow, error) {
+	row, err := scanJudgeSubmission(r.db.QueryRowContext(ctx,
+		judgeSubmissionSelect+`WHERE s.id = $2 AND `+judgeVisible, judgeID, submissionID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get judge submission: %w", err)
+	}
+	return row, nil
+}
+
+// ListLateEdits returns the late revisions of a submission saved after the given (judged) version.
+func (r *JudgeRepository) ListLateEdits(ctx context.Context, submissionID uuid.UUID, afterVersion int) ([]models.PSSubmissionVersion, error) {
+	return listSubmissionVersions(ctx, r.db, `SELECT `+submissionVersionColumns+`
+		FROM ps_submission_versions
+		WHERE submission_id = $1 AND is_late AND version > $2
+		ORDER BY version`, submissionID, afterVersion)
+}
+
+// UpsertNote saves the judge's note on a submission.
+func (r *JudgeRepository) UpsertNote(ctx context.Context, judgeID, submissionID uuid.UUID, note string) (*models.JudgeNote, error) {
+	var n models.JudgeNote
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO judge_notes (judge_id, submission_id, note, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (judge_id, submission_id) DO UPDATE SET note = EXCLUDED.note, updated_at = NOW()
+		RETURNING id, judge_id, submission_id, note, created_at, updated_at`,
+		judgeID, submissionID, note).Scan(&n.ID, &n.JudgeID, &n.SubmissionID, &n.Note, &n.CreatedAt, &n.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save judge note: %w", err)
+	}
+	return &n, nil
+}
+
+// ListNotes returns every judge's note on a submission.
+func (r *JudgeRepository) ListNotes(ctx context.Context, submissionID uuid.UUID) ([]models.JudgeNote, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT n.id, n.judge_id, u.name, n.submission_id, n.note, n.created_at, n.updated_at
+		FROM judge_notes n
+		JOIN users u ON u.id = n.judge_id
+		WHERE n.submission_id = $1
+		ORDER BY n.updated_at DESC`, submissionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query judge notes: %w", err)
+	}
+	defer rows.Close()
+	list := make([]models.JudgeNote, 0)
+	for rows.Next() {
+		var n models.JudgeNote
+		if err := rows.Scan(&n.ID, &n.JudgeID, &n.JudgeName, &n.SubmissionID, &n.Note, &n.CreatedAt, &n.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan judge note: %w", err)
+		}
+		list = append(list, n)
+	}
+	return list, rows.Err()
+}
+
+// ListJudges returns judge users with their assignment count in the event.
+func (r *JudgeRepository) ListJudges(ctx context.Context, eventID uuid.UUID) ([]models.Judge, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.id, u.email, u.name,
+		       (SELECT COUNT(*) FROM judge_assignments ja WHERE ja.judge_id = u.id AND ja.event_id = COALESCE($1, current_event_id())),
+		       u.created_at
+		FROM users u
+		WHERE u.role = 'judge'
+		ORDER BY u.name, u.email`, EventArg(eventID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query judges: %w", err)
+	}
+	defer rows.Close()
+	list := make([]models.Judge, 0)
+	for rows.Next() {
+		var j models.Judge
+		if err := rows.Scan(&j.ID, &j.Email, &j.Name, &j.AssignmentCount, &j.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan judge: %w", err)
+		}
+		list = append(list, j)
+	}
+	return list, rows.Err()
+}
+
+// ListAssignments returns a judge's assignments in the event.
+func (r *JudgeRepository) ListAssignments(ctx context.Context, judgeID, eventID uuid.UUID) ([]models.JudgeAssignment, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT ja.id, ja.event_id, ja.judge_id, ja.track, ja.submission_id, t.team_name, ja.created_by, ja.created_at
+		FROM judge_assignments ja
+		LEFT JOIN ps_submissions s ON s.id = ja.submission_id
+		LEFT JOIN teams t ON t.id = s.team_id
+		WHERE ja.judge_id = $1 AND ja.event_id = COALESCE($2, current_event_id())
+		ORDER BY ja.track NULLS LAST, t.team_name`, judgeID, EventArg(eventID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query judge assignments: %w", err)
+	}
+	defer rows.Close()
+	list := make([]models.JudgeAssignment, 0)
+	for rows.Next() {
+		var a models.JudgeAssignment
+		if err := rows.Scan(&a.ID, &a.EventID, &a.JudgeID, &a.Track, &a.SubmissionID, &a.TeamName, &a.CreatedBy, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan judge assignment: %w", err)
+		}
+		list = append(list, a)
+	}
+	return list, rows.Err()
+}
+
+// CreateAssignment assigns a track or a submission to a judge. Track assignments are scoped to
+// the event; submission assignments take the submission's event.
+func (r *JudgeRepository) CreateAssignment(ctx context.Context, a *models.JudgeAssignment, eventID uuid.UUID) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO judge_assignments (event_id, judge_id, track, submission_id, created_by, created_at)
+		VALUES (COALESCE((SELECT event_id FROM ps_submissions WHERE id = $4), $1, current_event_id()), $2, $3, $4, $5, NOW())
+		RETURNING id, event_id, created_at`,
+		EventArg(eventID), a.JudgeID, a.Track, a.SubmissionID, a.CreatedBy).Scan(&a.ID, &a.EventID, &a.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create judge assignment (already assigned?): %w", err)
+	}
+	return nil
+}
+
+// DeleteAssignment removes one of a judge's assignments.
+func (r *JudgeRepository) DeleteAssignment(ctx context.Context, judgeID, assignmentID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM judge_assignments WHERE id = $1 AND judge_id = $2`, assignmentID, judgeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete judge assignment: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("assignment not found")
+	}
+	return nil
+}
+
+// SubmissionExists reports whether a submission exists.
+func (r *JudgeRepository) SubmissionExists(ctx context.Context, submissionID uuid.UUID) (bool, error) {
+	var ok bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM ps_submissions WHERE id = $1)`, submissionID).Scan(&ok); err != nil {
+		return false, fmt.Errorf("failed to check submission: %w", err)
+	}
+	return ok, nil
+}
@@ -0,0 +1,347 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/database"
+	"github.com/rift26/backend/internal/models"
+)
+
+// ErrRoundFinalized is returned when a finalized judging round's rubrics or scores would change.
+var ErrRoundFinalized = errors.New("judging round is finalized; its rubrics and scores are locked")
+
+// ErrRoundOtherEvent is returned when a score is saved in a round of another event than the submission's.
+var ErrRoundOtherEvent = errors.New("judging round belongs to another event than the submission")
+
+type JudgingRepository struct {
+	db *database.DB
+}
+
+func NewJudgingRepository(db *database.DB) *JudgingRepository {
+	return &JudgingRepository{db: db}
+}
+
+// RoundScoreRow is a score sheet of a round with the submission details the leaderboard shows.
+type RoundScoreRow struct {
+	models.JudgeScore
+	TeamID   uuid.UUID
+	TeamName string
+	City     *string
+	Track    string
+	PSName   string
+}
+
+const roundColumns = `id, event_id, name, normalize, finalized_at, finalized_by, created_by, created_at`
+
+func scanRound(row rowScanner) (*models.JudgingRound, error) {
+	var r models.JudgingRound
+	if err := row.Scan(&r.ID, &r.EventID, &r.Name, &r.Normalize, &r.FinalizedAt, &r.FinalizedBy, &r.CreatedBy, &r.CreatedAt); err != nil {
+		return nil, err
+	}
+	r.Rubrics = make([]models.JudgingRubric, 0)
+	return &r, nil
+}
+
+// lockOpenRound takes the round row FOR UPDATE for the rest of tx and returns its event. Rubric and
+// score writes hold the lock until they commit, so FinalizeRound (which updates the row) waits for
+// them and no write lands after a round is finalized.
+func lockOpenRound(ctx context.Context, tx *sql.Tx, roundID uuid.UUID) (uuid.UUID, error) {
+	var eventID uuid.UUID
+	var finalized bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT event_id, finalized_at IS NOT NULL FROM judging_rounds WHERE id = $1 FOR UPDATE`, roundID).Scan(&eventID, &finalized)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, fmt.Errorf("judging round not found")
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to lock judging round: %w", err)
+	}
+	if finalized {
+		return uuid.Nil, ErrRoundFinalized
+	}
+	return eventID, nil
+}
+
+// ListRounds returns the event's judging rounds with their rubrics, oldest first.
+func (r *JudgingRepository) ListRounds(ctx context.Context, eventID uuid.UUID) ([]models.JudgingRound, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+roundColumns+`
+		FROM judging_rounds
+		WHERE event_id = COALESCE($1, current_event_id())
+		ORDER BY created_at`, EventArg(eventID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query judging rounds: %w", err)
+	}
+	defer rows.Close()
+	list := make([]models.JudgingRound, 0)
+	index := make(map[uuid.UUID]int)
+	for rows.Next() {
+		round, err := scanRound(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan judging round: %w", err)
+		}
+		index[round.ID] = len(list)
+		list = append(list, *round)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rubrics, err := r.listRubrics(ctx, `
+		SELECT b.id, b.round_id, b.track, b.criteria, b.updated_by, b.created_at, b.updated_at
+		FROM judging_rubrics b
+		JOIN judging_rounds jr ON jr.id = b.round_id
+		WHERE jr.event_id = COALESCE($1, current_event_id())
+		ORDER BY LOWER(b.track)`, EventArg(eventID))
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range rubrics {
+		if i, ok := index[b.RoundID]; ok {
+			list[i].Rubrics = append(list[i].Rubrics, b)
+		}
+	}
+	return list, nil
+}
+
+// GetRound returns a round with its rubrics, or nil.
+func (r *JudgingRepository) GetRound(ctx context.Context, id uuid.UUID) (*models.JudgingRound, error) {
+	round, err := scanRound(r.db.QueryRowContext(ctx, `SELECT `+roundColumns+` FROM judging_rounds WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get judging round: %w", err)
+	}
+	rubrics, err := r.listRubrics(ctx, `
+		SELECT id, round_id, track, criteria, updated_by, created_at, updated_at
+		FROM judging_rubrics
+		WHERE round_id = $1
+		ORDER BY LOWER(track)`, id)
+	if err != nil {
+		return nil, err
+	}
+	round.Rubrics = rubrics
+	return round, nil
+}
+
+// CreateRound adds a round to the event (uuid.Nil = current event).
+func (r *JudgingRepository) CreateRound(ctx context.Context, round *models.JudgingRound, eventID uuid.UUID) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO judging_rounds (event_id, name, normalize, created_by, created_at)
+		VALUES (COALESCE($1, current_event_id()), $2, $3, $4, NOW())
+		RETURNING id, event_id, created_at`,
+		EventArg(eventID), round.Name, round.Normalize, round.CreatedBy).Scan(&round.ID, &round.EventID, &round.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create judging round (name already used?): %w", err)
+	}
+	round.Rubrics = make([]models.JudgingRubric, 0)
+	return nil
+}
+
+// UpdateRound renames an open round or changes its normalization default.
+func (r *JudgingRepository) UpdateRound(ctx context.Context, id uuid.UUID, name string, normalize bool) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE judging_rounds SET name = $2, normalize = $3
+		WHERE id = $1 AND finalized_at IS NULL`, id, name, normalize)
+	if err != nil {
+		return fmt.Errorf("failed to update judging round (name already used?): %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrRoundFinalized
+	}
+	return nil
+}
+
+// FinalizeRound locks an open round's rubrics and scores.
+func (r *JudgingRepository) FinalizeRound(ctx context.Context, id uuid.UUID, finalizedBy *uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE judging_rounds SET finalized_at = NOW(), finalized_by = $2
+		WHERE id = $1 AND finalized_at IS NULL`, id, finalizedBy)
+	if err != nil {
+		return fmt.Errorf("failed to finalize judging round: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrRoundFinalized
+	}
+	return nil
+}
+
+func (r *JudgingRepository) listRubrics(ctx context.Context, query string, args ...interface{}) ([]models.JudgingRubric, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query judging rubrics: %w", err)
+	}
+	defer rows.Close()
+	list := make([]models.JudgingRubric, 0)
+	for rows.Next() {
+		b, err := scanRubric(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan judging rubric: %w", err)
+		}
+		list = append(list, *b)
+	}
+	return list, rows.Err()
+}
+
+func scanRubric(row rowScanner) (*models.JudgingRubric, error) {
+	var b models.JudgingRubric
+	var criteria []byte
+	if err := row.Scan(&b.ID, &b.RoundID, &b.Track, &criteria, &b.UpdatedBy, &b.CreatedAt, &b.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(criteria, &b.Criteria); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// UpsertRubric creates or replaces the rubric of a track (matched case-insensitively) in an open round.
+func (r *JudgingRepository) UpsertRubric(ctx context.Context, roundID uuid.UUID, track string, criteria []models.RubricCriterion, updatedBy *uuid.UUID) (*models.JudgingRubric, error) {
+	raw, err := json.Marshal(criteria)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rubric criteria: %w", err)
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := lockOpenRound(ctx, tx, roundID); err != nil {
+		return nil, err
+	}
+	b, err := scanRubric(tx.QueryRowContext(ctx, `
+		INSERT INTO judging_rubrics (round_id, track, criteria, updated_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		ON CONFLICT (round_id, LOWER(track)) DO UPDATE
+		SET track = EXCLUDED.track, criteria = EXCLUDED.criteria, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING id, round_id, track, criteria, updated_by, created_at, updated_at`,
+		roundID, track, string(raw), updatedBy))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save judging rubric: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit judging rubric: %w", err)
+	}
+	return b, nil
+}
+
+// DeleteRubric removes a rubric from an open round. Score sheets are kept.
+func (r *JudgingRepository) DeleteRubric(ctx context.Context, roundID, rubricID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := lockOpenRound(ctx, tx, roundID); err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM judging_rubrics WHERE round_id = $1 AND id = $2`, roundID, rubricID)
+	if err != nil {
+		return fmt.Errorf("failed to delete judging rubric: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("rubric not found")
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit judging rubric: %w", err)
+	}
+	return nil
+}
+
+// UpsertScore saves a judge's sheet for a submission in an open round.
+func (r *JudgingRepository) UpsertScore(ctx context.Context, s *models.JudgeScore) error {
+	scores, err := json.Marshal(s.Scores)
+	if err != nil {
+		return fmt.Errorf("failed to encode scores: %w", err)
+	}
+	comments, err := json.Marshal(s.Comments)
+	if err != nil {
+		return fmt.Errorf("failed to encode score comments: %w", err)
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	roundEventID, err := lockOpenRound(ctx, tx, s.RoundID)
+	if err != nil {
+		return err
+	}
+	var submissionEventID uuid.UUID
+	if err := tx.QueryRowContext(ctx, `SELECT event_id FROM ps_submissions WHERE id = $1`, s.SubmissionID).Scan(&submissionEventID); err != nil {
+		return fmt.Errorf("failed to load submission event: %w", err)
+	}
+	if submissionEventID != roundEventID {
+		return ErrRoundOtherEvent
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO judge_scores (round_id, judge_id, submission_id, scores, comments, comment, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		ON CONFLICT (round_id, judge_id, submission_id) DO UPDATE
+		SET scores = EXCLUDED.scores, comments = EXCLUDED.comments, comment = EXCLUDED.comment, updated_at = NOW()
+		RETURNING id, created_at, updated_at`,
+		s.RoundID, s.JudgeID, s.SubmissionID, string(scores), string(comments), s.Comment).Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save judge score: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit judge score: %w", err)
+	}
+	return nil
+}
+
+// ListSubmissionScores returns the score sheets of a submission across rounds, only the judge's
+// own when judgeID is set.
+func (r *JudgingRepository) ListSubmissionScores(ctx context.Context, submissionID uuid.UUID, judgeID *uuid.UUID) ([]RoundScoreRow, error) {
+	return r.listScores(ctx, `js.submission_id = $1 AND ($2::uuid IS NULL OR js.judge_id = $2)`, submissionID, judgeID)
+}
+
+// ListRoundScores returns every score sheet of a round.
+func (r *JudgingRepository) ListRoundScores(ctx context.Context, roundID uuid.UUID) ([]RoundScoreRow, error) {
+	return r.listScores(ctx, `js.round_id = $1`, roundID)
+}
+
+func (r *JudgingRepository) listScores(ctx context.Context, where string, args ...interface{}) ([]RoundScoreRow, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT js.id, js.round_id, js.judge_id, u.name, js.submission_id, js.scores, js.comments, js.comment,
+		       js.created_at, js.updated_at, t.id, t.team_name, t.city, pst.track, pst.title
+		FROM judge_scores js
+		JOIN judging_rounds jr ON jr.id = js.round_id
+		JOIN users u ON u.id = js.judge_id
+		JOIN ps_submissions s ON s.id = js.submission_id
+		JOIN teams t ON t.id = s.team_id
+		JOIN problem_statements pst ON pst.id = s.problem_statement_id
+		WHERE `+where+`
+		ORDER BY jr.created_at, js.submission_id, u.name`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query judge scores: %w", err)
+	}
+	defer rows.Close()
+	list := make([]RoundScoreRow, 0)
+	for rows.Next() {
+		var row RoundScoreRow
+		var scores, comments []byte
+		err := rows.Scan(&row.ID, &row.RoundID, &row.JudgeID, &row.JudgeName, &row.SubmissionID,
+			&scores, &comments, &row.Comment, &row.CreatedAt, &row.UpdatedAt,
+			&row.TeamID, &row.TeamName, &row.City, &row.Track, &row.PSName)
+		if err == nil {
+			err = json.Unmarshal(scores, &row.Scores)
+		}
+		if err == nil {
+			err = json.Unmarshal(comments, &row.Comments)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan judge score: %w", err)
+		}
+		list = append(list, row)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/database"
+)
+
+// MagicLinkRepository records issued magic-link tokens so each can be used once
+type MagicLinkRepository struct {
+	db *database.DB
+}
+
+func NewMagicLinkRepository(db *database.DB) *MagicLinkRepository {
+	return &MagicLinkRepository{db: db}
+}
+
+// Create records a newly issued link
+func (r *MagicLinkRepository) Create(ctx context.Context, id, teamID uuid.UUID, email string, expiresAt time.Time, ip string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO magic_links (id, team_id, email, expires_at, requested_ip)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+	`, id, teamID, email, expiresAt, ip)
+	if err != nil {
+		return fmt.Errorf("failed to create magic link: %w", err)
+	}
+	return nil
+}
+
+// CountSince returns how many links were issued to an email since the given time
+func (r *MagicLinkRepository) CountSince(ctx context.Context, email string, since time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM magic_links WHERE LOWER(email) = LOWER($1) AND created_at >= $2
+	`, email, since).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count magic links: %w", err)
+	}
+	return count, nil
+}
+
+// Consume marks an unused, unexpired link as used. Returns false if it was already used,
+// has expired or does not belong to the team and email.
+func (r *MagicLinkRepository) Consume(ctx context.Context, id, teamID uuid.UUID, email string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE magic_links SET used_at = NOW()
+		WHERE id = $1 AND team_id = $2 AND LOWER(email) = LOWER($3)
+		  AND used_at IS NULL AND expires_at > NOW()
+	`, id, teamID, email)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume magic link: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to consume magic link: %w", err)
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/database"
+	"github.com/rift26/backend/internal/models"
+)
+
+type OTPLockoutRepository struct {
+	db *database.DB
+}
+
+func NewOTPLockoutRepository(db *database.DB) *OTPLockoutRepository {
+	return &OTPLockoutRepository{db: db}
+}
+
+const otpLockoutColumns = `l.team_id, COALESCE(t.team_name, ''), l.failed_attempts, l.lockout_count, l.locked_until, l.last_failed_at`
+
+func scanOTPLockout(row rowScanner) (*models.OTPLockout, error) {
+	var l models.OTPLockout
+	var lockedUntil, lastFailedAt sql.NullTime
+	if err := row.Scan(&l.TeamID, &l.TeamName, &l.FailedAttempts, &l.LockoutCount, &lockedUntil, &lastFailedAt); err != nil {
+		return nil, err
+	}
+	if lockedUntil.Valid {
+		l.LockedUntil = &lockedUntil.Time
+	}
+	if lastFailedAt.Valid {
+		l.LastFailedAt = &lastFailedAt.Time
+	}
+	return &l, nil
+}
+
+// Get returns the team's lockout state, or nil if it has no failed attempts on record
+func (r *OTPLockoutRepository) Get(ctx context.Context, teamID uuid.UUID) (*models.OTPLockout, error) {
+	l, err := scanOTPLockout(r.db.QueryRowContext(ctx, `
+		SELECT `+otpLockoutColumns+`
+		FROM otp_lockouts l LEFT JOIN teams t ON t.id = l.team_id
+		WHERE l.team_id = $1
+	`, teamID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OTP lockout: %w", err)
+	}
+	return l, nil
+}
+
+// RecordFailure counts a failed verification. On reaching maxAttempts the team is locked for
+// lockoutFor(lockout_count) and the attempt counter starts over.
+func (r *OTPLockoutRepository) RecordFailure(ctx context.Context, teamID uuid.UUID, maxAttempts int, lockoutFor func(lockoutCount int) time.Duration) (*models.OTPLockout, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var failed, lockouts int
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO otp_lockouts (team_id, failed_attempts, last_failed_at, updated_at)
+		VALUES ($1, 1, NOW(), NOW())
+		ON CONFLICT (team_id) DO UPDATE SET
+			failed_attempts = otp_lockouts.failed_attempts + 1,
+			last_failed_at = NOW(),
+			updated_at = NOW()
+		RETURNING failed_attempts, lockout_count
+	`, teamID).Scan(&failed, &lockouts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record OTP failure: %w", err)
+	}
+
+	if failed >= maxAttempts {
+		until := time.Now().Add(lockoutFor(lockouts))
+		_, err = tx.ExecContext(ctx, `
+			UPDATE otp_lockouts
+			SET failed_attempts = 0, lockout_count = lockout_count + 1, locked_until = $2, updated_at = NOW()
+			WHERE team_id = $1
+		`, teamID, until)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock out team: %w", err)
+		}
+	}
+
+	l, err := scanOTPLockout(tx.QueryRowContext(ctx, `
+		SELECT `+otpLockoutColumns+`
+		FROM otp_lockouts l LEFT JOIN teams t ON t.id = l.team_id
+		WHERE l.team_id = $1
+	`, teamID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OTP lockout: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit OTP failure: %w", err)
+	}
+	return l, nil
+}
+
+// Clear removes the team's failure count and any lockout. Returns false if there was none.
+func (r *OTPLockoutRepository) Clear(ctx context.Context, teamID uuid.UUID) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM otp_lockouts WHERE team_id = $1`, teamID)
+	if err != nil {
+		return false, fmt.Errorf("failed to clear OTP lockout: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to clear OTP lockout: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ListLocked returns teams that are currently locked out, most recent first
+func (r *OTPLockoutRepository) ListLocked(ctx context.Context) ([]models.OTPLockout, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+otpLockoutColumns+`
+		FROM otp_lockouts l LEFT JOIN teams t ON t.id = l.team_id
+		WHERE l.locked_until > NOW()
+		ORDER BY l.locked_until DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list OTP lockouts: %w", err)
+	}
+	defer rows.Close()
+
+	lockouts := []models.OTPLockout{}
+	for rows.Next() {
+		l, err := scanOTPLockout(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan OTP lockout: %w", err)
+		}
+		lockouts = append(lockouts, *l)
+	}
+	return lockouts, rows.Err()
+}
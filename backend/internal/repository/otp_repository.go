@@ -20,8 +20,8 @@ func NewOTPRepository(db *database.DB) *OTPRepository {
 	return &OTPRepository{db: db}
 }
 
-// CreateOTP generates and stores a new OTP for email
-func (r *OTPRepository) CreateOTP(ctx context.Context, email string, teamID uuid.UUID) (*models.OTP, error) {
+// CreateOTP generates and stores a new OTP for email, valid only for the given purpose
+func (r *OTPRepository) CreateOTP(ctx context.Context, email string, teamID uuid.UUID, purpose models.OTPPurpose) (*models.OTP, error) {
 	// Generate 6-digit OTP
 	otpCode := fmt.Sprintf("%06d", rand.Intn(1000000))
 
@@ -29,14 +29,14 @@ func (r *OTPRepository) CreateOTP(ctx context.Context, email string, teamID uuid
 	expiresAt := time.Now().Add(5 * time.Minute)
 
 	query := `
-		INSERT INTO otps (email, otp_code, team_id, expires_at)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, email, otp_code, team_id, expires_at, verified, created_at
+		INSERT INTO otps (email, otp_code, team_id, expires_at, purpose)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, email, otp_code, purpose, team_id, expires_at, verified, created_at
 	`
 
 	var otp models.OTP
-	err := r.db.QueryRowContext(ctx, query, email, otpCode, teamID, expiresAt).Scan(
-		&otp.ID, &otp.Email, &otp.OTPCode, &otp.TeamID,
+	err := r.db.QueryRowContext(ctx, query, email, otpCode, teamID, expiresAt, purpose).Scan(
+		&otp.ID, &otp.Email, &otp.OTPCode, &otp.Purpose, &otp.TeamID,
 		&otp.ExpiresAt, &otp.Verified, &otp.CreatedAt,
 	)
 	if err != nil {
@@ -46,12 +46,12 @@ func (r *OTPRepository) CreateOTP(ctx context.Context, email string, teamID uuid
 	return &otp, nil
 }
 
-// VerifyOTP validates an OTP code for email
-func (r *OTPRepository) VerifyOTP(ctx context.Context, email, otpCode string, teamID uuid.UUID) (bool, error) {
+// VerifyOTP validates an OTP code for email; codes issued for another purpose don't match
+func (r *OTPRepository) VerifyOTP(ctx context.Context, email, otpCode string, teamID uuid.UUID, purpose models.OTPPurpose) (bool, error) {
 	query := `
 		SELECT id, expires_at, verified
 		FROM otps
-		WHERE email = $1 AND otp_code = $2 AND team_id = $3
+		WHERE email = $1 AND otp_code = $2 AND team_id = $3 AND purpose = $4
 		ORDER BY created_at DESC
 		LIMIT 1
 	`
@@ -60,7 +60,7 @@ func (r *OTPRepository) VerifyOTP(ctx context.Context, email, otpCode string, te
 	var expiresAt time.Time
 	var verified bool
 
-	err := r.db.QueryRowContext(ctx, query, email, otpCode, teamID).Scan(&id, &expiresAt, &verified)
+	err := r.db.QueryRowContext(ctx, query, email, otpCode, teamID, purpose).Scan(&id, &expiresAt, &verified)
 	if err == sql.ErrNoRows {
 		return false, nil // OTP not found
 	}
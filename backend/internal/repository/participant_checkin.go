@@ -3,6 +3,7 @@ package repository
 import (
 	"database/sql"
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/google/uuid"
@@ -305,13 +306,14 @@ func (r *ParticipantCheckInRepository) CreateTableConfirmation(confirmation *mod
 	return nil
 }
 
-// allowedCities is the whitelist for GetRecentByCity to avoid SQL injection when inlining (avoids pq statement cache bug).
-var allowedCities = map[string]bool{"BLR": true, "PUNE": true, "NOIDA": true, "LKO": true}
+// cityCodeFormat restricts the city inlined by GetRecentByCity to the shape of a cities.code
+// to avoid SQL injection when inlining (avoids pq statement cache bug).
+var cityCodeFormat = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
 
 // GetRecentByCity returns recent check-ins for volunteers in the given city (for volunteer-admin dashboard).
-// city must be one of BLR, PUNE, NOIDA, LKO. No bound params to avoid pq prepared-statement cache issues.
+// city must be a city code (see CityService.Normalize). No bound params to avoid pq prepared-statement cache issues.
 func (r *ParticipantCheckInRepository) GetRecentByCity(city string, limit int) ([]models.CheckInWithDetails, error) {
-	if !allowedCities[city] {
+	if !cityCodeFormat.MatchString(city) {
 		return nil, fmt.Errorf("invalid city for check-ins: %s", city)
 	}
 	query := fmt.Sprintf(`
@@ -352,7 +354,7 @@ type CheckedInTeamFilters struct {
 
 // GetCheckedInTeamsByCity returns one row per checked-in team (team name, size, room allocated) for the city.
 func (r *ParticipantCheckInRepository) GetCheckedInTeamsByCity(city string, limit int, f CheckedInTeamFilters) ([]models.CheckedInTeam, error) {
-	if !allowedCities[city] {
+	if !cityCodeFormat.MatchString(city) {
 		return nil, fmt.Errorf("invalid city: %s", city)
 	}
 	// Base: distinct teams from participant_check_ins (via volunteers in city). Table = volunteer's assigned table (volunteers are table-specific).
@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/rift26/backend/internal/database"
@@ -16,7 +19,39 @@ func NewProblemStatementRepository(db *database.DB) *ProblemStatementRepository
 	return &ProblemStatementRepository{db: db}
 }
 
-func (r *ProblemStatementRepository) Create(ctx context.Context, ps *models.PSItem) error {
+const problemStatementColumns = `id, track, title, file_path, submission_fields, max_teams, city_max_teams, created_at, updated_at`
+
+func scanProblemStatement(row rowScanner) (*models.PSItem, error) {
+	var ps models.PSItem
+	var maxTeams sql.NullInt64
+	var cityMax []byte
+	err := row.Scan(&ps.ID, &ps.Track, &ps.Name, &ps.FilePath, &ps.SubmissionFields, &maxTeams, &cityMax, &ps.CreatedAt, &ps.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if maxTeams.Valid {
+		v := int(maxTeams.Int64)
+		ps.MaxTeams = &v
+	}
+	if len(cityMax) > 0 {
+		if err := json.Unmarshal(cityMax, &ps.CityMaxTeams); err != nil {
+			return nil, fmt.Errorf("failed to parse city_max_teams: %w", err)
+		}
+	}
+	return &ps, nil
+}
+
+// cityMaxTeamsJSON encodes per-city caps for the JSONB column
+func cityMaxTeamsJSON(caps map[string]int) string {
+	if len(caps) == 0 {
+		return "{}"
+	}
+	b, _ := json.Marshal(caps)
+	return string(b)
+}
+
+// Create inserts a problem statement into the event (uuid.Nil = current event).
+func (r *ProblemStatementRepository) Create(ctx context.Context, eventID uuid.UUID, ps *models.PSItem) error {
 	ps.ID = uuid.New()
 	var submissionFieldsValue interface{}
 	if ps.SubmissionFields.Valid {
@@ -25,43 +60,106 @@ func (r *ProblemStatementRepository) Create(ctx context.Context, ps *models.PSIt
 		submissionFieldsValue = nil
 	}
 	query := `
-		INSERT INTO problem_statements (id, track, title, file_path, submission_fields, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		INSERT INTO problem_statements (id, track, title, file_path, submission_fields, max_teams, city_max_teams, event_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8, current_event_id()), NOW(), NOW())
 		RETURNING created_at, updated_at
 	`
-	return r.db.QueryRowContext(ctx, query, ps.ID, ps.Track, ps.Name, ps.FilePath, submissionFieldsValue).Scan(&ps.CreatedAt, &ps.UpdatedAt)
+	return r.db.QueryRowContext(ctx, query, ps.ID, ps.Track, ps.Name, ps.FilePath, submissionFieldsValue,
+		ps.MaxTeams, cityMaxTeamsJSON(ps.CityMaxTeams), EventArg(eventID)).Scan(&ps.CreatedAt, &ps.UpdatedAt)
 }
 
-func (r *ProblemStatementRepository) GetAll(ctx context.Context) ([]models.PSItem, error) {
+// GetAll returns the problem statements of the event (uuid.Nil = current event).
+func (r *ProblemStatementRepository) GetAll(ctx context.Context, eventID uuid.UUID) ([]models.PSItem, error) {
 	query := `
-		SELECT id, track, title, file_path, submission_fields, created_at, updated_at
+		SELECT ` + problemStatementColumns + `
 		FROM problem_statements
+		WHERE event_id = COALESCE($1, current_event_id())
 		ORDER BY created_at ASC
 	`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, EventArg(eventID))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var list []models.PSItem
 	for rows.Next() {
-		var ps models.PSItem
-		if err := rows.Scan(&ps.ID, &ps.Track, &ps.Name, &ps.FilePath, &ps.SubmissionFields, &ps.CreatedAt, &ps.UpdatedAt); err != nil {
+		ps, err := scanProblemStatement(rows)
+		if err != nil {
 			return nil, err
 		}
-		list = append(list, ps)
+		list = append(list, *ps)
 	}
 	return list, rows.Err()
 }
 
 func (r *ProblemStatementRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.PSItem, error) {
-	query := `SELECT id, track, title, file_path, submission_fields, created_at, updated_at FROM problem_statements WHERE id = $1`
-	var ps models.PSItem
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&ps.ID, &ps.Track, &ps.Name, &ps.FilePath, &ps.SubmissionFields, &ps.CreatedAt, &ps.UpdatedAt)
+	query := `SELECT ` + problemStatementColumns + ` FROM problem_statements WHERE id = $1`
+	return scanProblemStatement(r.db.QueryRowContext(ctx, query, id))
+}
+
+// SetCapacity replaces a problem statement's overall and per-city caps. Returns false if it does not exist.
+func (r *ProblemStatementRepository) SetCapacity(ctx context.Context, id uuid.UUID, maxTeams *int, cityMaxTeams map[string]int) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE problem_statements SET max_teams = $2, city_max_teams = $3, updated_at = NOW() WHERE id = $1
+	`, id, maxTeams, cityMaxTeamsJSON(cityMaxTeams))
 	if err != nil {
-		return nil, err
+		return false, fmt.Errorf("failed to set problem statement capacity: %w", err)
 	}
-	return &ps, nil
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to set problem statement capacity: %w", err)
+	}
+	return n > 0, nil
+}
+
+// SetSubmissionFields replaces the submission field schema of a problem statement. Returns false if it doesn't exist.
+func (r *ProblemStatementRepository) SetSubmissionFields(ctx context.Context, id uuid.UUID, fieldsJSON string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE problem_statements SET submission_fields = $2, updated_at = NOW() WHERE id = $1
+	`, id, fieldsJSON)
+	if err != nil {
+		return false, fmt.Errorf("failed to set problem statement submission fields: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to set problem statement submission fields: %w", err)
+	}
+	return n > 0, nil
+}
+
+// LockCounts returns how many teams of the event have locked each problem statement (uuid.Nil = current event).
+func (r *ProblemStatementRepository) LockCounts(ctx context.Context, eventID uuid.UUID) (map[uuid.UUID]*models.PSLockCount, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT s.problem_statement_id, COALESCE(t.city, ''), COUNT(*)
+		FROM ps_selections s
+		JOIN teams t ON t.id = s.team_id
+		WHERE s.event_id = COALESCE($1, current_event_id())
+		GROUP BY s.problem_statement_id, t.city
+	`, EventArg(eventID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count problem statement locks: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[uuid.UUID]*models.PSLockCount)
+	for rows.Next() {
+		var psID uuid.UUID
+		var city string
+		var n int
+		if err := rows.Scan(&psID, &city, &n); err != nil {
+			return nil, fmt.Errorf("failed to scan problem statement lock count: %w", err)
+		}
+		c, ok := counts[psID]
+		if !ok {
+			c = &models.PSLockCount{ByCity: make(map[string]int)}
+			counts[psID] = c
+		}
+		c.Total += n
+		if city != "" {
+			c.ByCity[city] += n
+		}
+	}
+	return counts, rows.Err()
 }
 
 func (r *ProblemStatementRepository) Delete(ctx context.Context, id uuid.UUID) error {
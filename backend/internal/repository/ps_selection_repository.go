@@ -3,7 +3,9 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rift26/backend/internal/database"
@@ -18,26 +20,199 @@ func NewPSSelectionRepository(db *database.DB) *PSSelectionRepository {
 	return &PSSelectionRepository{db: db}
 }
 
-func (r *PSSelectionRepository) Create(ctx context.Context, sel *models.PSSelection) error {
+// ErrPSFull is returned when a problem statement has reached its overall or city cap
+var ErrPSFull = errors.New("problem statement is full")
+
+// PSLockChange describes who is changing a team's problem statement and what is allowed.
+type PSLockChange struct {
+	Actor          string // leader email or admin email
+	AdminReassign  bool   // admin override: recorded as a reassignment and not counted as a team switch
+	Reason         string
+	IgnoreCapacity bool
+	// Allow vets a change away from the team's current selection (nil on the first lock).
+	// lockedFor is the time since the team first locked a problem statement.
+	Allow func(current *models.PSSelection, lockedFor time.Duration) error
+}
+
+// LockWithinCapacity locks the problem statement for the team, but only while the problem
+// statement is under its overall cap and the cap of the team's city, and only if change.Allow
+// accepts the change. A problem statement of another event than the team's is reported as not found. The team and problem statement rows are locked for the
+// transaction so concurrent locks cannot overshoot a cap or a switch limit. The team's own
+// current selection does not count against a cap. Locking the PS the team already has is a no-op.
+// Every change is recorded in ps_selection_history.
+func (r *PSSelectionRepository) LockWithinCapacity(ctx context.Context, sel *models.PSSelection, change PSLockChange) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var city sql.NullString
+	if err := tx.QueryRowContext(ctx, `SELECT city FROM teams WHERE id = $1 FOR UPDATE`, sel.TeamID).Scan(&city); err != nil {
+		return fmt.Errorf("failed to get team city: %w", err)
+	}
+
+	var current *models.PSSelection
+	var lockedForSeconds int64
+	cur := models.PSSelection{}
+	err = tx.QueryRowContext(ctx, `
+		SELECT problem_statement_id, created_at, switch_count, EXTRACT(EPOCH FROM (NOW() - created_at))::bigint
+		FROM ps_selections WHERE team_id = $1
+	`, sel.TeamID).Scan(&cur.ProblemStatementID, &cur.CreatedAt, &cur.SwitchCount, &lockedForSeconds)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return fmt.Errorf("failed to get current selection: %w", err)
+	default:
+		cur.TeamID = sel.TeamID
+		current = &cur
+	}
+	if current != nil && current.ProblemStatementID == sel.ProblemStatementID {
+		return nil
+	}
+	if change.Allow != nil {
+		if err := change.Allow(current, time.Duration(lockedForSeconds)*time.Second); err != nil {
+			return err
+		}
+	}
+
+	var maxTeams, cityMax sql.NullInt64
+	err = tx.QueryRowContext(ctx, `
+		SELECT max_teams, (city_max_teams ->> $2)::int FROM problem_statements
+		WHERE id = $1 AND event_id = (SELECT event_id FROM teams WHERE id = $3)
+		FOR UPDATE
+	`, sel.ProblemStatementID, city.String, sel.TeamID).Scan(&maxTeams, &cityMax)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("problem statement not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock problem statement: %w", err)
+	}
+
+	if !change.IgnoreCapacity && (maxTeams.Valid || cityMax.Valid) {
+		var total, inCity int
+		err = tx.QueryRowContext(ctx, `
+			SELECT COUNT(*), COUNT(*) FILTER (WHERE t.city = $3)
+			FROM ps_selections s
+			JOIN teams t ON t.id = s.team_id
+			WHERE s.problem_statement_id = $1 AND s.team_id <> $2
+		`, sel.ProblemStatementID, sel.TeamID, city.String).Scan(&total, &inCity)
+		if err != nil {
+			return fmt.Errorf("failed to count problem statement locks: %w", err)
+		}
+		if (maxTeams.Valid && int64(total) >= maxTeams.Int64) || (cityMax.Valid && int64(inCity) >= cityMax.Int64) {
+			return ErrPSFull
+		}
+	}
+
+	action := models.PSActionLock
+	switchIncrement := 0
+	switch {
+	case change.AdminReassign:
+		action = models.PSActionReassign
+	case current != nil:
+		action = models.PSActionSwitch
+		switchIncrement = 1
+	}
+
 	sel.ID = uuid.New()
-	query := `
-		INSERT INTO ps_selections (id, team_id, problem_statement_id, leader_email, locked_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, NOW(), NOW(), NOW())
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO ps_selections (id, team_id, problem_statement_id, leader_email, locked_at, created_at, updated_at, event_id)
+		VALUES ($1, $2, $3, $4, NOW(), NOW(), NOW(), (SELECT event_id FROM teams WHERE id = $2))
 		ON CONFLICT (team_id) DO UPDATE SET
 			problem_statement_id = EXCLUDED.problem_statement_id,
 			leader_email = EXCLUDED.leader_email,
+			switch_count = ps_selections.switch_count + $5,
 			locked_at = NOW(),
 			updated_at = NOW()
-		RETURNING locked_at, created_at, updated_at
+		RETURNING id, locked_at, created_at, updated_at, switch_count
+	`, sel.ID, sel.TeamID, sel.ProblemStatementID, sel.LeaderEmail, switchIncrement).Scan(&sel.ID, &sel.LockedAt, &sel.CreatedAt, &sel.UpdatedAt, &sel.SwitchCount)
+	if err != nil {
+		return fmt.Errorf("failed to lock problem statement: %w", err)
+	}
+
+	actorType := "team"
+	if change.AdminReassign {
+		actorType = "admin"
+	}
+	var from *uuid.UUID
+	if current != nil {
+		from = &current.ProblemStatementID
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO ps_selection_history (event_id, team_id, from_problem_statement_id, to_problem_statement_id, action, actor_type, actor, reason)
+		VALUES ((SELECT event_id FROM teams WHERE id = $1), $1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''))
+	`, sel.TeamID, from, sel.ProblemStatementID, action, actorType, change.Actor, change.Reason)
+	if err != nil {
+		return fmt.Errorf("failed to record selection history: %w", err)
+	}
+	return tx.Commit()
+}
+
+// ListHistory returns problem statement changes of the event (uuid.Nil = current event), newest
+// first. teamID filters to one team when not uuid.Nil.
+func (r *PSSelectionRepository) ListHistory(ctx context.Context, eventID, teamID uuid.UUID, limit int) ([]models.PSSelectionHistory, error) {
+	query := `
+		SELECT h.id, h.team_id, t.team_name,
+		       h.from_problem_statement_id, pf.title, h.to_problem_statement_id, pt.title,
+		       h.action, h.actor_type, h.actor, h.reason, h.created_at
+		FROM ps_selection_history h
+		JOIN teams t ON t.id = h.team_id
+		LEFT JOIN problem_statements pf ON pf.id = h.from_problem_statement_id
+		LEFT JOIN problem_statements pt ON pt.id = h.to_problem_statement_id
+		WHERE t.event_id = COALESCE($1, current_event_id())
 	`
-	return r.db.QueryRowContext(ctx, query, sel.ID, sel.TeamID, sel.ProblemStatementID, sel.LeaderEmail).Scan(&sel.LockedAt, &sel.CreatedAt, &sel.UpdatedAt)
+	args := []interface{}{EventArg(eventID)}
+	if teamID != uuid.Nil {
+		args = append(args, teamID)
+		query += fmt.Sprintf(" AND h.team_id = $%d", len(args))
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY h.created_at DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list selection history: %w", err)
+	}
+	defer rows.Close()
+
+	list := []models.PSSelectionHistory{}
+	for rows.Next() {
+		var h models.PSSelectionHistory
+		var from, to uuid.NullUUID
+		var fromName, toName, actor, reason sql.NullString
+		if err := rows.Scan(&h.ID, &h.TeamID, &h.TeamName, &from, &fromName, &to, &toName,
+			&h.Action, &h.ActorType, &actor, &reason, &h.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan selection history: %w", err)
+		}
+		if from.Valid {
+			h.FromProblemStatementID = &from.UUID
+		}
+		if fromName.Valid {
+			h.FromPSName = &fromName.String
+		}
+		if to.Valid {
+			h.ToProblemStatementID = &to.UUID
+		}
+		if toName.Valid {
+			h.ToPSName = &toName.String
+		}
+		if actor.Valid {
+			h.Actor = &actor.String
+		}
+		if reason.Valid {
+			h.Reason = &reason.String
+		}
+		list = append(list, h)
+	}
+	return list, rows.Err()
 }
 
 func (r *PSSelectionRepository) GetByTeamID(ctx context.Context, teamID uuid.UUID) (*models.PSSelection, error) {
-	query := `SELECT id, team_id, problem_statement_id, leader_email, locked_at, created_at, updated_at, is_semi_finalist, position, best_web3 FROM ps_selections WHERE team_id = $1`
+	query := `SELECT id, team_id, problem_statement_id, leader_email, locked_at, created_at, updated_at, is_semi_finalist, position, best_web3, switch_count FROM ps_selections WHERE team_id = $1`
 	var sel models.PSSelection
 	var pos sql.NullInt32
-	err := r.db.QueryRowContext(ctx, query, teamID).Scan(&sel.ID, &sel.TeamID, &sel.ProblemStatementID, &sel.LeaderEmail, &sel.LockedAt, &sel.CreatedAt, &sel.UpdatedAt, &sel.IsSemiFinalist, &pos, &sel.BestWeb3)
+	err := r.db.QueryRowContext(ctx, query, teamID).Scan(&sel.ID, &sel.TeamID, &sel.ProblemStatementID, &sel.LeaderEmail, &sel.LockedAt, &sel.CreatedAt, &sel.UpdatedAt, &sel.IsSemiFinalist, &pos, &sel.BestWeb3, &sel.SwitchCount)
 	if err != nil {
 		return nil, err
 	}
@@ -48,7 +223,8 @@ func (r *PSSelectionRepository) GetByTeamID(ctx context.Context, teamID uuid.UUI
 	return &sel, nil
 }
 
-func (r *PSSelectionRepository) GetAllWithDetails(ctx context.Context, city *string) ([]models.PSSelectionWithDetails, error) {
+// GetAllWithDetails returns checked-in teams' selections of the event (uuid.Nil = current event).
+func (r *PSSelectionRepository) GetAllWithDetails(ctx context.Context, eventID uuid.UUID, city *string) ([]models.PSSelectionWithDetails, error) {
 	query := `
 		SELECT 
 			ps.id, ps.team_id, ps.problem_statement_id, ps.leader_email, ps.locked_at, ps.created_at, ps.updated_at, ps.is_semi_finalist, ps.position, ps.best_web3,
@@ -59,11 +235,11 @@ func (r *PSSelectionRepository) GetAllWithDetails(ctx context.Context, city *str
 		JOIN teams t ON ps.team_id = t.id
 		LEFT JOIN team_members tm ON tm.team_id = t.id AND tm.role = 'leader'
 		JOIN problem_statements pst ON ps.problem_statement_id = pst.id
-		WHERE t.status = 'checked_in'
+		WHERE t.status = 'checked_in' AND t.event_id = COALESCE($1, current_event_id())
 	`
-	args := []interface{}{}
+	args := []interface{}{EventArg(eventID)}
 	if city != nil && *city != "" {
-		query += " AND t.city = $1"
+		query += " AND t.city = $2"
 		args = append(args, *city)
 	}
 	query += " ORDER BY ps.locked_at DESC"
@@ -108,8 +284,8 @@ func (r *PSSelectionRepository) SetSemiFinalist(ctx context.Context, teamID uuid
 	return nil
 }
 
-// GetSemiFinalistsWithDetails returns only semi-finalist selections with team and PS info.
-func (r *PSSelectionRepository) GetSemiFinalistsWithDetails(ctx context.Context, city *string) ([]models.PSSelectionWithDetails, error) {
+// GetSemiFinalistsWithDetails returns only semi-finalist selections of the event (uuid.Nil = current event) with team and PS info.
+func (r *PSSelectionRepository) GetSemiFinalistsWithDetails(ctx context.Context, eventID uuid.UUID, city *string) ([]models.PSSelectionWithDetails, error) {
 	query := `
 		SELECT 
 			ps.id, ps.team_id, ps.problem_statement_id, ps.leader_email, ps.locked_at, ps.created_at, ps.updated_at, ps.is_semi_finalist, ps.position, ps.best_web3,
@@ -120,11 +296,11 @@ func (r *PSSelectionRepository) GetSemiFinalistsWithDetails(ctx context.Context,
 		JOIN teams t ON ps.team_id = t.id
 		LEFT JOIN team_members tm ON tm.team_id = t.id AND tm.role = 'leader'
 		JOIN problem_statements pst ON ps.problem_statement_id = pst.id
-		WHERE t.status = 'checked_in' AND ps.is_semi_finalist = TRUE
+		WHERE t.status = 'checked_in' AND ps.is_semi_finalist = TRUE AND t.event_id = COALESCE($1, current_event_id())
 	`
-	args := []interface{}{}
+	args := []interface{}{EventArg(eventID)}
 	if city != nil && *city != "" {
-		query += " AND t.city = $1"
+		query += " AND t.city = $2"
 		args = append(args, *city)
 	}
 	query += " ORDER BY ps.locked_at DESC"
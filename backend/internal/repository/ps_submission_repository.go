@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/rift26/backend/internal/database"
 	"github.com/rift26/backend/internal/models"
 )
@@ -21,7 +23,7 @@ func NewPSSubmissionRepository(db *database.DB) *PSSubmissionRepository {
 func (r *PSSubmissionRepository) GetByTeamAndPS(ctx context.Context, teamID, psID uuid.UUID) (*models.PSSubmission, error) {
 	query := `
 		SELECT id, team_id, problem_statement_id, linkedin_url, github_url, live_url, extra_notes, custom_fields,
-		       submitted_at, created_at, updated_at
+		       version, is_late, submitted_by, submitted_at, created_at, updated_at
 		FROM ps_submissions
 		WHERE team_id = $1 AND problem_statement_id = $2
 	`
@@ -29,7 +31,7 @@ func (r *PSSubmissionRepository) GetByTeamAndPS(ctx context.Context, teamID, psI
 	err := r.db.QueryRowContext(ctx, query, teamID, psID).Scan(
 		&sub.ID, &sub.TeamID, &sub.ProblemStatementID,
 		&sub.LinkedinURL, &sub.GithubURL, &sub.LiveURL, &sub.ExtraNotes, &sub.CustomFields,
-		&sub.SubmittedAt, &sub.CreatedAt, &sub.UpdatedAt,
+		&sub.Version, &sub.IsLate, &sub.SubmittedBy, &sub.SubmittedAt, &sub.CreatedAt, &sub.UpdatedAt,
 	)
 	if err != nil {
 		// Return nil if not found; caller can treat as no submission yet
@@ -38,7 +40,11 @@ func (r *PSSubmissionRepository) GetByTeamAndPS(ctx context.Context, teamID, psI
 	return &sub, nil
 }
 
-func (r *PSSubmissionRepository) Upsert(ctx context.Context, sub *models.PSSubmission) error {
+// SaveRevision stores a new revision of the team's submission: ps_submissions is updated to it and
+// the revision is kept in ps_submission_versions with the next version number. deadline is the
+// portal's scheduled close at the time of saving (nil when unscheduled). Attachments uploaded with
+// the revision are recorded in the same transaction.
+func (r *PSSubmissionRepository) SaveRevision(ctx context.Context, sub *models.PSSubmission, deadline *time.Time, attachments []models.SubmissionAttachment) error {
 	if sub.ID == uuid.Nil {
 		sub.ID = uuid.New()
 	}
@@ -48,13 +54,33 @@ func (r *PSSubmissionRepository) Upsert(ctx context.Context, sub *models.PSSubmi
 	} else {
 		customFieldsValue = nil
 	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i := range attachments {
+		a := &attachments[i]
+		if err := tx.QueryRowContext(ctx, `
+			INSERT INTO ps_submission_attachments (id, event_id, team_id, problem_statement_id, field_key,
+				filename, content_type, size_bytes, sha256, storage_key, uploaded_by, created_at)
+			VALUES ($1, (SELECT event_id FROM teams WHERE id = $2), $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW())
+			RETURNING created_at`,
+			a.ID, a.TeamID, a.ProblemStatementID, a.FieldKey, a.Filename, a.ContentType, a.SizeBytes, a.SHA256, a.StorageKey, a.UploadedBy,
+		).Scan(&a.CreatedAt); err != nil {
+			return fmt.Errorf("insert ps_submission_attachment: %w", err)
+		}
+	}
+
 	query := `
 		INSERT INTO ps_submissions (
 			id, team_id, problem_statement_id, linkedin_url, github_url, live_url, extra_notes, custom_fields,
-			submitted_at, created_at, updated_at
+			version, is_late, submitted_by, submitted_at, created_at, updated_at, event_id
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8,
-			NOW(), NOW(), NOW()
+			1, $9, $10, NOW(), NOW(), NOW(), (SELECT event_id FROM teams WHERE id = $2)
 		)
 		ON CONFLICT (team_id, problem_statement_id) DO UPDATE SET
 			linkedin_url = EXCLUDED.linkedin_url,
@@ -62,23 +88,133 @@ func (r *PSSubmissionRepository) Upsert(ctx context.Context, sub *models.PSSubmi
 			live_url = EXCLUDED.live_url,
 			extra_notes = EXCLUDED.extra_notes,
 			custom_fields = EXCLUDED.custom_fields,
+			version = ps_submissions.version + 1,
+			is_late = EXCLUDED.is_late,
+			submitted_by = EXCLUDED.submitted_by,
 			submitted_at = NOW(),
 			updated_at = NOW()
-		RETURNING submitted_at, created_at, updated_at
+		RETURNING id, version, submitted_at, created_at, updated_at
 	`
-	if err := r.db.QueryRowContext(
+	if err := tx.QueryRowContext(
 		ctx,
 		query,
 		sub.ID, sub.TeamID, sub.ProblemStatementID,
 		sub.LinkedinURL, sub.GithubURL, sub.LiveURL, sub.ExtraNotes, customFieldsValue,
-	).Scan(&sub.SubmittedAt, &sub.CreatedAt, &sub.UpdatedAt); err != nil {
+		sub.IsLate, sub.SubmittedBy,
+	).Scan(&sub.ID, &sub.Version, &sub.SubmittedAt, &sub.CreatedAt, &sub.UpdatedAt); err != nil {
 		return fmt.Errorf("upsert ps_submission: %w", err)
 	}
-	return nil
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO ps_submission_versions (
+			submission_id, event_id, team_id, problem_statement_id, version,
+			linkedin_url, github_url, live_url, extra_notes, custom_fields,
+			submitted_by, is_late, deadline, submitted_at
+		)
+		SELECT id, event_id, team_id, problem_statement_id, version,
+			linkedin_url, github_url, live_url, extra_notes, custom_fields,
+			submitted_by, is_late, $2, submitted_at
+		FROM ps_submissions WHERE id = $1`,
+		sub.ID, deadline,
+	); err != nil {
+		return fmt.Errorf("insert ps_submission_version: %w", err)
+	}
+	return tx.Commit()
+}
+
+const submissionAttachmentColumns = `id, team_id, problem_statement_id, field_key, filename, content_type,
+	size_bytes, sha256, storage_key, uploaded_by, created_at`
+
+// GetAttachments returns the attachments with the given IDs, keyed by ID (missing ones are left out).
+func (r *PSSubmissionRepository) GetAttachments(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.SubmissionAttachment, error) {
+	out := make(map[uuid.UUID]models.SubmissionAttachment, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	idStrings := make([]string, len(ids))
+	for i, id := range ids {
+		idStrings[i] = id.String()
+	}
+	rows, err := r.db.QueryContext(ctx, `SELECT `+submissionAttachmentColumns+`
+		FROM ps_submission_attachments WHERE id = ANY($1::uuid[])`, pq.Array(idStrings))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query submission attachments: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		a, err := scanSubmissionAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan submission attachment: %w", err)
+		}
+		out[a.ID] = *a
+	}
+	return out, rows.Err()
+}
+
+// GetSubmissionAttachment returns an attachment if some version of the submission references it, or nil.
+func (r *PSSubmissionRepository) GetSubmissionAttachment(ctx context.Context, submissionID, attachmentID uuid.UUID) (*models.SubmissionAttachment, error) {
+	a, err := scanSubmissionAttachment(r.db.QueryRowContext(ctx, `SELECT `+submissionAttachmentColumns+`
+		FROM ps_submission_attachments a
+		WHERE a.id = $2 AND EXISTS (
+			SELECT 1 FROM ps_submission_versions v, jsonb_each_text(COALESCE(v.custom_fields, '{}'::jsonb)) f
+			WHERE v.submission_id = $1 AND f.value = a.id::text
+		)`, submissionID, attachmentID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get submission attachment: %w", err)
+	}
+	return a, nil
+}
+
+func scanSubmissionAttachment(row rowScanner) (*models.SubmissionAttachment, error) {
+	var a models.SubmissionAttachment
+	err := row.Scan(&a.ID, &a.TeamID, &a.ProblemStatementID, &a.FieldKey, &a.Filename, &a.ContentType,
+		&a.SizeBytes, &a.SHA256, &a.StorageKey, &a.UploadedBy, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+const submissionVersionColumns = `id, submission_id, team_id, problem_statement_id, version,
+	COALESCE(linkedin_url, ''), COALESCE(github_url, ''), COALESCE(live_url, ''), COALESCE(extra_notes, ''),
+	custom_fields, submitted_by, is_late, deadline, submitted_at`
+
+// listSubmissionVersions runs a query selecting submissionVersionColumns.
+func listSubmissionVersions(ctx context.Context, db *database.DB, query string, args ...interface{}) ([]models.PSSubmissionVersion, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query submission versions: %w", err)
+	}
+	defer rows.Close()
+	list := make([]models.PSSubmissionVersion, 0)
+	for rows.Next() {
+		var v models.PSSubmissionVersion
+		var customFields []byte
+		if err := rows.Scan(&v.ID, &v.SubmissionID, &v.TeamID, &v.ProblemStatementID, &v.Version,
+			&v.LinkedinURL, &v.GithubURL, &v.LiveURL, &v.ExtraNotes,
+			&customFields, &v.SubmittedBy, &v.IsLate, &v.Deadline, &v.SubmittedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan submission version: %w", err)
+		}
+		v.CustomFields = customFields
+		list = append(list, v)
+	}
+	return list, rows.Err()
+}
+
+// ListVersions returns every revision of a submission, oldest first.
+func (r *PSSubmissionRepository) ListVersions(ctx context.Context, submissionID uuid.UUID) ([]models.PSSubmissionVersion, error) {
+	return listSubmissionVersions(ctx, r.db, `SELECT `+submissionVersionColumns+`
+		FROM ps_submission_versions
+		WHERE submission_id = $1
+		ORDER BY version`, submissionID)
 }
 
 // JudgingRow is one row for the judging view: team + PS + submission fields.
 type JudgingRow struct {
+	SubmissionID       uuid.UUID  `json:"submission_id"`
 	TeamID             uuid.UUID  `json:"team_id"`
 	TeamName           string     `json:"team_name"`
 	City               *string    `json:"city"`
@@ -95,26 +231,34 @@ type JudgingRow struct {
 	CustomFieldsJSON   sql.NullString `json:"-" db:"custom_fields"`
 	PSFieldsJSON       sql.NullString `json:"-" db:"ps_submission_fields"` // submission_fields from problem_statements
 	SubmittedAt        string     `json:"submitted_at"`
+	Version            int        `json:"version"`         // revision shown: the final on-time one when there is one
+	IsLate             bool       `json:"is_late"`         // no on-time revision exists, so the shown one is late
+	LatestVersion      int        `json:"latest_version"`
+	LateEdits          int        `json:"late_edits"`      // late revisions saved after the one shown
 }
 
-// GetAllForJudging returns all submissions with team and PS details, optional city and PS filters.
-func (r *PSSubmissionRepository) GetAllForJudging(ctx context.Context, city *string, psID *uuid.UUID) ([]JudgingRow, error) {
+// GetAllForJudging returns all submissions of the event (uuid.Nil = current event) with team and PS details, optional city and PS filters.
+// Project fields come from the judged revision (ps_submission_judged); later late edits are only counted.
+func (r *PSSubmissionRepository) GetAllForJudging(ctx context.Context, eventID uuid.UUID, city *string, psID *uuid.UUID) ([]JudgingRow, error) {
 	query := `
 		SELECT 
-			t.id AS team_id, t.team_name, t.city,
+			s.id, t.id AS team_id, t.team_name, t.city,
 			(SELECT name FROM team_members WHERE team_id = t.id AND role = 'leader' LIMIT 1),
 			(SELECT email FROM team_members WHERE team_id = t.id AND role = 'leader' LIMIT 1),
 			(SELECT string_agg(name, ', ' ORDER BY role DESC, name) FROM team_members WHERE team_id = t.id),
 			s.problem_statement_id, pst.track AS ps_track, pst.title AS ps_name,
-			COALESCE(s.linkedin_url,''), COALESCE(s.github_url,''), COALESCE(s.live_url,''), COALESCE(s.extra_notes,''),
-			s.custom_fields, pst.submission_fields, s.submitted_at
+			COALESCE(j.linkedin_url,''), COALESCE(j.github_url,''), COALESCE(j.live_url,''), COALESCE(j.extra_notes,''),
+			j.custom_fields, pst.submission_fields, j.submitted_at,
+			j.version, j.is_late, s.version,
+			(SELECT COUNT(*) FROM ps_submission_versions lv WHERE lv.submission_id = s.id AND lv.is_late AND lv.version > j.version)
 		FROM ps_submissions s
+		JOIN ps_submission_judged j ON j.submission_id = s.id
 		JOIN teams t ON s.team_id = t.id
 		JOIN problem_statements pst ON s.problem_statement_id = pst.id
-		WHERE 1=1
+		WHERE s.event_id = COALESCE($1, current_event_id())
 	`
-	args := []interface{}{}
-	argNum := 1
+	args := []interface{}{EventArg(eventID)}
+	argNum := 2
 	if city != nil && *city != "" {
 		query += fmt.Sprintf(" AND t.city = $%d", argNum)
 		args = append(args, *city)
@@ -136,11 +280,12 @@ func (r *PSSubmissionRepository) GetAllForJudging(ctx context.Context, city *str
 		var row JudgingRow
 		var submittedAt interface{}
 		var leaderName, leaderEmail, memberNames sql.NullString
-		err := rows.Scan(&row.TeamID, &row.TeamName, &row.City,
+		err := rows.Scan(&row.SubmissionID, &row.TeamID, &row.TeamName, &row.City,
 			&leaderName, &leaderEmail, &memberNames,
 			&row.ProblemStatementID, &row.PSTrack, &row.PSName,
 			&row.LinkedinURL, &row.GithubURL, &row.LiveURL, &row.ExtraNotes,
-			&row.CustomFieldsJSON, &row.PSFieldsJSON, &submittedAt)
+			&row.CustomFieldsJSON, &row.PSFieldsJSON, &submittedAt,
+			&row.Version, &row.IsLate, &row.LatestVersion, &row.LateEdits)
 		if err != nil {
 			return nil, err
 		}
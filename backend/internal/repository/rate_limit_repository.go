@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/rift26/backend/internal/database"
+)
+
+// rateLimitCleanupInterval is how often expired buckets are deleted
+const rateLimitCleanupInterval = 10 * time.Minute
+
+// RateLimitRepository is the Postgres rate limit store shared by all API instances
+type RateLimitRepository struct {
+	db *database.DB
+
+	mu          sync.Mutex
+	lastCleanup time.Time
+}
+
+func NewRateLimitRepository(db *database.DB) *RateLimitRepository {
+	return &RateLimitRepository{db: db}
+}
+
+// Allow records a hit for key if it is under limit in the sliding window ending now.
+// The estimate is the current window's count plus the previous window's count weighted
+// by how much of it still overlaps the sliding window.
+func (r *RateLimitRepository) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	r.maybeCleanup()
+
+	now := time.Now()
+	start := now.Truncate(window)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Serialize concurrent hits on the same key across instances
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, key); err != nil {
+		return false, fmt.Errorf("failed to lock rate limit key: %w", err)
+	}
+
+	var current, previous int
+	err = tx.QueryRowContext(ctx, `
+		SELECT
+			COALESCE(SUM(count) FILTER (WHERE window_start = $2), 0),
+			COALESCE(SUM(count) FILTER (WHERE window_start = $3), 0)
+		FROM rate_limit_buckets
+		WHERE key = $1 AND window_start IN ($2, $3)
+	`, key, start, start.Add(-window)).Scan(&current, &previous)
+	if err != nil {
+		return false, fmt.Errorf("failed to read rate limit: %w", err)
+	}
+
+	if !underLimit(current, previous, now.Sub(start), window, limit) {
+		return false, tx.Commit()
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO rate_limit_buckets (key, window_start, count, expires_at)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (key, window_start) DO UPDATE SET count = rate_limit_buckets.count + 1
+	`, key, start, start.Add(2*window))
+	if err != nil {
+		return false, fmt.Errorf("failed to record rate limit hit: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit rate limit hit: %w", err)
+	}
+	return true, nil
+}
+
+// Reset clears all counters for key
+func (r *RateLimitRepository) Reset(ctx context.Context, key string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM rate_limit_buckets WHERE key = $1`, key); err != nil {
+		return fmt.Errorf("failed to reset rate limit: %w", err)
+	}
+	return nil
+}
+
+// maybeCleanup deletes expired buckets in the background at most once per interval
+func (r *RateLimitRepository) maybeCleanup() {
+	r.mu.Lock()
+	if time.Since(r.lastCleanup) < rateLimitCleanupInterval {
+		r.mu.Unlock()
+		return
+	}
+	r.lastCleanup = time.Now()
+	r.mu.Unlock()
+
+	go func() {
+		if _, err := r.db.Exec(`DELETE FROM rate_limit_buckets WHERE expires_at < NOW()`); err != nil {
+			log.Printf("[RateLimit] Failed to clean up expired buckets: %v", err)
+		}
+	}()
+}
+
+// underLimit applies the sliding window estimate
+func underLimit(current, previous int, elapsed, window time.Duration, limit int) bool {
+	weight := 1 - float64(elapsed)/float64(window)
+	return float64(previous)*weight+float64(current) < float64(limit)
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/database"
+	"github.com/rift26/backend/internal/models"
+)
+
+type SessionRepository struct {
+	db *database.DB
+}
+
+func NewSessionRepository(db *database.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+const sessionColumns = `id, subject_id, role, email, team_id, city, user_agent, ip, expires_at, revoked_at, created_at, last_used_at`
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSession(row rowScanner) (*models.AuthSession, error) {
+	var s models.AuthSession
+	err := row.Scan(&s.ID, &s.SubjectID, &s.Role, &s.Email, &s.TeamID, &s.City, &s.UserAgent, &s.IP,
+		&s.ExpiresAt, &s.RevokedAt, &s.CreatedAt, &s.LastUsedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Create inserts a new session with the hash of its first refresh token.
+func (r *SessionRepository) Create(ctx context.Context, s *models.AuthSession, refreshHash string) error {
+	saved, err := scanSession(r.db.QueryRowContext(ctx, `
+		INSERT INTO auth_sessions (subject_id, role, email, team_id, city, refresh_token_hash, user_agent, ip, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING `+sessionColumns,
+		s.SubjectID, s.Role, s.Email, s.TeamID, s.City, refreshHash, s.UserAgent, s.IP, s.ExpiresAt))
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	*s = *saved
+	return nil
+}
+
+// GetByRefreshHash finds the session owning a refresh token. reused is true when the hash is the
+// session's previous (already rotated) token.
+func (r *SessionRepository) GetByRefreshHash(ctx context.Context, hash string) (s *models.AuthSession, reused bool, err error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+sessionColumns+`, previous_token_hash = $1
+		FROM auth_sessions
+		WHERE refresh_token_hash = $1 OR previous_token_hash = $1
+		LIMIT 1`, hash)
+	var sess models.AuthSession
+	var prev sql.NullBool
+	err = row.Scan(&sess.ID, &sess.SubjectID, &sess.Role, &sess.Email, &sess.TeamID, &sess.City, &sess.UserAgent, &sess.IP,
+		&sess.ExpiresAt, &sess.RevokedAt, &sess.CreatedAt, &sess.LastUsedAt, &prev)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get session: %w", err)
+	}
+	return &sess, prev.Valid && prev.Bool, nil
+}
+
+// Rotate swaps the refresh token of an active session. It returns false if the old token was
+// already rotated or the session was revoked meanwhile.
+func (r *SessionRepository) Rotate(ctx context.Context, id uuid.UUID, oldHash, newHash string, expiresAt time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE auth_sessions
+		SET previous_token_hash = refresh_token_hash, refresh_token_hash = $3, expires_at = $4, last_used_at = NOW()
+		WHERE id = $1 AND refresh_token_hash = $2 AND revoked_at IS NULL`,
+		id, oldHash, newHash, expiresAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to rotate session: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// Revoke ends one session.
+func (r *SessionRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE auth_sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// RevokeAllForSubject ends every session of a subject and rejects its older sessionless tokens.
+func (r *SessionRepository) RevokeAllForSubject(ctx context.Context, subjectID uuid.UUID) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE auth_sessions SET revoked_at = NOW() WHERE subject_id = $1 AND revoked_at IS NULL`, subjectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO auth_revocations (subject_id, revoked_before) VALUES ($1, NOW())
+		ON CONFLICT (subject_id) DO UPDATE SET revoked_before = EXCLUDED.revoked_before`, subjectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record revocation: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit revocation: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// GetByID returns a session, or nil if not found.
+func (r *SessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.AuthSession, error) {
+	s, err := scanSession(r.db.QueryRowContext(ctx, `SELECT `+sessionColumns+` FROM auth_sessions WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return s, nil
+}
+
+// GetRevokedBefore returns the cutoff for sessionless tokens of a subject, or nil if none.
+func (r *SessionRepository) GetRevokedBefore(ctx context.Context, subjectID uuid.UUID) (*time.Time, error) {
+	var t time.Time
+	err := r.db.QueryRowContext(ctx, `SELECT revoked_before FROM auth_revocations WHERE subject_id = $1`, subjectID).Scan(&t)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revocation: %w", err)
+	}
+	return &t, nil
+}
+
+// ListActive returns the unexpired, unrevoked sessions of a subject, newest first.
+func (r *SessionRepository) ListActive(ctx context.Context, subjectID uuid.UUID) ([]models.AuthSession, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+sessionColumns+` FROM auth_sessions
+		WHERE subject_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_used_at DESC`, subjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+	list := []models.AuthSession{}
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		list = append(list, *s)
+	}
+	return list, rows.Err()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/google/uuid"
 	"github.com/rift26/backend/internal/database"
 )
 
@@ -15,9 +16,10 @@ func NewSettingsRepository(db *database.DB) *SettingsRepository {
 	return &SettingsRepository{db: db}
 }
 
-func (r *SettingsRepository) Get(ctx context.Context, key string) (string, error) {
+// Settings are scoped to an event (uuid.Nil = current event).
+func (r *SettingsRepository) Get(ctx context.Context, eventID uuid.UUID, key string) (string, error) {
 	var value string
-	err := r.db.QueryRowContext(ctx, `SELECT value FROM settings WHERE key = $1`, key).Scan(&value)
+	err := r.db.QueryRowContext(ctx, `SELECT value FROM settings WHERE event_id = COALESCE($1, current_event_id()) AND key = $2`, EventArg(eventID), key).Scan(&value)
 	if err == sql.ErrNoRows {
 		return "", nil
 	}
@@ -27,16 +29,16 @@ func (r *SettingsRepository) Get(ctx context.Context, key string) (string, error
 	return value, nil
 }
 
-func (r *SettingsRepository) Set(ctx context.Context, key, value string) error {
+func (r *SettingsRepository) Set(ctx context.Context, eventID uuid.UUID, key, value string) error {
 	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO settings (key, value, updated_at) VALUES ($1, $2, NOW())
-		ON CONFLICT (key) DO UPDATE SET value = $2, updated_at = NOW()
-	`, key, value)
+		INSERT INTO settings (event_id, key, value, updated_at) VALUES (COALESCE($1, current_event_id()), $2, $3, NOW())
+		ON CONFLICT (event_id, key) DO UPDATE SET value = $3, updated_at = NOW()
+	`, EventArg(eventID), key, value)
 	return err
 }
 
 // Delete removes a setting by key (e.g. to reset release state).
-func (r *SettingsRepository) Delete(ctx context.Context, key string) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM settings WHERE key = $1`, key)
+func (r *SettingsRepository) Delete(ctx context.Context, eventID uuid.UUID, key string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM settings WHERE event_id = COALESCE($1, current_event_id()) AND key = $2`, EventArg(eventID), key)
 	return err
 }
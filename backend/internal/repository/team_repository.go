@@ -21,45 +21,72 @@ func NewTeamRepository(db *database.DB) *TeamRepository {
 	return &TeamRepository{db: db}
 }
 
-// SearchByName performs fuzzy search on team names (teams that completed RSVP I and/or Final Confirmation)
-func (r *TeamRepository) SearchByName(ctx context.Context, teamName string) ([]models.Team, error) {
-	query := `
-		SELECT id, team_name, city, status, problem_statement, qr_code_token,
-		       rsvp_locked, rsvp_locked_at, checked_in_at, checked_in_by,
-		       dashboard_token, created_at, updated_at, member_count
-		FROM teams
-		WHERE LOWER(team_name) LIKE LOWER($1) 
-		  AND status IN ('rsvp_done', 'rsvp2_done', 'checked_in')
-		ORDER BY team_name
-		LIMIT 10
+// TeamSearchHit is one ranked team search result with its leader (first member if no leader).
+type TeamSearchHit struct {
+	Team        models.Team
+	LeaderName  string
+	LeaderEmail string
+	Score       float64
+}
+
+// Search ranks teams of the event (uuid.Nil = current; that completed RSVP I and/or Final Confirmation
+// and have members) by trigram word similarity of the query to the team name and member names;
+// team-name prefix matches rank first, ties by team name. query must already be lower-cased.
+// Returns one page of hits plus the total number of matches.
+func (r *TeamRepository) Search(ctx context.Context, eventID uuid.UUID, query string, limit, offset int) ([]TeamSearchHit, int, error) {
+	sqlQuery := `
+		WITH matched AS (
+			SELECT t.id,
+			       GREATEST(
+			           word_similarity($1, LOWER(t.team_name)),
+			           COALESCE((SELECT MAX(word_similarity($1, LOWER(m.name))) FROM team_members m WHERE m.team_id = t.id), 0) * 0.9
+			       ) + CASE WHEN strpos(LOWER(t.team_name), $1) = 1 THEN 1 ELSE 0 END AS score
+			FROM teams t
+			WHERE t.event_id = COALESCE($4, current_event_id())
+			  AND t.status IN ('rsvp_done', 'rsvp2_done', 'checked_in')
+			  AND EXISTS (SELECT 1 FROM team_members m WHERE m.team_id = t.id)
+			  AND ($1 <% LOWER(t.team_name)
+			       OR EXISTS (SELECT 1 FROM team_members m WHERE m.team_id = t.id AND $1 <% LOWER(m.name)))
+		)
+		SELECT t.id, t.team_name, t.city, t.status, t.rsvp_locked, t.member_count,
+		       l.name, l.email, mt.score, COUNT(*) OVER () AS total
+		FROM matched mt
+		JOIN teams t ON t.id = mt.id
+		JOIN LATERAL (
+			SELECT m.name, m.email FROM team_members m
+			WHERE m.team_id = t.id
+			ORDER BY (m.role = 'leader') DESC, m.created_at
+			LIMIT 1
+		) l ON TRUE
+		ORDER BY mt.score DESC, t.team_name, t.id
+		LIMIT $2 OFFSET $3
 	`
-	rows, err := r.db.QueryContext(ctx, query, "%"+teamName+"%")
+	rows, err := r.db.QueryContext(ctx, sqlQuery, query, limit, offset, EventArg(eventID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to search teams: %w", err)
+		return nil, 0, fmt.Errorf("failed to search teams: %w", err)
 	}
 	defer rows.Close()
 
-	var teams []models.Team
+	hits := []TeamSearchHit{}
+	total := 0
 	for rows.Next() {
-		var team models.Team
+		var h TeamSearchHit
 		err := rows.Scan(
-			&team.ID, &team.TeamName, &team.City, &team.Status,
-			&team.ProblemStatement, &team.QRCodeToken, &team.RSVPLocked,
-			&team.RSVPLockedAt, &team.CheckedInAt, &team.CheckedInBy,
-			&team.DashboardToken, &team.CreatedAt, &team.UpdatedAt,
-			&team.MemberCount,
+			&h.Team.ID, &h.Team.TeamName, &h.Team.City, &h.Team.Status,
+			&h.Team.RSVPLocked, &h.Team.MemberCount,
+			&h.LeaderName, &h.LeaderEmail, &h.Score, &total,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan team: %w", err)
+			return nil, 0, fmt.Errorf("failed to scan team: %w", err)
 		}
-		teams = append(teams, team)
+		hits = append(hits, h)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("row iteration error: %w", err)
+		return nil, 0, fmt.Errorf("row iteration error: %w", err)
 	}
 
-	return teams, nil
+	return hits, total, nil
 }
 
 // GetByID retrieves a team with its members
@@ -68,7 +95,8 @@ func (r *TeamRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Tea
 	query := `
 		SELECT id, team_name, city, status, problem_statement, qr_code_token,
 		       rsvp_locked, rsvp_locked_at, rsvp2_locked, rsvp2_locked_at, rsvp2_selected_members,
-		       checked_in_at, checked_in_by, dashboard_token, created_at, updated_at
+		       checked_in_at, checked_in_by, dashboard_token, created_at, updated_at,
+		       previous_status, inactive_at, inactive_reason, event_id
 		FROM teams WHERE id = $1
 	`
 	var team models.Team
@@ -78,6 +106,7 @@ func (r *TeamRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Tea
 		&team.RSVPLockedAt, &team.RSVP2Locked, &team.RSVP2LockedAt, &team.RSVP2SelectedMembers,
 		&team.CheckedInAt, &team.CheckedInBy, &team.DashboardToken, 
 		&team.CreatedAt, &team.UpdatedAt,
+		&team.PreviousStatus, &team.InactiveAt, &team.InactiveReason, &team.EventID,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -129,27 +158,29 @@ func (r *TeamRepository) GetByQRToken(ctx context.Context, token string) (*model
 	return &team, nil
 }
 
-// GetByDashboardToken retrieves a team by dashboard token (for member access).
+// GetDashboardTeam retrieves a team for its dashboard (the link is resolved by DashboardTokenService).
 // Joins event_tables to return registration desk name/number when allocated.
-func (r *TeamRepository) GetByDashboardToken(ctx context.Context, token string) (*models.Team, error) {
+func (r *TeamRepository) GetDashboardTeam(ctx context.Context, teamID uuid.UUID) (*models.Team, error) {
 	query := `
 		SELECT t.id, t.team_name, t.city, t.status, t.problem_statement, t.qr_code_token,
 		       t.rsvp_locked, t.rsvp_locked_at, t.rsvp2_locked, t.rsvp2_locked_at, t.rsvp2_selected_members,
 		       t.checked_in_at, t.checked_in_by, t.dashboard_token, t.created_at, t.updated_at,
-		       t.registration_desk_id, et.table_name AS registration_desk_table_name, et.table_number AS registration_desk_table_number
+		       t.registration_desk_id, et.table_name AS registration_desk_table_name, et.table_number AS registration_desk_table_number,
+		       t.inactive_at, t.inactive_reason
 		FROM teams t
 		LEFT JOIN event_tables et ON t.registration_desk_id = et.id
-		WHERE t.dashboard_token = $1
+		WHERE t.id = $1
 	`
 	var team models.Team
 	var deskName, deskNumber sql.NullString
-	err := r.db.QueryRowContext(ctx, query, token).Scan(
+	err := r.db.QueryRowContext(ctx, query, teamID).Scan(
 		&team.ID, &team.TeamName, &team.City, &team.Status,
 		&team.ProblemStatement, &team.QRCodeToken, &team.RSVPLocked,
 		&team.RSVPLockedAt, &team.RSVP2Locked, &team.RSVP2LockedAt, &team.RSVP2SelectedMembers,
 		&team.CheckedInAt, &team.CheckedInBy, &team.DashboardToken,
 		&team.CreatedAt, &team.UpdatedAt,
 		&team.RegistrationDeskID, &deskName, &deskNumber,
+		&team.InactiveAt, &team.InactiveReason,
 	)
 	if err == nil {
 		if deskName.Valid {
@@ -163,7 +194,7 @@ func (r *TeamRepository) GetByDashboardToken(ctx context.Context, token string)
 		return nil, nil
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get team by dashboard token: %w", err)
+		return nil, fmt.Errorf("failed to get dashboard team: %w", err)
 	}
 
 	// Get members based on RSVP II status
@@ -186,14 +217,14 @@ func (r *TeamRepository) GetByDashboardToken(ctx context.Context, token string)
 	return &team, nil
 }
 
-// GetTeamIDsByCity returns team IDs for teams in the given city with status rsvp2_done only (eligible for registration desk), ordered by created_at.
-func (r *TeamRepository) GetTeamIDsByCity(ctx context.Context, city string) ([]uuid.UUID, error) {
+// GetTeamIDsByCity returns team IDs for the event's teams (uuid.Nil = current) in the given city with status rsvp2_done only (eligible for registration desk), ordered by created_at.
+func (r *TeamRepository) GetTeamIDsByCity(ctx context.Context, eventID uuid.UUID, city string) ([]uuid.UUID, error) {
 	query := `
 		SELECT id FROM teams
-		WHERE city = $1 AND status = 'rsvp2_done'
+		WHERE city = $1 AND status = 'rsvp2_done' AND event_id = COALESCE($2, current_event_id())
 		ORDER BY created_at ASC
 	`
-	rows, err := r.db.QueryContext(ctx, query, city)
+	rows, err := r.db.QueryContext(ctx, query, city, EventArg(eventID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get team IDs by city: %w", err)
 	}
@@ -221,19 +252,21 @@ func (r *TeamRepository) SetRegistrationDesk(ctx context.Context, teamID, deskID
 	return nil
 }
 
-// ClearRegistrationDesksForRSVP2Teams sets registration_desk_id = NULL for all teams with status = 'rsvp2_done'.
+// ClearRegistrationDesksForRSVP2Teams sets registration_desk_id = NULL for the event's teams (uuid.Nil = current) with status = 'rsvp2_done'.
 // Call before re-running allocation so previous allocations are removed.
-func (r *TeamRepository) ClearRegistrationDesksForRSVP2Teams(ctx context.Context) (int64, error) {
-	res, err := r.db.ExecContext(ctx, `UPDATE teams SET registration_desk_id = NULL, updated_at = NOW() WHERE status = 'rsvp2_done'`)
+func (r *TeamRepository) ClearRegistrationDesksForRSVP2Teams(ctx context.Context, eventID uuid.UUID) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE teams SET registration_desk_id = NULL, updated_at = NOW() WHERE status = 'rsvp2_done' AND event_id = COALESCE($1, current_event_id())`, EventArg(eventID))
 	if err != nil {
 		return 0, fmt.Errorf("failed to clear registration desks: %w", err)
 	}
 	return res.RowsAffected()
 }
 
-// ClearAllRegistrationDesks sets registration_desk_id = NULL for all teams that have a desk allocated.
-func (r *TeamRepository) ClearAllRegistrationDesks(ctx context.Context) (int64, error) {
-	res, err := r.db.ExecContext(ctx, `UPDATE teams SET registration_desk_id = NULL, updated_at = NOW() WHERE registration_desk_id IS NOT NULL`)
+// ClearAllRegistrationDesks sets registration_desk_id = NULL for the event's teams (uuid.Nil = current event) that have a desk allocated.
+func (r *TeamRepository) ClearAllRegistrationDesks(ctx context.Context, eventID uuid.UUID) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE teams SET registration_desk_id = NULL, updated_at = NOW()
+		WHERE registration_desk_id IS NOT NULL AND event_id = COALESCE($1, current_event_id())`, EventArg(eventID))
 	if err != nil {
 		return 0, fmt.Errorf("failed to clear all registration desks: %w", err)
 	}
@@ -452,13 +485,14 @@ func (r *TeamRepository) CreateMember(ctx context.Context, member models.TeamMem
 	return nil
 }
 
-// GetCheckInStats returns check-in statistics
-func (r *TeamRepository) GetCheckInStats(ctx context.Context) (map[string]interface{}, error) {
+// GetCheckInStats returns check-in statistics for the event (uuid.Nil = current event)
+func (r *TeamRepository) GetCheckInStats(ctx context.Context, eventID uuid.UUID) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
+	ev := EventArg(eventID)
 
 	// Total teams
 	var totalTeams int
-	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM teams`).Scan(&totalTeams)
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM teams WHERE event_id = COALESCE($1, current_event_id())`, ev).Scan(&totalTeams)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total teams: %w", err)
 	}
@@ -466,7 +500,7 @@ func (r *TeamRepository) GetCheckInStats(ctx context.Context) (map[string]interf
 
 	// RSVP confirmed (RSVP I)
 	var rsvpConfirmed int
-	err = r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM teams WHERE rsvp_locked = true`).Scan(&rsvpConfirmed)
+	err = r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM teams WHERE rsvp_locked = true AND event_id = COALESCE($1, current_event_id())`, ev).Scan(&rsvpConfirmed)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get RSVP count: %w", err)
 	}
@@ -474,7 +508,7 @@ func (r *TeamRepository) GetCheckInStats(ctx context.Context) (map[string]interf
 
 	// RSVP II confirmed
 	var rsvp2Confirmed int
-	err = r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM teams WHERE rsvp2_locked = true`).Scan(&rsvp2Confirmed)
+	err = r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM teams WHERE rsvp2_locked = true AND event_id = COALESCE($1, current_event_id())`, ev).Scan(&rsvp2Confirmed)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get RSVP II count: %w", err)
 	}
@@ -482,14 +516,14 @@ func (r *TeamRepository) GetCheckInStats(ctx context.Context) (map[string]interf
 
 	// Checked in
 	var checkedIn int
-	err = r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM teams WHERE checked_in_at IS NOT NULL`).Scan(&checkedIn)
+	err = r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM teams WHERE checked_in_at IS NOT NULL AND event_id = COALESCE($1, current_event_id())`, ev).Scan(&checkedIn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get checked-in count: %w", err)
 	}
 	stats["checked_in"] = checkedIn
 
 	// By city
-	rows, err := r.db.QueryContext(ctx, `SELECT city, COUNT(*) FROM teams WHERE city IS NOT NULL GROUP BY city`)
+	rows, err := r.db.QueryContext(ctx, `SELECT city, COUNT(*) FROM teams WHERE city IS NOT NULL AND event_id = COALESCE($1, current_event_id()) GROUP BY city`, ev)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get city stats: %w", err)
 	}
@@ -509,17 +543,131 @@ func (r *TeamRepository) GetCheckInStats(ctx context.Context) (map[string]interf
 	return stats, nil
 }
 
-// GetAllWithFilters retrieves all teams with optional filters
-func (r *TeamRepository) GetAllWithFilters(ctx context.Context, status, city string) ([]models.Team, error) {
+// GetCityCheckInStats returns the GetCheckInStats counts for teams of one city (a city code).
+func (r *TeamRepository) GetCityCheckInStats(ctx context.Context, eventID uuid.UUID, city string) (map[string]interface{}, error) {
+	var total, rsvp, rsvp2, checkedIn int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*),
+		       COUNT(*) FILTER (WHERE rsvp_locked = true),
+		       COUNT(*) FILTER (WHERE rsvp2_locked = true),
+		       COUNT(*) FILTER (WHERE checked_in_at IS NOT NULL)
+		FROM teams
+		WHERE event_id = COALESCE($1, current_event_id()) AND city = $2
+	`, EventArg(eventID), city).Scan(&total, &rsvp, &rsvp2, &checkedIn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get city check-in stats: %w", err)
+	}
+	return map[string]interface{}{
+		"total_teams":     total,
+		"rsvp_confirmed":  rsvp,
+		"rsvp2_confirmed": rsvp2,
+		"checked_in":      checkedIn,
+		"city":            city,
+	}, nil
+}
+
+// StreamRoster walks every member of every team of the event (uuid.Nil = current event) matching
+// the admin list filters and calls fn per row, without loading the roster into memory.
+func (r *TeamRepository) StreamRoster(ctx context.Context, eventID uuid.UUID, status, city string, fn func(*models.RosterRow) error) error {
+	query := `
+		SELECT t.id, t.team_name, t.city, t.status, t.member_count,
+		       t.rsvp_locked_at, t.rsvp2_locked_at, t.checked_in_at,
+		       COALESCE(et.table_name, et.table_number),
+		       seat.block_name, seat.room_name, seat.seat_label,
+		       ps.track, ps.name, sel.locked_at,
+		       sub.github_url, sub.live_url, sub.linkedin_url, sub.submitted_at,
+		       tm.name, tm.role::text, tm.email, tm.phone, tm.tshirt_size,
+		       COALESCE(t.rsvp2_selected_members ? tm.id::text, FALSE),
+		       ci.checked_in_at
+		FROM teams t
+		LEFT JOIN team_members tm ON tm.team_id = t.id
+		LEFT JOIN event_tables et ON et.id = t.registration_desk_id
+		LEFT JOIN LATERAL (
+			SELECT b.name AS block_name, rm.name AS room_name, s.seat_label
+			FROM seat_allocations sa
+			LEFT JOIN blocks b ON b.id = sa.block_id
+			LEFT JOIN rooms rm ON rm.id = sa.room_id
+			LEFT JOIN seats s ON s.id = sa.seat_id
+			WHERE sa.team_id = t.id
+			LIMIT 1
+		) seat ON TRUE
+		LEFT JOIN ps_selections sel ON sel.team_id = t.id
+		LEFT JOIN problem_statements ps ON ps.id = sel.problem_statement_id
+		LEFT JOIN LATERAL (
+			SELECT github_url, live_url, linkedin_url, submitted_at
+			FROM ps_submissions
+			WHERE team_id = t.id AND problem_statement_id = sel.problem_statement_id
+			ORDER BY submitted_at DESC
+			LIMIT 1
+		) sub ON TRUE
+		LEFT JOIN LATERAL (
+			SELECT MIN(p.checked_in_at) AS checked_in_at
+			FROM participant_check_ins p
+			WHERE p.team_member_id = tm.id
+		) ci ON TRUE
+		WHERE t.event_id = COALESCE($1, current_event_id())
+	`
+	args := []interface{}{EventArg(eventID)}
+	argPos := 2
+
+	if status != "" {
+		query += fmt.Sprintf(" AND t.status = $%d", argPos)
+		args = append(args, status)
+		argPos++
+	}
+
+	if city != "" {
+		query += fmt.Sprintf(" AND t.city = $%d", argPos)
+		args = append(args, city)
+		argPos++
+	}
+
+	query += " ORDER BY t.city, t.team_name, t.id, (tm.role = 'leader') DESC, tm.created_at"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query roster: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.RosterRow
+		err := rows.Scan(
+			&row.TeamID, &row.TeamName, &row.City, &row.Status, &row.MemberCount,
+			&row.RSVP1At, &row.RSVP2At, &row.TeamCheckedInAt,
+			&row.RegistrationDesk,
+			&row.SeatBlock, &row.SeatRoom, &row.SeatLabel,
+			&row.PSTrack, &row.PSName, &row.PSLockedAt,
+			&row.GithubURL, &row.LiveURL, &row.LinkedinURL, &row.SubmittedAt,
+			&row.MemberName, &row.MemberRole, &row.MemberEmail, &row.MemberPhone, &row.TShirtSize,
+			&row.RSVP2Selected,
+			&row.CheckedInAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan roster row: %w", err)
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating roster rows: %w", err)
+	}
+	return nil
+}
+
+// GetAllWithFilters retrieves all teams of the event (uuid.Nil = current event) with optional filters
+func (r *TeamRepository) GetAllWithFilters(ctx context.Context, eventID uuid.UUID, status, city string) ([]models.Team, error) {
 	query := `
 		SELECT id, team_name, city, status, problem_statement, qr_code_token,
 		       rsvp_locked, rsvp_locked_at, checked_in_at, checked_in_by,
 		       dashboard_token, member_count, created_at, updated_at
 		FROM teams
-		WHERE 1=1
+		WHERE event_id = COALESCE($1, current_event_id())
 	`
-	args := []interface{}{}
-	argPos := 1
+	args := []interface{}{EventArg(eventID)}
+	argPos := 2
 
 	if status != "" {
 		query += fmt.Sprintf(" AND status = $%d", argPos)
@@ -618,7 +766,8 @@ func (r *TeamRepository) GetTeamMembers(ctx context.Context, teamID uuid.UUID) (
 	return r.GetMembersByTeamID(ctx, teamID)
 }
 
-// CreateTeamWithMembers creates a team and its members in a single transaction
+// CreateTeamWithMembers creates a team and its members in a single transaction.
+// The team goes into team.EventID, or the current event when that is uuid.Nil.
 func (r *TeamRepository) CreateTeamWithMembers(ctx context.Context, team models.Team, members []models.TeamMember) error {
 	// Start transaction
 	tx, err := r.db.BeginTx(ctx, nil)
@@ -631,13 +780,13 @@ func (r *TeamRepository) CreateTeamWithMembers(ctx context.Context, team models.
 	teamQuery := `
 		INSERT INTO teams (id, team_name, city, status, member_count, problem_statement, 
 		                   qr_code_token, dashboard_token, rsvp_locked, rsvp_locked_at, 
-		                   rsvp2_locked, rsvp2_locked_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		                   rsvp2_locked, rsvp2_locked_at, event_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, COALESCE($13, current_event_id()))
 	`
 	_, err = tx.ExecContext(ctx, teamQuery, 
 		team.ID, team.TeamName, team.City, team.Status, team.MemberCount, team.ProblemStatement,
 		team.QRCodeToken, team.DashboardToken, team.RSVPLocked, team.RSVPLockedAt,
-		team.RSVP2Locked, team.RSVP2LockedAt)
+		team.RSVP2Locked, team.RSVP2LockedAt, EventArg(team.EventID))
 	if err != nil {
 		return fmt.Errorf("failed to create team: %w", err)
 	}
@@ -665,8 +814,9 @@ func (r *TeamRepository) CreateTeamWithMembers(ctx context.Context, team models.
 	return nil
 }
 
-// ClearAllData deletes all teams and members - for testing only
-func (r *TeamRepository) ClearAllData(ctx context.Context) error {
+// ClearAllData deletes the event's teams and members (uuid.Nil = current event) - for testing only.
+// Other editions are left untouched.
+func (r *TeamRepository) ClearAllData(ctx context.Context, eventID uuid.UUID) error {
 	// Start transaction
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -674,14 +824,16 @@ func (r *TeamRepository) ClearAllData(ctx context.Context) error {
 	}
 	defer tx.Rollback()
 
-	// Delete all team members first (foreign key constraint)
-	_, err = tx.ExecContext(ctx, "DELETE FROM team_members")
+	// Delete the event's team members first (foreign key constraint)
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM team_members
+		WHERE team_id IN (SELECT id FROM teams WHERE event_id = COALESCE($1, current_event_id()))`, EventArg(eventID))
 	if err != nil {
 		return fmt.Errorf("failed to delete team members: %w", err)
 	}
 
-	// Delete all teams
-	_, err = tx.ExecContext(ctx, "DELETE FROM teams")
+	// Delete the event's teams
+	_, err = tx.ExecContext(ctx, `DELETE FROM teams WHERE event_id = COALESCE($1, current_event_id())`, EventArg(eventID))
 	if err != nil {
 		return fmt.Errorf("failed to delete teams: %w", err)
 	}
@@ -694,17 +846,17 @@ func (r *TeamRepository) ClearAllData(ctx context.Context) error {
 	return nil
 }
 
-// CheckPhoneExists checks if a phone number already exists in the database
-func (r *TeamRepository) CheckPhoneExists(ctx context.Context, phone string) (bool, string, error) {
+// CheckPhoneExists checks if a phone number is already registered for the event (uuid.Nil = current)
+func (r *TeamRepository) CheckPhoneExists(ctx context.Context, eventID uuid.UUID, phone string) (bool, string, error) {
 	query := `
 		SELECT tm.phone, t.team_name 
 		FROM team_members tm
 		JOIN teams t ON tm.team_id = t.id
-		WHERE tm.phone = $1
+		WHERE tm.phone = $1 AND t.event_id = COALESCE($2, current_event_id())
 		LIMIT 1
 	`
 	var existingPhone, teamName string
-	err := r.db.QueryRowContext(ctx, query, phone).Scan(&existingPhone, &teamName)
+	err := r.db.QueryRowContext(ctx, query, phone, EventArg(eventID)).Scan(&existingPhone, &teamName)
 	if err == sql.ErrNoRows {
 		return false, "", nil
 	}
@@ -714,17 +866,17 @@ func (r *TeamRepository) CheckPhoneExists(ctx context.Context, phone string) (bo
 	return true, teamName, nil
 }
 
-// CheckEmailExists checks if an email already exists in the database
-func (r *TeamRepository) CheckEmailExists(ctx context.Context, email string) (bool, string, error) {
+// CheckEmailExists checks if an email is already registered for the event (uuid.Nil = current)
+func (r *TeamRepository) CheckEmailExists(ctx context.Context, eventID uuid.UUID, email string) (bool, string, error) {
 	query := `
 		SELECT tm.email, t.team_name 
 		FROM team_members tm
 		JOIN teams t ON tm.team_id = t.id
-		WHERE LOWER(tm.email) = LOWER($1)
+		WHERE LOWER(tm.email) = LOWER($1) AND t.event_id = COALESCE($2, current_event_id())
 		LIMIT 1
 	`
 	var existingEmail, teamName string
-	err := r.db.QueryRowContext(ctx, query, email).Scan(&existingEmail, &teamName)
+	err := r.db.QueryRowContext(ctx, query, email, EventArg(eventID)).Scan(&existingEmail, &teamName)
 	if err == sql.ErrNoRows {
 		return false, "", nil
 	}
@@ -734,8 +886,8 @@ func (r *TeamRepository) CheckEmailExists(ctx context.Context, email string) (bo
 	return true, teamName, nil
 }
 
-// CheckTeamExistsByNameAndLeader checks if a team with the same name and leader email exists
-func (r *TeamRepository) CheckTeamExistsByNameAndLeader(ctx context.Context, teamName, leaderEmail string) (*models.Team, error) {
+// CheckTeamExistsByNameAndLeader checks if a team with the same name and leader email exists in the event (uuid.Nil = current)
+func (r *TeamRepository) CheckTeamExistsByNameAndLeader(ctx context.Context, eventID uuid.UUID, teamName, leaderEmail string) (*models.Team, error) {
 	query := `
 		SELECT t.id, t.team_name, t.city, t.status, t.rsvp_locked, t.created_at
 		FROM teams t
@@ -743,10 +895,11 @@ func (r *TeamRepository) CheckTeamExistsByNameAndLeader(ctx context.Context, tea
 		WHERE LOWER(t.team_name) = LOWER($1) 
 		AND LOWER(tm.email) = LOWER($2)
 		AND tm.role = 'leader'
+		AND t.event_id = COALESCE($3, current_event_id())
 		LIMIT 1
 	`
 	var team models.Team
-	err := r.db.QueryRowContext(ctx, query, teamName, leaderEmail).Scan(
+	err := r.db.QueryRowContext(ctx, query, teamName, leaderEmail, EventArg(eventID)).Scan(
 		&team.ID, &team.TeamName, &team.City, &team.Status, &team.RSVPLocked, &team.CreatedAt,
 	)
 	if err == sql.ErrNoRows {
@@ -847,3 +1000,60 @@ func (r *TeamRepository) UpdateTeamStatus(ctx context.Context, teamID uuid.UUID,
 	}
 	return nil
 }
+
+// MarkInactive moves a team to withdrawn / no_show, remembering its previous status and releasing its registration desk.
+// Returns false if the team was already inactive or does not exist.
+func (r *TeamRepository) MarkInactive(ctx context.Context, teamID uuid.UUID, status models.TeamStatus, reason, by string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE teams
+		SET previous_status = status, status = $1, inactive_at = NOW(), inactive_reason = NULLIF($2, ''), inactive_by = $3,
+		    registration_desk_id = NULL, updated_at = NOW()
+		WHERE id = $4 AND status NOT IN ('withdrawn', 'no_show')
+	`, string(status), reason, by, teamID)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark team %s: %w", status, err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// GetNoShowCandidateIDs returns teams of the event (uuid.Nil = current) in the city that completed Final Confirmation but never checked in.
+func (r *TeamRepository) GetNoShowCandidateIDs(ctx context.Context, eventID uuid.UUID, city string) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id FROM teams
+		WHERE city = $1 AND status = 'rsvp2_done' AND checked_in_at IS NULL AND event_id = COALESCE($2, current_event_id())
+		ORDER BY created_at ASC
+	`, city, EventArg(eventID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get no-show candidates: %w", err)
+	}
+	defer rows.Close()
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan team ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Reinstate restores a withdrawn / no-show team to the status it had before. The desk is not re-assigned.
+func (r *TeamRepository) Reinstate(ctx context.Context, teamID uuid.UUID) (models.TeamStatus, error) {
+	var restored models.TeamStatus
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE teams
+		SET status = COALESCE(previous_status, 'rsvp2_done'), previous_status = NULL,
+		    inactive_at = NULL, inactive_reason = NULL, inactive_by = NULL, updated_at = NOW()
+		WHERE id = $1 AND status IN ('withdrawn', 'no_show')
+		RETURNING status
+	`, teamID).Scan(&restored)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("team is not withdrawn or marked as no-show")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to reinstate team: %w", err)
+	}
+	return restored, nil
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/rift26/backend/internal/database"
+	"github.com/rift26/backend/internal/models"
+)
+
+type TwoFactorRepository struct {
+	db *database.DB
+}
+
+func NewTwoFactorRepository(db *database.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+// Get returns a subject's enrollment (pending or enabled), or nil if none.
+func (r *TwoFactorRepository) Get(ctx context.Context, subjectID uuid.UUID) (*models.TwoFactor, error) {
+	var tf models.TwoFactor
+	err := r.db.QueryRowContext(ctx, `
+		SELECT subject_id, role, secret_encrypted, enabled_at, last_counter, created_at, updated_at
+		FROM two_factor WHERE subject_id = $1`, subjectID).Scan(
+		&tf.SubjectID, &tf.Role, &tf.SecretEncrypted, &tf.EnabledAt, &tf.LastCounter, &tf.CreatedAt, &tf.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get two-factor enrollment: %w", err)
+	}
+	return &tf, nil
+}
+
+// SavePending stores a new secret awaiting confirmation. An enabled enrollment is left untouched.
+func (r *TwoFactorRepository) SavePending(ctx context.Context, subjectID uuid.UUID, role models.UserRole, secretEncrypted string) error {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO two_factor (subject_id, role, secret_encrypted, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (subject_id) DO UPDATE SET secret_encrypted = EXCLUDED.secret_encrypted, last_counter = NULL, updated_at = NOW()
+		WHERE two_factor.enabled_at IS NULL`, subjectID, role, secretEncrypted)
+	if err != nil {
+		return fmt.Errorf("failed to save two-factor secret: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("two-factor authentication is already enabled")
+	}
+	return nil
+}
+
+// Enable confirms a pending enrollment and replaces its recovery codes.
+func (r *TwoFactorRepository) Enable(ctx context.Context, subjectID uuid.UUID, counter int64, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE two_factor SET enabled_at = NOW(), last_counter = $2, updated_at = NOW()
+		WHERE subject_id = $1 AND enabled_at IS NULL`, subjectID, counter)
+	if err != nil {
+		return fmt.Errorf("failed to enable two-factor: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("no pending two-factor enrollment")
+	}
+	if err := replaceRecoveryCodes(ctx, tx, subjectID, codeHashes); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit two-factor: %w", err)
+	}
+	return nil
+}
+
+// AcceptCounter records a TOTP time step as used. It returns false if that step (or a later one)
+// was already used, i.e. the code is a replay.
+func (r *TwoFactorRepository) AcceptCounter(ctx context.Context, subjectID uuid.UUID, counter int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE two_factor SET last_counter = $2, updated_at = NOW()
+		WHERE subject_id = $1 AND (last_counter IS NULL OR last_counter < $2)`, subjectID, counter)
+	if err != nil {
+		return false, fmt.Errorf("failed to record two-factor code: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used. It returns false if there is none.
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, subjectID uuid.UUID, codeHash string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE two_factor_recovery_codes SET used_at = NOW()
+		WHERE id = (
+			SELECT id FROM two_factor_recovery_codes
+			WHERE subject_id = $1 AND code_hash = $2 AND used_at IS NULL
+			LIMIT 1
+		)`, subjectID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// ReplaceRecoveryCodes discards all recovery codes of a subject and stores new ones.
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, subjectID uuid.UUID, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	if err := replaceRecoveryCodes(ctx, tx, subjectID, codeHashes); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit recovery codes: %w", err)
+	}
+	return nil
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, subjectID uuid.UUID, codeHashes []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM two_factor_recovery_codes WHERE subject_id = $1`, subjectID); err != nil {
+		return fmt.Errorf("failed to clear recovery codes: %w", err)
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO two_factor_recovery_codes (subject_id, code_hash)
+		SELECT $1, h FROM UNNEST($2::text[]) AS h`, subjectID, pq.Array(codeHashes))
+	if err != nil {
+		return fmt.Errorf("failed to store recovery codes: %w", err)
+	}
+	return nil
+}
+
+// CountRecoveryCodes returns how many unused recovery codes a subject has left.
+func (r *TwoFactorRepository) CountRecoveryCodes(ctx context.Context, subjectID uuid.UUID) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM two_factor_recovery_codes WHERE subject_id = $1 AND used_at IS NULL`, subjectID).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+	return n, nil
+}
+
+// Delete removes a subject's enrollment and recovery codes. It returns false if there was none.
+func (r *TwoFactorRepository) Delete(ctx context.Context, subjectID uuid.UUID) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM two_factor WHERE subject_id = $1`, subjectID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete two-factor enrollment: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// CreateChallenge stores a second login step.
+func (r *TwoFactorRepository) CreateChallenge(ctx context.Context, c *models.TwoFactorChallenge, tokenHash string) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO two_factor_challenges (token_hash, subject_id, role, email, city, setup, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`,
+		tokenHash, c.SubjectID, c.Role, c.Email, c.City, c.Setup, c.ExpiresAt).Scan(&c.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create two-factor challenge: %w", err)
+	}
+	// Housekeeping: drop challenges that expired a while ago
+	_, _ = r.db.ExecContext(ctx, `DELETE FROM two_factor_challenges WHERE expires_at < NOW() - INTERVAL '1 day'`)
+	return nil
+}
+
+// GetChallenge returns an open (unexpired, unconsumed) challenge, or nil.
+func (r *TwoFactorRepository) GetChallenge(ctx context.Context, tokenHash string) (*models.TwoFactorChallenge, error) {
+	var c models.TwoFactorChallenge
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, subject_id, role, email, city, setup, attempts, expires_at, consumed_at
+		FROM two_factor_challenges
+		WHERE token_hash = $1 AND consumed_at IS NULL AND expires_at > NOW()`, tokenHash).Scan(
+		&c.ID, &c.SubjectID, &c.Role, &c.Email, &c.City, &c.Setup, &c.Attempts, &c.ExpiresAt, &c.ConsumedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get two-factor challenge: %w", err)
+	}
+	return &c, nil
+}
+
+// RecordAttempt counts a failed code against a challenge and returns the new attempt count.
+func (r *TwoFactorRepository) RecordAttempt(ctx context.Context, id uuid.UUID) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE two_factor_challenges SET attempts = attempts + 1 WHERE id = $1 RETURNING attempts`, id).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record two-factor attempt: %w", err)
+	}
+	return n, nil
+}
+
+// ConsumeChallenge closes a challenge. It returns false if it was already consumed.
+func (r *TwoFactorRepository) ConsumeChallenge(ctx context.Context, id uuid.UUID) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE two_factor_challenges SET consumed_at = NOW() WHERE id = $1 AND consumed_at IS NULL`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume two-factor challenge: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
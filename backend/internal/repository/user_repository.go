@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/rift26/backend/internal/database"
 	"github.com/rift26/backend/internal/models"
 	"golang.org/x/crypto/bcrypt"
@@ -19,18 +21,21 @@ func NewUserRepository(db *database.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-// GetByEmail retrieves a user by email
-func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
-	query := `
-		SELECT id, email, password_hash, name, role, created_at, updated_at
-		FROM users WHERE email = $1
-	`
+// userColumns selects a users row; invited users have no password hash yet.
+const userColumns = `id, email, COALESCE(password_hash, ''), name, role, is_active, created_at, updated_at`
 
+func scanUser(row rowScanner) (*models.User, error) {
 	var user models.User
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID, &user.Email, &user.PasswordHash, &user.Name,
-		&user.Role, &user.CreatedAt, &user.UpdatedAt,
-	)
+	if err := row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Name,
+		&user.Role, &user.IsActive, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetByEmail retrieves a user by email
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE email = $1`, email))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -38,21 +43,12 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 		return nil, fmt.Errorf("failed to get user by email: %w", err)
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 // GetByID retrieves a user by ID
 func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
-	query := `
-		SELECT id, email, password_hash, name, role, created_at, updated_at
-		FROM users WHERE id = $1
-	`
-
-	var user models.User
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID, &user.Email, &user.PasswordHash, &user.Name,
-		&user.Role, &user.CreatedAt, &user.UpdatedAt,
-	)
+	user, err := scanUser(r.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1`, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -60,7 +56,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 		return nil, fmt.Errorf("failed to get user by ID: %w", err)
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 // Create creates a new user
@@ -74,19 +70,29 @@ func (r *UserRepository) Create(ctx context.Context, email, password, name strin
 	query := `
 		INSERT INTO users (email, password_hash, name, role)
 		VALUES ($1, $2, $3, $4)
-		RETURNING id, email, password_hash, name, role, created_at, updated_at
-	`
+		RETURNING ` + userColumns
 
-	var user models.User
-	err = r.db.QueryRowContext(ctx, query, email, string(hashedPassword), name, role).Scan(
-		&user.ID, &user.Email, &user.PasswordHash, &user.Name,
-		&user.Role, &user.CreatedAt, &user.UpdatedAt,
-	)
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, email, string(hashedPassword), name, role))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	return &user, nil
+	return user, nil
+}
+
+// CreateInvited creates a user without a password; they set one through an invite link
+func (r *UserRepository) CreateInvited(ctx context.Context, email, name string, role models.UserRole, invitedBy *uuid.UUID) (*models.User, error) {
+	query := `
+		INSERT INTO users (email, password_hash, name, role, invited_by)
+		VALUES ($1, NULL, $2, $3, $4)
+		RETURNING ` + userColumns
+
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, email, name, role, invitedBy))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return user, nil
 }
 
 // ValidatePassword checks if the provided password matches the user's hashed password
@@ -94,3 +100,142 @@ func (r *UserRepository) ValidatePassword(user *models.User, password string) bo
 	err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
 	return err == nil
 }
+
+// Delete removes a user
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return nil
+}
+
+// ListAdmins returns admin accounts with roles, 2FA state and last login (from sessions)
+func (r *UserRepository) ListAdmins(ctx context.Context) ([]models.AdminUser, error) {
+	return r.queryAdmins(ctx, `WHERE u.role = 'admin' ORDER BY u.is_active DESC, u.email`)
+}
+
+// GetAdmin returns one admin account, or nil if not found
+func (r *UserRepository) GetAdmin(ctx context.Context, id uuid.UUID) (*models.AdminUser, error) {
+	list, err := r.queryAdmins(ctx, `WHERE u.role = 'admin' AND u.id = $1`, id)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return &list[0], nil
+}
+
+func (r *UserRepository) queryAdmins(ctx context.Context, where string, args ...interface{}) ([]models.AdminUser, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.id, u.email, u.name, u.is_active, u.password_hash IS NOT NULL,
+		       COALESCE((SELECT ARRAY_AGG(uar.role_key ORDER BY uar.role_key) FROM user_admin_roles uar WHERE uar.user_id = u.id), '{}'),
+		       EXISTS (SELECT 1 FROM two_factor tf WHERE tf.subject_id = u.id AND tf.enabled_at IS NOT NULL),
+		       (SELECT MAX(s.created_at) FROM auth_sessions s WHERE s.subject_id = u.id),
+		       (SELECT MAX(s.last_used_at) FROM auth_sessions s WHERE s.subject_id = u.id),
+		       u.disabled_at, u.invited_by, u.created_at
+		FROM users u
+		`+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query admin users: %w", err)
+	}
+	defer rows.Close()
+	list := make([]models.AdminUser, 0)
+	for rows.Next() {
+		var u models.AdminUser
+		var roles pq.StringArray
+		if err := rows.Scan(&u.ID, &u.Email, &u.Name, &u.IsActive, &u.PasswordSet, &roles, &u.TwoFactorEnabled,
+			&u.LastLoginAt, &u.LastActiveAt, &u.DisabledAt, &u.InvitedBy, &u.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan admin user: %w", err)
+		}
+		u.Roles = []string(roles)
+		list = append(list, u)
+	}
+	return list, rows.Err()
+}
+
+// UpdateName changes a user's display name
+func (r *UserRepository) UpdateName(ctx context.Context, id uuid.UUID, name string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE users SET name = $2, updated_at = NOW() WHERE id = $1`, id, name)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return nil
+}
+
+// SetActive disables or re-enables a user. Disabling fails with ErrNoRoleManager if it would
+// leave no active admin able to manage roles.
+func (r *UserRepository) SetActive(ctx context.Context, id uuid.UUID, active bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE users SET is_active = $2, disabled_at = CASE WHEN $2 THEN NULL ELSE NOW() END, updated_at = NOW()
+		WHERE id = $1`, id, active)
+	if err != nil {
+		return fmt.Errorf("failed to update user status: %w", err)
+	}
+	if !active {
+		if err := ensureRoleManager(ctx, tx); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit user status: %w", err)
+	}
+	return nil
+}
+
+// SetPassword stores a new bcrypt hash
+func (r *UserRepository) SetPassword(ctx context.Context, id uuid.UUID, password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, `UPDATE users SET password_hash = $2, updated_at = NOW() WHERE id = $1`, id, string(hashedPassword))
+	if err != nil {
+		return fmt.Errorf("failed to set password: %w", err)
+	}
+	return nil
+}
+
+// CreatePasswordToken stores a one-time set-password link and voids the user's earlier ones
+func (r *UserRepository) CreatePasswordToken(ctx context.Context, userID uuid.UUID, tokenHash, purpose string, expiresAt time.Time, createdBy *uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE password_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`, userID); err != nil {
+		return fmt.Errorf("failed to void password tokens: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO password_tokens (user_id, token_hash, purpose, expires_at, created_by)
+		VALUES ($1, $2, $3, $4, $5)`, userID, tokenHash, purpose, expiresAt, createdBy)
+	if err != nil {
+		return fmt.Errorf("failed to create password token: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit password token: %w", err)
+	}
+	return nil
+}
+
+// ConsumePasswordToken marks a valid token as used and returns its user, or uuid.Nil if the
+// token is unknown, expired or already used
+func (r *UserRepository) ConsumePasswordToken(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE password_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id`, tokenHash).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to use password token: %w", err)
+	}
+	return userID, nil
+}
@@ -19,7 +19,7 @@ func NewVolunteerRepository(db *database.DB) *VolunteerRepository {
 	return &VolunteerRepository{db: db}
 }
 
-// GetByEmail retrieves a volunteer by email
+// GetByEmail retrieves an active volunteer of the current event by email
 func (r *VolunteerRepository) GetByEmail(email string) (*models.Volunteer, error) {
 	var volunteer models.Volunteer
 	query := `
@@ -27,7 +27,7 @@ func (r *VolunteerRepository) GetByEmail(email string) (*models.Volunteer, error
 		       t.table_name, t.table_number
 		FROM volunteers v
 		LEFT JOIN event_tables t ON v.table_id = t.id
-		WHERE v.email = $1 AND v.is_active = true
+		WHERE v.email = $1 AND v.is_active = true AND v.event_id = current_event_id()
 	`
 	err := r.db.QueryRow(query, email).Scan(
 		&volunteer.ID,
@@ -109,17 +109,17 @@ func (r *VolunteerRepository) Create(volunteer *models.Volunteer) error {
 	).Scan(&volunteer.ID, &volunteer.CreatedAt, &volunteer.UpdatedAt, &volunteer.IsActive)
 }
 
-// GetAll retrieves all volunteers with optional filters
-func (r *VolunteerRepository) GetAll(city *string, tableID *uuid.UUID) ([]models.Volunteer, error) {
+// GetAll retrieves all volunteers of the event (uuid.Nil = current event) with optional filters
+func (r *VolunteerRepository) GetAll(eventID uuid.UUID, city *string, tableID *uuid.UUID) ([]models.Volunteer, error) {
 	query := `
 		SELECT v.id, v.email, v.password_hash, v.table_id, v.city, v.is_active, v.created_by, v.created_at, v.updated_at,
 		       t.table_name, t.table_number
 		FROM volunteers v
 		LEFT JOIN event_tables t ON v.table_id = t.id
-		WHERE 1=1
+		WHERE v.event_id = COALESCE($1, current_event_id())
 	`
-	args := []interface{}{}
-	argCount := 1
+	args := []interface{}{EventArg(eventID)}
+	argCount := 2
 
 	if city != nil && *city != "" {
 		query += fmt.Sprintf(" AND v.city = $%d", argCount)
@@ -20,7 +20,7 @@ func NewVolunteerAdminRepository(db *database.DB) *VolunteerAdminRepository {
 func (r *VolunteerAdminRepository) GetByEmail(email string) (*models.VolunteerAdmin, error) {
 	var v models.VolunteerAdmin
 	query := `SELECT id, email, password_hash, city, is_active, created_by, created_at, updated_at
-	          FROM volunteer_admins WHERE email = $1 AND is_active = true`
+	          FROM volunteer_admins WHERE email = $1 AND is_active = true AND event_id = current_event_id()`
 	err := r.db.QueryRow(query, email).Scan(
 		&v.ID, &v.Email, &v.PasswordHash, &v.City, &v.IsActive, &v.CreatedBy, &v.CreatedAt, &v.UpdatedAt,
 	)
@@ -63,10 +63,11 @@ func (r *VolunteerAdminRepository) Create(v *models.VolunteerAdmin) error {
 		Scan(&v.ID, &v.CreatedAt, &v.UpdatedAt, &v.IsActive)
 }
 
-func (r *VolunteerAdminRepository) GetAll() ([]models.VolunteerAdmin, error) {
+// GetAll lists volunteer admins of the event (uuid.Nil = current event).
+func (r *VolunteerAdminRepository) GetAll(eventID uuid.UUID) ([]models.VolunteerAdmin, error) {
 	query := `SELECT id, email, city, is_active, created_by, created_at, updated_at
-	          FROM volunteer_admins ORDER BY created_at DESC`
-	rows, err := r.db.Query(query)
+	          FROM volunteer_admins WHERE event_id = COALESCE($1, current_event_id()) ORDER BY created_at DESC`
+	rows, err := r.db.Query(query, EventArg(eventID))
 	if err != nil {
 		return nil, err
 	}
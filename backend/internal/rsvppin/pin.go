@@ -2,23 +2,102 @@ package rsvppin
 
 import (
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/binary"
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultRotation is the PIN rotation window when none is configured.
+const DefaultRotation = 3 * time.Hour
+
+// Scope names an independent PIN. Each scope rotates on the same schedule but has its own value.
+type Scope string
+
+const (
+	ScopeRSVP  Scope = "rsvp"  // RSVP I
+	ScopeFinal Scope = "final" // RSVP II / final confirmation
 )
 
-const rotationHours = 3
+const deskScopePrefix = "desk:"
 
-// GetCurrentPIN returns the 6-digit PIN for the current 3-hour window.
-func GetCurrentPIN(secret string) string {
-	return getPINForTime(secret, time.Now())
+// DeskScope is the PIN scope of one registration desk.
+func DeskScope(deskID uuid.UUID) Scope {
+	return Scope(deskScopePrefix + deskID.String())
 }
 
-// getPINForTime returns the 6-digit PIN for the 3-hour window containing t.
-func getPINForTime(secret string, t time.Time) string {
-	window := t.Unix() / (rotationHours * 3600)
+// ParseScope reads a scope name ("rsvp", "final" or "desk:<id>").
+func ParseScope(s string) (Scope, bool) {
+	switch Scope(s) {
+	case ScopeRSVP, ScopeFinal:
+		return Scope(s), true
+	}
+	if deskID, ok := DeskID(Scope(s)); ok {
+		return DeskScope(deskID), true
+	}
+	return "", false
+}
+
+// DeskID returns the registration desk of a desk scope.
+func DeskID(scope Scope) (uuid.UUID, bool) {
+	id, ok := strings.CutPrefix(string(scope), deskScopePrefix)
+	if !ok {
+		return uuid.Nil, false
+	}
+	deskID, err := uuid.Parse(id)
+	return deskID, err == nil
+}
+
+// PINs generates and validates rotating 6-digit PINs for every scope.
+type PINs struct {
+	rsvpSecret  string
+	finalSecret string
+	rotation    time.Duration
+}
+
+// New returns a PIN generator; rotations under a minute fall back to DefaultRotation.
+// The final scope falls back to a value derived from the RSVP secret when finalSecret
+// is empty; desk PINs are always derived from the final scope's secret.
+func New(rsvpSecret, finalSecret string, rotation time.Duration) *PINs {
+	if rotation < time.Minute {
+		rotation = DefaultRotation
+	}
+	return &PINs{rsvpSecret: rsvpSecret, finalSecret: finalSecret, rotation: rotation}
+}
+
+// Rotation returns the rotation window.
+func (p *PINs) Rotation() time.Duration {
+	return p.rotation
+}
+
+// secret returns the key for a scope. RSVP and an explicitly configured final scope use
+// their secret as-is, so PINs stay the same as before scopes existed.
+func (p *PINs) secret(scope Scope) string {
+	if scope == ScopeRSVP {
+		return p.rsvpSecret
+	}
+	base := p.finalSecret
+	if base == "" {
+		base = p.rsvpSecret + "|" + string(ScopeFinal)
+	}
+	if scope == ScopeFinal {
+		return base
+	}
+	return base + "|" + string(scope)
+}
+
+// window returns the index of the rotation window containing t.
+func (p *PINs) window(t time.Time) int64 {
+	return t.Unix() / int64(p.rotation/time.Second)
+}
+
+// pinForWindow returns the 6-digit PIN of a scope for one rotation window.
+func (p *PINs) pinForWindow(scope Scope, window int64) string {
 	h := sha256.New()
-	h.Write([]byte(secret))
+	h.Write([]byte(p.secret(scope)))
 	buf := make([]byte, 8)
 	binary.BigEndian.PutUint64(buf, uint64(window))
 	h.Write(buf)
@@ -28,25 +107,34 @@ func getPINForTime(secret string, t time.Time) string {
 	return fmt.Sprintf("%06d", n)
 }
 
-// ValidatePIN checks if the provided PIN matches the current window's PIN.
-func ValidatePIN(secret, pin string) bool {
+// Current returns the scope's PIN for the current window.
+func (p *PINs) Current(scope Scope) string {
+	return p.pinForWindow(scope, p.window(time.Now()))
+}
+
+// Validate checks pin against the scope's current window and one window either side,
+// so a PIN read out just before rotation (or a desk clock slightly ahead) still works.
+func (p *PINs) Validate(scope Scope, pin string) bool {
 	if len(pin) != 6 {
 		return false
 	}
-	current := GetCurrentPIN(secret)
-	return pin == current
+	current := p.window(time.Now())
+	valid := false
+	for w := current - 1; w <= current+1; w++ {
+		if subtle.ConstantTimeCompare([]byte(pin), []byte(p.pinForWindow(scope, w))) == 1 {
+			valid = true
+		}
+	}
+	return valid
 }
 
-// GetNextRotationTime returns the time when the PIN will next change.
-func GetNextRotationTime() time.Time {
-	now := time.Now()
-	windowSec := int64(rotationHours * 3600)
-	elapsed := now.Unix() % windowSec
-	next := now.Unix() + (windowSec - elapsed)
-	return time.Unix(next, 0)
+// NextRotation returns the time when PINs will next change.
+func (p *PINs) NextRotation() time.Time {
+	windowSec := int64(p.rotation / time.Second)
+	return time.Unix((p.window(time.Now())+1)*windowSec, 0)
 }
 
-// GetSecondsUntilRotation returns seconds until next PIN rotation.
-func GetSecondsUntilRotation() int64 {
-	return GetNextRotationTime().Unix() - time.Now().Unix()
+// SecondsUntilRotation returns seconds until next PIN rotation.
+func (p *PINs) SecondsUntilRotation() int64 {
+	return p.NextRotation().Unix() - time.Now().Unix()
 }
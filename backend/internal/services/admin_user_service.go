@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+	"github.com/rift26/backend/pkg/email"
+)
+
+const (
+	inviteLinkTTL = 72 * time.Hour
+	resetLinkTTL  = 1 * time.Hour
+)
+
+// AdminUserService manages admin accounts: invites, set-password links, disabling and re-enabling.
+type AdminUserService struct {
+	userRepo          *repository.UserRepository
+	permissionService *PermissionService
+	sessions          *SessionService
+	emailService      *email.EmailService
+	frontendURL       string
+}
+
+func NewAdminUserService(userRepo *repository.UserRepository, permissionService *PermissionService, sessions *SessionService, emailService *email.EmailService, frontendURL string) *AdminUserService {
+	return &AdminUserService{
+		userRepo:          userRepo,
+		permissionService: permissionService,
+		sessions:          sessions,
+		emailService:      emailService,
+		frontendURL:       strings.TrimRight(frontendURL, "/"),
+	}
+}
+
+// List returns all admin accounts with last login times.
+func (s *AdminUserService) List(ctx context.Context) ([]models.AdminUser, error) {
+	return s.userRepo.ListAdmins(ctx)
+}
+
+// Get returns one admin account, or nil.
+func (s *AdminUserService) Get(ctx context.Context, id uuid.UUID) (*models.AdminUser, error) {
+	return s.userRepo.GetAdmin(ctx, id)
+}
+
+// Invite creates an admin without a password, grants roles and emails a set-password link.
+// The account is created even if the email fails; resend with SendPasswordReset.
+func (s *AdminUserService) Invite(ctx context.Context, req models.InviteAdminRequest, invitedBy uuid.UUID) (*models.AdminUser, bool, error) {
+	addr := strings.ToLower(strings.TrimSpace(req.Email))
+	existing, err := s.userRepo.GetByEmail(ctx, addr)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return nil, false, fmt.Errorf("a user with this email already exists")
+	}
+	user, err := s.userRepo.CreateInvited(ctx, addr, strings.TrimSpace(req.Name), models.UserRoleAdmin, &invitedBy)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(req.Roles) > 0 {
+		if err := s.permissionService.SetUserRoles(ctx, user.ID, req.Roles, &invitedBy); err != nil {
+			_ = s.userRepo.Delete(ctx, user.ID)
+			return nil, false, err
+		}
+	}
+	sent := s.sendPasswordLink(ctx, user, models.PasswordTokenInvite, &invitedBy) == nil
+	admin, err := s.userRepo.GetAdmin(ctx, user.ID)
+	return admin, sent, err
+}
+
+// Update changes an admin's display name.
+func (s *AdminUserService) Update(ctx context.Context, id uuid.UUID, req models.UpdateAdminUserRequest) (*models.AdminUser, error) {
+	if _, err := s.getAdmin(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdateName(ctx, id, strings.TrimSpace(req.Name)); err != nil {
+		return nil, err
+	}
+	return s.userRepo.GetAdmin(ctx, id)
+}
+
+// SetActive disables or re-enables an admin. Disabling ends all their sessions immediately.
+func (s *AdminUserService) SetActive(ctx context.Context, id uuid.UUID, active bool, actor uuid.UUID) error {
+	if !active && id == actor {
+		return fmt.Errorf("you cannot disable your own account")
+	}
+	if _, err := s.getAdmin(ctx, id); err != nil {
+		return err
+	}
+	if err := s.userRepo.SetActive(ctx, id, active); err != nil {
+		return err
+	}
+	if !active {
+		if _, err := s.sessions.RevokeAll(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendPasswordReset emails a one-time link to choose a new password (also resends invites).
+func (s *AdminUserService) SendPasswordReset(ctx context.Context, id uuid.UUID, actor uuid.UUID) error {
+	user, err := s.getAdmin(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !user.IsActive {
+		return fmt.Errorf("account is disabled")
+	}
+	purpose := models.PasswordTokenReset
+	if user.PasswordHash == "" {
+		purpose = models.PasswordTokenInvite
+	}
+	return s.sendPasswordLink(ctx, user, purpose, &actor)
+}
+
+// SetPassword redeems a set-password link. Existing sessions end so the new password takes over.
+func (s *AdminUserService) SetPassword(ctx context.Context, token, password string) error {
+	userID, err := s.userRepo.ConsumePasswordToken(ctx, hashRefreshToken(token))
+	if err != nil {
+		return err
+	}
+	if userID == uuid.Nil {
+		return fmt.Errorf("invalid or expired link")
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil || !user.IsActive {
+		return fmt.Errorf("account is disabled")
+	}
+	if err := s.userRepo.SetPassword(ctx, userID, password); err != nil {
+		return err
+	}
+	_, err = s.sessions.RevokeAll(ctx, userID)
+	return err
+}
+
+func (s *AdminUserService) sendPasswordLink(ctx context.Context, user *models.User, purpose string, createdBy *uuid.UUID) error {
+	ttl, validFor := resetLinkTTL, "1 hour"
+	if purpose == models.PasswordTokenInvite {
+		ttl, validFor = inviteLinkTTL, "3 days"
+	}
+	token, hash, err := newRefreshToken()
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.CreatePasswordToken(ctx, user.ID, hash, purpose, time.Now().Add(ttl), createdBy); err != nil {
+		return err
+	}
+	link := s.frontendURL + "/admin/set-password?token=" + url.QueryEscape(token)
+	if err := s.emailService.SendPasswordLinkEmail(user.Email, user.Name, link, purpose == models.PasswordTokenInvite, validFor); err != nil {
+		log.Printf("[AdminUsers] Failed to email %s link to %s: %v", purpose, user.Email, err)
+		return fmt.Errorf("failed to send email")
+	}
+	return nil
+}
+
+func (s *AdminUserService) getAdmin(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.Role != models.UserRoleAdmin {
+		return nil, fmt.Errorf("admin user not found")
+	}
+	return user, nil
+}
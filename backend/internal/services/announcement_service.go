@@ -4,10 +4,12 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
 )
 
 type AnnouncementService struct {
@@ -18,7 +20,8 @@ func NewAnnouncementService(db *sql.DB) *AnnouncementService {
 	return &AnnouncementService{db: db}
 }
 
-func (s *AnnouncementService) CreateAnnouncement(req models.CreateAnnouncementRequest, createdByEmail string) (*models.Announcement, error) {
+// CreateAnnouncement creates an announcement in the event (uuid.Nil = current event).
+func (s *AnnouncementService) CreateAnnouncement(eventID uuid.UUID, req models.CreateAnnouncementRequest, createdByEmail string) (*models.Announcement, error) {
 	announcementID := uuid.New()
 
 	// Get user ID from email
@@ -47,9 +50,9 @@ func (s *AnnouncementService) CreateAnnouncement(req models.CreateAnnouncementRe
 	}
 
 	_, err = s.db.Exec(`
-		INSERT INTO announcements (id, title, content, priority, filters, is_active, created_by, created_at, updated_at, button_text, button_url)
-		VALUES ($1, $2, $3, $4, $5, true, $6, $7, $7, $8, $9)
-	`, announcementID, req.Title, req.Content, priority, filtersJSON, createdByPtr, now, req.ButtonText, req.ButtonURL)
+		INSERT INTO announcements (id, title, content, priority, filters, is_active, created_by, created_at, updated_at, button_text, button_url, event_id)
+		VALUES ($1, $2, $3, $4, $5, true, $6, $7, $7, $8, $9, COALESCE($10, current_event_id()))
+	`, announcementID, req.Title, req.Content, priority, filtersJSON, createdByPtr, now, req.ButtonText, req.ButtonURL, repository.EventArg(eventID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create announcement: %w", err)
 	}
@@ -80,27 +83,34 @@ func (s *AnnouncementService) GetAnnouncementsForTeam(teamID string) ([]models.A
 	// Get team info
 	var memberCount int
 	var city sql.NullString
+	var status models.TeamStatus
+	var eventID uuid.UUID
 	err = s.db.QueryRow(`
-		SELECT member_count, COALESCE(city, '')
+		SELECT member_count, COALESCE(city, ''), status, event_id
 		FROM teams
 		WHERE id = $1
-	`, tid).Scan(&memberCount, &city)
+	`, tid).Scan(&memberCount, &city, &status, &eventID)
 	if err != nil {
 		return nil, fmt.Errorf("team not found: %w", err)
 	}
 
+	// Withdrawn and no-show teams no longer receive announcements
+	if status.IsInactive() {
+		return []models.Announcement{}, nil
+	}
+
 	cityStr := ""
 	if city.Valid {
 		cityStr = city.String
 	}
 
-	// Get all active announcements
+	// Get all active announcements of the team's event
 	rows, err := s.db.Query(`
 		SELECT id, title, content, priority, COALESCE(filters, '{}'), created_at, button_text, button_url
 		FROM announcements
-		WHERE is_active = true
+		WHERE is_active = true AND event_id = $1
 		ORDER BY priority DESC, created_at DESC
-	`)
+	`, eventID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query announcements: %w", err)
 	}
@@ -195,12 +205,14 @@ func (s *AnnouncementService) matchesFilters(filters models.AnnouncementFilters,
 	return true
 }
 
-func (s *AnnouncementService) GetAllAnnouncements() ([]models.Announcement, error) {
+// GetAllAnnouncements lists announcements of the event (uuid.Nil = current event).
+func (s *AnnouncementService) GetAllAnnouncements(eventID uuid.UUID) ([]models.Announcement, error) {
 	rows, err := s.db.Query(`
 		SELECT id, title, content, priority, is_active, COALESCE(filters, '{}'), created_by, created_at, updated_at, button_text, button_url
 		FROM announcements
+		WHERE event_id = COALESCE($1, current_event_id())
 		ORDER BY created_at DESC
-	`)
+	`, repository.EventArg(eventID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query announcements: %w", err)
 	}
@@ -240,6 +252,50 @@ func (s *AnnouncementService) GetAllAnnouncements() ([]models.Announcement, erro
 	return announcements, nil
 }
 
+// GetBroadcastAnnouncements returns active announcements of the current event that target every team,
+// optionally narrowed to one city (cityNames are its spellings, see CityService.Variations; nil means all cities).
+// Announcements aimed at team sizes, specific teams or RSVP states are left out.
+func (s *AnnouncementService) GetBroadcastAnnouncements(cityNames []string) ([]models.Announcement, error) {
+	all, err := s.GetAllAnnouncements(uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	announcements := []models.Announcement{}
+	for _, ann := range all {
+		if !ann.IsActive {
+			continue
+		}
+		var filters models.AnnouncementFilters
+		if len(ann.Filters) > 0 {
+			if err := json.Unmarshal(ann.Filters, &filters); err != nil {
+				continue
+			}
+		}
+		if len(filters.TeamSizes) > 0 || len(filters.TeamIDs) > 0 || filters.OnlyRSVP1Done || filters.OnlyShortlistedNoRSVP1 {
+			continue
+		}
+		if len(filters.Cities) > 0 && len(cityNames) > 0 && !cityMatches(filters.Cities, cityNames) {
+			continue
+		}
+		ann.Filters = nil
+		announcements = append(announcements, ann)
+	}
+	return announcements, nil
+}
+
+// cityMatches reports whether any targeted city is one of the names (case-insensitive)
+func cityMatches(targets, names []string) bool {
+	for _, t := range targets {
+		for _, n := range names {
+			if strings.EqualFold(strings.TrimSpace(t), n) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (s *AnnouncementService) DeleteAnnouncement(announcementID string) error {
 	aid, err := uuid.Parse(announcementID)
 	if err != nil {
@@ -264,10 +320,12 @@ func (s *AnnouncementService) DeleteAnnouncement(announcementID string) error {
 	return nil
 }
 
-func (s *AnnouncementService) GetTeamsMatchingFilters(filters models.AnnouncementFilters) ([]uuid.UUID, error) {
-	query := "SELECT id FROM teams WHERE 1=1"
-	var args []interface{}
-	argCount := 0
+// GetTeamsMatchingFilters returns teams of the event (uuid.Nil = current event) matching the filters.
+func (s *AnnouncementService) GetTeamsMatchingFilters(eventID uuid.UUID, filters models.AnnouncementFilters) ([]uuid.UUID, error) {
+	// Withdrawn and no-show teams are never targeted
+	query := "SELECT id FROM teams WHERE event_id = COALESCE($1, current_event_id()) AND status NOT IN ('withdrawn', 'no_show')"
+	args := []interface{}{repository.EventArg(eventID)}
+	argCount := 1
 
 	// Filter: only teams with RSVP I done, Final Confirmation (RSVP II) not done.
 	// Exclude any team that has completed RSVP II (status rsvp2_done/checked_in or rsvp2_locked = true).
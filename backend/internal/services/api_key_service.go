@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+)
+
+// apiKeyPrefix marks integration keys so they can't be mistaken for JWTs
+const apiKeyPrefix = "rk_"
+
+// APIKeyService manages integration API keys and authenticates requests made with them
+type APIKeyService struct {
+	repo        *repository.APIKeyRepository
+	cityService *CityService
+}
+
+func NewAPIKeyService(repo *repository.APIKeyRepository, cityService *CityService) *APIKeyService {
+	return &APIKeyService{repo: repo, cityService: cityService}
+}
+
+// IsAPIKey reports whether a credential looks like an API key
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, apiKeyPrefix)
+}
+
+// Create issues a key. The plaintext key is only returned here.
+func (s *APIKeyService) Create(ctx context.Context, req models.CreateAPIKeyRequest, createdBy uuid.UUID) (*models.CreatedAPIKey, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if len(req.Scopes) == 0 {
+		return nil, fmt.Errorf("at least one scope is required")
+	}
+	for _, scope := range req.Scopes {
+		if !models.IsValidAPIKeyScope(scope) {
+			return nil, fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("expires_at must be in the future")
+	}
+
+	key := &models.APIKey{Name: name, Scopes: req.Scopes, ExpiresAt: req.ExpiresAt}
+	if createdBy != uuid.Nil {
+		key.CreatedBy = &createdBy
+	}
+	if city := strings.TrimSpace(req.City); city != "" {
+		code, ok, err := s.cityService.Normalize(ctx, city)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown city %q", city)
+		}
+		key.City = &code
+	}
+
+	token, hash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	plaintext := apiKeyPrefix + token
+	key.Prefix = plaintext[:len(apiKeyPrefix)+8]
+	if err := s.repo.Create(ctx, key, hash); err != nil {
+		return nil, err
+	}
+	return &models.CreatedAPIKey{APIKey: *key, Key: plaintext}, nil
+}
+
+// List returns all keys without their secrets
+func (s *APIKeyService) List(ctx context.Context) ([]models.APIKey, error) {
+	return s.repo.List(ctx)
+}
+
+// Revoke disables a key; requests using it fail immediately
+func (s *APIKeyService) Revoke(ctx context.Context, id uuid.UUID) (bool, error) {
+	return s.repo.Revoke(ctx, id)
+}
+
+// Authenticate returns the active key matching the plaintext key, or nil.
+// Keys are looked up on every request so revocation takes effect at once.
+func (s *APIKeyService) Authenticate(ctx context.Context, plaintext, ip string) (*models.APIKey, error) {
+	key, err := s.repo.GetActiveByHash(ctx, hashRefreshToken(plaintext))
+	if err != nil || key == nil {
+		return nil, err
+	}
+	if err := s.repo.TouchLastUsed(ctx, key.ID, ip); err != nil {
+		log.Printf("[APIKeys] %v", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+)
+
+const (
+	auditDefaultPageSize = 50
+	auditMaxPageSize     = 200
+)
+
+// AuditService records privileged actions and serves the audit log to admins
+type AuditService struct {
+	repo *repository.AuditLogRepository
+}
+
+func NewAuditService(repo *repository.AuditLogRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// Record stores an entry, computing its diff from the before and after snapshots
+func (s *AuditService) Record(ctx context.Context, entry *models.AuditLog) error {
+	if entry.Diff == nil {
+		entry.Diff = auditDiff(entry.Before, entry.After)
+	}
+	return s.repo.Create(ctx, entry)
+}
+
+// auditDiff returns {"field": {"before": x, "after": y}} for each top-level field that differs.
+// Non-object snapshots are compared whole. Nil when there is no before snapshot or nothing changed.
+func auditDiff(before, after json.RawMessage) json.RawMessage {
+	if len(before) == 0 {
+		return nil
+	}
+	var b, a interface{}
+	if json.Unmarshal(before, &b) != nil {
+		return nil
+	}
+	if len(after) > 0 && json.Unmarshal(after, &a) != nil {
+		return nil
+	}
+
+	type change struct {
+		Before interface{} `json:"before"`
+		After  interface{} `json:"after"`
+	}
+	changes := map[string]change{}
+	bm, bok := b.(map[string]interface{})
+	am, aok := a.(map[string]interface{})
+	if bok && (aok || a == nil) {
+		for k, bv := range bm {
+			if av, ok := am[k]; !ok || !reflect.DeepEqual(av, bv) {
+				changes[k] = change{Before: bv, After: am[k]}
+			}
+		}
+		for k, av := range am {
+			if _, ok := bm[k]; !ok {
+				changes[k] = change{After: av}
+			}
+		}
+	} else if !reflect.DeepEqual(a, b) {
+		changes["value"] = change{Before: b, After: a}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	out, err := json.Marshal(changes)
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+// parseAuditTime accepts RFC3339 or a date. A bare "to" date includes that whole day.
+func parseAuditTime(v string, endOfDay bool) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: use RFC3339 or YYYY-MM-DD", v)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+func auditQuery(f models.AuditLogFilter) (repository.AuditLogQuery, error) {
+	q := repository.AuditLogQuery{AuditLogFilter: f}
+	var err error
+	if q.From, err = parseAuditTime(f.From, false); err != nil {
+		return q, err
+	}
+	if q.To, err = parseAuditTime(f.To, true); err != nil {
+		return q, err
+	}
+	return q, nil
+}
+
+// List returns one page of the audit log, newest first
+func (s *AuditService) List(ctx context.Context, f models.AuditLogFilter) (*models.AuditLogPage, error) {
+	q, err := auditQuery(f)
+	if err != nil {
+		return nil, err
+	}
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.PageSize < 1 {
+		f.PageSize = auditDefaultPageSize
+	}
+	if f.PageSize > auditMaxPageSize {
+		f.PageSize = auditMaxPageSize
+	}
+	entries, total, err := s.repo.List(ctx, q, f.PageSize, (f.Page-1)*f.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AuditLogPage{Entries: entries, Total: total, Page: f.Page, PageSize: f.PageSize}, nil
+}
+
+// ValidateFilter reports filter errors before an export starts streaming
+func (s *AuditService) ValidateFilter(f models.AuditLogFilter) error {
+	_, err := auditQuery(f)
+	return err
+}
+
+// ExportCSV writes every matching entry as CSV, newest first
+func (s *AuditService) ExportCSV(ctx context.Context, w io.Writer, f models.AuditLogFilter) error {
+	q, err := auditQuery(f)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	header := []string{"time", "actor_email", "actor_role", "actor_id", "ip", "method", "path", "action",
+		"target_type", "target_id", "status", "diff", "before", "after", "event_id", "user_agent"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	err = s.repo.Each(ctx, q, func(l *models.AuditLog) error {
+		actorID, eventID := "", ""
+		if l.ActorID != nil {
+			actorID = l.ActorID.String()
+		}
+		if l.EventID != nil {
+			eventID = l.EventID.String()
+		}
+		row := []string{
+			l.CreatedAt.Format(time.RFC3339), l.ActorEmail, l.ActorRole, actorID, l.IP, l.Method, l.Path, l.Action,
+			l.TargetType, l.TargetID, strconv.Itoa(l.StatusCode),
+			string(l.Diff), string(l.Before), string(l.After), eventID, strings.ReplaceAll(l.UserAgent, "\n", " "),
+		}
+		// Paths, bodies and user agents are client-supplied
+		for i := range row {
+			row[i] = csvSafe(row[i])
+		}
+		return cw.Write(row)
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
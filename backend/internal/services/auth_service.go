@@ -7,30 +7,29 @@ import (
 	"github.com/google/uuid"
 	"github.com/rift26/backend/internal/models"
 	"github.com/rift26/backend/internal/repository"
-	"github.com/rift26/backend/internal/utils"
 	"github.com/rift26/backend/pkg/auth"
 )
 
 type AuthService struct {
 	teamRepo     *repository.TeamRepository
 	firebaseAuth *auth.FirebaseAuthService
-	jwtSecret    string
+	sessions     *SessionService
 }
 
 func NewAuthService(
 	teamRepo *repository.TeamRepository,
 	firebaseAuth *auth.FirebaseAuthService,
-	jwtSecret string,
+	sessions *SessionService,
 ) *AuthService {
 	return &AuthService{
 		teamRepo:     teamRepo,
 		firebaseAuth: firebaseAuth,
-		jwtSecret:    jwtSecret,
+		sessions:     sessions,
 	}
 }
 
 // VerifyFirebaseToken verifies Firebase ID token and authenticates team leader
-func (s *AuthService) VerifyFirebaseToken(ctx context.Context, idToken string, teamID uuid.UUID) (*models.FirebaseAuthResponse, error) {
+func (s *AuthService) VerifyFirebaseToken(ctx context.Context, idToken string, teamID uuid.UUID, client models.SessionClient) (*models.FirebaseAuthResponse, error) {
 	// Verify Firebase ID token
 	_, phoneNumber, err := s.firebaseAuth.VerifyIDToken(ctx, idToken)
 	if err != nil {
@@ -61,23 +60,23 @@ func (s *AuthService) VerifyFirebaseToken(ctx context.Context, idToken string, t
 		return nil, fmt.Errorf("phone number does not match team leader")
 	}
 
-	// Generate JWT token for the team leader
-	// Firebase UID is not a valid UUID format, so we use the teamID for JWT
-	token, err := utils.GenerateJWT(
-		teamID, // Use team ID as the primary identifier
-		"",     // Email not required for phone auth
-		models.UserRoleParticipant,
-		&teamID,
-		s.jwtSecret,
-	)
+	// Start a session for the team leader
+	// Firebase UID is not a valid UUID format, so we use the teamID as the subject
+	tokens, err := s.sessions.Issue(ctx, models.SessionSubject{
+		SubjectID: teamID,
+		Role:      models.UserRoleParticipant,
+		TeamID:    &teamID,
+	}, client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate JWT: %w", err)
+		return nil, fmt.Errorf("failed to issue session: %w", err)
 	}
 
 	return &models.FirebaseAuthResponse{
-		Token:       token,
-		Team:        *team,
-		PhoneNumber: phoneNumber,
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		Team:         *team,
+		PhoneNumber:  phoneNumber,
 	}, nil
 }
 
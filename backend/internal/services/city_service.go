@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+)
+
+var cityCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// CityService manages the configurable list of event cities and resolves free-text city names to codes.
+type CityService struct {
+	repo *repository.CityRepository
+
+	mu       sync.RWMutex
+	cities   []models.CityConfig
+	cachedAt time.Time
+}
+
+func NewCityService(repo *repository.CityRepository) *CityService {
+	return &CityService{repo: repo}
+}
+
+// cityCacheTTL bounds how long the city list is cached between lookups.
+const cityCacheTTL = 30 * time.Second
+
+// List returns all cities (including inactive ones), cached briefly.
+func (s *CityService) List(ctx context.Context) ([]models.CityConfig, error) {
+	s.mu.RLock()
+	if s.cities != nil && time.Since(s.cachedAt) < cityCacheTTL {
+		list := s.cities
+		s.mu.RUnlock()
+		return list, nil
+	}
+	s.mu.RUnlock()
+
+	list, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.cities, s.cachedAt = list, time.Now()
+	s.mu.Unlock()
+	return list, nil
+}
+
+// Active returns only active cities.
+func (s *CityService) Active(ctx context.Context) ([]models.CityConfig, error) {
+	list, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	active := make([]models.CityConfig, 0, len(list))
+	for _, c := range list {
+		if c.IsActive {
+			active = append(active, c)
+		}
+	}
+	return active, nil
+}
+
+// Get returns a city by code, or nil if not found.
+func (s *CityService) Get(ctx context.Context, code string) (*models.CityConfig, error) {
+	list, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	code = strings.ToUpper(strings.TrimSpace(code))
+	for i := range list {
+		if list[i].Code == code {
+			c := list[i]
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+// Normalize resolves a code, display name or alias (case-insensitive) to a city code; ok is false
+// for unknown input. If nothing matches exactly, a name or alias that appears as whole words in the
+// input wins, so CSV values like "Bengaluru, Karnataka" still resolve but "Punecity" does not.
+func (s *CityService) Normalize(ctx context.Context, input string) (code string, ok bool, err error) {
+	in := strings.ToLower(strings.TrimSpace(input))
+	if in == "" {
+		return "", false, nil
+	}
+	list, err := s.List(ctx)
+	if err != nil {
+		return "", false, fmt.Errorf("load cities: %w", err)
+	}
+	for _, c := range list {
+		for _, n := range cityNames(c) {
+			if in == n {
+				return c.Code, true, nil
+			}
+		}
+	}
+	words := " " + cityWords(in) + " "
+	for _, c := range list {
+		for _, n := range cityNames(c) {
+			if w := cityWords(n); w != "" && strings.Contains(words, " "+w+" ") {
+				return c.Code, true, nil
+			}
+		}
+	}
+	return "", false, nil
+}
+
+// normalizeOrInput is Normalize for the best-effort lookups below: unknown input and lookup
+// failures both fall back to the input itself.
+func (s *CityService) normalizeOrInput(input string) (string, bool) {
+	code, ok, err := s.Normalize(context.Background(), input)
+	if err != nil || !ok {
+		return input, false
+	}
+	return code, true
+}
+
+// Variations returns the lower-case spellings of a city (code, name and aliases), used to match
+// tables and volunteers that store free-text city names. Unknown input is returned as-is.
+func (s *CityService) Variations(input string) []string {
+	code, ok := s.normalizeOrInput(input)
+	if !ok {
+		return []string{strings.ToLower(strings.TrimSpace(input))}
+	}
+	c, _ := s.Get(context.Background(), code)
+	if c == nil {
+		return []string{strings.ToLower(code)}
+	}
+	return cityNames(*c)
+}
+
+// HasSeatAllocation reports whether the city uses block/room seat allocation.
+func (s *CityService) HasSeatAllocation(input string) bool {
+	code, ok := s.normalizeOrInput(input)
+	if !ok {
+		return false
+	}
+	c, _ := s.Get(context.Background(), code)
+	return c != nil && c.SeatAllocation
+}
+
+// SeatAllocationCity returns the first active city that uses seat allocation, if any.
+func (s *CityService) SeatAllocationCity(ctx context.Context) (*models.CityConfig, error) {
+	list, err := s.Active(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if list[i].SeatAllocation {
+			c := list[i]
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+// Codes returns the codes of all active cities in display order.
+func (s *CityService) Codes(ctx context.Context) ([]string, error) {
+	list, err := s.Active(ctx)
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]string, len(list))
+	for i, c := range list {
+		codes[i] = c.Code
+	}
+	return codes, nil
+}
+
+// Create validates and inserts a new city.
+func (s *CityService) Create(ctx context.Context, req models.CreateCityRequest) (*models.CityConfig, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	if !cityCodePattern.MatchString(code) {
+		return nil, fmt.Errorf("code may only contain uppercase letters, digits and underscores")
+	}
+	tz := strings.TrimSpace(req.Timezone)
+	if tz == "" {
+		tz = "Asia/Kolkata"
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return nil, fmt.Errorf("invalid timezone: %s", tz)
+	}
+	c := &models.CityConfig{
+		Code:           code,
+		Name:           strings.TrimSpace(req.Name),
+		Aliases:        normalizeAliases(req.Aliases),
+		Timezone:       tz,
+		VenueName:      req.VenueName,
+		VenueAddress:   req.VenueAddress,
+		VenueMapsURL:   req.VenueMapsURL,
+		SeatAllocation: req.SeatAllocation,
+		IsActive:       true,
+		SortOrder:      req.SortOrder,
+	}
+	if err := s.repo.Create(ctx, c); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return c, nil
+}
+
+// Update changes the editable fields of a city. The code itself is immutable.
+func (s *CityService) Update(ctx context.Context, code string, req models.UpdateCityRequest) (*models.CityConfig, error) {
+	c, err := s.repo.GetByCode(ctx, strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, fmt.Errorf("city not found")
+	}
+	if req.Name != nil {
+		c.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Aliases != nil {
+		c.Aliases = normalizeAliases(*req.Aliases)
+	}
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone: %s", *req.Timezone)
+		}
+		c.Timezone = *req.Timezone
+	}
+	if req.VenueName != nil {
+		c.VenueName = req.VenueName
+	}
+	if req.VenueAddress != nil {
+		c.VenueAddress = req.VenueAddress
+	}
+	if req.VenueMapsURL != nil {
+		c.VenueMapsURL = req.VenueMapsURL
+	}
+	if req.SeatAllocation != nil {
+		c.SeatAllocation = *req.SeatAllocation
+	}
+	if req.IsActive != nil {
+		c.IsActive = *req.IsActive
+	}
+	if req.SortOrder != nil {
+		c.SortOrder = *req.SortOrder
+	}
+	if err := s.repo.Update(ctx, c); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return c, nil
+}
+
+// Delete removes a city that nothing references.
+func (s *CityService) Delete(ctx context.Context, code string) error {
+	if err := s.repo.Delete(ctx, strings.ToUpper(strings.TrimSpace(code))); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+func (s *CityService) invalidate() {
+	s.mu.Lock()
+	s.cities = nil
+	s.mu.Unlock()
+}
+
+// cityNames returns the lower-case code, name and aliases of a city without duplicates.
+func cityNames(c models.CityConfig) []string {
+	names := []string{strings.ToLower(c.Code), strings.ToLower(c.Name)}
+	for _, a := range c.Aliases {
+		names = append(names, strings.ToLower(a))
+	}
+	return normalizeAliases(names)
+}
+
+// cityWords lower-cases s and joins its letter/digit runs with single spaces ("Bengaluru, KA" -> "bengaluru ka").
+func cityWords(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+func normalizeAliases(in []string) []string {
+	out := make([]string, 0, len(in))
+	seen := make(map[string]bool)
+	for _, a := range in {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if a == "" || seen[a] {
+			continue
+		}
+		seen[a] = true
+		out = append(out, a)
+	}
+	return out
+}
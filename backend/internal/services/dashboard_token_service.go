@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+)
+
+// dashboardTokenPrefixLen is how much of a link token is kept to identify it in lists
+const dashboardTokenPrefixLen = 8
+
+// DashboardLinkError is returned when a dashboard link is unknown, expired or revoked.
+// Gone is set for links that existed but can no longer be used.
+type DashboardLinkError struct {
+	Reason string
+	Gone   bool
+}
+
+func (e *DashboardLinkError) Error() string {
+	return e.Reason
+}
+
+// DashboardTokenService issues, resolves and revokes team dashboard links and logs their use.
+type DashboardTokenService struct {
+	repo        *repository.DashboardTokenRepository
+	ttl         time.Duration
+	frontendURL string
+}
+
+func NewDashboardTokenService(repo *repository.DashboardTokenRepository, ttl time.Duration, frontendURL string) *DashboardTokenService {
+	return &DashboardTokenService{repo: repo, ttl: ttl, frontendURL: frontendURL}
+}
+
+// Resolve returns the active link for a token. A team's current teams.dashboard_token that has no
+// row yet is registered on first use and expires one TTL later.
+func (s *DashboardTokenService) Resolve(ctx context.Context, token string) (*models.DashboardToken, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, &DashboardLinkError{Reason: "Dashboard not found"}
+	}
+	hash := hashRefreshToken(token)
+	t, err := s.repo.GetByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		t, err = s.repo.RegisterCurrent(ctx, token, hash, tokenPrefix(token), time.Now().Add(s.ttl))
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case t == nil:
+		return nil, &DashboardLinkError{Reason: "Dashboard not found"}
+	case t.RevokedAt != nil:
+		return nil, &DashboardLinkError{Reason: "This dashboard link has been revoked. Ask your team leader for a new one", Gone: true}
+	case t.IsExpired():
+		return nil, &DashboardLinkError{Reason: "This dashboard link has expired. Ask your team leader for a new one", Gone: true}
+	}
+	return t, nil
+}
+
+// RecordAccess logs a request made with a link. Failures are logged, not returned.
+func (s *DashboardTokenService) RecordAccess(ctx context.Context, t *models.DashboardToken, ip, userAgent, method, path string) {
+	err := s.repo.LogAccess(ctx, &models.DashboardAccessLog{
+		TokenID:   t.ID,
+		TeamID:    t.TeamID,
+		IP:        optional(ip),
+		UserAgent: optional(userAgent),
+		Method:    method,
+		Path:      path,
+	})
+	if err != nil {
+		log.Printf("[Dashboard] Failed to log access for token %s: %v", t.ID, err)
+	}
+}
+
+// Issue generates a new link for the team, replacing its active links with the same access.
+// createdBy describes who asked for it (e.g. "leader:<email>" or "admin:<email>").
+func (s *DashboardTokenService) Issue(ctx context.Context, teamID uuid.UUID, access models.DashboardAccess, createdBy string) (*models.IssuedDashboardToken, error) {
+	if access != models.DashboardAccessFull && access != models.DashboardAccessReadOnly {
+		return nil, fmt.Errorf("access must be %q or %q", models.DashboardAccessFull, models.DashboardAccessReadOnly)
+	}
+	token, hash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(s.ttl)
+	t := &models.DashboardToken{
+		TeamID:    teamID,
+		Prefix:    tokenPrefix(token),
+		Access:    access,
+		CreatedBy: optional(createdBy),
+		ExpiresAt: &expiresAt,
+	}
+	if err := s.repo.Issue(ctx, t, token, hash); err != nil {
+		return nil, err
+	}
+	return &models.IssuedDashboardToken{
+		DashboardToken: *t,
+		Token:          token,
+		URL:            strings.TrimRight(s.frontendURL, "/") + "/dashboard/" + token,
+	}, nil
+}
+
+// List returns the team's links without their tokens
+func (s *DashboardTokenService) List(ctx context.Context, teamID uuid.UUID) ([]models.DashboardToken, error) {
+	return s.repo.ListByTeam(ctx, teamID)
+}
+
+// Revoke disables one of the team's links immediately
+func (s *DashboardTokenService) Revoke(ctx context.Context, teamID, id uuid.UUID) (bool, error) {
+	return s.repo.Revoke(ctx, teamID, id)
+}
+
+// AccessLogs returns the latest requests made with one of the team's links
+func (s *DashboardTokenService) AccessLogs(ctx context.Context, teamID, tokenID uuid.UUID, limit int) ([]models.DashboardAccessLog, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return s.repo.ListAccessLogs(ctx, teamID, tokenID, limit)
+}
+
+func tokenPrefix(token string) string {
+	if len(token) > dashboardTokenPrefixLen {
+		return token[:dashboardTokenPrefixLen]
+	}
+	return token
+}
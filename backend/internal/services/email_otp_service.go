@@ -4,36 +4,71 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rift26/backend/internal/models"
 	"github.com/rift26/backend/internal/repository"
-	"github.com/rift26/backend/internal/utils"
 	"github.com/rift26/backend/pkg/email"
 )
 
+// OTPLockoutPolicy locks a team out of OTP verification after MaxAttempts failures.
+// The lockout starts at BaseDuration and doubles with each repeat, up to MaxDuration.
+type OTPLockoutPolicy struct {
+	MaxAttempts  int
+	BaseDuration time.Duration
+	MaxDuration  time.Duration
+}
+
+// lockoutFor returns how long the team is locked out after its nth previous lockout
+func (p OTPLockoutPolicy) lockoutFor(previous int) time.Duration {
+	d := p.BaseDuration
+	for i := 0; i < previous && d < p.MaxDuration; i++ {
+		d *= 2
+	}
+	if d > p.MaxDuration {
+		d = p.MaxDuration
+	}
+	return d
+}
+
+// OTPLockedError is returned while a team is locked out of OTP verification
+type OTPLockedError struct {
+	Until time.Time
+}
+
+func (e *OTPLockedError) Error() string {
+	return fmt.Sprintf("too many failed OTP attempts. Please try again after %s", e.Until.Format(time.RFC3339))
+}
+
 // EmailOTPService handles email OTP operations
 type EmailOTPService struct {
 	otpRepo      *repository.OTPRepository
+	lockouts     *repository.OTPLockoutRepository
 	teamRepo     *repository.TeamRepository
 	emailService *email.EmailService
-	jwtSecret    string
+	sessions     *SessionService
+	lockout      OTPLockoutPolicy
 	enableOTP    bool // Feature flag to enable/disable OTP
 }
 
 // NewEmailOTPService creates a new EmailOTPService instance
 func NewEmailOTPService(
 	otpRepo *repository.OTPRepository,
+	lockouts *repository.OTPLockoutRepository,
 	teamRepo *repository.TeamRepository,
 	emailService *email.EmailService,
-	jwtSecret string,
+	sessions *SessionService,
+	lockout OTPLockoutPolicy,
 	enableOTP bool,
 ) *EmailOTPService {
 	return &EmailOTPService{
 		otpRepo:      otpRepo,
+		lockouts:     lockouts,
 		teamRepo:     teamRepo,
 		emailService: emailService,
-		jwtSecret:    jwtSecret,
+		sessions:     sessions,
+		lockout:      lockout,
 		enableOTP:    enableOTP,
 	}
 }
@@ -92,7 +127,7 @@ func (s *EmailOTPService) SendOTP(ctx context.Context, teamID uuid.UUID, email s
 	}
 
 	// Generate OTP
-	otp, err := s.otpRepo.CreateOTP(ctx, email, teamID)
+	otp, err := s.otpRepo.CreateOTP(ctx, email, teamID, models.OTPPurposeLogin)
 	if err != nil {
 		return fmt.Errorf("failed to create OTP: %w", err)
 	}
@@ -114,7 +149,7 @@ func (s *EmailOTPService) SendOTP(ctx context.Context, teamID uuid.UUID, email s
 
 // VerifyOTP verifies an OTP and returns JWT token and team data
 // If enableOTP is false, it skips OTP verification and only validates email
-func (s *EmailOTPService) VerifyOTP(ctx context.Context, teamID uuid.UUID, email, otpCode string) (*models.FirebaseAuthResponse, error) {
+func (s *EmailOTPService) VerifyOTP(ctx context.Context, teamID uuid.UUID, email, otpCode string, client models.SessionClient) (*models.FirebaseAuthResponse, error) {
 	// Get team details first
 	team, err := s.teamRepo.GetByID(ctx, teamID)
 	if err != nil {
@@ -155,32 +190,29 @@ func (s *EmailOTPService) VerifyOTP(ctx context.Context, teamID uuid.UUID, email
 		log.Printf("[AUTH] OTP disabled - email-only auth successful for %s (Team: %s)", email, team.TeamName)
 	} else {
 		// OTP is enabled - verify it
-		valid, err := s.otpRepo.VerifyOTP(ctx, email, otpCode, teamID)
-		if err != nil {
-			return nil, fmt.Errorf("OTP verification failed: %w", err)
-		}
-		if !valid {
-			return nil, fmt.Errorf("invalid or expired OTP")
+		if err := s.verifyWithLockout(ctx, teamID, email, otpCode, models.OTPPurposeLogin); err != nil {
+			return nil, err
 		}
 	}
 
-	// Generate JWT token with email
-	token, err := utils.GenerateJWT(
-		teamID,
-		email,
-		models.UserRoleParticipant,
-		&teamID,
-		s.jwtSecret,
-	)
+	// Start a session for the team leader (access token carries the email)
+	tokens, err := s.sessions.Issue(ctx, models.SessionSubject{
+		SubjectID: teamID,
+		Role:      models.UserRoleParticipant,
+		Email:     email,
+		TeamID:    &teamID,
+	}, client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate JWT token: %w", err)
+		return nil, fmt.Errorf("failed to issue session: %w", err)
 	}
 
 	// Return response (reusing FirebaseAuthResponse model)
 	response := &models.FirebaseAuthResponse{
-		Token:       token,
-		Team:        *team,
-		PhoneNumber: email, // Using this field for email  for backward compatibility
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		Team:         *team,
+		PhoneNumber:  email, // Using this field for email  for backward compatibility
 	}
 
 	// Debug log to check team data
@@ -190,6 +222,122 @@ func (s *EmailOTPService) VerifyOTP(ctx context.Context, teamID uuid.UUID, email
 	return response, nil
 }
 
+// leaderEmailMatches returns nil if email is the team leader's registered email.
+func (s *EmailOTPService) leaderEmailMatches(ctx context.Context, teamID uuid.UUID, email string) error {
+	return matchLeaderEmail(ctx, s.teamRepo, teamID, email)
+}
+
+// matchLeaderEmail returns nil if email is the registered email of the team's leader
+// (or of its first member when no leader is marked).
+func matchLeaderEmail(ctx context.Context, teamRepo *repository.TeamRepository, teamID uuid.UUID, email string) error {
+	members, err := teamRepo.GetMembersByTeamID(ctx, teamID)
+	if err != nil {
+		return fmt.Errorf("failed to get team members: %w", err)
+	}
+	if len(members) == 0 {
+		return fmt.Errorf("no members found for this team")
+	}
+	leaderEmail := members[0].Email
+	for _, member := range members {
+		if member.Role == models.RoleLeader {
+			leaderEmail = member.Email
+			break
+		}
+	}
+	if !caseInsensitiveEqual(leaderEmail, email) {
+		return fmt.Errorf("email does not match team leader's registered email")
+	}
+	return nil
+}
+
+// SendWithdrawalOTP emails the leader a code that only confirms withdrawing the team.
+// Unlike SendOTP this always sends a code, regardless of ENABLE_EMAIL_OTP.
+func (s *EmailOTPService) SendWithdrawalOTP(ctx context.Context, teamID uuid.UUID, email string) error {
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		return fmt.Errorf("failed to get team details: %w", err)
+	}
+	if team == nil {
+		return fmt.Errorf("team not found")
+	}
+	if err := s.leaderEmailMatches(ctx, teamID, email); err != nil {
+		return err
+	}
+
+	isRateLimited, err := s.otpRepo.IsRateLimited(ctx, email)
+	if err != nil {
+		return fmt.Errorf("failed to check rate limit: %w", err)
+	}
+	if isRateLimited {
+		return fmt.Errorf("too many OTP requests. Please try again later")
+	}
+
+	otp, err := s.otpRepo.CreateOTP(ctx, email, teamID, models.OTPPurposeWithdrawal)
+	if err != nil {
+		return fmt.Errorf("failed to create OTP: %w", err)
+	}
+
+	go func() {
+		if err := s.emailService.SendWithdrawalOTP(email, otp.OTPCode, team.TeamName); err != nil {
+			log.Printf("[EMAIL ERROR] Failed to send withdrawal OTP to %s: %v", email, err)
+		}
+	}()
+	return nil
+}
+
+// VerifyWithdrawalOTP checks a code sent by SendWithdrawalOTP (single use); login codes are rejected.
+func (s *EmailOTPService) VerifyWithdrawalOTP(ctx context.Context, teamID uuid.UUID, email, otpCode string) error {
+	if err := s.leaderEmailMatches(ctx, teamID, email); err != nil {
+		return err
+	}
+	return s.verifyWithLockout(ctx, teamID, email, otpCode, models.OTPPurposeWithdrawal)
+}
+
+// verifyWithLockout checks an OTP issued for purpose, counting failures against the team.
+// A locked-out team is rejected even with a correct code.
+func (s *EmailOTPService) verifyWithLockout(ctx context.Context, teamID uuid.UUID, email, otpCode string, purpose models.OTPPurpose) error {
+	lockout, err := s.lockouts.Get(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if lockout.IsLocked() {
+		return &OTPLockedError{Until: *lockout.LockedUntil}
+	}
+
+	valid, verifyErr := s.otpRepo.VerifyOTP(ctx, email, otpCode, teamID, purpose)
+	if valid {
+		if lockout != nil {
+			if _, err := s.lockouts.Clear(ctx, teamID); err != nil {
+				log.Printf("[AUTH] Failed to reset OTP failures for team %s: %v", teamID, err)
+			}
+		}
+		return nil
+	}
+
+	lockout, err = s.lockouts.RecordFailure(ctx, teamID, s.lockout.MaxAttempts, s.lockout.lockoutFor)
+	if err != nil {
+		return err
+	}
+	if lockout.IsLocked() {
+		log.Printf("[AUTH] Team %s locked out of OTP verification until %s", teamID, lockout.LockedUntil.Format(time.RFC3339))
+		return &OTPLockedError{Until: *lockout.LockedUntil}
+	}
+	if verifyErr != nil {
+		return fmt.Errorf("OTP verification failed: %w", verifyErr)
+	}
+	return fmt.Errorf("invalid or expired OTP")
+}
+
+// ListLockouts returns teams currently locked out of OTP verification (admin)
+func (s *EmailOTPService) ListLockouts(ctx context.Context) ([]models.OTPLockout, error) {
+	return s.lockouts.ListLocked(ctx)
+}
+
+// ClearLockout lifts a team's OTP lockout and resets its failure count (admin)
+func (s *EmailOTPService) ClearLockout(ctx context.Context, teamID uuid.UUID) (bool, error) {
+	return s.lockouts.Clear(ctx, teamID)
+}
+
 // caseInsensitiveEqual compares two strings case-insensitively
 func caseInsensitiveEqual(a, b string) bool {
 	if len(a) != len(b) {
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+)
+
+// defaultEventName is used in emails and certificates when no current event can be loaded.
+const defaultEventName = "RIFT '26"
+
+var eventSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// EventService manages editions of the hackathon and which one is current.
+type EventService struct {
+	repo *repository.EventRepository
+
+	mu       sync.RWMutex
+	current  *models.Event
+	cachedAt time.Time
+}
+
+func NewEventService(repo *repository.EventRepository) *EventService {
+	return &EventService{repo: repo}
+}
+
+// currentCacheTTL bounds how long the current event is cached between lookups.
+const currentCacheTTL = 30 * time.Second
+
+// List returns all events, newest first.
+func (s *EventService) List(ctx context.Context) ([]models.Event, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// Get returns an event by ID, or nil if not found.
+func (s *EventService) Get(ctx context.Context, id uuid.UUID) (*models.Event, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// Current returns the current event (cached briefly).
+func (s *EventService) Current(ctx context.Context) (*models.Event, error) {
+	s.mu.RLock()
+	if s.current != nil && time.Since(s.cachedAt) < currentCacheTTL {
+		e := s.current
+		s.mu.RUnlock()
+		return e, nil
+	}
+	s.mu.RUnlock()
+
+	e, err := s.repo.GetCurrent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, fmt.Errorf("no current event configured")
+	}
+	s.mu.Lock()
+	s.current, s.cachedAt = e, time.Now()
+	s.mu.Unlock()
+	return e, nil
+}
+
+// CurrentName returns the display name of the current event, falling back to RIFT '26.
+func (s *EventService) CurrentName() string {
+	e, err := s.Current(context.Background())
+	if err != nil || e == nil {
+		return defaultEventName
+	}
+	return e.Name
+}
+
+// Create validates and inserts a new event. New events are not current until activated.
+func (s *EventService) Create(ctx context.Context, req models.CreateEventRequest) (*models.Event, error) {
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !eventSlugPattern.MatchString(slug) {
+		return nil, fmt.Errorf("slug may only contain lowercase letters, digits and dashes")
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && req.EndsAt.Before(*req.StartsAt) {
+		return nil, fmt.Errorf("ends_at must not be before starts_at")
+	}
+	e := &models.Event{
+		Slug:     slug,
+		Name:     strings.TrimSpace(req.Name),
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+	}
+	if err := s.repo.Create(ctx, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Update changes the name and dates of an event.
+func (s *EventService) Update(ctx context.Context, id uuid.UUID, req models.UpdateEventRequest) (*models.Event, error) {
+	e, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, fmt.Errorf("event not found")
+	}
+	if req.Name != nil {
+		e.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.StartsAt != nil {
+		e.StartsAt = req.StartsAt
+	}
+	if req.EndsAt != nil {
+		e.EndsAt = req.EndsAt
+	}
+	if e.StartsAt != nil && e.EndsAt != nil && e.EndsAt.Before(*e.StartsAt) {
+		return nil, fmt.Errorf("ends_at must not be before starts_at")
+	}
+	if err := s.repo.Update(ctx, e); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return e, nil
+}
+
+// Activate makes the event current. New registrations, volunteers and content default to it.
+func (s *EventService) Activate(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.SetCurrent(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+func (s *EventService) invalidate() {
+	s.mu.Lock()
+	s.current = nil
+	s.mu.Unlock()
+}
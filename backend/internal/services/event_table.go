@@ -9,11 +9,12 @@ import (
 )
 
 type EventTableService struct {
-	repo *repository.EventTableRepository
+	repo        *repository.EventTableRepository
+	cityService *CityService
 }
 
-func NewEventTableService(repo *repository.EventTableRepository) *EventTableService {
-	return &EventTableService{repo: repo}
+func NewEventTableService(repo *repository.EventTableRepository, cityService *CityService) *EventTableService {
+	return &EventTableService{repo: repo, cityService: cityService}
 }
 
 // CreateEventTable creates a new event table
@@ -51,8 +52,12 @@ func (s *EventTableService) GetEventTable(id uuid.UUID) (*models.EventTable, err
 }
 
 // GetAllEventTables retrieves all event tables with optional filters
-func (s *EventTableService) GetAllEventTables(city *string, isActive *bool) ([]models.EventTable, error) {
-	return s.repo.GetAll(city, isActive)
+func (s *EventTableService) GetAllEventTables(eventID uuid.UUID, city *string, isActive *bool) ([]models.EventTable, error) {
+	var cityNames []string
+	if city != nil {
+		cityNames = s.cityService.Variations(*city)
+	}
+	return s.repo.GetAll(eventID, cityNames, isActive)
 }
 
 // UpdateEventTable updates an event table
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+)
+
+// JudgeService handles judge accounts, their assignments and what they can see.
+type JudgeService struct {
+	repo     *repository.JudgeRepository
+	userRepo *repository.UserRepository
+	sessions *SessionService
+}
+
+func NewJudgeService(repo *repository.JudgeRepository, userRepo *repository.UserRepository, sessions *SessionService) *JudgeService {
+	return &JudgeService{repo: repo, userRepo: userRepo, sessions: sessions}
+}
+
+// Login authenticates a judge by email and password and starts a session.
+func (s *JudgeService) Login(ctx context.Context, email, password string, client models.SessionClient) (*models.TokenPair, *models.User, error) {
+	user, err := s.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+	if err != nil || user == nil || user.Role != models.UserRoleJudge || !user.ComparePassword(password) {
+		return nil, nil, fmt.Errorf("invalid credentials")
+	}
+	if !user.IsActive {
+		return nil, nil, fmt.Errorf("account disabled")
+	}
+	tokens, err := s.sessions.Issue(ctx, models.SessionSubject{
+		SubjectID: user.ID,
+		Role:      models.UserRoleJudge,
+		Email:     user.Email,
+	}, client)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate token")
+	}
+	return tokens, user, nil
+}
+
+// CreateJudge adds a judge account.
+func (s *JudgeService) CreateJudge(ctx context.Context, req models.CreateJudgeRequest) (*models.User, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	existing, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("a user with this email already exists")
+	}
+	return s.userRepo.Create(ctx, email, req.Password, strings.TrimSpace(req.Name), models.UserRoleJudge)
+}
+
+// ListJudges returns judges with their assignment count in the event.
+func (s *JudgeService) ListJudges(ctx context.Context, eventID uuid.UUID) ([]models.Judge, error) {
+	return s.repo.ListJudges(ctx, eventID)
+}
+
+// DeleteJudge removes a judge with their assignments and notes, and ends their sessions.
+func (s *JudgeService) DeleteJudge(ctx context.Context, judgeID uuid.UUID) error {
+	if _, err := s.getJudge(ctx, judgeID); err != nil {
+		return err
+	}
+	if err := s.userRepo.Delete(ctx, judgeID); err != nil {
+		return err
+	}
+	_, err := s.sessions.RevokeAll(ctx, judgeID)
+	return err
+}
+
+// ListAssignments returns a judge's tracks and submissions in the event.
+func (s *JudgeService) ListAssignments(ctx context.Context, judgeID, eventID uuid.UUID) ([]models.JudgeAssignment, error) {
+	return s.repo.ListAssignments(ctx, judgeID, eventID)
+}
+
+// Assign gives a judge a whole track or a single submission.
+func (s *JudgeService) Assign(ctx context.Context, judgeID, eventID uuid.UUID, req models.CreateJudgeAssignmentRequest, createdBy *uuid.UUID) (*models.JudgeAssignment, error) {
+	if _, err := s.getJudge(ctx, judgeID); err != nil {
+		return nil, err
+	}
+	a := &models.JudgeAssignment{JudgeID: judgeID, CreatedBy: createdBy}
+	switch {
+	case req.Track != nil && strings.TrimSpace(*req.Track) != "" && req.SubmissionID == nil:
+		track := strings.TrimSpace(*req.Track)
+		a.Track = &track
+	case req.SubmissionID != nil && (req.Track == nil || strings.TrimSpace(*req.Track) == ""):
+		ok, err := s.repo.SubmissionExists(ctx, *req.SubmissionID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("submission not found")
+		}
+		a.SubmissionID = req.SubmissionID
+	default:
+		return nil, fmt.Errorf("provide either track or submission_id")
+	}
+	if err := s.repo.CreateAssignment(ctx, a, eventID); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Unassign removes one of a judge's assignments.
+func (s *JudgeService) Unassign(ctx context.Context, judgeID, assignmentID uuid.UUID) error {
+	return s.repo.DeleteAssignment(ctx, judgeID, assignmentID)
+}
+
+// ListSubmissions returns the submissions assigned to the judge, without participant PII.
+func (s *JudgeService) ListSubmissions(ctx context.Context, judgeID, eventID uuid.UUID, psID *uuid.UUID) ([]repository.JudgeSubmissionRow, error) {
+	rows, err := s.repo.ListSubmissionsForJudge(ctx, judgeID, eventID, psID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		redactForJudge(&rows[i])
+	}
+	return rows, nil
+}
+
+// redactForJudge drops the custom field values judges must not see (see JudgeHiddenFields) from the
+// judged version and its late edits.
+func redactForJudge(row *repository.JudgeSubmissionRow) {
+	hidden := JudgeHiddenFields(row.PSFieldsJSON)
+	if len(hidden) == 0 {
+		return
+	}
+	if row.CustomFieldsJSON.Valid {
+		row.CustomFieldsJSON.String = string(redactCustomFields([]byte(row.CustomFieldsJSON.String), hidden))
+	}
+	for i := range row.LateEdits {
+		row.LateEdits[i].CustomFields = redactCustomFields(row.LateEdits[i].CustomFields, hidden)
+	}
+}
+
+// GetSubmission returns one assigned submission with its late edits, or nil if it is not assigned to the judge.
+// Late edits don't say who saved them, like the rest of the judge view.
+func (s *JudgeService) GetSubmission(ctx context.Context, judgeID, submissionID uuid.UUID) (*repository.JudgeSubmissionRow, error) {
+	row, err := s.repo.GetSubmissionForJudge(ctx, judgeID, submissionID)
+	if err != nil || row == nil {
+		return row, err
+	}
+	if row.LateEditCount > 0 {
+		edits, err := s.repo.ListLateEdits(ctx, submissionID, row.Version)
+		if err != nil {
+			return nil, err
+		}
+		for i := range edits {
+			edits[i].SubmittedBy = nil
+		}
+		row.LateEdits = edits
+	}
+	redactForJudge(row)
+	return row, nil
+}
+
+// SaveNote stores the judge's note on an assigned submission.
+func (s *JudgeService) SaveNote(ctx context.Context, judgeID, submissionID uuid.UUID, note string) (*models.JudgeNote, error) {
+	sub, err := s.repo.GetSubmissionForJudge(ctx, judgeID, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, fmt.Errorf("submission not found")
+	}
+	return s.repo.UpsertNote(ctx, judgeID, submissionID, strings.TrimSpace(note))
+}
+
+// ListNotes returns all judges' notes on a submission (admin).
+func (s *JudgeService) ListNotes(ctx context.Context, submissionID uuid.UUID) ([]models.JudgeNote, error) {
+	return s.repo.ListNotes(ctx, submissionID)
+}
+
+func (s *JudgeService) getJudge(ctx context.Context, judgeID uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, judgeID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.Role != models.UserRoleJudge {
+		return nil, fmt.Errorf("judge not found")
+	}
+	return user, nil
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+)
+
+// sheetTotal is the weighted mean of score/max_score over the rubric's criteria, scaled to 0-100.
+// It reports false when the sheet doesn't score every criterion (the rubric changed after it was saved).
+func sheetTotal(criteria []models.RubricCriterion, scores map[string]float64) (float64, bool) {
+	var sum, weights float64
+	for _, c := range criteria {
+		v, ok := scores[c.Key]
+		if !ok {
+			return 0, false
+		}
+		sum += c.Weight * math.Min(v, c.MaxScore) / c.MaxScore
+		weights += c.Weight
+	}
+	if weights == 0 {
+		return 0, false
+	}
+	return 100 * sum / weights, true
+}
+
+// meanStd returns the mean and population standard deviation of values.
+func meanStd(values []float64) (mean, std float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		std += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(std / float64(len(values)))
+}
+
+// aggregateScores turns a round's sheets into one unranked entry per submission. Sheets whose
+// track has no rubric, or that miss one of its criteria, are left out.
+//
+// The raw score is the mean of the judges' totals. When normalizing, each total becomes a z-score
+// against its judge's own totals in the round, which cancels out harsh and lenient judges; the
+// submission's mean z-score is mapped back onto the round's 0-100 scale as its score. A judge with
+// fewer than two sheets, or identical totals, shows no bias to remove, so their totals are
+// standardized against the whole round instead.
+func aggregateScores(round *models.JudgingRound, rows []repository.RoundScoreRow, normalize bool) []models.LeaderboardEntry {
+	type sheet struct {
+		row    *repository.RoundScoreRow
+		rubric *models.JudgingRubric
+		total  float64
+	}
+	sheets := make([]sheet, 0, len(rows))
+	all := make([]float64, 0, len(rows))
+	byJudge := make(map[uuid.UUID][]float64)
+	for i := range rows {
+		rubric := rubricForTrack(round, rows[i].Track)
+		if rubric == nil {
+			continue
+		}
+		total, ok := sheetTotal(rubric.Criteria, rows[i].Scores)
+		if !ok {
+			continue
+		}
+		sheets = append(sheets, sheet{row: &rows[i], rubric: rubric, total: total})
+		all = append(all, total)
+		byJudge[rows[i].JudgeID] = append(byJudge[rows[i].JudgeID], total)
+	}
+
+	roundMean, roundStd := meanStd(all)
+	type judgeStats struct{ mean, std float64 }
+	judges := make(map[uuid.UUID]judgeStats, len(byJudge))
+	for id, totals := range byJudge {
+		mean, std := meanStd(totals)
+		if len(totals) < 2 || std == 0 {
+			mean, std = roundMean, roundStd
+		}
+		judges[id] = judgeStats{mean: mean, std: std}
+	}
+
+	type acc struct {
+		entry    models.LeaderboardEntry
+		totals   []float64
+		zs       []float64
+		criteria map[string][]float64
+	}
+	bySubmission := make(map[uuid.UUID]*acc)
+	order := make([]uuid.UUID, 0)
+	for _, sh := range sheets {
+		a, ok := bySubmission[sh.row.SubmissionID]
+		if !ok {
+			a = &acc{
+				entry: models.LeaderboardEntry{
+					SubmissionID: sh.row.SubmissionID,
+					TeamID:       sh.row.TeamID,
+					TeamName:     sh.row.TeamName,
+					City:         sh.row.City,
+					Track:        sh.row.Track,
+					PSName:       sh.row.PSName,
+				},
+				criteria: make(map[string][]float64),
+			}
+			bySubmission[sh.row.SubmissionID] = a
+			order = append(order, sh.row.SubmissionID)
+		}
+		a.totals = append(a.totals, sh.total)
+		var z float64
+		if js := judges[sh.row.JudgeID]; js.std > 0 {
+			z = (sh.total - js.mean) / js.std
+		}
+		a.zs = append(a.zs, z)
+		for _, c := range sh.rubric.Criteria {
+			a.criteria[c.Key] = append(a.criteria[c.Key], sh.row.Scores[c.Key])
+		}
+	}
+
+	entries := make([]models.LeaderboardEntry, 0, len(order))
+	for _, id := range order {
+		a := bySubmission[id]
+		e := a.entry
+		e.JudgeCount = len(a.totals)
+		raw, _ := meanStd(a.totals)
+		e.RawScore = round2(raw)
+		e.Score = e.RawScore
+		if normalize {
+			z, _ := meanStd(a.zs)
+			z = math.Round(z*1000) / 1000
+			e.ZScore = &z
+			e.Score = round2(roundMean + z*roundStd)
+		}
+		e.Criteria = make(map[string]float64, len(a.criteria))
+		for key, values := range a.criteria {
+			mean, _ := meanStd(values)
+			e.Criteria[key] = round2(mean)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// rankEntries sorts by score, then raw score, judge count and team name; equal scores share a rank.
+func rankEntries(entries []models.LeaderboardEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.RawScore != b.RawScore {
+			return a.RawScore > b.RawScore
+		}
+		if a.JudgeCount != b.JudgeCount {
+			return a.JudgeCount > b.JudgeCount
+		}
+		return strings.ToLower(a.TeamName) < strings.ToLower(b.TeamName)
+	})
+	for i := range entries {
+		if i > 0 && entries[i].Score == entries[i-1].Score {
+			entries[i].Rank = entries[i-1].Rank
+		} else {
+			entries[i].Rank = i + 1
+		}
+	}
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,365 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+)
+
+// maxScoreCommentLen caps a per-criterion comment; the overall comment is capped by the request binding.
+const maxScoreCommentLen = 2000
+
+// JudgingNotFoundError is returned when a round, rubric or assigned submission doesn't exist.
+type JudgingNotFoundError struct {
+	What string
+}
+
+func (e *JudgingNotFoundError) Error() string {
+	return e.What + " not found"
+}
+
+// ScoreValidationError lists the criteria a score sheet got wrong, keyed by criterion key.
+type ScoreValidationError struct {
+	Criteria map[string]string
+}
+
+func (e *ScoreValidationError) Error() string {
+	return "some scores are invalid"
+}
+
+// JudgingService runs rubric judging: rounds with a rubric per track, judges' score sheets on
+// their assigned submissions and the per-round leaderboard.
+type JudgingService struct {
+	repo   *repository.JudgingRepository
+	judges *repository.JudgeRepository
+	cities *CityService
+}
+
+func NewJudgingService(repo *repository.JudgingRepository, judges *repository.JudgeRepository, cities *CityService) *JudgingService {
+	return &JudgingService{repo: repo, judges: judges, cities: cities}
+}
+
+// ListRounds returns the event's rounds with their rubrics.
+func (s *JudgingService) ListRounds(ctx context.Context, eventID uuid.UUID) ([]models.JudgingRound, error) {
+	return s.repo.ListRounds(ctx, eventID)
+}
+
+// GetRound returns a round with its rubrics.
+func (s *JudgingService) GetRound(ctx context.Context, id uuid.UUID) (*models.JudgingRound, error) {
+	round, err := s.repo.GetRound(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if round == nil {
+		return nil, &JudgingNotFoundError{What: "judging round"}
+	}
+	return round, nil
+}
+
+// CreateRound adds a round to the event.
+func (s *JudgingService) CreateRound(ctx context.Context, eventID uuid.UUID, req models.CreateJudgingRoundRequest, createdBy *uuid.UUID) (*models.JudgingRound, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	round := &models.JudgingRound{Name: name, Normalize: req.Normalize, CreatedBy: createdBy}
+	if err := s.repo.CreateRound(ctx, round, eventID); err != nil {
+		return nil, err
+	}
+	return round, nil
+}
+
+// UpdateRound renames an open round or changes whether its leaderboard normalizes by default.
+func (s *JudgingService) UpdateRound(ctx context.Context, id uuid.UUID, req models.UpdateJudgingRoundRequest) (*models.JudgingRound, error) {
+	round, err := s.GetRound(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if round.Finalized() {
+		return nil, repository.ErrRoundFinalized
+	}
+	name, normalize := round.Name, round.Normalize
+	if req.Name != nil {
+		if name = strings.TrimSpace(*req.Name); name == "" {
+			return nil, fmt.Errorf("name is required")
+		}
+	}
+	if req.Normalize != nil {
+		normalize = *req.Normalize
+	}
+	if err := s.repo.UpdateRound(ctx, id, name, normalize); err != nil {
+		return nil, err
+	}
+	round.Name, round.Normalize = name, normalize
+	return round, nil
+}
+
+// FinalizeRound locks the round: its rubrics and scores can no longer change.
+func (s *JudgingService) FinalizeRound(ctx context.Context, id uuid.UUID, finalizedBy *uuid.UUID) (*models.JudgingRound, error) {
+	round, err := s.GetRound(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if round.Finalized() {
+		return nil, repository.ErrRoundFinalized
+	}
+	if err := s.repo.FinalizeRound(ctx, id, finalizedBy); err != nil {
+		return nil, err
+	}
+	return s.GetRound(ctx, id)
+}
+
+// SetRubric creates or replaces the rubric of a track in an open round. Sheets already saved keep
+// their scores; those missing a new criterion have no total until the judge rescores them.
+func (s *JudgingService) SetRubric(ctx context.Context, roundID uuid.UUID, req models.JudgingRubricRequest, updatedBy *uuid.UUID) (*models.JudgingRubric, error) {
+	track := strings.TrimSpace(req.Track)
+	if track == "" {
+		return nil, fmt.Errorf("track is required")
+	}
+	criteria, err := parseRubricCriteria(req.Criteria)
+	if err != nil {
+		return nil, err
+	}
+	round, err := s.GetRound(ctx, roundID)
+	if err != nil {
+		return nil, err
+	}
+	if round.Finalized() {
+		return nil, repository.ErrRoundFinalized
+	}
+	return s.repo.UpsertRubric(ctx, roundID, track, criteria, updatedBy)
+}
+
+// DeleteRubric removes a track's rubric from an open round.
+func (s *JudgingService) DeleteRubric(ctx context.Context, roundID, rubricID uuid.UUID) error {
+	round, err := s.GetRound(ctx, roundID)
+	if err != nil {
+		return err
+	}
+	if round.Finalized() {
+		return repository.ErrRoundFinalized
+	}
+	return s.repo.DeleteRubric(ctx, roundID, rubricID)
+}
+
+// parseRubricCriteria trims and checks criteria: unique keys, a label, and positive finite weights and max scores.
+func parseRubricCriteria(in []models.RubricCriterion) ([]models.RubricCriterion, error) {
+	if len(in) == 0 {
+		return nil, fmt.Errorf("a rubric needs at least one criterion")
+	}
+	seen := make(map[string]bool, len(in))
+	out := make([]models.RubricCriterion, 0, len(in))
+	for _, c := range in {
+		c.Key = strings.TrimSpace(c.Key)
+		c.Label = strings.TrimSpace(c.Label)
+		c.Description = strings.TrimSpace(c.Description)
+		if !customFieldKeyPattern.MatchString(c.Key) {
+			return nil, fmt.Errorf("criterion key %q must be 1-64 letters, digits, '_' or '-'", c.Key)
+		}
+		if seen[c.Key] {
+			return nil, fmt.Errorf("duplicate criterion key %q", c.Key)
+		}
+		seen[c.Key] = true
+		if c.Label == "" {
+			return nil, fmt.Errorf("criterion %q needs a label", c.Key)
+		}
+		if !(c.Weight > 0) || math.IsInf(c.Weight, 0) {
+			return nil, fmt.Errorf("criterion %q needs a positive weight", c.Key)
+		}
+		if !(c.MaxScore > 0) || math.IsInf(c.MaxScore, 0) {
+			return nil, fmt.Errorf("criterion %q needs a positive max_score", c.Key)
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// SaveScore creates or replaces the judge's sheet for an assigned submission in an open round.
+// Every criterion of the rubric for the submission's track needs a score from 0 to its max_score.
+func (s *JudgingService) SaveScore(ctx context.Context, judgeID, submissionID uuid.UUID, req models.JudgeScoreRequest) (*models.JudgeScore, error) {
+	sub, err := s.judges.GetSubmissionForJudge(ctx, judgeID, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, &JudgingNotFoundError{What: "submission"}
+	}
+	round, err := s.GetRound(ctx, req.RoundID)
+	if err != nil {
+		return nil, err
+	}
+	if round.Finalized() {
+		return nil, repository.ErrRoundFinalized
+	}
+	rubric := rubricForTrack(round, sub.PSTrack)
+	if rubric == nil {
+		return nil, fmt.Errorf("round %q has no rubric for track %q", round.Name, sub.PSTrack)
+	}
+
+	invalid := make(map[string]string)
+	scores := make(map[string]float64, len(rubric.Criteria))
+	known := make(map[string]bool, len(rubric.Criteria))
+	for _, c := range rubric.Criteria {
+		known[c.Key] = true
+		v, ok := req.Scores[c.Key]
+		switch {
+		case !ok:
+			invalid[c.Key] = "Score is required"
+		case math.IsNaN(v) || v < 0 || v > c.MaxScore:
+			invalid[c.Key] = fmt.Sprintf("Score must be between 0 and %g", c.MaxScore)
+		default:
+			scores[c.Key] = v
+		}
+	}
+	for key := range req.Scores {
+		if !known[key] {
+			invalid[key] = "Not a criterion of this rubric"
+		}
+	}
+	comments := make(map[string]string)
+	for key, text := range req.Comments {
+		text = strings.TrimSpace(text)
+		switch {
+		case !known[key]:
+			invalid[key] = "Not a criterion of this rubric"
+		case utf8.RuneCountInString(text) > maxScoreCommentLen:
+			invalid[key] = fmt.Sprintf("Comment must be at most %d characters", maxScoreCommentLen)
+		case text != "":
+			comments[key] = text
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, &ScoreValidationError{Criteria: invalid}
+	}
+
+	score := &models.JudgeScore{
+		RoundID:      round.ID,
+		JudgeID:      judgeID,
+		SubmissionID: submissionID,
+		Scores:       scores,
+		Comments:     comments,
+		Comment:      strings.TrimSpace(req.Comment),
+	}
+	if err := s.repo.UpsertScore(ctx, score); err != nil {
+		return nil, err
+	}
+	if total, ok := sheetTotal(rubric.Criteria, scores); ok {
+		score.Total = &total
+	}
+	return score, nil
+}
+
+// ListJudgeScores returns the judge's own sheets for an assigned submission, with totals.
+func (s *JudgingService) ListJudgeScores(ctx context.Context, judgeID, submissionID uuid.UUID) ([]models.JudgeScore, error) {
+	rows, err := s.repo.ListSubmissionScores(ctx, submissionID, &judgeID)
+	if err != nil {
+		return nil, err
+	}
+	return s.withTotals(ctx, rows)
+}
+
+// ListSubmissionScores returns every judge's sheets for a submission, with totals (admin).
+func (s *JudgingService) ListSubmissionScores(ctx context.Context, submissionID uuid.UUID) ([]models.JudgeScore, error) {
+	rows, err := s.repo.ListSubmissionScores(ctx, submissionID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.withTotals(ctx, rows)
+}
+
+// withTotals computes each sheet's total under its round's current rubric for the submission's track.
+func (s *JudgingService) withTotals(ctx context.Context, rows []repository.RoundScoreRow) ([]models.JudgeScore, error) {
+	rounds := make(map[uuid.UUID]*models.JudgingRound)
+	list := make([]models.JudgeScore, 0, len(rows))
+	for _, row := range rows {
+		round, ok := rounds[row.RoundID]
+		if !ok {
+			var err error
+			if round, err = s.repo.GetRound(ctx, row.RoundID); err != nil {
+				return nil, err
+			}
+			rounds[row.RoundID] = round
+		}
+		score := row.JudgeScore
+		if rubric := rubricForTrack(round, row.Track); rubric != nil {
+			if total, ok := sheetTotal(rubric.Criteria, score.Scores); ok {
+				score.Total = &total
+			}
+		}
+		list = append(list, score)
+	}
+	return list, nil
+}
+
+// LeaderboardOptions narrows a leaderboard to a track and city and overrides the round's normalization.
+type LeaderboardOptions struct {
+	Track     string
+	City      string
+	Normalize *bool
+}
+
+// Leaderboard ranks the round's scored submissions. Normalization always uses every sheet of the
+// round, so a judge's bias is measured the same way whichever track or city is shown.
+func (s *JudgingService) Leaderboard(ctx context.Context, roundID uuid.UUID, opts LeaderboardOptions) (*models.Leaderboard, error) {
+	round, err := s.GetRound(ctx, roundID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.ListRoundScores(ctx, roundID)
+	if err != nil {
+		return nil, err
+	}
+	normalize := round.Normalize
+	if opts.Normalize != nil {
+		normalize = *opts.Normalize
+	}
+	entries := aggregateScores(round, rows, normalize)
+
+	track := strings.TrimSpace(opts.Track)
+	city := strings.TrimSpace(opts.City)
+	var cityNames map[string]bool
+	if city != "" {
+		cityNames = make(map[string]bool)
+		for _, name := range s.cities.Variations(city) {
+			cityNames[name] = true
+		}
+	}
+	filtered := make([]models.LeaderboardEntry, 0, len(entries))
+	for _, e := range entries {
+		if track != "" && !strings.EqualFold(e.Track, track) {
+			continue
+		}
+		if cityNames != nil && (e.City == nil || !cityNames[strings.ToLower(strings.TrimSpace(*e.City))]) {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+	rankEntries(filtered)
+
+	return &models.Leaderboard{
+		Round:      *round,
+		Track:      track,
+		City:       city,
+		Normalized: normalize,
+		Entries:    filtered,
+	}, nil
+}
+
+// rubricForTrack returns the round's rubric for a track (case-insensitive), or nil.
+func rubricForTrack(round *models.JudgingRound, track string) *models.JudgingRubric {
+	if round == nil {
+		return nil
+	}
+	for i := range round.Rubrics {
+		if strings.EqualFold(round.Rubrics[i].Track, strings.TrimSpace(track)) {
+			return &round.Rubrics[i]
+		}
+	}
+	return nil
+}
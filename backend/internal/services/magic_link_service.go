@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+	"github.com/rift26/backend/internal/utils"
+	"github.com/rift26/backend/pkg/email"
+)
+
+const (
+	magicLinkTTL = 15 * time.Minute
+	// At most magicLinkLimit links per email within magicLinkWindow
+	magicLinkLimit  = 3
+	magicLinkWindow = 15 * time.Minute
+)
+
+// MagicLinkService emails team leaders a single-use signed link that signs them in to the dashboard.
+// It sits alongside the email OTP flow and is gated by the same ENABLE_EMAIL_OTP flag.
+type MagicLinkService struct {
+	repo         *repository.MagicLinkRepository
+	teamRepo     *repository.TeamRepository
+	emailService *email.EmailService
+	sessions     *SessionService
+	jwtSecret    string
+	frontendURL  string
+	enabled      bool
+}
+
+// NewMagicLinkService creates a new MagicLinkService instance
+func NewMagicLinkService(
+	repo *repository.MagicLinkRepository,
+	teamRepo *repository.TeamRepository,
+	emailService *email.EmailService,
+	sessions *SessionService,
+	jwtSecret, frontendURL string,
+	enabled bool,
+) *MagicLinkService {
+	return &MagicLinkService{
+		repo:         repo,
+		teamRepo:     teamRepo,
+		emailService: emailService,
+		sessions:     sessions,
+		jwtSecret:    jwtSecret,
+		frontendURL:  frontendURL,
+		enabled:      enabled,
+	}
+}
+
+// Enabled reports whether magic links are sent (they follow ENABLE_EMAIL_OTP)
+func (s *MagicLinkService) Enabled() bool {
+	return s.enabled
+}
+
+// SendLink emails a sign-in link to the team leader.
+// If links are disabled it only validates the email, like SendOTP does.
+func (s *MagicLinkService) SendLink(ctx context.Context, teamID uuid.UUID, email string, client models.SessionClient) error {
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		return fmt.Errorf("failed to get team details: %w", err)
+	}
+	if team == nil {
+		return fmt.Errorf("team not found")
+	}
+	if err := matchLeaderEmail(ctx, s.teamRepo, teamID, email); err != nil {
+		return err
+	}
+
+	if !s.enabled {
+		log.Printf("[AUTH] Magic links disabled - email validated successfully for %s (Team: %s)", email, team.TeamName)
+		return nil
+	}
+
+	sent, err := s.repo.CountSince(ctx, email, time.Now().Add(-magicLinkWindow))
+	if err != nil {
+		return fmt.Errorf("failed to check rate limit: %w", err)
+	}
+	if sent >= magicLinkLimit {
+		return fmt.Errorf("too many sign-in link requests. Please try again later")
+	}
+
+	id := uuid.New()
+	expiresAt := time.Now().Add(magicLinkTTL)
+	token, err := utils.GenerateMagicLinkToken(id, teamID, email, magicLinkTTL, s.jwtSecret)
+	if err != nil {
+		return fmt.Errorf("failed to create sign-in link: %w", err)
+	}
+	if err := s.repo.Create(ctx, id, teamID, email, expiresAt, client.IP); err != nil {
+		return err
+	}
+
+	// The link opens the dashboard page, which exchanges the token with VerifyLink.
+	// Consuming it on a plain GET would let mail scanners burn the link before the leader clicks it.
+	link := fmt.Sprintf("%s/rsvp/%s?magic_token=%s", s.frontendURL, teamID, url.QueryEscape(token))
+
+	go func() {
+		if err := s.emailService.SendMagicLinkEmail(email, team.TeamName, link, "15 minutes"); err != nil {
+			log.Printf("[EMAIL ERROR] Failed to send magic link to %s: %v", email, err)
+		} else {
+			log.Printf("[EMAIL SUCCESS] Magic link sent to %s", email)
+		}
+	}()
+	return nil
+}
+
+// VerifyLink consumes a magic-link token and starts a team leader session
+func (s *MagicLinkService) VerifyLink(ctx context.Context, token string, client models.SessionClient) (*models.FirebaseAuthResponse, error) {
+	if !s.enabled {
+		return nil, fmt.Errorf("sign-in links are disabled")
+	}
+
+	claims, err := utils.ValidateMagicLinkToken(token, s.jwtSecret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid or expired sign-in link")
+	}
+	id, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid or expired sign-in link")
+	}
+
+	ok, err := s.repo.Consume(ctx, id, claims.TeamID, claims.Email)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("sign-in link has already been used or has expired")
+	}
+
+	team, err := s.teamRepo.GetByID(ctx, claims.TeamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team details: %w", err)
+	}
+	if team == nil {
+		return nil, fmt.Errorf("team not found")
+	}
+	// The leader may have changed since the link was sent
+	if err := matchLeaderEmail(ctx, s.teamRepo, claims.TeamID, claims.Email); err != nil {
+		return nil, err
+	}
+
+	teamID := claims.TeamID
+	tokens, err := s.sessions.Issue(ctx, models.SessionSubject{
+		SubjectID: teamID,
+		Role:      models.UserRoleParticipant,
+		Email:     claims.Email,
+		TeamID:    &teamID,
+	}, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue session: %w", err)
+	}
+
+	log.Printf("[AUTH] Magic link sign-in for %s (Team: %s)", claims.Email, team.TeamName)
+
+	return &models.FirebaseAuthResponse{
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		Team:         *team,
+		PhoneNumber:  claims.Email, // email, as in VerifyOTP
+	}, nil
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+)
+
+var roleKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// PermissionService manages admin roles and resolves the effective permissions of admin users.
+type PermissionService struct {
+	repo     *repository.AdminRoleRepository
+	userRepo *repository.UserRepository
+}
+
+func NewPermissionService(repo *repository.AdminRoleRepository, userRepo *repository.UserRepository) *PermissionService {
+	return &PermissionService{repo: repo, userRepo: userRepo}
+}
+
+// Catalog returns every assignable permission.
+func (s *PermissionService) Catalog() []models.PermissionInfo {
+	return models.Permissions
+}
+
+// EffectivePermissions returns the union of the permissions of a user's roles.
+// Sessions embed the result in access tokens, so changes apply on the next refresh.
+func (s *PermissionService) EffectivePermissions(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	return s.repo.GetUserPermissions(ctx, userID)
+}
+
+// ListRoles returns all roles.
+func (s *PermissionService) ListRoles(ctx context.Context) ([]models.AdminRole, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// CreateRole adds a custom role.
+func (s *PermissionService) CreateRole(ctx context.Context, req models.CreateAdminRoleRequest) (*models.AdminRole, error) {
+	key := strings.ToLower(strings.TrimSpace(req.Key))
+	if !roleKeyPattern.MatchString(key) {
+		return nil, fmt.Errorf("role key must be lower-case letters, digits or underscores")
+	}
+	perms, err := validatePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.GetByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("role %s already exists", key)
+	}
+	role := &models.AdminRole{
+		Key:         key,
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		Permissions: perms,
+	}
+	if err := s.repo.Create(ctx, role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// UpdateRole changes a role's name, description or permissions. Built-in roles can be edited
+// but not deleted.
+func (s *PermissionService) UpdateRole(ctx context.Context, key string, req models.UpdateAdminRoleRequest) (*models.AdminRole, error) {
+	role, err := s.repo.GetByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, fmt.Errorf("role not found")
+	}
+	if req.Name != nil {
+		role.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		role.Description = req.Description
+	}
+	if req.Permissions != nil {
+		perms, err := validatePermissions(*req.Permissions)
+		if err != nil {
+			return nil, err
+		}
+		role.Permissions = perms
+	}
+	if err := s.repo.Update(ctx, role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// DeleteRole removes a custom role and unassigns it from everyone.
+func (s *PermissionService) DeleteRole(ctx context.Context, key string) error {
+	return s.repo.Delete(ctx, key)
+}
+
+// ListAdminUsers returns admin users with their roles and effective permissions.
+func (s *PermissionService) ListAdminUsers(ctx context.Context) ([]models.AdminUserRoles, error) {
+	return s.repo.ListAdminUsers(ctx)
+}
+
+// SetUserRoles replaces the roles of an admin user. It refuses changes that would leave nobody
+// able to manage roles.
+func (s *PermissionService) SetUserRoles(ctx context.Context, userID uuid.UUID, roleKeys []string, grantedBy *uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.Role != models.UserRoleAdmin {
+		return fmt.Errorf("admin user not found")
+	}
+	keys := make([]string, 0, len(roleKeys))
+	seen := make(map[string]bool)
+	for _, k := range roleKeys {
+		k = strings.ToLower(strings.TrimSpace(k))
+		if k == "" || seen[k] {
+			continue
+		}
+		role, err := s.repo.GetByKey(ctx, k)
+		if err != nil {
+			return err
+		}
+		if role == nil {
+			return fmt.Errorf("unknown role: %s", k)
+		}
+		seen[k] = true
+		keys = append(keys, k)
+	}
+	return s.repo.SetUserRoles(ctx, userID, keys, grantedBy)
+}
+
+// validatePermissions rejects unknown permissions and drops duplicates.
+func validatePermissions(perms []models.Permission) ([]models.Permission, error) {
+	out := make([]models.Permission, 0, len(perms))
+	seen := make(map[models.Permission]bool)
+	for _, p := range perms {
+		p = models.Permission(strings.TrimSpace(string(p)))
+		if !models.IsValidPermission(p) {
+			return nil, fmt.Errorf("unknown permission: %s", p)
+		}
+		if !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+)
+
+// PhaseService resolves the event phase schedule stored in event_phases.
+// Resolution order for a city: city override > default override > city schedule > default schedule > closed.
+type PhaseService struct {
+	repo *repository.EventPhaseRepository
+}
+
+func NewPhaseService(repo *repository.EventPhaseRepository) *PhaseService {
+	return &PhaseService{repo: repo}
+}
+
+// normalizePhaseCity upper-cases a city code; "" means the default row.
+func normalizePhaseCity(city string) string {
+	return strings.ToUpper(strings.TrimSpace(city))
+}
+
+// Status returns the effective state of a phase for a city (city may be "" for the default) right now.
+func (s *PhaseService) Status(ctx context.Context, phase models.EventPhaseKey, city string) (*models.PhaseStatus, error) {
+	return s.StatusAt(ctx, phase, city, time.Now())
+}
+
+// StatusAt returns the effective state of a phase for a city at the given time.
+func (s *PhaseService) StatusAt(ctx context.Context, phase models.EventPhaseKey, city string, at time.Time) (*models.PhaseStatus, error) {
+	return s.statusAt(ctx, uuid.Nil, phase, city, at)
+}
+
+// EventStatus returns the state of a phase for a city right now in an event (uuid.Nil = current event; admin).
+func (s *PhaseService) EventStatus(ctx context.Context, eventID uuid.UUID, phase models.EventPhaseKey, city string) (*models.PhaseStatus, error) {
+	return s.statusAt(ctx, eventID, phase, city, time.Now())
+}
+
+func (s *PhaseService) statusAt(ctx context.Context, eventID uuid.UUID, phase models.EventPhaseKey, city string, at time.Time) (*models.PhaseStatus, error) {
+	city = normalizePhaseCity(city)
+	def, cityRow, err := s.repo.GetForCity(ctx, eventID, phase, city)
+	if err != nil {
+		return nil, err
+	}
+	if city == "" {
+		cityRow = nil
+	}
+	return resolvePhase(phase, city, def, cityRow, at), nil
+}
+
+func resolvePhase(phase models.EventPhaseKey, city string, def, cityRow *models.EventPhase, now time.Time) *models.PhaseStatus {
+	st := &models.PhaseStatus{Phase: phase, City: city, Mode: models.PhaseModeClosed, Source: "default"}
+
+	sched := cityRow
+	if sched == nil {
+		sched = def
+	}
+	if sched != nil {
+		st.OpensAt = sched.OpensAt
+		st.ClosesAt = sched.ClosesAt
+	}
+
+	if cityRow != nil && cityRow.OverrideMode != nil {
+		st.Mode, st.Source = *cityRow.OverrideMode, "override"
+		return st
+	}
+	if def != nil && def.OverrideMode != nil {
+		st.Mode, st.Source = *def.OverrideMode, "override"
+		return st
+	}
+	if sched == nil {
+		return st
+	}
+	st.Source = "schedule"
+	if sched.OpensAt != nil && now.Before(*sched.OpensAt) {
+		return st
+	}
+	if sched.ClosesAt != nil && !now.Before(*sched.ClosesAt) {
+		return st
+	}
+	st.Mode = sched.Mode
+	return st
+}
+
+// Mode returns the effective mode of a phase for a city.
+func (s *PhaseService) Mode(ctx context.Context, phase models.EventPhaseKey, city string) (models.PhaseMode, error) {
+	st, err := s.Status(ctx, phase, city)
+	if err != nil {
+		return models.PhaseModeClosed, err
+	}
+	return st.Mode, nil
+}
+
+// IsOpen returns true if the phase is open or PIN-protected for the city.
+func (s *PhaseService) IsOpen(ctx context.Context, phase models.EventPhaseKey, city string) (bool, error) {
+	st, err := s.Status(ctx, phase, city)
+	if err != nil {
+		return false, err
+	}
+	return st.IsOpen(), nil
+}
+
+// Snapshot returns the effective status of every phase for a city (used by /api/v1/config).
+func (s *PhaseService) Snapshot(ctx context.Context, city string) (map[models.EventPhaseKey]*models.PhaseStatus, error) {
+	return s.EventSnapshot(ctx, uuid.Nil, city)
+}
+
+// EventSnapshot returns the effective status of every phase for a city in an event (uuid.Nil = current event).
+func (s *PhaseService) EventSnapshot(ctx context.Context, eventID uuid.UUID, city string) (map[models.EventPhaseKey]*models.PhaseStatus, error) {
+	out := make(map[models.EventPhaseKey]*models.PhaseStatus, len(models.AllEventPhases))
+	for _, p := range models.AllEventPhases {
+		st, err := s.EventStatus(ctx, eventID, p, city)
+		if err != nil {
+			return nil, err
+		}
+		out[p] = st
+	}
+	return out, nil
+}
+
+// ListSchedule returns all raw schedule rows of the event (admin).
+func (s *PhaseService) ListSchedule(ctx context.Context, eventID uuid.UUID) ([]models.EventPhase, error) {
+	return s.repo.GetAll(ctx, eventID)
+}
+
+// UpsertSchedule validates and saves the mode and window of a phase for a city ("" = default).
+func (s *PhaseService) UpsertSchedule(ctx context.Context, eventID uuid.UUID, phase string, req models.UpsertEventPhaseRequest) (*models.EventPhase, error) {
+	if !models.IsValidEventPhase(phase) {
+		return nil, fmt.Errorf("unknown phase: %s", phase)
+	}
+	mode := strings.ToLower(strings.TrimSpace(req.Mode))
+	if !models.IsValidPhaseMode(mode) {
+		return nil, fmt.Errorf("mode must be open, closed or pin")
+	}
+	if req.OpensAt != nil && req.ClosesAt != nil && !req.ClosesAt.After(*req.OpensAt) {
+		return nil, fmt.Errorf("closes_at must be after opens_at")
+	}
+	p := &models.EventPhase{
+		Phase:    models.EventPhaseKey(phase),
+		City:     normalizePhaseCity(req.City),
+		Mode:     models.PhaseMode(mode),
+		OpensAt:  req.OpensAt,
+		ClosesAt: req.ClosesAt,
+	}
+	if err := s.repo.Upsert(ctx, eventID, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// SetOverride forces a phase open/closed/pin for a city ("" = all cities) in the event; nil mode clears the override.
+func (s *PhaseService) SetOverride(ctx context.Context, eventID uuid.UUID, phase models.EventPhaseKey, city string, mode *string) error {
+	if !models.IsValidEventPhase(string(phase)) {
+		return fmt.Errorf("unknown phase: %s", phase)
+	}
+	var m *models.PhaseMode
+	if mode != nil {
+		v := strings.ToLower(strings.TrimSpace(*mode))
+		if !models.IsValidPhaseMode(v) {
+			return fmt.Errorf("mode must be open, closed or pin")
+		}
+		pm := models.PhaseMode(v)
+		m = &pm
+	}
+	return s.repo.SetOverride(ctx, eventID, phase, normalizePhaseCity(city), m)
+}
+
+// Toggle is the legacy admin on/off switch for a phase across all cities of the current event.
+// When the schedules alone already give the requested state for every city the default override
+// is cleared, so later per-city schedule changes keep applying; otherwise an open/closed override
+// is set. It returns the resolved default status; Source is "override" while the override is in force.
+func (s *PhaseService) Toggle(ctx context.Context, phase models.EventPhaseKey, open bool) (*models.PhaseStatus, error) {
+	rows, err := s.repo.GetAll(ctx, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+	var def *models.EventPhase
+	var cityRows []*models.EventPhase
+	for i := range rows {
+		switch {
+		case rows[i].Phase != phase:
+		case rows[i].City == "":
+			d := rows[i]
+			d.OverrideMode = nil
+			def = &d
+		default:
+			cityRows = append(cityRows, &rows[i])
+		}
+	}
+
+	now := time.Now()
+	agrees := resolvePhase(phase, "", def, nil, now).IsOpen() == open
+	for _, row := range cityRows {
+		if resolvePhase(phase, row.City, def, row, now).IsOpen() != open {
+			agrees = false
+		}
+	}
+
+	var mode *string
+	if !agrees {
+		m := string(models.PhaseModeClosed)
+		if open {
+			m = string(models.PhaseModeOpen)
+		}
+		mode = &m
+	}
+	if err := s.SetOverride(ctx, uuid.Nil, phase, "", mode); err != nil {
+		return nil, err
+	}
+	return s.statusAt(ctx, uuid.Nil, phase, "", now)
+}
+
+// DeleteCitySchedule removes a city-specific row so the city falls back to the default.
+func (s *PhaseService) DeleteCitySchedule(ctx context.Context, eventID uuid.UUID, phase string, city string) error {
+	if !models.IsValidEventPhase(phase) {
+		return fmt.Errorf("unknown phase: %s", phase)
+	}
+	city = normalizePhaseCity(city)
+	if city == "" {
+		return fmt.Errorf("the default schedule cannot be deleted")
+	}
+	return s.repo.Delete(ctx, eventID, models.EventPhaseKey(phase), city)
+}
+
+// SeedDefaults creates default rows for phases that have none yet (first boot after migrating off env flags).
+func (s *PhaseService) SeedDefaults(ctx context.Context, defaults map[models.EventPhaseKey]models.PhaseMode) error {
+	for phase, mode := range defaults {
+		if err := s.repo.InsertIfMissing(ctx, uuid.Nil, phase, "", mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
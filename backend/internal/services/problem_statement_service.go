@@ -3,7 +3,12 @@ package services
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
 	"path/filepath"
 	"strings"
 	"time"
@@ -11,151 +16,260 @@ import (
 	"github.com/google/uuid"
 	"github.com/rift26/backend/internal/models"
 	"github.com/rift26/backend/internal/repository"
+	"github.com/rift26/backend/pkg/storage"
 )
 
-// PS release: 19 Feb 2026 11:30 AM IST = 2026-02-19 06:00 UTC
-var defaultReleaseTime = time.Date(2026, 2, 19, 6, 0, 0, 0, time.UTC)
+// pdfKeyPrefix is the storage folder of uploaded problem statement PDFs
+const pdfKeyPrefix = "problem_statements/"
 
-const settingKeyPSReleasedAt = "ps_released_at"
-const settingKeyPSSubmissionOpen = "ps_submission_open"             // "true" or "false" (locking window)
-const settingKeyPSFinalSubmissionOpen = "ps_final_submission_open" // "true" or "false" (project submission portal)
+// MaxPDFBytes caps an uploaded problem statement PDF
+const MaxPDFBytes = 20 << 20
 
 type ProblemStatementService struct {
-	repo       *repository.ProblemStatementRepository
-	settingsRepo *repository.SettingsRepository
-	baseURL    string // e.g. https://api.example.com - for building download URLs
-	uploadDir  string
+	repo         *repository.ProblemStatementRepository
+	phaseService *PhaseService
+	cityService  *CityService
+	store        storage.Store // uploaded PDFs (file_path holds the storage key)
+	urlTTL       time.Duration // lifetime of signed PDF download URLs
 }
 
-func NewProblemStatementService(repo *repository.ProblemStatementRepository, settingsRepo *repository.SettingsRepository, baseURL, uploadDir string) *ProblemStatementService {
-	return &ProblemStatementService{repo: repo, settingsRepo: settingsRepo, baseURL: baseURL, uploadDir: uploadDir}
+func NewProblemStatementService(repo *repository.ProblemStatementRepository, phaseService *PhaseService, cityService *CityService, store storage.Store, urlTTL time.Duration) *ProblemStatementService {
+	return &ProblemStatementService{repo: repo, phaseService: phaseService, cityService: cityService, store: store, urlTTL: urlTTL}
 }
 
-// IsReleased returns true if problem statements should be visible (after 19 Feb 2026 11:30 AM IST or early release).
-func (s *ProblemStatementService) IsReleased(ctx context.Context) (bool, error) {
-	val, err := s.settingsRepo.Get(ctx, settingKeyPSReleasedAt)
-	if err != nil {
-		return false, err
-	}
-	if val != "" {
-		t, err := time.Parse(time.RFC3339, val)
-		if err == nil && time.Now().UTC().After(t) {
-			return true, nil
-		}
-	}
-	return time.Now().UTC().After(defaultReleaseTime), nil
+// IsReleased returns true if problem statements should be visible for the city ("" = default schedule).
+func (s *ProblemStatementService) IsReleased(ctx context.Context, city string) (bool, error) {
+	return s.phaseService.IsOpen(ctx, models.PhasePSRelease, city)
 }
 
-// ReleaseEarly sets ps_released_at to now (for testing).
-func (s *ProblemStatementService) ReleaseEarly(ctx context.Context) error {
-	return s.settingsRepo.Set(ctx, settingKeyPSReleasedAt, time.Now().UTC().Format(time.RFC3339))
+// ReleaseEarly opens the ps_release phase for all cities (admin), with an override unless the schedules are already open.
+func (s *ProblemStatementService) ReleaseEarly(ctx context.Context) (*models.PhaseStatus, error) {
+	return s.phaseService.Toggle(ctx, models.PhasePSRelease, true)
 }
 
-// ResetRelease clears the early-release flag so the countdown shows again (for testing).
+// ResetRelease clears the release override so the scheduled release time applies again.
 func (s *ProblemStatementService) ResetRelease(ctx context.Context) error {
-	return s.settingsRepo.Delete(ctx, settingKeyPSReleasedAt)
+	return s.phaseService.SetOverride(ctx, uuid.Nil, models.PhasePSRelease, "", nil)
+}
+
+// ReleaseStatus returns the resolved ps_release phase (mode and scheduled open time) for the city.
+func (s *ProblemStatementService) ReleaseStatus(ctx context.Context, city string) (*models.PhaseStatus, error) {
+	return s.phaseService.Status(ctx, models.PhasePSRelease, city)
 }
 
-// ListPublic returns problem statements for public only if released; otherwise nil, false.
-func (s *ProblemStatementService) ListPublic(ctx context.Context) ([]models.ProblemStatementPublic, bool, error) {
-	released, err := s.IsReleased(ctx)
+// ListPublic returns problem statements for public only if released for the city; otherwise nil, false.
+// Each entry carries the remaining slots for the city (the tighter of the overall and city cap).
+func (s *ProblemStatementService) ListPublic(ctx context.Context, city string) ([]models.ProblemStatementPublic, bool, error) {
+	released, err := s.IsReleased(ctx, city)
 	if err != nil || !released {
 		return nil, false, err
 	}
-	list, err := s.repo.GetAll(ctx)
+	list, err := s.repo.GetAll(ctx, uuid.Nil)
+	if err != nil {
+		return nil, false, err
+	}
+	counts, err := s.repo.LockCounts(ctx, uuid.Nil)
+	if err != nil {
+		return nil, false, err
+	}
+	cityCode := city
+	code, ok, err := s.cityService.Normalize(ctx, city)
 	if err != nil {
 		return nil, false, err
 	}
+	if ok {
+		cityCode = code
+	}
 	out := make([]models.ProblemStatementPublic, 0, len(list))
 	for _, ps := range list {
-		// Download URL: if FilePath is a full URL (e.g. Google Drive link), use as-is; else legacy local file
+		// Download URL: if FilePath is a full URL (e.g. Google Drive link), use as-is; else a signed storage URL
 		var downloadURL string
-		if strings.HasPrefix(ps.FilePath, "http://") || strings.HasPrefix(ps.FilePath, "https://") {
+		if isExternalLink(ps.FilePath) {
 			downloadURL = ps.FilePath
+		} else if signed, err := s.SignedPDFURL(ctx, filepath.Base(ps.FilePath)); err == nil {
+			downloadURL = signed
 		} else {
-			filename := filepath.Base(ps.FilePath)
-			downloadURL = s.baseURL + "/api/v1/uploads/problem-statements/" + filename
+			log.Printf("[ProblemStatements] Failed to sign PDF URL for %s: %v", ps.ID, err)
 		}
-		out = append(out, models.ProblemStatementPublic{
+		item := models.ProblemStatementPublic{
 			ID:          ps.ID.String(),
 			Track:       ps.Track,
 			Name:        ps.Name,
 			DownloadURL: downloadURL,
 			CreatedAt:   ps.CreatedAt.Format(time.RFC3339),
-		})
+		}
+		item.MaxTeams, item.RemainingSlots = availability(&ps, counts[ps.ID], cityCode)
+		item.Full = item.RemainingSlots != nil && *item.RemainingSlots == 0
+		out = append(out, item)
 	}
 	return out, true, nil
 }
 
-// ListAdmin returns all problem statements (admin only).
-func (s *ProblemStatementService) ListAdmin(ctx context.Context) ([]models.PSItem, error) {
-	return s.repo.GetAll(ctx)
+// availability returns the cap that binds for a city and the slots left under it (nil, nil when unlimited).
+func availability(ps *models.PSItem, count *models.PSLockCount, city string) (*int, *int) {
+	if count == nil {
+		count = &models.PSLockCount{}
+	}
+	var max, remaining *int
+	consider := func(limit *int, used int) {
+		if limit == nil {
+			return
+		}
+		left := *limit - used
+		if left < 0 {
+			left = 0
+		}
+		if remaining == nil || left < *remaining {
+			max, remaining = limit, &left
+		}
+	}
+	consider(ps.MaxTeams, count.Total)
+	if city != "" {
+		consider(ps.CityLimit(city), count.ByCity[city])
+	}
+	return max, remaining
+}
+
+// LockCounts returns how many teams have locked each problem statement of the event.
+func (s *ProblemStatementService) LockCounts(ctx context.Context, eventID uuid.UUID) (map[uuid.UUID]*models.PSLockCount, error) {
+	return s.repo.LockCounts(ctx, eventID)
 }
 
-// Create saves a new problem statement (track, name, file path after upload).
-// submissionFieldsJSON is an optional JSON object describing which final submission fields are expected.
-func (s *ProblemStatementService) Create(ctx context.Context, track, name, filePath, submissionFieldsJSON string) (*models.PSItem, error) {
-	// If no explicit config is provided, default to enabling all standard fields.
-	if strings.TrimSpace(submissionFieldsJSON) == "" {
-		submissionFieldsJSON = `{"linkedin":true,"github":true,"live":true,"extra_notes":true}`
+// SetCapacity replaces a problem statement's overall and per-city caps. City keys are normalized to codes.
+func (s *ProblemStatementService) SetCapacity(ctx context.Context, id uuid.UUID, req models.PSCapacityRequest) (bool, error) {
+	cityMax := make(map[string]int, len(req.CityMaxTeams))
+	for city, max := range req.CityMaxTeams {
+		code, ok, err := s.cityService.Normalize(ctx, city)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, fmt.Errorf("unknown city %q", city)
+		}
+		if max < 0 {
+			return false, fmt.Errorf("city_max_teams for %s must not be negative", code)
+		}
+		cityMax[code] = max
+	}
+	return s.repo.SetCapacity(ctx, id, req.MaxTeams, cityMax)
+}
+
+// SetSubmissionFields replaces a problem statement's submission field schema after checking it.
+// Submissions already saved are kept as they are. Returns false if the problem statement doesn't exist.
+func (s *ProblemStatementService) SetSubmissionFields(ctx context.Context, id uuid.UUID, fields PSSubmissionFieldsConfig) (bool, error) {
+	if err := fields.Normalize(); err != nil {
+		return false, err
+	}
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return false, fmt.Errorf("marshal submission fields: %w", err)
+	}
+	return s.repo.SetSubmissionFields(ctx, id, string(raw))
+}
+
+// ListAdmin returns all problem statements of the event (admin only).
+func (s *ProblemStatementService) ListAdmin(ctx context.Context, eventID uuid.UUID) ([]models.PSItem, error) {
+	return s.repo.GetAll(ctx, eventID)
+}
+
+// Create saves a new problem statement (track, name, link or UploadPDF key). An uploaded PDF is
+// removed again if the problem statement can't be saved.
+// fields is its final submission field schema (see ParseSubmissionFields).
+// maxTeams optionally caps how many teams can lock it overall.
+func (s *ProblemStatementService) Create(ctx context.Context, eventID uuid.UUID, track, name, filePath string, fields *PSSubmissionFieldsConfig, maxTeams *int) (*models.PSItem, error) {
+	submissionFieldsJSON, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("marshal submission fields: %w", err)
 	}
 	ps := &models.PSItem{
 		Track:    track,
 		Name:     name,
 		FilePath: filePath,
 		SubmissionFields: sql.NullString{
-			String: submissionFieldsJSON,
-			Valid:  submissionFieldsJSON != "",
+			String: string(submissionFieldsJSON),
+			Valid:  true,
 		},
+		MaxTeams: maxTeams,
 	}
-	if err := s.repo.Create(ctx, ps); err != nil {
+	if err := s.repo.Create(ctx, eventID, ps); err != nil {
+		if filePath != "" && !isExternalLink(filePath) {
+			s.store.Delete(ctx, pdfKeyPrefix+filepath.Base(filePath))
+		}
 		return nil, fmt.Errorf("create problem statement: %w", err)
 	}
 	return ps, nil
 }
 
-// Delete removes a problem statement by ID.
+// Delete removes a problem statement by ID, and its uploaded PDF if it has one.
 func (s *ProblemStatementService) Delete(ctx context.Context, id uuid.UUID) error {
-	return s.repo.Delete(ctx, id)
-}
-
-// UploadDir returns the directory for storing PDFs.
-func (s *ProblemStatementService) UploadDir() string {
-	return s.uploadDir
+	ps, err := s.repo.GetByID(ctx, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if ps != nil && ps.FilePath != "" && !isExternalLink(ps.FilePath) {
+		if err := s.store.Delete(ctx, pdfKeyPrefix+filepath.Base(ps.FilePath)); err != nil {
+			log.Printf("[ProblemStatements] Failed to delete PDF of %s: %v", id, err)
+		}
+	}
+	return nil
 }
 
-// IsSubmissionOpen returns true if PS submission window is unlocked by admin.
-func (s *ProblemStatementService) IsSubmissionOpen(ctx context.Context) (bool, error) {
-	val, err := s.settingsRepo.Get(ctx, settingKeyPSSubmissionOpen)
+// UploadPDF checks that the file is a PDF within MaxPDFBytes and stores it; the returned key
+// is what Create takes as the file path.
+func (s *ProblemStatementService) UploadPDF(ctx context.Context, file *multipart.FileHeader) (string, error) {
+	if file.Size > MaxPDFBytes {
+		return "", fmt.Errorf("PDF must be at most %s", formatBytes(MaxPDFBytes))
+	}
+	src, err := file.Open()
 	if err != nil {
-		return false, err
+		return "", fmt.Errorf("open upload: %w", err)
+	}
+	defer src.Close()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(src, head)
+	if sniffContentType(head[:n], file.Filename) != "application/pdf" {
+		return "", fmt.Errorf("file must be a PDF")
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("read upload: %w", err)
+	}
+	key := pdfKeyPrefix + uuid.New().String() + ".pdf"
+	if err := s.store.Put(ctx, key, src, file.Size, "application/pdf"); err != nil {
+		return "", fmt.Errorf("store PDF: %w", err)
 	}
-	return val == "true", nil
+	return key, nil
 }
 
-// SetSubmissionOpen sets PS submission window open/closed (admin).
-func (s *ProblemStatementService) SetSubmissionOpen(ctx context.Context, open bool) error {
-	val := "false"
-	if open {
-		val = "true"
-	}
-	return s.settingsRepo.Set(ctx, settingKeyPSSubmissionOpen, val)
+// SignedPDFURL returns a signed, expiring download URL for an uploaded PDF by file name.
+func (s *ProblemStatementService) SignedPDFURL(ctx context.Context, filename string) (string, error) {
+	return s.store.SignedURL(ctx, pdfKeyPrefix+filename, s.urlTTL, storage.Download{Filename: filename, ContentType: "application/pdf"})
 }
 
-// IsFinalSubmissionOpen returns true if the final submission portal is open.
-func (s *ProblemStatementService) IsFinalSubmissionOpen(ctx context.Context) (bool, error) {
-	val, err := s.settingsRepo.Get(ctx, settingKeyPSFinalSubmissionOpen)
-	if err != nil {
-		return false, err
-	}
-	return val == "true", nil
+// isExternalLink reports whether a problem statement's file path is a link (e.g. Google Drive)
+// rather than an uploaded PDF.
+func isExternalLink(filePath string) bool {
+	return strings.HasPrefix(filePath, "http://") || strings.HasPrefix(filePath, "https://")
 }
 
-// SetFinalSubmissionOpen opens/closes the final submission portal.
-func (s *ProblemStatementService) SetFinalSubmissionOpen(ctx context.Context, open bool) error {
-	val := "false"
-	if open {
-		val = "true"
-	}
-	return s.settingsRepo.Set(ctx, settingKeyPSFinalSubmissionOpen, val)
+// IsSubmissionOpen returns true if the PS lock window is open for the city.
+func (s *ProblemStatementService) IsSubmissionOpen(ctx context.Context, city string) (bool, error) {
+	return s.phaseService.IsOpen(ctx, models.PhasePSLock, city)
+}
+
+// SetSubmissionOpen opens/closes the PS lock window for all cities (admin), see PhaseService.Toggle.
+func (s *ProblemStatementService) SetSubmissionOpen(ctx context.Context, open bool) (*models.PhaseStatus, error) {
+	return s.phaseService.Toggle(ctx, models.PhasePSLock, open)
+}
+
+// IsFinalSubmissionOpen returns true if the final submission portal is open for the city.
+func (s *ProblemStatementService) IsFinalSubmissionOpen(ctx context.Context, city string) (bool, error) {
+	return s.phaseService.IsOpen(ctx, models.PhaseFinalSubmission, city)
+}
+
+// SetFinalSubmissionOpen opens/closes the final submission portal for all cities (admin), see PhaseService.Toggle.
+func (s *ProblemStatementService) SetFinalSubmissionOpen(ctx context.Context, open bool) (*models.PhaseStatus, error) {
+	return s.phaseService.Toggle(ctx, models.PhaseFinalSubmission, open)
 }
@@ -2,27 +2,70 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rift26/backend/internal/models"
 	"github.com/rift26/backend/internal/repository"
 )
 
+// PSSwitchPolicy controls how a team may change its locked problem statement: up to MaxSwitches
+// times within Grace of its first lock (while the PS lock window is open). Zero disables switching.
+type PSSwitchPolicy struct {
+	Grace       time.Duration
+	MaxSwitches int
+}
+
 type PSSelectionService struct {
 	repo       *repository.PSSelectionRepository
 	teamRepo   *repository.TeamRepository
 	psRepo     *repository.ProblemStatementRepository
-	settingsRepo *repository.SettingsRepository
+	phaseService *PhaseService
+	switchPolicy PSSwitchPolicy
+}
+
+func NewPSSelectionService(repo *repository.PSSelectionRepository, teamRepo *repository.TeamRepository, psRepo *repository.ProblemStatementRepository, phaseService *PhaseService, switchPolicy PSSwitchPolicy) *PSSelectionService {
+	return &PSSelectionService{repo: repo, teamRepo: teamRepo, psRepo: psRepo, phaseService: phaseService, switchPolicy: switchPolicy}
+}
+
+// allowSwitch enforces the switch policy on a team's change away from its current selection
+func (s *PSSelectionService) allowSwitch(current *models.PSSelection, lockedFor time.Duration) error {
+	if current == nil {
+		return nil
+	}
+	if s.switchPolicy.MaxSwitches <= 0 || s.switchPolicy.Grace <= 0 {
+		return fmt.Errorf("your problem statement is already locked and cannot be changed. Raise a ticket if you need help")
+	}
+	if lockedFor > s.switchPolicy.Grace {
+		return fmt.Errorf("problem statements can only be changed within %d minutes of locking. Raise a ticket if you need help", int(s.switchPolicy.Grace.Minutes()))
+	}
+	if current.SwitchCount >= s.switchPolicy.MaxSwitches {
+		return fmt.Errorf("you have already changed your problem statement %d time(s), the maximum allowed", current.SwitchCount)
+	}
+	return nil
 }
 
-func NewPSSelectionService(repo *repository.PSSelectionRepository, teamRepo *repository.TeamRepository, psRepo *repository.ProblemStatementRepository, settingsRepo *repository.SettingsRepository) *PSSelectionService {
-	return &PSSelectionService{repo: repo, teamRepo: teamRepo, psRepo: psRepo, settingsRepo: settingsRepo}
+// SwitchStatus reports whether the team can still change its selection, for the dashboard.
+func (s *PSSelectionService) SwitchStatus(sel *models.PSSelection) models.PSSwitchStatus {
+	left := s.switchPolicy.MaxSwitches - sel.SwitchCount
+	if left < 0 || s.switchPolicy.Grace <= 0 {
+		left = 0
+	}
+	deadline := sel.CreatedAt.Add(s.switchPolicy.Grace)
+	status := models.PSSwitchStatus{SwitchesLeft: left}
+	if left > 0 {
+		status.SwitchDeadline = &deadline
+		status.CanSwitch = time.Now().Before(deadline)
+	}
+	return status
 }
 
-// LockPS locks a problem statement for a team (requires checked_in, submission window open, leader email match).
-func (s *PSSelectionService) LockPS(ctx context.Context, teamID, psID uuid.UUID, leaderEmail string) error {
+// LockPS locks or switches the team's problem statement (requires checked_in, a PS of the team's event
+// and the PS lock window open; switching follows the switch policy). actor is the leader email or session that asked.
+// The caller must have authorized the team session.
+func (s *PSSelectionService) LockPS(ctx context.Context, teamID, psID uuid.UUID, actor string) error {
 	team, err := s.teamRepo.GetByID(ctx, teamID)
 	if err != nil {
 		return fmt.Errorf("get team: %w", err)
@@ -34,27 +77,28 @@ func (s *PSSelectionService) LockPS(ctx context.Context, teamID, psID uuid.UUID,
 	if team.Status != models.StatusCheckedIn {
 		return fmt.Errorf("team must be checked_in to lock a problem statement")
 	}
-	// Check submission window
-	val, err := s.settingsRepo.Get(ctx, "ps_submission_open")
+	// Check PS lock window for the team's city
+	var city string
+	if team.City != nil {
+		city = string(*team.City)
+	}
+	open, err := s.phaseService.IsOpen(ctx, models.PhasePSLock, city)
 	if err != nil {
 		return fmt.Errorf("check submission window: %w", err)
 	}
-	if val != "true" {
-		return fmt.Errorf("PS submission window is locked by admin")
+	if !open {
+		return fmt.Errorf("PS submission window is closed")
 	}
-	// Verify leader email matches
-	var leaderFound bool
+	// The selection records the team leader's email
+	var leaderEmail string
 	for _, m := range team.Members {
-		if m.Role == models.RoleLeader && strings.EqualFold(m.Email, leaderEmail) {
-			leaderFound = true
+		if m.Role == models.RoleLeader {
+			leaderEmail = m.Email
 			break
 		}
 	}
-	if !leaderFound {
-		return fmt.Errorf("leader email does not match team leader")
-	}
 	// Verify PS exists
-	_, err = s.psRepo.GetByID(ctx, psID)
+	ps, err := s.psRepo.GetByID(ctx, psID)
 	if err != nil {
 		return fmt.Errorf("problem statement not found: %w", err)
 	}
@@ -63,7 +107,53 @@ func (s *PSSelectionService) LockPS(ctx context.Context, teamID, psID uuid.UUID,
 		ProblemStatementID: psID,
 		LeaderEmail:       leaderEmail,
 	}
-	return s.repo.Create(ctx, sel)
+	// Enforce the PS's overall and city caps and the switch policy
+	err = s.repo.LockWithinCapacity(ctx, sel, repository.PSLockChange{Actor: actor, Allow: s.allowSwitch})
+	if errors.Is(err, repository.ErrPSFull) {
+		return fmt.Errorf("%w: %s (%s) has no slots left, please choose another problem statement", err, ps.Name, ps.Track)
+	}
+	return err
+}
+
+// Reassign sets a team's problem statement as an admin override: no phase window or switch limit,
+// and with force also no capacity cap. The change is recorded with the admin and reason.
+func (s *PSSelectionService) Reassign(ctx context.Context, teamID, psID uuid.UUID, adminEmail, reason string, force bool) error {
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		return fmt.Errorf("get team: %w", err)
+	}
+	if team == nil {
+		return fmt.Errorf("team not found")
+	}
+	ps, err := s.psRepo.GetByID(ctx, psID)
+	if err != nil {
+		return fmt.Errorf("problem statement not found: %w", err)
+	}
+	sel := &models.PSSelection{TeamID: teamID, ProblemStatementID: psID}
+	for _, m := range team.Members {
+		if m.Role == models.RoleLeader {
+			sel.LeaderEmail = m.Email
+			break
+		}
+	}
+	err = s.repo.LockWithinCapacity(ctx, sel, repository.PSLockChange{
+		Actor:          adminEmail,
+		AdminReassign:  true,
+		Reason:         reason,
+		IgnoreCapacity: force,
+	})
+	if errors.Is(err, repository.ErrPSFull) {
+		return fmt.Errorf("%w: %s (%s) has no slots left; use force to exceed the cap", err, ps.Name, ps.Track)
+	}
+	return err
+}
+
+// History returns problem statement changes of the event, optionally for one team (uuid.Nil = all).
+func (s *PSSelectionService) History(ctx context.Context, eventID, teamID uuid.UUID, limit int) ([]models.PSSelectionHistory, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 200
+	}
+	return s.repo.ListHistory(ctx, eventID, teamID, limit)
 }
 
 // GetByTeamID returns the team's locked PS selection.
@@ -73,12 +163,12 @@ func (s *PSSelectionService) GetByTeamID(ctx context.Context, teamID uuid.UUID)
 
 // GetAllWithDetails returns all PS selections with team and PS details (for /checkps).
 func (s *PSSelectionService) GetAllWithDetails(ctx context.Context, city *string) ([]models.PSSelectionWithDetails, error) {
-	return s.repo.GetAllWithDetails(ctx, city)
+	return s.repo.GetAllWithDetails(ctx, uuid.Nil, city)
 }
 
-// GetSemiFinalistsWithDetails returns only semi-finalist selections.
-func (s *PSSelectionService) GetSemiFinalistsWithDetails(ctx context.Context, city *string) ([]models.PSSelectionWithDetails, error) {
-	return s.repo.GetSemiFinalistsWithDetails(ctx, city)
+// GetSemiFinalistsWithDetails returns only semi-finalist selections of the event.
+func (s *PSSelectionService) GetSemiFinalistsWithDetails(ctx context.Context, eventID uuid.UUID, city *string) ([]models.PSSelectionWithDetails, error) {
+	return s.repo.GetSemiFinalistsWithDetails(ctx, eventID, city)
 }
 
 // SetSemiFinalist marks or unmarks a team's selection as semi-finalist.
@@ -4,22 +4,31 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"strings"
+	"mime/multipart"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rift26/backend/internal/models"
 	"github.com/rift26/backend/internal/repository"
 )
 
-const settingKeyPSSubmissionPortalOpen = "ps_final_submission_open" // Same key as ProblemStatementService uses
-
 type PSSubmissionService struct {
-	subRepo        *repository.PSSubmissionRepository
-	selectionRepo  *repository.PSSelectionRepository
-	teamRepo       *repository.TeamRepository
-	psRepo         *repository.ProblemStatementRepository
-	settingsRepo   *repository.SettingsRepository
+	subRepo       *repository.PSSubmissionRepository
+	selectionRepo *repository.PSSelectionRepository
+	teamRepo      *repository.TeamRepository
+	psRepo        *repository.ProblemStatementRepository
+	phaseService  *PhaseService
+	latePolicy    LateSubmissionPolicy
+	uploads       SubmissionUploadConfig
+}
+
+// LateSubmissionPolicy decides what happens to submissions after the portal's scheduled close
+// (the final_submission phase's closes_at).
+type LateSubmissionPolicy struct {
+	Accept bool          // store late submissions flagged as late instead of rejecting them
+	Window time.Duration // how long after the close late submissions are accepted (0 = until an admin closes the portal)
 }
 
 func NewPSSubmissionService(
@@ -27,77 +36,109 @@ func NewPSSubmissionService(
 	selectionRepo *repository.PSSelectionRepository,
 	teamRepo *repository.TeamRepository,
 	psRepo *repository.ProblemStatementRepository,
-	settingsRepo *repository.SettingsRepository,
+	phaseService *PhaseService,
+	latePolicy LateSubmissionPolicy,
+	uploads SubmissionUploadConfig,
 ) *PSSubmissionService {
 	return &PSSubmissionService{
 		subRepo:       subRepo,
 		selectionRepo: selectionRepo,
 		teamRepo:      teamRepo,
 		psRepo:        psRepo,
-		settingsRepo:  settingsRepo,
+		phaseService:  phaseService,
+		latePolicy:    latePolicy,
+		uploads:       uploads,
 	}
 }
 
-func (s *PSSubmissionService) IsPortalOpen(ctx context.Context) (bool, error) {
-	val, err := s.settingsRepo.Get(ctx, settingKeyPSSubmissionPortalOpen)
-	if err != nil {
-		return false, err
-	}
-	return val == "true", nil
+// IsPortalOpen returns true if the final_submission phase is open for the city.
+func (s *PSSubmissionService) IsPortalOpen(ctx context.Context, city string) (bool, error) {
+	return s.phaseService.IsOpen(ctx, models.PhaseFinalSubmission, city)
 }
 
-func (s *PSSubmissionService) SetPortalOpen(ctx context.Context, open bool) error {
-	val := "false"
-	if open {
-		val = "true"
-	}
-	return s.settingsRepo.Set(ctx, settingKeyPSSubmissionPortalOpen, val)
+// submissionWindow is whether a team can submit right now and whether the submission is late.
+type submissionWindow struct {
+	Open     bool
+	Late     bool
+	Deadline *time.Time // the portal's scheduled close, nil when unscheduled
 }
 
-type PSSubmissionForm struct {
-	Allowed           bool                     `json:"allowed"`
-	PortalOpen        bool                     `json:"portal_open"`
-	Submitted         bool                     `json:"submitted"` // true when team has already saved a submission (read-only form)
-	ProblemName       string                   `json:"problem_name,omitempty"`
-	LinkedinURL       string                   `json:"linkedin_url,omitempty"`
-	GithubURL         string                   `json:"github_url,omitempty"`
-	LiveURL           string                   `json:"live_url,omitempty"`
-	ExtraNotes        string                   `json:"extra_notes,omitempty"`
-	CustomFieldValues map[string]string        `json:"custom_field_values,omitempty"`
-	Fields            PSSubmissionFieldsConfig `json:"fields"`
+// window resolves the submission window for a city. Submissions after the scheduled close are late:
+// they are accepted while an admin keeps the portal open, or, with a late policy, for its window after
+// a scheduled close. A portal closed by an admin override takes nothing.
+func (s *PSSubmissionService) window(ctx context.Context, city string) (*submissionWindow, error) {
+	st, err := s.phaseService.Status(ctx, models.PhaseFinalSubmission, city)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	w := &submissionWindow{Deadline: st.ClosesAt}
+	pastDeadline := st.ClosesAt != nil && !now.Before(*st.ClosesAt)
+	if st.IsOpen() {
+		w.Open, w.Late = true, pastDeadline
+		return w, nil
+	}
+	if !s.latePolicy.Accept || st.Source != "schedule" || !pastDeadline {
+		return w, nil
+	}
+	if s.latePolicy.Window > 0 && !now.Before(st.ClosesAt.Add(s.latePolicy.Window)) {
+		return w, nil
+	}
+	// Only a portal that was open until its scheduled close keeps taking (late) submissions
+	before, err := s.phaseService.StatusAt(ctx, models.PhaseFinalSubmission, city, st.ClosesAt.Add(-time.Second))
+	if err != nil {
+		return nil, err
+	}
+	w.Open, w.Late = before.IsOpen(), before.IsOpen()
+	return w, nil
 }
 
-type PSSubmissionFieldsConfig struct {
-	Linkedin     bool                     `json:"linkedin"`
-	Github       bool                     `json:"github"`
-	Live         bool                     `json:"live"`
-	ExtraNotes   bool                     `json:"extra_notes"`
-	CustomFields []CustomFieldDefinition  `json:"custom_fields,omitempty"`
+// teamCity returns the team's city code, or "" when unset.
+func teamCity(team *models.Team) string {
+	if team == nil || team.City == nil {
+		return ""
+	}
+	return string(*team.City)
 }
 
-type CustomFieldDefinition struct {
-	Key   string `json:"key"`   // e.g., "custom_1", "custom_2"
-	Label string `json:"label"` // e.g., "Demo Video", "Documentation"
+type PSSubmissionForm struct {
+	Allowed           bool                          `json:"allowed"`
+	PortalOpen        bool                          `json:"portal_open"`
+	Submitted         bool                          `json:"submitted"` // true when team has already saved a submission (read-only form)
+	Late              bool                          `json:"late"`      // the scheduled close has passed; saving now is flagged late
+	Deadline          *time.Time                    `json:"deadline,omitempty"`
+	Version           int                           `json:"version,omitempty"` // latest saved revision
+	SubmittedLate     bool                          `json:"submitted_late,omitempty"`
+	SubmittedAt       *time.Time                    `json:"submitted_at,omitempty"`
+	ProblemName       string                        `json:"problem_name,omitempty"`
+	LinkedinURL       string                        `json:"linkedin_url,omitempty"`
+	GithubURL         string                        `json:"github_url,omitempty"`
+	LiveURL           string                        `json:"live_url,omitempty"`
+	ExtraNotes        string                        `json:"extra_notes,omitempty"`
+	CustomFieldValues map[string]string             `json:"custom_field_values,omitempty"`
+	Attachments       []models.SubmissionAttachment `json:"attachments,omitempty"` // files named by the file fields
+	Fields            PSSubmissionFieldsConfig      `json:"fields"`
 }
 
 // GetTeamForm returns submission form data for a team if portal is open and PS is locked.
 func (s *PSSubmissionService) GetTeamForm(ctx context.Context, teamID uuid.UUID) (*PSSubmissionForm, error) {
 	form := &PSSubmissionForm{}
 
-	open, err := s.IsPortalOpen(ctx)
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("get team: %w", err)
+	}
+
+	w, err := s.window(ctx, teamCity(team))
 	if err != nil {
 		return nil, fmt.Errorf("check portal status: %w", err)
 	}
-	form.PortalOpen = open
-	if !open {
+	form.PortalOpen, form.Late, form.Deadline = w.Open, w.Late, w.Deadline
+	if !w.Open {
 		// Portal closed; nothing more to show
 		return form, nil
 	}
 
-	team, err := s.teamRepo.GetByID(ctx, teamID)
-	if err != nil {
-		return nil, fmt.Errorf("get team: %w", err)
-	}
 	if team == nil || team.Status != models.StatusCheckedIn {
 		return form, nil
 	}
@@ -116,25 +157,16 @@ func (s *PSSubmissionService) GetTeamForm(ctx context.Context, teamID uuid.UUID)
 	form.Allowed = true
 	form.ProblemName = ps.Name
 
-	// Use submission criteria for this PS only. Omitted booleans = false so only configured fields show.
-	var cfg PSSubmissionFieldsConfig
-	if ps.SubmissionFields.Valid && ps.SubmissionFields.String != "" {
-		_ = json.Unmarshal([]byte(ps.SubmissionFields.String), &cfg)
-	} else {
-		// No config stored: default all standard fields on for backward compatibility
-		cfg = PSSubmissionFieldsConfig{
-			Linkedin:   true,
-			Github:     true,
-			Live:       true,
-			ExtraNotes: true,
-		}
-	}
-	form.Fields = cfg
+	// Use the submission field schema of this PS only
+	form.Fields = submissionFieldsOf(ps)
 
 	// Existing submission if any
 	existing, err := s.subRepo.GetByTeamAndPS(ctx, teamID, sel.ProblemStatementID)
 	if err == nil && existing != nil {
 		form.Submitted = true
+		form.Version = existing.Version
+		form.SubmittedLate = existing.IsLate
+		form.SubmittedAt = &existing.SubmittedAt
 		form.LinkedinURL = existing.LinkedinURL
 		form.GithubURL = existing.GithubURL
 		form.LiveURL = existing.LiveURL
@@ -146,54 +178,106 @@ func (s *PSSubmissionService) GetTeamForm(ctx context.Context, teamID uuid.UUID)
 				form.CustomFieldValues = customFields
 			}
 		}
+		form.Attachments, err = s.teamAttachments(ctx, teamID, form.Fields, form.CustomFieldValues)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return form, nil
 }
 
-// Submit saves the submission for a team; requires checked_in and locked PS.
-func (s *PSSubmissionService) Submit(
-	ctx context.Context,
-	teamID uuid.UUID,
-	linkedinURL, githubURL, liveURL, extraNotes string,
-	customFieldValues map[string]string,
-) error {
-	open, err := s.IsPortalOpen(ctx)
+// SubmissionInput is what a team sends with a submission
+type SubmissionInput struct {
+	LinkedinURL       string
+	GithubURL         string
+	LiveURL           string
+	ExtraNotes        string
+	CustomFieldValues map[string]string
+	Files             map[string]*multipart.FileHeader // uploads for file fields, by field key
+}
+
+// Submit saves a new revision of the team's submission; requires checked_in and locked PS.
+// Values are checked against the PS field schema and uploads against their file field (size and
+// sniffed content type); invalid fields come back together as a *SubmissionValidationError.
+// A file field keeps an earlier upload when its value is that attachment's ID. Earlier revisions are
+// kept. Revisions after the scheduled close are flagged late or rejected depending on the late
+// submission policy. submittedBy records who saved it.
+func (s *PSSubmissionService) Submit(ctx context.Context, teamID uuid.UUID, in SubmissionInput, submittedBy string) (*models.PSSubmission, error) {
+	team, err := s.teamRepo.GetByID(ctx, teamID)
 	if err != nil {
-		return fmt.Errorf("check portal status: %w", err)
-	}
-	if !open {
-		return fmt.Errorf("submission portal is closed")
+		return nil, fmt.Errorf("get team: %w", err)
 	}
 
-	team, err := s.teamRepo.GetByID(ctx, teamID)
+	w, err := s.window(ctx, teamCity(team))
 	if err != nil {
-		return fmt.Errorf("get team: %w", err)
+		return nil, fmt.Errorf("check portal status: %w", err)
+	}
+	if !w.Open {
+		if w.Deadline != nil && !time.Now().Before(*w.Deadline) {
+			return nil, fmt.Errorf("the submission deadline has passed")
+		}
+		return nil, fmt.Errorf("submission portal is closed")
 	}
 	if team == nil || team.Status != models.StatusCheckedIn {
-		return fmt.Errorf("team must be checked_in to submit")
+		return nil, fmt.Errorf("team must be checked_in to submit")
 	}
 
 	sel, err := s.selectionRepo.GetByTeamID(ctx, teamID)
 	if err != nil || sel == nil {
-		return fmt.Errorf("team has not locked a problem statement yet")
+		return nil, fmt.Errorf("team has not locked a problem statement yet")
+	}
+
+	ps, err := s.psRepo.GetByID(ctx, sel.ProblemStatementID)
+	if err != nil || ps == nil {
+		return nil, fmt.Errorf("problem statement not found")
+	}
+	cfg := submissionFieldsOf(ps)
+	custom := make(map[string]string, len(in.CustomFieldValues))
+	for k, v := range in.CustomFieldValues {
+		custom[k] = v
+	}
+	uploads, fieldErrs := s.checkUploads(cfg, in.Files, custom)
+	values, err := cfg.validate(submissionValues{
+		LinkedinURL: in.LinkedinURL,
+		GithubURL:   in.GithubURL,
+		LiveURL:     in.LiveURL,
+		ExtraNotes:  in.ExtraNotes,
+		Custom:      custom,
+	})
+	var invalid *SubmissionValidationError
+	if errors.As(err, &invalid) {
+		for k, v := range invalid.Fields {
+			if _, seen := fieldErrs[k]; !seen {
+				fieldErrs[k] = v
+			}
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	if err := s.checkAttachmentRefs(ctx, cfg, teamID, ps.ID, values.Custom, uploads, fieldErrs); err != nil {
+		return nil, err
+	}
+	if len(fieldErrs) > 0 {
+		return nil, &SubmissionValidationError{Fields: fieldErrs}
 	}
 
-	// Basic trimming; detailed URL validation can be added if needed
 	sub := &models.PSSubmission{
 		TeamID:             teamID,
 		ProblemStatementID: sel.ProblemStatementID,
-		LinkedinURL:        strings.TrimSpace(linkedinURL),
-		GithubURL:          strings.TrimSpace(githubURL),
-		LiveURL:            strings.TrimSpace(liveURL),
-		ExtraNotes:         strings.TrimSpace(extraNotes),
+		LinkedinURL:        values.LinkedinURL,
+		GithubURL:          values.GithubURL,
+		LiveURL:            values.LiveURL,
+		ExtraNotes:         values.ExtraNotes,
+		IsLate:             w.Late,
+		SubmittedBy:        optional(submittedBy),
 	}
 
 	// Handle custom fields
-	if len(customFieldValues) > 0 {
-		customFieldsJSON, err := json.Marshal(customFieldValues)
+	if len(values.Custom) > 0 {
+		customFieldsJSON, err := json.Marshal(values.Custom)
 		if err != nil {
-			return fmt.Errorf("marshal custom fields: %w", err)
+			return nil, fmt.Errorf("marshal custom fields: %w", err)
 		}
 		sub.CustomFields = sql.NullString{
 			String: string(customFieldsJSON),
@@ -201,9 +285,23 @@ func (s *PSSubmissionService) Submit(
 		}
 	}
 
-	if err := s.subRepo.Upsert(ctx, sub); err != nil {
-		return err
+	attachments, err := s.storeUploads(ctx, teamID, ps.ID, uploads, submittedBy)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.subRepo.SaveRevision(ctx, sub, w.Deadline, attachments); err != nil {
+		s.removeStored(ctx, attachments)
+		return nil, err
 	}
-	return nil
+	return sub, nil
 }
 
+// MaxUploadRequestBytes caps the body of a multipart submission
+func (s *PSSubmissionService) MaxUploadRequestBytes() int64 {
+	return s.uploads.MaxRequestBytes()
+}
+
+// ListVersions returns every revision of a submission with who saved it (admin).
+func (s *PSSubmissionService) ListVersions(ctx context.Context, submissionID uuid.UUID) ([]models.PSSubmissionVersion, error) {
+	return s.subRepo.ListVersions(ctx, submissionID)
+}
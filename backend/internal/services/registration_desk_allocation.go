@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/rift26/backend/internal/models"
+	"github.com/google/uuid"
 	"github.com/rift26/backend/internal/repository"
 )
 
@@ -13,10 +13,11 @@ import (
 type RegistrationDeskAllocationService struct {
 	teamRepo       *repository.TeamRepository
 	eventTableRepo *repository.EventTableRepository
+	cityService    *CityService
 }
 
-func NewRegistrationDeskAllocationService(teamRepo *repository.TeamRepository, eventTableRepo *repository.EventTableRepository) *RegistrationDeskAllocationService {
-	return &RegistrationDeskAllocationService{teamRepo: teamRepo, eventTableRepo: eventTableRepo}
+func NewRegistrationDeskAllocationService(teamRepo *repository.TeamRepository, eventTableRepo *repository.EventTableRepository, cityService *CityService) *RegistrationDeskAllocationService {
+	return &RegistrationDeskAllocationService{teamRepo: teamRepo, eventTableRepo: eventTableRepo, cityService: cityService}
 }
 
 // CityAllocationResult is the result of allocating desks for one city.
@@ -32,26 +33,29 @@ type AllocateResult struct {
 	ByCity []CityAllocationResult `json:"by_city"`
 }
 
-var allocationCities = []string{string(models.CityBLR), string(models.CityPUNE), string(models.CityNOIDA), string(models.CityLKO)}
-
-// AllocateRegistrationDesks assigns each rsvp2_done team to a registration desk in its city.
+// AllocateRegistrationDesks assigns each rsvp2_done team of the event (uuid.Nil = current) to a registration desk in its city.
 // Previous allocations (for rsvp2_done teams) are cleared before assigning. Teams are distributed round-robin.
-func (s *RegistrationDeskAllocationService) AllocateRegistrationDesks(ctx context.Context) (*AllocateResult, error) {
+func (s *RegistrationDeskAllocationService) AllocateRegistrationDesks(ctx context.Context, eventID uuid.UUID) (*AllocateResult, error) {
 	// Clear previous registration desk allocations for all rsvp2_done teams
-	_, err := s.teamRepo.ClearRegistrationDesksForRSVP2Teams(ctx)
+	_, err := s.teamRepo.ClearRegistrationDesksForRSVP2Teams(ctx, eventID)
 	if err != nil {
 		return nil, fmt.Errorf("clear previous allocations: %w", err)
 	}
 
+	allocationCities, err := s.cityService.Codes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load cities: %w", err)
+	}
+
 	result := &AllocateResult{ByCity: make([]CityAllocationResult, 0, len(allocationCities))}
 
 	for _, city := range allocationCities {
-		teamIDs, err := s.teamRepo.GetTeamIDsByCity(ctx, city)
+		teamIDs, err := s.teamRepo.GetTeamIDsByCity(ctx, eventID, city)
 		if err != nil {
 			return nil, fmt.Errorf("get teams for city %s: %w", city, err)
 		}
 		isActive := true
-		tables, err := s.eventTableRepo.GetAll(&city, &isActive)
+		tables, err := s.eventTableRepo.GetAll(eventID, s.cityService.Variations(city), &isActive)
 		if err != nil {
 			return nil, fmt.Errorf("get event tables for city %s: %w", city, err)
 		}
@@ -75,7 +79,7 @@ func (s *RegistrationDeskAllocationService) AllocateRegistrationDesks(ctx contex
 	return result, nil
 }
 
-// ClearAllRegistrationDesks clears registration_desk_id for all teams that have one set.
-func (s *RegistrationDeskAllocationService) ClearAllRegistrationDesks(ctx context.Context) (int64, error) {
-	return s.teamRepo.ClearAllRegistrationDesks(ctx)
+// ClearAllRegistrationDesks clears registration_desk_id for the event's teams that have one set.
+func (s *RegistrationDeskAllocationService) ClearAllRegistrationDesks(ctx context.Context, eventID uuid.UUID) (int64, error) {
+	return s.teamRepo.ClearAllRegistrationDesks(ctx, eventID)
 }
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+	"github.com/rift26/backend/pkg/xlsx"
+)
+
+// RosterColumn is one selectable column of the roster export.
+type RosterColumn struct {
+	Key    string `json:"key"`
+	Header string `json:"header"`
+	value  func(*models.RosterRow) string
+}
+
+// rosterColumns lists every exportable column in default order.
+var rosterColumns = []RosterColumn{
+	{"team_id", "Team ID", func(r *models.RosterRow) string { return r.TeamID.String() }},
+	{"team_name", "Team Name", func(r *models.RosterRow) string { return r.TeamName }},
+	{"city", "City", func(r *models.RosterRow) string { return rosterStr(r.City) }},
+	{"status", "Status", func(r *models.RosterRow) string { return r.Status }},
+	{"member_count", "Member Count", func(r *models.RosterRow) string { return strconv.Itoa(r.MemberCount) }},
+	{"rsvp1_at", "RSVP I At", func(r *models.RosterRow) string { return rosterTime(r.RSVP1At) }},
+	{"rsvp2_at", "RSVP II At", func(r *models.RosterRow) string { return rosterTime(r.RSVP2At) }},
+	{"team_checked_in_at", "Team Checked In At", func(r *models.RosterRow) string { return rosterTime(r.TeamCheckedInAt) }},
+	{"registration_desk", "Registration Desk", func(r *models.RosterRow) string { return rosterStr(r.RegistrationDesk) }},
+	{"seat_block", "Seat Block", func(r *models.RosterRow) string { return rosterStr(r.SeatBlock) }},
+	{"seat_room", "Seat Room", func(r *models.RosterRow) string { return rosterStr(r.SeatRoom) }},
+	{"seat_label", "Seat Label", func(r *models.RosterRow) string { return rosterStr(r.SeatLabel) }},
+	{"ps_track", "PS Track", func(r *models.RosterRow) string { return rosterStr(r.PSTrack) }},
+	{"ps_name", "PS Name", func(r *models.RosterRow) string { return rosterStr(r.PSName) }},
+	{"ps_locked_at", "PS Locked At", func(r *models.RosterRow) string { return rosterTime(r.PSLockedAt) }},
+	{"github_url", "GitHub URL", func(r *models.RosterRow) string { return rosterStr(r.GithubURL) }},
+	{"live_url", "Live URL", func(r *models.RosterRow) string { return rosterStr(r.LiveURL) }},
+	{"linkedin_url", "LinkedIn URL", func(r *models.RosterRow) string { return rosterStr(r.LinkedinURL) }},
+	{"submitted_at", "Submitted At", func(r *models.RosterRow) string { return rosterTime(r.SubmittedAt) }},
+	{"member_name", "Member Name", func(r *models.RosterRow) string { return rosterStr(r.MemberName) }},
+	{"member_role", "Member Role", func(r *models.RosterRow) string { return rosterStr(r.MemberRole) }},
+	{"member_email", "Member Email", func(r *models.RosterRow) string { return rosterStr(r.MemberEmail) }},
+	{"member_phone", "Member Phone", func(r *models.RosterRow) string { return rosterStr(r.MemberPhone) }},
+	{"tshirt_size", "T-Shirt Size", func(r *models.RosterRow) string { return rosterStr(r.TShirtSize) }},
+	{"rsvp2_selected", "RSVP II Selected", func(r *models.RosterRow) string { return rosterYesNo(r.RSVP2Selected) }},
+	{"checked_in", "Checked In", func(r *models.RosterRow) string { return rosterYesNo(r.CheckedInAt != nil) }},
+	{"checked_in_at", "Checked In At", func(r *models.RosterRow) string { return rosterTime(r.CheckedInAt) }},
+}
+
+// RosterExportService streams the full team roster (one row per participant) as CSV or XLSX.
+type RosterExportService struct {
+	teamRepo *repository.TeamRepository
+}
+
+func NewRosterExportService(teamRepo *repository.TeamRepository) *RosterExportService {
+	return &RosterExportService{teamRepo: teamRepo}
+}
+
+// Columns returns the selectable columns in default order.
+func (s *RosterExportService) Columns() []RosterColumn {
+	return rosterColumns
+}
+
+// ResolveColumns maps comma-separated column keys to columns, keeping the requested order.
+// An empty selection means every column.
+func (s *RosterExportService) ResolveColumns(keys string) ([]RosterColumn, error) {
+	if strings.TrimSpace(keys) == "" {
+		return rosterColumns, nil
+	}
+	byKey := make(map[string]RosterColumn, len(rosterColumns))
+	for _, c := range rosterColumns {
+		byKey[c.Key] = c
+	}
+	var cols []RosterColumn
+	for _, k := range strings.Split(keys, ",") {
+		k = strings.TrimSpace(k)
+		if k == "" {
+			continue
+		}
+		c, ok := byKey[k]
+		if !ok {
+			return nil, fmt.Errorf("unknown column: %s", k)
+		}
+		cols = append(cols, c)
+	}
+	if len(cols) == 0 {
+		return rosterColumns, nil
+	}
+	return cols, nil
+}
+
+// Export writes the roster for the event (uuid.Nil = current event) to w in the given format ("csv" or "xlsx").
+// Rows are flushed as they are read so large rosters never sit in memory.
+func (s *RosterExportService) Export(ctx context.Context, w io.Writer, format string, eventID uuid.UUID, status, city string, cols []RosterColumn) error {
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.Header
+	}
+	// Both writers get the same formula escaping, so a file converted between formats stays safe
+	record := make([]string, len(cols))
+	fill := func(row *models.RosterRow) []string {
+		for i, c := range cols {
+			record[i] = csvSafe(c.value(row))
+		}
+		return record
+	}
+	flusher, _ := w.(interface{ Flush() })
+
+	switch format {
+	case "xlsx":
+		xw, err := xlsx.NewWriter(w, "Roster")
+		if err != nil {
+			return err
+		}
+		if err := xw.WriteRow(header); err != nil {
+			return err
+		}
+		n := 0
+		err = s.teamRepo.StreamRoster(ctx, eventID, status, city, func(row *models.RosterRow) error {
+			if err := xw.WriteRow(fill(row)); err != nil {
+				return err
+			}
+			if n++; n%500 == 0 {
+				if err := xw.Flush(); err != nil {
+					return err
+				}
+				if flusher != nil {
+					flusher.Flush()
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return xw.Close()
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		n := 0
+		err := s.teamRepo.StreamRoster(ctx, eventID, status, city, func(row *models.RosterRow) error {
+			if err := cw.Write(fill(row)); err != nil {
+				return err
+			}
+			if n++; n%500 == 0 {
+				cw.Flush()
+				if flusher != nil {
+					flusher.Flush()
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func rosterStr(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func rosterTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func rosterYesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
+
+// csvSafe prefixes values that spreadsheet apps would evaluate as formulas (team and member
+// names are user-supplied). A leading + or - is left alone when the rest is a plain number, so
+// phone numbers like +91 98765 43210 and negative numbers export unchanged.
+func csvSafe(v string) string {
+	if v == "" || !strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return v
+	}
+	if (v[0] == '+' || v[0] == '-') && plainNumber(v[1:]) {
+		return v
+	}
+	return "'" + v
+}
+
+// plainNumber reports whether s is digits, optionally grouped by spaces, with at most one decimal point.
+func plainNumber(s string) bool {
+	digits, points := 0, 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '.':
+			points++
+		case r != ' ':
+			return false
+		}
+	}
+	return digits > 0 && points <= 1
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,11 +13,12 @@ import (
 )
 
 type SeatAllocationService struct {
-	db *gorm.DB
+	db          *gorm.DB
+	cityService *CityService
 }
 
-func NewSeatAllocationService(db *gorm.DB) *SeatAllocationService {
-	return &SeatAllocationService{db: db}
+func NewSeatAllocationService(db *gorm.DB, cityService *CityService) *SeatAllocationService {
+	return &SeatAllocationService{db: db, cityService: cityService}
 }
 
 // AllocateSeat allocates a seat (or group of seats for teams of 2/3/4) to a team.
@@ -48,10 +50,16 @@ func (s *SeatAllocationService) AllocateSeat(teamID uuid.UUID, volunteerID uuid.
 		return nil, errors.New("team size could not be determined (member_count or team_members is empty)")
 	}
 
+	cityNames, err := s.teamCityNames(tx, teamID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	// Try preferred block first; if no merged cell for this team size in that block, try next blocks (any block in order)
-	seats, err := s.findBestAvailableSeats(tx, teamSize, preferredBlockName)
+	seats, err := s.findBestAvailableSeats(tx, cityNames, teamSize, preferredBlockName)
 	if err != nil && preferredBlockName != nil && *preferredBlockName != "" {
-		seats, err = s.findBestAvailableSeats(tx, teamSize, nil)
+		seats, err = s.findBestAvailableSeats(tx, cityNames, teamSize, nil)
 	}
 	if err != nil {
 		tx.Rollback()
@@ -109,14 +117,77 @@ func (s *SeatAllocationService) AllocateSeat(teamID uuid.UUID, volunteerID uuid.
 	return alloc, nil
 }
 
-// baseJoin returns a fresh query chain for Bengaluru seat lookups (do not reuse; GORM has no Clone).
-func baseJoin(tx *gorm.DB, preferredBlockName *string) *gorm.DB {
+// ReleaseSeat frees a team's seat allocation (all seats of a merged group) and room occupancy.
+// Returns false when the team had no allocation.
+func (s *SeatAllocationService) ReleaseSeat(teamID uuid.UUID) (bool, error) {
+	released := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var alloc models.SeatAllocation
+		if err := tx.Where("team_id = ?", teamID).First(&alloc).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		var seat models.Seat
+		if err := tx.First(&seat, "id = ?", alloc.SeatID).Error; err == nil && seat.SeatGroupID != nil {
+			if err := tx.Model(&models.Seat{}).Where("seat_group_id = ?", *seat.SeatGroupID).Update("is_available", true).Error; err != nil {
+				return fmt.Errorf("failed to free seat group: %w", err)
+			}
+		} else if err := tx.Model(&models.Seat{}).Where("id = ?", alloc.SeatID).Update("is_available", true).Error; err != nil {
+			return fmt.Errorf("failed to free seat: %w", err)
+		}
+
+		if err := tx.Model(&models.Room{}).
+			Where("id = ?", alloc.RoomID).
+			UpdateColumn("current_occupancy", gorm.Expr("GREATEST(current_occupancy - ?, 0)", alloc.TeamSize)).Error; err != nil {
+			return fmt.Errorf("failed to update room occupancy: %w", err)
+		}
+		if err := tx.Delete(&alloc).Error; err != nil {
+			return fmt.Errorf("failed to delete allocation: %w", err)
+		}
+		released = true
+		return nil
+	})
+	return released, err
+}
+
+// teamCityNames returns the spellings of the team's city that blocks may store; the city must use seat allocation.
+func (s *SeatAllocationService) teamCityNames(tx *gorm.DB, teamID uuid.UUID) ([]string, error) {
+	var team models.Team
+	if err := tx.Select("city").First(&team, teamID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get team city: %w", err)
+	}
+	if team.City == nil || !s.cityService.HasSeatAllocation(string(*team.City)) {
+		return nil, errors.New("team's city does not use seat allocation")
+	}
+	return s.cityService.Variations(string(*team.City)), nil
+}
+
+// seatCityNames returns the spellings of every active city that uses seat allocation.
+func (s *SeatAllocationService) seatCityNames() []string {
+	names := []string{}
+	cities, err := s.cityService.Active(context.Background())
+	if err != nil {
+		return names
+	}
+	for _, c := range cities {
+		if c.SeatAllocation {
+			names = append(names, s.cityService.Variations(c.Code)...)
+		}
+	}
+	return names
+}
+
+// baseJoin returns a fresh query chain for seat lookups in one city (do not reuse; GORM has no Clone).
+func baseJoin(tx *gorm.DB, cityNames []string, preferredBlockName *string) *gorm.DB {
 	query := tx.Model(&models.Seat{}).
 		Joins("JOIN rooms ON rooms.id = seats.room_id").
 		Joins("JOIN blocks ON blocks.id = rooms.block_id").
 		Where("seats.is_available = ? AND seats.is_active = ? AND rooms.is_active = ? AND blocks.is_active = ?",
 			true, true, true, true).
-		Where("blocks.city = ?", "bengaluru")
+		Where("LOWER(TRIM(blocks.city)) IN ?", cityNames)
 
 	if preferredBlockName != nil && *preferredBlockName != "" {
 		query = query.Where("blocks.name = ?", *preferredBlockName)
@@ -128,12 +199,12 @@ func baseJoin(tx *gorm.DB, preferredBlockName *string) *gorm.DB {
 // findBestAvailableSeats returns one or more seats (a group) for the given team size.
 // Teams of 2, 3, or 4: only seats with matching team_size_preference and same seat_group_id (all available).
 // Team of 1: single seat with team_size_preference IS NULL (or unset).
-func (s *SeatAllocationService) findBestAvailableSeats(tx *gorm.DB, teamSize int, preferredBlockName *string) ([]*models.Seat, error) {
+func (s *SeatAllocationService) findBestAvailableSeats(tx *gorm.DB, cityNames []string, teamSize int, preferredBlockName *string) ([]*models.Seat, error) {
 	if teamSize >= 2 && teamSize <= 4 {
 		// Strict: only merged groups of exactly this team size (all seats in group available).
 		// Order: 1st block, 1st room, then row A (row 1), 1st column — deterministic, not random.
 		var candidates []models.Seat
-		err := baseJoin(tx, preferredBlockName).
+		err := baseJoin(tx, cityNames, preferredBlockName).
 			Where("seats.team_size_preference = ? AND seats.seat_group_id IS NOT NULL", teamSize).
 			Order("blocks.display_order ASC, rooms.display_order ASC, seats.row_number ASC, seats.column_number ASC").
 			Find(&candidates).Error
@@ -187,12 +258,12 @@ func (s *SeatAllocationService) findBestAvailableSeats(tx *gorm.DB, teamSize int
 
 	// Team of 1: single seat, prefer no team_size_preference (solo seat)
 	var seat models.Seat
-	err := baseJoin(tx, preferredBlockName).
+	err := baseJoin(tx, cityNames, preferredBlockName).
 		Where("seats.team_size_preference IS NULL").
 		Order("blocks.display_order ASC, rooms.display_order ASC, seats.row_number ASC, seats.column_number ASC").
 		First(&seat).Error
 	if err != nil {
-		err = baseJoin(tx, preferredBlockName).
+		err = baseJoin(tx, cityNames, preferredBlockName).
 			Order("blocks.display_order ASC, rooms.display_order ASC, seats.row_number ASC, seats.column_number ASC").
 			First(&seat).Error
 	}
@@ -319,7 +390,7 @@ func (s *SeatAllocationService) GetAllocationStats() (map[string]interface{}, er
 	}
 	stats["available_slots_by_team_size"] = availableSlots
 
-	// Per-room stats: block, room name, capacity, occupied (from actual allocations), available (Bengaluru only)
+	// Per-room stats: block, room name, capacity, occupied (from actual allocations), available (seat allocation cities only)
 	// Occupied = SUM(team_size) from seat_allocations for that room (avoids stale rooms.current_occupancy)
 	type RoomStatRow struct {
 		BlockName      string
@@ -335,7 +406,7 @@ func (s *SeatAllocationService) GetAllocationStats() (map[string]interface{}, er
 		Joins("JOIN blocks ON blocks.id = rooms.block_id").
 		Joins("LEFT JOIN seats ON seats.room_id = rooms.id AND seats.is_active = true").
 		Where("rooms.is_active = ? AND blocks.is_active = ?", true, true).
-		Where("LOWER(TRIM(blocks.city)) IN (?, ?, ?)", "bengaluru", "blr", "bangalore").
+		Where("LOWER(TRIM(blocks.city)) IN ?", s.seatCityNames()).
 		Group("rooms.id, blocks.name, rooms.name, rooms.capacity, blocks.display_order, rooms.display_order").
 		Order("blocks.display_order, rooms.display_order").
 		Scan(&roomStatRows)
@@ -355,3 +426,37 @@ func (s *SeatAllocationService) GetAllocationStats() (map[string]interface{}, er
 
 	return stats, nil
 }
+
+// RoomOccupancy is one room's capacity and live occupancy, for display screens.
+type RoomOccupancy struct {
+	City             string `json:"city"`
+	BlockName        string `json:"block_name"`
+	RoomName         string `json:"room_name"`
+	Capacity         int    `json:"capacity"`
+	CurrentOccupancy int    `json:"current_occupancy"`
+	AvailableSeats   int64  `json:"available_seats"`
+}
+
+// GetRoomOccupancy lists active rooms of seat allocation cities, or of one city when city is set.
+// Occupancy is computed from seat_allocations like GetAllocationStats.
+func (s *SeatAllocationService) GetRoomOccupancy(city string) ([]RoomOccupancy, error) {
+	cityNames := s.seatCityNames()
+	if city != "" {
+		cityNames = s.cityService.Variations(city)
+	}
+
+	rooms := []RoomOccupancy{}
+	err := s.db.Model(&models.Room{}).
+		Select("blocks.city as city, blocks.name as block_name, rooms.name as room_name, rooms.capacity, (SELECT COALESCE(SUM(team_size), 0) FROM seat_allocations WHERE room_id = rooms.id) as current_occupancy, COUNT(seats.id) FILTER (WHERE seats.is_available = true) as available_seats").
+		Joins("JOIN blocks ON blocks.id = rooms.block_id").
+		Joins("LEFT JOIN seats ON seats.room_id = rooms.id AND seats.is_active = true").
+		Where("rooms.is_active = ? AND blocks.is_active = ?", true, true).
+		Where("LOWER(TRIM(blocks.city)) IN ?", cityNames).
+		Group("rooms.id, blocks.city, blocks.name, rooms.name, rooms.capacity, blocks.display_order, rooms.display_order").
+		Order("blocks.display_order, rooms.display_order").
+		Scan(&rooms).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load room occupancy: %w", err)
+	}
+	return rooms, nil
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+	"github.com/rift26/backend/internal/utils"
+)
+
+// revocationCacheTTL bounds how long a session's revoked/active status is cached between lookups.
+// Revocations made through this service clear the cache immediately.
+const revocationCacheTTL = 15 * time.Second
+
+type revocationEntry struct {
+	revoked  bool
+	cutoff   *time.Time
+	cachedAt time.Time
+}
+
+// SessionService issues access tokens with rotating refresh tokens and checks revocation.
+type SessionService struct {
+	repo       *repository.SessionRepository
+	jwtSecret  string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	// permissionSource resolves admin permissions; nil leaves admin tokens without permissions
+	permissionSource func(ctx context.Context, userID uuid.UUID) ([]string, error)
+
+	mu       sync.Mutex
+	sessions map[uuid.UUID]revocationEntry // by session id
+	subjects map[uuid.UUID]revocationEntry // by subject id (legacy tokens)
+}
+
+func NewSessionService(repo *repository.SessionRepository, jwtSecret string, accessTTL, refreshTTL time.Duration) *SessionService {
+	return &SessionService{
+		repo:       repo,
+		jwtSecret:  jwtSecret,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		sessions:   make(map[uuid.UUID]revocationEntry),
+		subjects:   make(map[uuid.UUID]revocationEntry),
+	}
+}
+
+// SetPermissionSource sets how admin permissions are resolved when tokens are issued or refreshed.
+func (s *SessionService) SetPermissionSource(fn func(ctx context.Context, userID uuid.UUID) ([]string, error)) {
+	s.permissionSource = fn
+}
+
+// Issue starts a new session for the subject and returns its first token pair.
+func (s *SessionService) Issue(ctx context.Context, subject models.SessionSubject, client models.SessionClient) (*models.TokenPair, error) {
+	refresh, hash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	sess := &models.AuthSession{
+		SubjectID: subject.SubjectID,
+		Role:      subject.Role,
+		Email:     subject.Email,
+		TeamID:    subject.TeamID,
+		City:      subject.City,
+		UserAgent: optional(client.UserAgent),
+		IP:        optional(client.IP),
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	}
+	if err := s.repo.Create(ctx, sess, hash); err != nil {
+		return nil, err
+	}
+	return s.pair(ctx, subject, sess.ID, refresh, sess.ExpiresAt)
+}
+
+// Refresh rotates a refresh token and returns a new pair. Presenting an already rotated token
+// revokes the whole session, since it means the token was copied.
+func (s *SessionService) Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error) {
+	hash := hashRefreshToken(refreshToken)
+	sess, reused, err := s.repo.GetByRefreshHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if sess == nil || sess.RevokedAt != nil || time.Now().After(sess.ExpiresAt) {
+		return nil, fmt.Errorf("invalid or expired refresh token")
+	}
+	if reused {
+		log.Printf("[Sessions] Refresh token reuse on session %s (subject %s); revoking", sess.ID, sess.SubjectID)
+		_ = s.RevokeSession(ctx, sess.ID)
+		return nil, fmt.Errorf("invalid or expired refresh token")
+	}
+
+	refresh, newHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(s.refreshTTL)
+	ok, err := s.repo.Rotate(ctx, sess.ID, hash, newHash, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid or expired refresh token")
+	}
+	subject := models.SessionSubject{
+		SubjectID: sess.SubjectID,
+		Role:      sess.Role,
+		Email:     sess.Email,
+		TeamID:    sess.TeamID,
+		City:      sess.City,
+	}
+	return s.pair(ctx, subject, sess.ID, refresh, expiresAt)
+}
+
+// Logout revokes the session owning the refresh token. Unknown tokens are ignored.
+func (s *SessionService) Logout(ctx context.Context, refreshToken string) error {
+	sess, _, err := s.repo.GetByRefreshHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		return err
+	}
+	if sess == nil {
+		return nil
+	}
+	return s.RevokeSession(ctx, sess.ID)
+}
+
+// RevokeSession ends one session; its access tokens stop working immediately.
+func (s *SessionService) RevokeSession(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.Revoke(ctx, id); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	delete(s.sessions, id)
+	s.mu.Unlock()
+	return nil
+}
+
+// RevokeAll ends every session of a user, volunteer, volunteer admin or team, including tokens
+// issued before sessions existed. Returns the number of sessions revoked.
+func (s *SessionService) RevokeAll(ctx context.Context, subjectID uuid.UUID) (int64, error) {
+	n, err := s.repo.RevokeAllForSubject(ctx, subjectID)
+	if err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	s.sessions = make(map[uuid.UUID]revocationEntry)
+	delete(s.subjects, subjectID)
+	s.mu.Unlock()
+	return n, nil
+}
+
+// ListActive returns the active sessions of a subject.
+func (s *SessionService) ListActive(ctx context.Context, subjectID uuid.UUID) ([]models.AuthSession, error) {
+	return s.repo.ListActive(ctx, subjectID)
+}
+
+// ParseAccessToken validates an access token's signature and expiry and rejects revoked sessions.
+func (s *SessionService) ParseAccessToken(token string) (*utils.Claims, error) {
+	claims, err := utils.ValidateJWT(token, s.jwtSecret)
+	if err != nil {
+		return nil, err
+	}
+	if s.isRevoked(claims) {
+		return nil, fmt.Errorf("token has been revoked")
+	}
+	return claims, nil
+}
+
+// isRevoked checks the token's session, or for legacy tokens without one, the subject's cutoff.
+// Lookup failures count as revoked.
+func (s *SessionService) isRevoked(claims *utils.Claims) bool {
+	ctx := context.Background()
+	now := time.Now()
+
+	if claims.SessionID != nil {
+		id := *claims.SessionID
+		s.mu.Lock()
+		e, ok := s.sessions[id]
+		s.mu.Unlock()
+		if !ok || now.Sub(e.cachedAt) > revocationCacheTTL {
+			sess, err := s.repo.GetByID(ctx, id)
+			if err != nil {
+				log.Printf("[Sessions] Failed to check session %s: %v", id, err)
+				return true
+			}
+			e = revocationEntry{revoked: sess == nil || sess.RevokedAt != nil, cachedAt: now}
+			s.mu.Lock()
+			s.sessions[id] = e
+			s.mu.Unlock()
+		}
+		return e.revoked
+	}
+
+	s.mu.Lock()
+	e, ok := s.subjects[claims.UserID]
+	s.mu.Unlock()
+	if !ok || now.Sub(e.cachedAt) > revocationCacheTTL {
+		cutoff, err := s.repo.GetRevokedBefore(ctx, claims.UserID)
+		if err != nil {
+			log.Printf("[Sessions] Failed to check revocation for %s: %v", claims.UserID, err)
+			return true
+		}
+		e = revocationEntry{cutoff: cutoff, cachedAt: now}
+		s.mu.Lock()
+		s.subjects[claims.UserID] = e
+		s.mu.Unlock()
+	}
+	return e.cutoff != nil && (claims.IssuedAt == nil || !claims.IssuedAt.Time.After(*e.cutoff))
+}
+
+func (s *SessionService) pair(ctx context.Context, subject models.SessionSubject, sessionID uuid.UUID, refresh string, refreshExpiresAt time.Time) (*models.TokenPair, error) {
+	if subject.Role == models.UserRoleAdmin && s.permissionSource != nil {
+		perms, err := s.permissionSource(ctx, subject.SubjectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve permissions: %w", err)
+		}
+		subject.Permissions = perms
+	}
+	access, err := utils.GenerateAccessToken(subject, sessionID, s.accessTTL, s.jwtSecret)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TokenPair{
+		Token:            access,
+		RefreshToken:     refresh,
+		ExpiresIn:        int(s.accessTTL.Seconds()),
+		RefreshExpiresAt: refreshExpiresAt,
+		Permissions:      subject.Permissions,
+	}, nil
+}
+
+// newRefreshToken returns a random opaque refresh token and its SHA-256 hash.
+func newRefreshToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,324 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/pkg/storage"
+)
+
+// SubmissionUploadConfig is where submission attachments are stored and how large they may be
+type SubmissionUploadConfig struct {
+	Store    storage.Store // shared with the problem statement PDFs; objects live under submissionKeyPrefix
+	MaxBytes int64         // per file; file fields can only lower it
+	BaseURL  string        // API public URL for building download links
+	URLTTL   time.Duration // lifetime of signed download URLs
+}
+
+// submissionKeyPrefix is the storage folder of submission attachments; storage_key is relative to it
+const submissionKeyPrefix = "submissions/"
+
+// MaxRequestBytes caps a whole multipart submission: one maximum-size file per file field plus the form values
+func (c SubmissionUploadConfig) MaxRequestBytes() int64 {
+	return maxFileFields*c.MaxBytes + 1<<20
+}
+
+// officeTypes maps extensions of zip-based documents, which sniff as application/zip, to their types
+var officeTypes = map[string]string{
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+var storageExtPattern = regexp.MustCompile(`^\.[a-z0-9]{1,10}$`)
+
+// sniffContentType detects a file's type from its first bytes; the client's Content-Type is ignored.
+// The name only refines zip-based office documents and SVG, which the sniffer can't tell apart.
+func sniffContentType(head []byte, filename string) string {
+	ct, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	ext := strings.ToLower(filepath.Ext(filename))
+	switch ct {
+	case "application/zip":
+		if t, ok := officeTypes[ext]; ok {
+			return t
+		}
+	case "text/xml", "text/plain":
+		if ext == ".svg" && bytes.Contains(head, []byte("<svg")) {
+			return "image/svg+xml"
+		}
+	}
+	return ct
+}
+
+// pendingUpload is a received file that passed its field's checks and is not stored yet
+type pendingUpload struct {
+	id          uuid.UUID
+	field       CustomFieldDefinition
+	file        *multipart.FileHeader
+	contentType string
+}
+
+// checkUploads checks each uploaded file against its file field (size cap and sniffed content type)
+// and names the new attachment in custom. Files for fields that aren't file fields are ignored.
+func (s *PSSubmissionService) checkUploads(cfg PSSubmissionFieldsConfig, files map[string]*multipart.FileHeader, custom map[string]string) ([]pendingUpload, map[string]string) {
+	var uploads []pendingUpload
+	errs := map[string]string{}
+	for _, f := range cfg.CustomFields {
+		file := files[f.Key]
+		if f.Type != FieldTypeFile || file == nil {
+			continue
+		}
+		if limit := f.fileSizeLimit(s.uploads.MaxBytes); file.Size > limit {
+			errs[f.Key] = fmt.Sprintf("must be at most %s", formatBytes(limit))
+			continue
+		}
+		if file.Size == 0 {
+			errs[f.Key] = "is empty"
+			continue
+		}
+		src, err := file.Open()
+		if err != nil {
+			errs[f.Key] = "could not be read"
+			continue
+		}
+		head := make([]byte, 512)
+		n, _ := io.ReadFull(src, head)
+		src.Close()
+		contentType := sniffContentType(head[:n], file.Filename)
+		if !f.acceptsType(contentType) {
+			errs[f.Key] = fmt.Sprintf("file type %s is not accepted", contentType)
+			continue
+		}
+		u := pendingUpload{id: uuid.New(), field: f, file: file, contentType: contentType}
+		custom[f.Key] = u.id.String()
+		uploads = append(uploads, u)
+	}
+	return uploads, errs
+}
+
+// checkAttachmentRefs makes sure file field values that aren't new uploads name files this team
+// uploaded for the same field of the same problem statement.
+func (s *PSSubmissionService) checkAttachmentRefs(ctx context.Context, cfg PSSubmissionFieldsConfig, teamID, psID uuid.UUID, custom map[string]string, uploads []pendingUpload, errs map[string]string) error {
+	isNew := map[uuid.UUID]bool{}
+	for _, u := range uploads {
+		isNew[u.id] = true
+	}
+	refs := cfg.fileFieldIDs(custom)
+	ids := make([]uuid.UUID, 0, len(refs))
+	for _, id := range refs {
+		if !isNew[id] {
+			ids = append(ids, id)
+		}
+	}
+	existing, err := s.subRepo.GetAttachments(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for key, id := range refs {
+		if isNew[id] {
+			continue
+		}
+		a, ok := existing[id]
+		if !ok || a.TeamID != teamID || a.ProblemStatementID != psID || a.FieldKey != key {
+			errs[key] = "must be a file uploaded for this field"
+		}
+	}
+	return nil
+}
+
+// storeUploads writes checked uploads to storage. On error the files written so far are removed.
+func (s *PSSubmissionService) storeUploads(ctx context.Context, teamID, psID uuid.UUID, uploads []pendingUpload, uploadedBy string) ([]models.SubmissionAttachment, error) {
+	attachments := make([]models.SubmissionAttachment, 0, len(uploads))
+	for _, u := range uploads {
+		a, err := s.storeUpload(ctx, u)
+		if err != nil {
+			s.removeStored(ctx, attachments)
+			return nil, err
+		}
+		a.TeamID, a.ProblemStatementID, a.UploadedBy = teamID, psID, optional(uploadedBy)
+		attachments = append(attachments, *a)
+	}
+	return attachments, nil
+}
+
+func (s *PSSubmissionService) storeUpload(ctx context.Context, u pendingUpload) (*models.SubmissionAttachment, error) {
+	filename := strings.TrimSpace(filepath.Base(u.file.Filename))
+	if filename == "" || filename == "." || filename == string(filepath.Separator) {
+		filename = u.field.Key
+	}
+	for utf8.RuneCountInString(filename) > 255 {
+		_, size := utf8.DecodeLastRuneInString(filename)
+		filename = filename[:len(filename)-size]
+	}
+	ext := strings.ToLower(filepath.Ext(filename))
+	if !storageExtPattern.MatchString(ext) {
+		ext = ""
+	}
+	a := &models.SubmissionAttachment{
+		ID:          u.id,
+		FieldKey:    u.field.Key,
+		Filename:    filename,
+		ContentType: u.contentType,
+		StorageKey:  u.id.String() + ext,
+	}
+
+	src, err := u.file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open upload: %w", err)
+	}
+	defer src.Close()
+	// The multipart header's size is what checkUploads held against the field's cap
+	hash := sha256.New()
+	body := io.TeeReader(io.LimitReader(src, u.file.Size), hash)
+	if err := s.uploads.Store.Put(ctx, submissionKeyPrefix+a.StorageKey, body, u.file.Size, a.ContentType); err != nil {
+		return nil, fmt.Errorf("store upload: %w", err)
+	}
+	a.SizeBytes, a.SHA256 = u.file.Size, hex.EncodeToString(hash.Sum(nil))
+	return a, nil
+}
+
+// removeStored deletes stored files whose submission was not saved
+func (s *PSSubmissionService) removeStored(ctx context.Context, attachments []models.SubmissionAttachment) {
+	for _, a := range attachments {
+		if err := s.uploads.Store.Delete(ctx, submissionKeyPrefix+a.StorageKey); err != nil {
+			log.Printf("[Submissions] Failed to remove unsaved attachment %s: %v", a.StorageKey, err)
+		}
+	}
+}
+
+// OpenAttachment returns an attachment's content from storage; the caller closes it.
+func (s *PSSubmissionService) OpenAttachment(ctx context.Context, a *models.SubmissionAttachment) (io.ReadCloser, error) {
+	body, _, err := s.uploads.Store.Open(ctx, submissionKeyPrefix+a.StorageKey)
+	return body, err
+}
+
+// withSignedURL adds a signed, expiring direct download URL to an attachment.
+func (s *PSSubmissionService) withSignedURL(ctx context.Context, a *models.SubmissionAttachment) {
+	signed, err := s.uploads.Store.SignedURL(ctx, submissionKeyPrefix+a.StorageKey, s.uploads.URLTTL, storage.Download{
+		Filename:    a.Filename,
+		ContentType: a.ContentType,
+	})
+	if err != nil {
+		log.Printf("[Submissions] Failed to sign download URL for attachment %s: %v", a.ID, err)
+		return
+	}
+	expires := time.Now().Add(s.uploads.URLTTL)
+	a.SignedURL, a.SignedURLExpiresAt = signed, &expires
+}
+
+// teamAttachments loads the files named by the team's current file field values, with download URLs
+// under the team's submission route.
+func (s *PSSubmissionService) teamAttachments(ctx context.Context, teamID uuid.UUID, cfg PSSubmissionFieldsConfig, custom map[string]string) ([]models.SubmissionAttachment, error) {
+	fileIDs := cfg.fileFieldIDs(custom)
+	if len(fileIDs) == 0 {
+		return nil, nil
+	}
+	ids := make([]uuid.UUID, 0, len(fileIDs))
+	for _, id := range fileIDs {
+		ids = append(ids, id)
+	}
+	found, err := s.subRepo.GetAttachments(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	var out []models.SubmissionAttachment
+	for _, f := range cfg.CustomFields {
+		if a, ok := found[fileIDs[f.Key]]; ok && a.TeamID == teamID {
+			a.DownloadURL = fmt.Sprintf("%s/api/v1/teams/%s/submission/attachments/%s", s.uploads.BaseURL, teamID, a.ID)
+			s.withSignedURL(ctx, &a)
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+// TeamAttachment returns one of the team's own attachments, or nil.
+func (s *PSSubmissionService) TeamAttachment(ctx context.Context, teamID, attachmentID uuid.UUID) (*models.SubmissionAttachment, error) {
+	found, err := s.subRepo.GetAttachments(ctx, []uuid.UUID{attachmentID})
+	if err != nil {
+		return nil, err
+	}
+	a, ok := found[attachmentID]
+	if !ok || a.TeamID != teamID {
+		return nil, nil
+	}
+	return &a, nil
+}
+
+// SubmissionAttachment returns an attachment used by any version of the submission, or nil.
+func (s *PSSubmissionService) SubmissionAttachment(ctx context.Context, submissionID, attachmentID uuid.UUID) (*models.SubmissionAttachment, error) {
+	return s.subRepo.GetSubmissionAttachment(ctx, submissionID, attachmentID)
+}
+
+// SubmissionFiles is the attachments of one submission to be listed
+type SubmissionFiles struct {
+	SubmissionID uuid.UUID
+	PSFields     sql.NullString // problem_statements.submission_fields
+	CustomFields sql.NullString // the shown version's custom_fields
+}
+
+// ListAttachments loads the files named by each submission's file fields, with download URLs under
+// pathPrefix + "<submission id>/attachments/<attachment id>". The result is keyed by submission.
+func (s *PSSubmissionService) ListAttachments(ctx context.Context, subs []SubmissionFiles, pathPrefix string) (map[uuid.UUID][]models.SubmissionAttachment, error) {
+	refs := make(map[uuid.UUID][]uuid.UUID, len(subs))
+	var ids []uuid.UUID
+	for _, sub := range subs {
+		if !sub.CustomFields.Valid || sub.CustomFields.String == "" {
+			continue
+		}
+		var custom map[string]string
+		if json.Unmarshal([]byte(sub.CustomFields.String), &custom) != nil {
+			continue
+		}
+		cfg := submissionFieldsOf(&models.PSItem{SubmissionFields: sub.PSFields})
+		fileIDs := cfg.fileFieldIDs(custom)
+		for _, f := range cfg.CustomFields {
+			if id, ok := fileIDs[f.Key]; ok {
+				refs[sub.SubmissionID] = append(refs[sub.SubmissionID], id)
+				ids = append(ids, id)
+			}
+		}
+	}
+	found, err := s.subRepo.GetAttachments(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[uuid.UUID][]models.SubmissionAttachment, len(refs))
+	for subID, subRefs := range refs {
+		for _, id := range subRefs {
+			if a, ok := found[id]; ok {
+				a.DownloadURL = fmt.Sprintf("%s%s%s/attachments/%s", s.uploads.BaseURL, pathPrefix, subID, id)
+				s.withSignedURL(ctx, &a)
+				out[subID] = append(out[subID], a)
+			}
+		}
+	}
+	return out, nil
+}
+
+// formatBytes renders a size limit for error messages
+func formatBytes(n int64) string {
+	if n >= 1<<20 && n%(1<<20) == 0 {
+		return fmt.Sprintf("%d MB", n>>20)
+	}
+	if n >= 1<<10 {
+		return fmt.Sprintf("%d KB", n>>10)
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
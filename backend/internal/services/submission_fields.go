@@ -0,0 +1,431 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+)
+
+// SubmissionFieldType is the kind of value a custom submission field takes
+type SubmissionFieldType string
+
+const (
+	FieldTypeURL        SubmissionFieldType = "url"
+	FieldTypeGithubRepo SubmissionFieldType = "github-repo"
+	FieldTypeText       SubmissionFieldType = "text"
+	FieldTypeLongText   SubmissionFieldType = "long-text"
+	FieldTypeNumber     SubmissionFieldType = "number"
+	FieldTypeEmail      SubmissionFieldType = "email"
+	FieldTypeSelect     SubmissionFieldType = "select"
+	FieldTypeCheckbox   SubmissionFieldType = "checkbox"
+	FieldTypeFile       SubmissionFieldType = "file" // value is the ID of an uploaded attachment
+)
+
+// maxFileFields caps the file fields of one problem statement (and so the files in one upload)
+const maxFileFields = 5
+
+// defaultAllowedFileTypes applies to file fields that don't list allowed_types: documents, slides and images
+var defaultAllowedFileTypes = []string{
+	"application/pdf",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	"image/png", "image/jpeg", "image/gif", "image/webp", "image/svg+xml",
+}
+
+// Length caps for fields that don't set max_length
+const (
+	defaultTextMaxLength     = 500
+	defaultLongTextMaxLength = 10000
+)
+
+var customFieldKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// PSSubmissionFieldsConfig is the submission field schema of a problem statement (problem_statements.submission_fields).
+// The standard fields are switched on or off; custom fields carry their own definition.
+type PSSubmissionFieldsConfig struct {
+	Linkedin     bool                    `json:"linkedin"`
+	Github       bool                    `json:"github"`
+	Live         bool                    `json:"live"`
+	ExtraNotes   bool                    `json:"extra_notes"`
+	CustomFields []CustomFieldDefinition `json:"custom_fields,omitempty"`
+}
+
+// CustomFieldDefinition describes one custom submission field. Configs saved before field types
+// existed only have key and label, which reads as an optional text field.
+type CustomFieldDefinition struct {
+	Key       string              `json:"key"`   // e.g., "custom_1", "custom_2"
+	Label     string              `json:"label"` // e.g., "Demo Video", "Documentation"
+	Type      SubmissionFieldType `json:"type"`
+	Required  bool                `json:"required,omitempty"`
+	Pattern   string              `json:"pattern,omitempty"` // regular expression the value must match
+	MinLength int                 `json:"min_length,omitempty"`
+	MaxLength int                 `json:"max_length,omitempty"`
+	HelpText  string              `json:"help_text,omitempty"`
+	Options   []string            `json:"options,omitempty"` // choices of a select field
+	// File fields: accepted content types ("application/pdf", "image/*") and size cap in bytes
+	// (0 = the server's upload limit, which also caps larger values)
+	AllowedTypes []string `json:"allowed_types,omitempty"`
+	MaxBytes     int64    `json:"max_bytes,omitempty"`
+	Private      bool     `json:"private,omitempty"` // shown to admins only, never to judges
+}
+
+// SubmissionValidationError lists what is wrong with each invalid submission field, keyed by
+// field ("linkedin_url", "github_url", "live_url", "extra_notes" or a custom field key).
+type SubmissionValidationError struct {
+	Fields map[string]string
+}
+
+func (e *SubmissionValidationError) Error() string {
+	return "some submission fields are invalid"
+}
+
+// defaultSubmissionFields is the schema of a problem statement without a stored config: every standard field on.
+var defaultSubmissionFields = PSSubmissionFieldsConfig{Linkedin: true, Github: true, Live: true, ExtraNotes: true}
+
+// ParseSubmissionFields reads and checks a submission field schema given by an admin.
+// An empty string is the default schema.
+func ParseSubmissionFields(raw string) (*PSSubmissionFieldsConfig, error) {
+	if strings.TrimSpace(raw) == "" {
+		cfg := defaultSubmissionFields
+		return &cfg, nil
+	}
+	var cfg PSSubmissionFieldsConfig
+	if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+		return nil, fmt.Errorf("submission_fields must be a JSON object: %v", err)
+	}
+	if err := cfg.Normalize(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// submissionFieldsOf returns a problem statement's schema. Stored configs are read leniently so
+// older or hand-edited ones keep working; unknown field types fall back to text.
+func submissionFieldsOf(ps *models.PSItem) PSSubmissionFieldsConfig {
+	if !ps.SubmissionFields.Valid || ps.SubmissionFields.String == "" {
+		return defaultSubmissionFields
+	}
+	// Omitted booleans = false so only configured fields show
+	var cfg PSSubmissionFieldsConfig
+	_ = json.Unmarshal([]byte(ps.SubmissionFields.String), &cfg)
+	_ = cfg.Normalize()
+	return cfg
+}
+
+// normalizeFieldType maps "" to text and accepts underscores ("long_text") for dashes.
+func normalizeFieldType(t SubmissionFieldType) (SubmissionFieldType, bool) {
+	t = SubmissionFieldType(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(string(t))), "_", "-"))
+	switch t {
+	case "":
+		return FieldTypeText, true
+	case FieldTypeURL, FieldTypeGithubRepo, FieldTypeText, FieldTypeLongText,
+		FieldTypeNumber, FieldTypeEmail, FieldTypeSelect, FieldTypeCheckbox, FieldTypeFile:
+		return t, true
+	}
+	return FieldTypeText, false
+}
+
+// Normalize fills in field types and trims definitions, then checks the schema: keys are unique and
+// don't clash with standard fields, patterns compile, length limits make sense, select fields have options
+// and file fields list valid content types.
+// Every field is normalized even when an error is returned.
+func (cfg *PSSubmissionFieldsConfig) Normalize() error {
+	var problems []string
+	seen := map[string]bool{"linkedin_url": true, "github_url": true, "live_url": true, "extra_notes": true}
+	fileFields := 0
+	for i := range cfg.CustomFields {
+		f := &cfg.CustomFields[i]
+		f.Key = strings.TrimSpace(f.Key)
+		f.Label = strings.TrimSpace(f.Label)
+		f.HelpText = strings.TrimSpace(f.HelpText)
+		t, ok := normalizeFieldType(f.Type)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: unknown type %q", f.Key, f.Type))
+		}
+		f.Type = t
+		options := f.Options[:0]
+		for _, o := range f.Options {
+			if o = strings.TrimSpace(o); o != "" {
+				options = append(options, o)
+			}
+		}
+		f.Options = options
+
+		switch {
+		case !customFieldKeyPattern.MatchString(f.Key):
+			problems = append(problems, fmt.Sprintf("custom field %d: key must be 1-64 letters, digits, - or _", i+1))
+		case seen[f.Key]:
+			problems = append(problems, fmt.Sprintf("%s: duplicate key", f.Key))
+		}
+		seen[f.Key] = true
+		if f.Label == "" {
+			problems = append(problems, fmt.Sprintf("%s: label is required", f.Key))
+		}
+		if f.Pattern != "" {
+			if _, err := regexp.Compile(f.Pattern); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: invalid pattern: %v", f.Key, err))
+			}
+		}
+		if f.MinLength < 0 || f.MaxLength < 0 || (f.MaxLength > 0 && f.MinLength > f.MaxLength) {
+			problems = append(problems, fmt.Sprintf("%s: invalid length limits", f.Key))
+		}
+		if f.Type == FieldTypeSelect && len(f.Options) == 0 {
+			problems = append(problems, fmt.Sprintf("%s: select fields need options", f.Key))
+		}
+		if f.Type == FieldTypeFile {
+			fileFields++
+			types := f.AllowedTypes[:0]
+			for _, t := range f.AllowedTypes {
+				t = strings.ToLower(strings.TrimSpace(t))
+				if t == "" {
+					continue
+				}
+				if parts := strings.Split(t, "/"); len(parts) != 2 || parts[0] == "" || parts[0] == "*" || parts[1] == "" {
+					problems = append(problems, fmt.Sprintf("%s: invalid content type %q", f.Key, t))
+				}
+				types = append(types, t)
+			}
+			f.AllowedTypes = types
+			if f.MaxBytes < 0 {
+				problems = append(problems, fmt.Sprintf("%s: max_bytes must not be negative", f.Key))
+			}
+		}
+	}
+	if fileFields > maxFileFields {
+		problems = append(problems, fmt.Sprintf("at most %d file fields are allowed", maxFileFields))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid submission fields: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// submissionValues are a team's submitted values before and after validation
+type submissionValues struct {
+	LinkedinURL string
+	GithubURL   string
+	LiveURL     string
+	ExtraNotes  string
+	Custom      map[string]string
+}
+
+// validate checks a team's values against the schema and returns them normalized. Standard fields
+// that are switched off and unknown custom keys are dropped; standard links must be valid URLs when given.
+// All invalid fields are reported together in a *SubmissionValidationError.
+func (cfg PSSubmissionFieldsConfig) validate(in submissionValues) (submissionValues, error) {
+	out := submissionValues{Custom: map[string]string{}}
+	errs := map[string]string{}
+	check := func(key string, on bool, field CustomFieldDefinition, value string) string {
+		if !on {
+			return ""
+		}
+		v, problem := field.validate(value)
+		if problem != "" {
+			errs[key] = problem
+		}
+		return v
+	}
+	out.LinkedinURL = check("linkedin_url", cfg.Linkedin, CustomFieldDefinition{Label: "LinkedIn URL", Type: FieldTypeURL}, in.LinkedinURL)
+	out.GithubURL = check("github_url", cfg.Github, CustomFieldDefinition{Label: "GitHub repository", Type: FieldTypeGithubRepo}, in.GithubURL)
+	out.LiveURL = check("live_url", cfg.Live, CustomFieldDefinition{Label: "Live URL", Type: FieldTypeURL}, in.LiveURL)
+	out.ExtraNotes = check("extra_notes", cfg.ExtraNotes, CustomFieldDefinition{Label: "Extra notes", Type: FieldTypeLongText}, in.ExtraNotes)
+	for _, f := range cfg.CustomFields {
+		if v := check(f.Key, true, f, in.Custom[f.Key]); v != "" {
+			out.Custom[f.Key] = v
+		}
+	}
+	if len(errs) > 0 {
+		return out, &SubmissionValidationError{Fields: errs}
+	}
+	return out, nil
+}
+
+// validate checks one value against the field and returns it normalized, or what is wrong with it.
+func (f CustomFieldDefinition) validate(value string) (string, string) {
+	v := strings.TrimSpace(value)
+	if f.Type == FieldTypeCheckbox {
+		checked, err := strconv.ParseBool(v)
+		if v != "" && err != nil {
+			return "", "must be checked or unchecked"
+		}
+		if f.Required && !checked {
+			return "", "must be checked"
+		}
+		return strconv.FormatBool(checked), ""
+	}
+	if v == "" {
+		if f.Required {
+			return "", "is required"
+		}
+		return "", ""
+	}
+	if f.Type == FieldTypeFile {
+		// Uploads are checked when they are received; the value only has to name one
+		if _, err := uuid.Parse(v); err != nil {
+			return "", "must be an uploaded file"
+		}
+		return v, ""
+	}
+
+	switch f.Type {
+	case FieldTypeURL:
+		u, ok := parseWebURL(v)
+		if !ok {
+			return "", "must be a valid http(s) link"
+		}
+		v = u.String()
+	case FieldTypeGithubRepo:
+		u, ok := parseWebURL(v)
+		if !ok || (strings.ToLower(u.Host) != "github.com" && strings.ToLower(u.Host) != "www.github.com") {
+			return "", "must be a GitHub repository link (https://github.com/owner/repo)"
+		}
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return "", "must link to a repository (https://github.com/owner/repo)"
+		}
+		v = u.String()
+	case FieldTypeText:
+		if strings.ContainsAny(v, "\r\n") {
+			return "", "must be a single line"
+		}
+	case FieldTypeNumber:
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return "", "must be a number"
+		}
+	case FieldTypeEmail:
+		addr, err := mail.ParseAddress(v)
+		if err != nil || addr.Address != v {
+			return "", "must be a valid email address"
+		}
+	case FieldTypeSelect:
+		found := false
+		for _, o := range f.Options {
+			if o == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", "must be one of: " + strings.Join(f.Options, ", ")
+		}
+	}
+
+	length := utf8.RuneCountInString(v)
+	maxLength := f.MaxLength
+	if maxLength == 0 {
+		maxLength = defaultTextMaxLength
+		if f.Type == FieldTypeLongText {
+			maxLength = defaultLongTextMaxLength
+		}
+	}
+	if length > maxLength {
+		return "", fmt.Sprintf("must be at most %d characters", maxLength)
+	}
+	if length < f.MinLength {
+		return "", fmt.Sprintf("must be at least %d characters", f.MinLength)
+	}
+	if f.Pattern != "" {
+		// Patterns are checked when the schema is saved; a broken one in an old config is skipped
+		if re, err := regexp.Compile(f.Pattern); err == nil && !re.MatchString(v) {
+			return "", "is not in the expected format"
+		}
+	}
+	return v, ""
+}
+
+// acceptsType reports whether a file field takes the (sniffed) content type
+func (f CustomFieldDefinition) acceptsType(contentType string) bool {
+	allowed := f.AllowedTypes
+	if len(allowed) == 0 {
+		allowed = defaultAllowedFileTypes
+	}
+	for _, t := range allowed {
+		if t == contentType || (strings.HasSuffix(t, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(t, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// fileSizeLimit is the file field's cap, never above the server's upload limit
+func (f CustomFieldDefinition) fileSizeLimit(serverLimit int64) int64 {
+	if f.MaxBytes > 0 && f.MaxBytes < serverLimit {
+		return f.MaxBytes
+	}
+	return serverLimit
+}
+
+// fileFieldIDs returns the attachment IDs named by a submission's file fields, keyed by field
+func (cfg PSSubmissionFieldsConfig) fileFieldIDs(custom map[string]string) map[string]uuid.UUID {
+	ids := map[string]uuid.UUID{}
+	for _, f := range cfg.CustomFields {
+		if f.Type != FieldTypeFile {
+			continue
+		}
+		if id, err := uuid.Parse(custom[f.Key]); err == nil {
+			ids[f.Key] = id
+		}
+	}
+	return ids
+}
+
+// hiddenFromJudges reports whether judges must not see the field's value: contact details
+// (email fields) and fields marked private.
+func (f CustomFieldDefinition) hiddenFromJudges() bool {
+	return f.Private || f.Type == FieldTypeEmail
+}
+
+// JudgeHiddenFields returns the custom field keys of a problem statement's schema whose values
+// judges must not see.
+func JudgeHiddenFields(psFields sql.NullString) map[string]bool {
+	hidden := map[string]bool{}
+	for _, f := range submissionFieldsOf(&models.PSItem{SubmissionFields: psFields}).CustomFields {
+		if f.hiddenFromJudges() {
+			hidden[f.Key] = true
+		}
+	}
+	return hidden
+}
+
+// redactCustomFields drops the hidden keys from a custom_fields JSON object. A value that isn't
+// an object is dropped whole rather than passed through unchecked.
+func redactCustomFields(raw []byte, hidden map[string]bool) []byte {
+	if len(raw) == 0 || len(hidden) == 0 {
+		return raw
+	}
+	var values map[string]json.RawMessage
+	if json.Unmarshal(raw, &values) != nil {
+		return nil
+	}
+	for key := range hidden {
+		delete(values, key)
+	}
+	out, err := json.Marshal(values)
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+// parseWebURL parses an http(s) link with a real host; a missing scheme is taken as https.
+func parseWebURL(v string) (*url.URL, bool) {
+	if strings.ContainsAny(v, " \t\r\n") {
+		return nil, false
+	}
+	if !strings.Contains(v, "://") {
+		v = "https://" + v
+	}
+	u, err := url.Parse(v)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || !strings.Contains(u.Hostname(), ".") {
+		return nil, false
+	}
+	return u, true
+}
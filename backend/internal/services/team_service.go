@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"unicode"
 
 	"github.com/google/uuid"
 	"github.com/rift26/backend/internal/models"
@@ -15,63 +16,99 @@ import (
 type TeamService struct {
 	teamRepo         *repository.TeamRepository
 	announcementRepo *repository.AnnouncementRepository
+	cityService      *CityService
 }
 
 func NewTeamService(
 	teamRepo *repository.TeamRepository,
 	announcementRepo *repository.AnnouncementRepository,
+	cityService *CityService,
 ) *TeamService {
 	return &TeamService{
 		teamRepo:         teamRepo,
 		announcementRepo: announcementRepo,
+		cityService:      cityService,
 	}
 }
 
-// SearchTeams performs fuzzy search and returns masked emails with leader details
-func (s *TeamService) SearchTeams(ctx context.Context, query string) ([]models.TeamSearchResponse, error) {
-	teams, err := s.teamRepo.SearchByName(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to search teams: %w", err)
-	}
+// Public team search limits. The endpoint is unauthenticated, so pages are small and only the
+// first searchMaxResults matches are reachable to make walking the whole team list impractical.
+const (
+	searchMinAlnum    = 3
+	searchMinDistinct = 2
+	searchPageSize    = 10
+	searchMaxPageSize = 10
+	searchMaxResults  = 50
+)
 
-	var results []models.TeamSearchResponse
-	for _, team := range teams {
-		// Get team members to find leader
-		members, err := s.teamRepo.GetMembersByTeamID(ctx, team.ID)
-		if err != nil || len(members) == 0 {
-			continue // Skip teams without members
+// NormalizeSearchQuery lower-cases and collapses whitespace in a search query and rejects queries
+// too weak to be a real name (fewer than 3 letters/digits, or a single repeated character).
+func NormalizeSearchQuery(query string) (string, error) {
+	q := strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	alnum := 0
+	distinct := make(map[rune]bool)
+	for _, r := range q {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			alnum++
+			distinct[r] = true
 		}
+	}
+	if alnum < searchMinAlnum || len(distinct) < searchMinDistinct {
+		return "", fmt.Errorf("search query must contain at least %d letters or digits", searchMinAlnum)
+	}
+	return q, nil
+}
 
-		// Find leader
-		var leaderName string
-		var leaderEmail string
-		for _, member := range members {
-			if member.Role == "leader" {
-				leaderName = member.Name
-				leaderEmail = member.Email
-				break
-			}
-		}
+// SearchTeams performs ranked fuzzy search over team, leader and member names and returns
+// one page of results with masked leader emails from the event (uuid.Nil = current).
+// query must come from NormalizeSearchQuery.
+func (s *TeamService) SearchTeams(ctx context.Context, eventID uuid.UUID, query string, page, pageSize int) (*models.TeamSearchPage, error) {
+	if pageSize <= 0 {
+		pageSize = searchPageSize
+	}
+	if pageSize > searchMaxPageSize {
+		pageSize = searchMaxPageSize
+	}
+	if page <= 0 {
+		page = 1
+	}
+	result := &models.TeamSearchPage{Teams: []models.TeamSearchResponse{}, Page: page, PageSize: pageSize}
 
-		// If no leader found, use first member
-		if leaderName == "" && len(members) > 0 {
-			leaderName = members[0].Name
-			leaderEmail = members[0].Email
-		}
+	offset := (page - 1) * pageSize
+	if offset >= searchMaxResults {
+		return result, nil
+	}
+	limit := pageSize
+	if offset+limit > searchMaxResults {
+		limit = searchMaxResults - offset
+	}
+
+	hits, total, err := s.teamRepo.Search(ctx, eventID, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search teams: %w", err)
+	}
+	if total > searchMaxResults {
+		total = searchMaxResults
+	}
 
-		results = append(results, models.TeamSearchResponse{
+	for _, hit := range hits {
+		team := hit.Team
+		result.Teams = append(result.Teams, models.TeamSearchResponse{
 			ID:          team.ID,
 			TeamName:    team.TeamName,
-			LeaderName:  leaderName,
-			MaskedEmail: utils.MaskEmail(leaderEmail),
+			LeaderName:  hit.LeaderName,
+			MaskedEmail: utils.MaskEmail(hit.LeaderEmail),
 			City:        team.City,
 			Status:      team.Status,
 			MemberCount: team.MemberCount, // Use count from database
 			RSVPLocked:  team.RSVPLocked,  // Include RSVP lock status
+			Score:       hit.Score,
 		})
 	}
-
-	return results, nil
+	result.Count = len(result.Teams)
+	result.Total = total
+	result.HasMore = offset+len(hits) < total
+	return result, nil
 }
 
 // GetTeamByID retrieves full team details
@@ -171,6 +208,19 @@ func (s *TeamService) SubmitRSVP(ctx context.Context, teamID uuid.UUID, city mod
 		return fmt.Errorf("team RSVP is already locked")
 	}
 
+	// Resolve the city (code, name or alias) to an active city code
+	code, ok, err := s.cityService.Normalize(ctx, string(city))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("invalid city: %s", city)
+	}
+	if cfg, err := s.cityService.Get(ctx, code); err != nil || cfg == nil || !cfg.IsActive {
+		return fmt.Errorf("city %s is not accepting registrations", code)
+	}
+	city = models.City(code)
+
 	// Validate member updates
 	if len(memberUpdates) == 0 {
 		return fmt.Errorf("at least one member required")
@@ -286,14 +336,13 @@ func (s *TeamService) SubmitRSVP(ctx context.Context, teamID uuid.UUID, city mod
 }
 
 // GetDashboard retrieves team dashboard data including QR code and announcements
-func (s *TeamService) GetDashboard(ctx context.Context, token string) (*models.Team, []models.Announcement, string, error) {
-	// Get team by dashboard token
-	team, err := s.teamRepo.GetByDashboardToken(ctx, token)
+func (s *TeamService) GetDashboard(ctx context.Context, teamID uuid.UUID) (*models.Team, []models.Announcement, string, error) {
+	team, err := s.teamRepo.GetDashboardTeam(ctx, teamID)
 	if err != nil {
 		return nil, nil, "", fmt.Errorf("failed to get team: %w", err)
 	}
 	if team == nil {
-		return nil, nil, "", fmt.Errorf("invalid dashboard token")
+		return nil, nil, "", fmt.Errorf("team not found")
 	}
 
 	// Get active announcements
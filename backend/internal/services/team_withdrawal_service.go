@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+)
+
+// TeamWithdrawalService handles leader withdrawals, admin no-show marking and reinstatement.
+type TeamWithdrawalService struct {
+	teamRepo              *repository.TeamRepository
+	emailOTPService       *EmailOTPService
+	seatAllocationService *SeatAllocationService
+	cityService           *CityService
+	phaseService          *PhaseService
+}
+
+func NewTeamWithdrawalService(teamRepo *repository.TeamRepository, emailOTPService *EmailOTPService, seatAllocationService *SeatAllocationService, cityService *CityService, phaseService *PhaseService) *TeamWithdrawalService {
+	return &TeamWithdrawalService{
+		teamRepo:              teamRepo,
+		emailOTPService:       emailOTPService,
+		seatAllocationService: seatAllocationService,
+		cityService:           cityService,
+		phaseService:          phaseService,
+	}
+}
+
+// ErrCheckinOpen is returned by MarkNoShows while the city's check-in phase is still open.
+var ErrCheckinOpen = errors.New("check-in is still open for this city; close the checkin phase first or pass force")
+
+// NoShowResult summarises a bulk no-show run.
+type NoShowResult struct {
+	City              string             `json:"city"`
+	DryRun            bool               `json:"dry_run"`
+	TeamIDs           []uuid.UUID        `json:"team_ids"`
+	Marked            int                `json:"marked"`
+	SeatReleaseErrors []SeatReleaseError `json:"seat_release_errors"` // marked teams whose seat is still allocated
+}
+
+// SeatReleaseError is a marked team whose seat allocation could not be released.
+type SeatReleaseError struct {
+	TeamID uuid.UUID `json:"team_id"`
+	Error  string    `json:"error"`
+}
+
+// RequestWithdrawal sends the leader an OTP to confirm withdrawal.
+func (s *TeamWithdrawalService) RequestWithdrawal(ctx context.Context, teamID uuid.UUID, email string) error {
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		return fmt.Errorf("get team: %w", err)
+	}
+	if team == nil {
+		return fmt.Errorf("team not found")
+	}
+	if team.Status.IsInactive() {
+		return fmt.Errorf("team has already withdrawn")
+	}
+	return s.emailOTPService.SendWithdrawalOTP(ctx, teamID, email)
+}
+
+// Withdraw verifies the leader's OTP and withdraws the team, releasing its desk and seat.
+func (s *TeamWithdrawalService) Withdraw(ctx context.Context, teamID uuid.UUID, email, otpCode, reason string) error {
+	if err := s.emailOTPService.VerifyWithdrawalOTP(ctx, teamID, email, otpCode); err != nil {
+		return err
+	}
+	ok, err := s.teamRepo.MarkInactive(ctx, teamID, models.StatusWithdrawn, strings.TrimSpace(reason), email)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("team has already withdrawn")
+	}
+	if err := s.releaseSeat(teamID); err != nil {
+		log.Printf("[WITHDRAWAL] Failed to release seat for team %s: %v", teamID, err)
+	}
+	return nil
+}
+
+// MarkNoShows marks every team of the event (uuid.Nil = current) in the city that confirmed (RSVP II)
+// but never checked in as no_show. With dryRun the candidates are returned without changing anything.
+// While the city's checkin phase is open it refuses with ErrCheckinOpen unless force is set.
+func (s *TeamWithdrawalService) MarkNoShows(ctx context.Context, eventID uuid.UUID, city string, dryRun, force bool, by string) (*NoShowResult, error) {
+	code, ok, err := s.cityService.Normalize(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid city: %s", city)
+	}
+	city = code
+
+	ids, err := s.teamRepo.GetNoShowCandidateIDs(ctx, eventID, city)
+	if err != nil {
+		return nil, err
+	}
+	result := &NoShowResult{City: city, DryRun: dryRun, TeamIDs: ids, SeatReleaseErrors: []SeatReleaseError{}}
+	if result.TeamIDs == nil {
+		result.TeamIDs = []uuid.UUID{}
+	}
+	if dryRun {
+		return result, nil
+	}
+	if !force {
+		st, err := s.phaseService.EventStatus(ctx, eventID, models.PhaseCheckin, city)
+		if err != nil {
+			return nil, err
+		}
+		if st.IsOpen() {
+			return result, ErrCheckinOpen
+		}
+	}
+	for _, id := range ids {
+		ok, err := s.teamRepo.MarkInactive(ctx, id, models.StatusNoShow, "Did not check in", by)
+		if err != nil {
+			return result, err
+		}
+		if ok {
+			result.Marked++
+			if err := s.releaseSeat(id); err != nil {
+				result.SeatReleaseErrors = append(result.SeatReleaseErrors, SeatReleaseError{TeamID: id, Error: err.Error()})
+			}
+		}
+	}
+	return result, nil
+}
+
+// Reinstate restores a withdrawn / no-show team to its previous status (admin).
+func (s *TeamWithdrawalService) Reinstate(ctx context.Context, teamID uuid.UUID) (models.TeamStatus, error) {
+	return s.teamRepo.Reinstate(ctx, teamID)
+}
+
+// releaseSeat frees the team's seat allocation if any. Callers report failures; the team stays inactive.
+func (s *TeamWithdrawalService) releaseSeat(teamID uuid.UUID) error {
+	if s.seatAllocationService == nil {
+		return nil
+	}
+	_, err := s.seatAllocationService.ReleaseSeat(teamID)
+	return err
+}
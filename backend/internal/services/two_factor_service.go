@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rift26/backend/internal/models"
+	"github.com/rift26/backend/internal/repository"
+	"github.com/rift26/backend/pkg/qrcode"
+	"github.com/rift26/backend/pkg/totp"
+)
+
+const (
+	twoFactorIssuer       = "RIFT '26"
+	twoFactorChallengeTTL = 5 * time.Minute
+	twoFactorMaxAttempts  = 5
+	twoFactorSkew         = 1 // accept the previous and next 30s step for clock drift
+	recoveryCodeCount     = 10
+)
+
+// TwoFactorService handles TOTP enrollment, recovery codes and the second login step for
+// admins and volunteer admins.
+type TwoFactorService struct {
+	repo     *repository.TwoFactorRepository
+	sessions *SessionService
+	gcm      cipher.AEAD
+	enforced map[models.UserRole]bool
+}
+
+// NewTwoFactorService creates the service. secretKey encrypts TOTP secrets at rest; enforced lists
+// roles that must enroll before they can log in.
+func NewTwoFactorService(repo *repository.TwoFactorRepository, sessions *SessionService, secretKey string, enforced []models.UserRole) *TwoFactorService {
+	key := sha256.Sum256([]byte(secretKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		panic(fmt.Sprintf("two-factor cipher: %v", err))
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(fmt.Sprintf("two-factor cipher: %v", err))
+	}
+	s := &TwoFactorService{repo: repo, sessions: sessions, gcm: gcm, enforced: make(map[models.UserRole]bool)}
+	for _, role := range enforced {
+		s.enforced[role] = true
+	}
+	return s
+}
+
+// Login finishes a password login: it starts a session, or when 2FA is enabled (or enforced but
+// not yet set up) returns a challenge the client completes through VerifyChallenge.
+func (s *TwoFactorService) Login(ctx context.Context, subject models.SessionSubject, client models.SessionClient) (*models.LoginResult, error) {
+	tf, err := s.repo.Get(ctx, subject.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+	enabled := tf != nil && tf.EnabledAt != nil
+	if !enabled && !s.enforced[subject.Role] {
+		tokens, err := s.sessions.Issue(ctx, subject, client)
+		if err != nil {
+			return nil, err
+		}
+		return &models.LoginResult{Tokens: tokens}, nil
+	}
+
+	token, hash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	challenge := &models.TwoFactorChallenge{
+		SubjectID: subject.SubjectID,
+		Role:      subject.Role,
+		Email:     subject.Email,
+		City:      optional(subject.City),
+		Setup:     !enabled,
+		ExpiresAt: time.Now().Add(twoFactorChallengeTTL),
+	}
+	if err := s.repo.CreateChallenge(ctx, challenge, hash); err != nil {
+		return nil, err
+	}
+	return &models.LoginResult{
+		TwoFactorRequired:      enabled,
+		TwoFactorSetupRequired: !enabled,
+		ChallengeToken:         token,
+	}, nil
+}
+
+// SetupChallenge starts enrollment for a user whose role requires 2FA but who has none yet.
+func (s *TwoFactorService) SetupChallenge(ctx context.Context, challengeToken string) (*models.TwoFactorEnrollment, error) {
+	c, err := s.openChallenge(ctx, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !c.Setup {
+		return nil, fmt.Errorf("two-factor authentication is already set up")
+	}
+	return s.BeginEnrollment(ctx, c.SubjectID, c.Role, c.Email)
+}
+
+// VerifyChallenge checks the second factor and starts the session. For setup challenges it also
+// confirms enrollment and returns the new recovery codes.
+func (s *TwoFactorService) VerifyChallenge(ctx context.Context, req models.TwoFactorVerifyRequest, client models.SessionClient) (*models.TokenPair, []string, error) {
+	c, err := s.openChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var recoveryCodes []string
+	if c.Setup {
+		recoveryCodes, err = s.ConfirmEnrollment(ctx, c.SubjectID, req.Code)
+	} else if req.RecoveryCode != "" {
+		err = s.useRecoveryCode(ctx, c.SubjectID, req.RecoveryCode)
+	} else {
+		err = s.checkCode(ctx, c.SubjectID, req.Code)
+	}
+	if err != nil {
+		if n, _ := s.repo.RecordAttempt(ctx, c.ID); n >= twoFactorMaxAttempts {
+			_, _ = s.repo.ConsumeChallenge(ctx, c.ID)
+			return nil, nil, fmt.Errorf("too many attempts, please log in again")
+		}
+		return nil, nil, err
+	}
+
+	if ok, err := s.repo.ConsumeChallenge(ctx, c.ID); err != nil || !ok {
+		return nil, nil, fmt.Errorf("invalid or expired challenge")
+	}
+	subject := models.SessionSubject{SubjectID: c.SubjectID, Role: c.Role, Email: c.Email}
+	if c.City != nil {
+		subject.City = *c.City
+	}
+	tokens, err := s.sessions.Issue(ctx, subject, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tokens, recoveryCodes, nil
+}
+
+// Status reports whether 2FA is enabled and whether the role requires it.
+func (s *TwoFactorService) Status(ctx context.Context, subjectID uuid.UUID, role models.UserRole) (*models.TwoFactorStatus, error) {
+	status := &models.TwoFactorStatus{Enforced: s.enforced[role]}
+	tf, err := s.repo.Get(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	if tf != nil && tf.EnabledAt != nil {
+		status.Enabled, status.EnabledAt = true, tf.EnabledAt
+		if status.RecoveryCodesRemaining, err = s.repo.CountRecoveryCodes(ctx, subjectID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// BeginEnrollment generates a new secret and returns it with a provisioning QR code. 2FA is not
+// active until ConfirmEnrollment succeeds.
+func (s *TwoFactorService) BeginEnrollment(ctx context.Context, subjectID uuid.UUID, role models.UserRole, email string) (*models.TwoFactorEnrollment, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.seal(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SavePending(ctx, subjectID, role, sealed); err != nil {
+		return nil, err
+	}
+	uri := totp.ProvisioningURI(twoFactorIssuer, email, secret)
+	qr, err := qrcode.GenerateTextQR(uri)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TwoFactorEnrollment{Secret: secret, ProvisioningURI: uri, QRCode: qr}, nil
+}
+
+// ConfirmEnrollment activates a pending enrollment with a code from the authenticator app and
+// returns one-time recovery codes (shown once).
+func (s *TwoFactorService) ConfirmEnrollment(ctx context.Context, subjectID uuid.UUID, code string) ([]string, error) {
+	tf, err := s.repo.Get(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	if tf == nil || tf.EnabledAt != nil {
+		return nil, fmt.Errorf("no pending two-factor enrollment")
+	}
+	secret, err := s.open(tf.SecretEncrypted)
+	if err != nil {
+		return nil, err
+	}
+	counter, ok := totp.Validate(secret, code, time.Now(), twoFactorSkew)
+	if !ok {
+		return nil, fmt.Errorf("invalid code")
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Enable(ctx, subjectID, counter, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable turns 2FA off after checking a current code. Not allowed where 2FA is enforced.
+func (s *TwoFactorService) Disable(ctx context.Context, subjectID uuid.UUID, role models.UserRole, code string) error {
+	if s.enforced[role] {
+		return fmt.Errorf("two-factor authentication is required for your role")
+	}
+	if err := s.checkCode(ctx, subjectID, code); err != nil {
+		return err
+	}
+	_, err := s.repo.Delete(ctx, subjectID)
+	return err
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after checking a current code.
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, subjectID uuid.UUID, code string) ([]string, error) {
+	if err := s.checkCode(ctx, subjectID, code); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceRecoveryCodes(ctx, subjectID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Reset removes a locked-out user's 2FA and ends their sessions; they enroll again on next login.
+func (s *TwoFactorService) Reset(ctx context.Context, subjectID uuid.UUID) error {
+	ok, err := s.repo.Delete(ctx, subjectID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("two-factor authentication is not set up for this account")
+	}
+	_, err = s.sessions.RevokeAll(ctx, subjectID)
+	return err
+}
+
+func (s *TwoFactorService) openChallenge(ctx context.Context, token string) (*models.TwoFactorChallenge, error) {
+	c, err := s.repo.GetChallenge(ctx, hashRefreshToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if c == nil || c.Attempts >= twoFactorMaxAttempts {
+		return nil, fmt.Errorf("invalid or expired challenge, please log in again")
+	}
+	return c, nil
+}
+
+// checkCode validates a TOTP code for an enabled enrollment and rejects replays.
+func (s *TwoFactorService) checkCode(ctx context.Context, subjectID uuid.UUID, code string) error {
+	tf, err := s.repo.Get(ctx, subjectID)
+	if err != nil {
+		return err
+	}
+	if tf == nil || tf.EnabledAt == nil {
+		return fmt.Errorf("two-factor authentication is not enabled")
+	}
+	secret, err := s.open(tf.SecretEncrypted)
+	if err != nil {
+		return err
+	}
+	counter, ok := totp.Validate(secret, code, time.Now(), twoFactorSkew)
+	if !ok {
+		return fmt.Errorf("invalid code")
+	}
+	accepted, err := s.repo.AcceptCounter(ctx, subjectID, counter)
+	if err != nil {
+		return err
+	}
+	if !accepted {
+		return fmt.Errorf("code already used, wait for the next one")
+	}
+	return nil
+}
+
+func (s *TwoFactorService) useRecoveryCode(ctx context.Context, subjectID uuid.UUID, code string) error {
+	ok, err := s.repo.UseRecoveryCode(ctx, subjectID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("invalid recovery code")
+	}
+	return nil
+}
+
+func (s *TwoFactorService) seal(secret string) (string, error) {
+	nonce := make([]byte, s.gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(s.gcm.Seal(nonce, nonce, []byte(secret), nil)), nil
+}
+
+func (s *TwoFactorService) open(sealed string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < s.gcm.NonceSize() {
+		return "", fmt.Errorf("failed to decrypt two-factor secret")
+	}
+	n := s.gcm.NonceSize()
+	plain, err := s.gcm.Open(nil, raw[:n], raw[n:], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt two-factor secret (was TWO_FACTOR_KEY changed?)")
+	}
+	return string(plain), nil
+}
+
+// newRecoveryCodes returns recovery codes like "k3f9-x2m7" and their hashes.
+func newRecoveryCodes() (codes, hashes []string, err error) {
+	const alphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+		}
+		for j := range b {
+			b[j] = alphabet[int(b[j])%len(alphabet)]
+		}
+		code := string(b[:4]) + "-" + string(b[4:])
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode normalizes case and separators before hashing.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,28 +1,28 @@
 package services
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/rift26/backend/internal/models"
 	"github.com/rift26/backend/internal/repository"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type VolunteerService struct {
-	repo *repository.VolunteerRepository
+	repo     *repository.VolunteerRepository
+	sessions *SessionService
 }
 
-func NewVolunteerService(repo *repository.VolunteerRepository) *VolunteerService {
-	return &VolunteerService{repo: repo}
+func NewVolunteerService(repo *repository.VolunteerRepository, sessions *SessionService) *VolunteerService {
+	return &VolunteerService{repo: repo, sessions: sessions}
 }
 
-// Login authenticates a volunteer and returns a JWT token
-func (s *VolunteerService) Login(email, password string) (*models.VolunteerLoginResponse, error) {
+// Login authenticates a volunteer and starts a session
+func (s *VolunteerService) Login(email, password string, client models.SessionClient) (*models.VolunteerLoginResponse, error) {
 	volunteer, err := s.repo.GetByEmail(email)
 	if err != nil {
 		return nil, fmt.Errorf("invalid credentials")
@@ -34,8 +34,13 @@ func (s *VolunteerService) Login(email, password string) (*models.VolunteerLogin
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
-	// Generate JWT token (no table_id needed)
-	token, err := s.generateToken(volunteer)
+	// Start a session (no table_id needed)
+	tokens, err := s.sessions.Issue(context.Background(), models.SessionSubject{
+		SubjectID: volunteer.ID,
+		Role:      models.UserRoleVolunteer,
+		Email:     volunteer.Email,
+		City:      volunteer.City,
+	}, client)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token")
 	}
@@ -50,75 +55,23 @@ func (s *VolunteerService) Login(email, password string) (*models.VolunteerLogin
 	})
 
 	return &models.VolunteerLoginResponse{
-		Token:     token,
-		Volunteer: *volunteer,
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		Volunteer:    *volunteer,
 	}, nil
 }
 
-// generateToken creates a JWT token for a volunteer
-func (s *VolunteerService) generateToken(volunteer *models.Volunteer) (string, error) {
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "your-secret-key-change-in-production"
-	}
-
-	// Use the standard Claims struct to ensure compatibility with ValidateJWT
-	// This ensures the role is properly typed as models.UserRole
-	claims := jwt.MapClaims{
-		"user_id": volunteer.ID.String(), // Required by AuthMiddleware
-		"email":   volunteer.Email,
-		"city":    volunteer.City,
-		"role":    string(models.UserRoleVolunteer), // Must be "volunteer" string for models.UserRole
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
-		"iat":     time.Now().Unix(),
-		"nbf":     time.Now().Unix(),
-		"iss":     "rift26-api",
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(jwtSecret))
-}
-
-// VerifyToken verifies a volunteer JWT token and returns the volunteer
+// VerifyToken verifies a volunteer access token (including revocation) and returns the volunteer
 func (s *VolunteerService) VerifyToken(tokenString string) (*models.Volunteer, error) {
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "your-secret-key-change-in-production"
-	}
-
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method")
-		}
-		return []byte(jwtSecret), nil
-	})
-
-	if err != nil || !token.Valid {
+	claims, err := s.sessions.ParseAccessToken(tokenString)
+	if err != nil {
 		return nil, fmt.Errorf("invalid token")
 	}
-
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil, fmt.Errorf("invalid token claims")
-	}
-
-	// Check if this is a volunteer token
-	tokenType, _ := claims["type"].(string)
-	if tokenType != "volunteer" {
+	if claims.Role != models.UserRoleVolunteer {
 		return nil, fmt.Errorf("invalid token type")
 	}
-
-	volunteerIDStr, ok := claims["volunteer_id"].(string)
-	if !ok {
-		return nil, fmt.Errorf("invalid volunteer ID in token")
-	}
-
-	volunteerID, err := uuid.Parse(volunteerIDStr)
-	if err != nil {
-		return nil, fmt.Errorf("invalid volunteer ID format")
-	}
-
-	return s.repo.GetByID(volunteerID)
+	return s.repo.GetByID(claims.UserID)
 }
 
 // GetByID retrieves a volunteer by ID (wraps repo method)
@@ -157,8 +110,8 @@ func (s *VolunteerService) CreateVolunteer(req *models.CreateVolunteerRequest, a
 }
 
 // GetAllVolunteers retrieves all volunteers with optional filters
-func (s *VolunteerService) GetAllVolunteers(city *string, tableID *uuid.UUID) ([]models.Volunteer, error) {
-	return s.repo.GetAll(city, tableID)
+func (s *VolunteerService) GetAllVolunteers(eventID uuid.UUID, city *string, tableID *uuid.UUID) ([]models.Volunteer, error) {
+	return s.repo.GetAll(eventID, city, tableID)
 }
 
 // GetVolunteerLogs retrieves activity logs for a volunteer
@@ -174,9 +127,13 @@ func (s *VolunteerService) UpdateVolunteer(volunteer *models.Volunteer) error {
 	return s.repo.Update(volunteer)
 }
 
-// DeleteVolunteer deletes a volunteer
+// DeleteVolunteer deletes a volunteer and ends their sessions
 func (s *VolunteerService) DeleteVolunteer(id uuid.UUID) error {
-	return s.repo.Delete(id)
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	_, err := s.sessions.RevokeAll(context.Background(), id)
+	return err
 }
 
 // LogActivity logs a volunteer activity
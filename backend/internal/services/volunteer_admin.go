@@ -1,11 +1,9 @@
 package services
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/rift26/backend/internal/models"
 	"github.com/rift26/backend/internal/repository"
@@ -13,14 +11,16 @@ import (
 )
 
 type VolunteerAdminService struct {
-	repo *repository.VolunteerAdminRepository
+	repo      *repository.VolunteerAdminRepository
+	sessions  *SessionService
+	twoFactor *TwoFactorService
 }
 
-func NewVolunteerAdminService(repo *repository.VolunteerAdminRepository) *VolunteerAdminService {
-	return &VolunteerAdminService{repo: repo}
+func NewVolunteerAdminService(repo *repository.VolunteerAdminRepository, sessions *SessionService, twoFactor *TwoFactorService) *VolunteerAdminService {
+	return &VolunteerAdminService{repo: repo, sessions: sessions, twoFactor: twoFactor}
 }
 
-func (s *VolunteerAdminService) Login(email, password string) (*models.VolunteerAdminLoginResponse, error) {
+func (s *VolunteerAdminService) Login(email, password string, client models.SessionClient) (*models.VolunteerAdminLoginResponse, error) {
 	v, err := s.repo.GetByEmail(email)
 	if err != nil {
 		return nil, fmt.Errorf("invalid credentials")
@@ -28,35 +28,34 @@ func (s *VolunteerAdminService) Login(email, password string) (*models.Volunteer
 	if err := bcrypt.CompareHashAndPassword([]byte(v.PasswordHash), []byte(password)); err != nil {
 		return nil, fmt.Errorf("invalid credentials")
 	}
-	token, err := s.generateToken(v)
+	result, err := s.twoFactor.Login(context.Background(), models.SessionSubject{
+		SubjectID: v.ID,
+		Role:      models.UserRoleVolunteerAdmin,
+		Email:     v.Email,
+		City:      v.City,
+	}, client)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token")
 	}
+	if result.Tokens == nil {
+		return &models.VolunteerAdminLoginResponse{
+			Email:                  v.Email,
+			City:                   v.City,
+			TwoFactorRequired:      result.TwoFactorRequired,
+			TwoFactorSetupRequired: result.TwoFactorSetupRequired,
+			ChallengeToken:         result.ChallengeToken,
+		}, nil
+	}
+	tokens := result.Tokens
 	return &models.VolunteerAdminLoginResponse{
-		Token: token,
-		Email: v.Email,
-		City:  v.City,
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		Email:        v.Email,
+		City:         v.City,
 	}, nil
 }
 
-func (s *VolunteerAdminService) generateToken(v *models.VolunteerAdmin) (string, error) {
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "your-secret-key-change-in-production"
-	}
-	claims := jwt.MapClaims{
-		"user_id": v.ID.String(),
-		"email":   v.Email,
-		"city":    v.City,
-		"role":    string(models.UserRoleVolunteerAdmin),
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
-		"iat":     time.Now().Unix(),
-		"iss":     "rift26-api",
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(jwtSecret))
-}
-
 func (s *VolunteerAdminService) Create(req *models.CreateVolunteerAdminRequest, createdBy uuid.UUID) (*models.VolunteerAdmin, error) {
 	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -75,10 +74,14 @@ func (s *VolunteerAdminService) Create(req *models.CreateVolunteerAdminRequest,
 	return v, nil
 }
 
-func (s *VolunteerAdminService) GetAll() ([]models.VolunteerAdmin, error) {
-	return s.repo.GetAll()
+func (s *VolunteerAdminService) GetAll(eventID uuid.UUID) ([]models.VolunteerAdmin, error) {
+	return s.repo.GetAll(eventID)
 }
 
 func (s *VolunteerAdminService) Delete(id uuid.UUID) error {
-	return s.repo.Delete(id)
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	_, err := s.sessions.RevokeAll(context.Background(), id)
+	return err
 }
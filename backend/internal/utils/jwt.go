@@ -10,22 +10,28 @@ import (
 )
 
 type Claims struct {
-	UserID uuid.UUID       `json:"user_id"`
-	TeamID *uuid.UUID      `json:"team_id,omitempty"`
-	Email  string          `json:"email,omitempty"`
-	Role   models.UserRole `json:"role"`
+	UserID      uuid.UUID       `json:"user_id"`
+	TeamID      *uuid.UUID      `json:"team_id,omitempty"`
+	Email       string          `json:"email,omitempty"`
+	Role        models.UserRole `json:"role"`
+	City        string          `json:"city,omitempty"`
+	SessionID   *uuid.UUID      `json:"sid,omitempty"`   // server-side session; nil for legacy tokens
+	Permissions []string        `json:"perms,omitempty"` // effective admin permissions
 	jwt.RegisteredClaims
 }
 
-// GenerateJWT creates a JWT token for a user
-func GenerateJWT(userID uuid.UUID, email string, role models.UserRole, teamID *uuid.UUID, secret string) (string, error) {
+// GenerateAccessToken creates a short-lived access token bound to a server-side session
+func GenerateAccessToken(subject models.SessionSubject, sessionID uuid.UUID, ttl time.Duration, secret string) (string, error) {
 	claims := Claims{
-		UserID: userID,
-		TeamID: teamID,
-		Email:  email,
-		Role:   role,
+		UserID:      subject.SubjectID,
+		TeamID:      subject.TeamID,
+		Email:       subject.Email,
+		Role:        subject.Role,
+		City:        subject.City,
+		SessionID:   &sessionID,
+		Permissions: subject.Permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "rift26-api",
@@ -57,13 +63,18 @@ func ValidateJWT(tokenString, secret string) (*Claims, error) {
 
 	// Handle both Claims struct and MapClaims (for volunteer tokens)
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		for _, aud := range claims.Audience {
+			if aud == magicLinkAudience {
+				return nil, fmt.Errorf("invalid token")
+			}
+		}
 		return claims, nil
 	}
 
 	// If token was created with MapClaims, extract and convert
 	if mapClaims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
 		claims := &Claims{}
-		
+
 		// Extract user_id
 		if userIDStr, ok := mapClaims["user_id"].(string); ok {
 			userID, err := uuid.Parse(userIDStr)
@@ -71,12 +82,12 @@ func ValidateJWT(tokenString, secret string) (*Claims, error) {
 				claims.UserID = userID
 			}
 		}
-		
+
 		// Extract email
 		if email, ok := mapClaims["email"].(string); ok {
 			claims.Email = email
 		}
-		
+
 		// Extract role (critical for RoleMiddleware)
 		if roleStr, ok := mapClaims["role"].(string); ok {
 			claims.Role = models.UserRole(roleStr)
@@ -88,7 +99,7 @@ func ValidateJWT(tokenString, secret string) (*Claims, error) {
 				claims.Role = models.UserRoleVolunteer // Default for volunteer tokens
 			}
 		}
-		
+
 		// Extract team_id if present
 		if teamIDStr, ok := mapClaims["team_id"].(string); ok {
 			teamID, err := uuid.Parse(teamIDStr)
@@ -96,12 +107,56 @@ func ValidateJWT(tokenString, secret string) (*Claims, error) {
 				claims.TeamID = &teamID
 			}
 		}
-		
+
 		// Note: table_id and city are in MapClaims but not in Claims struct
 		// They'll be extracted separately by middleware if needed
-		
+
 		return claims, nil
 	}
 
 	return nil, fmt.Errorf("invalid token")
 }
+
+// magicLinkAudience marks tokens that may only be exchanged for a session, never used as access tokens
+const magicLinkAudience = "magic-link"
+
+// MagicLinkClaims identifies the team leader a magic link was issued to
+type MagicLinkClaims struct {
+	TeamID uuid.UUID `json:"team_id"`
+	Email  string    `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// GenerateMagicLinkToken signs a short-lived single-use login token; id is recorded server-side
+func GenerateMagicLinkToken(id, teamID uuid.UUID, email string, ttl time.Duration, secret string) (string, error) {
+	now := time.Now()
+	claims := MagicLinkClaims{
+		TeamID: teamID,
+		Email:  email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id.String(),
+			Audience:  jwt.ClaimStrings{magicLinkAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "rift26-api",
+		},
+	}
+
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return tokenString, nil
+}
+
+// ValidateMagicLinkToken checks the signature, expiry and audience of a magic-link token
+func ValidateMagicLinkToken(tokenString, secret string) (*MagicLinkClaims, error) {
+	claims := &MagicLinkClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(magicLinkAudience))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+	return claims, nil
+}
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_event_phases_phase;
+DROP TABLE IF EXISTS event_phases;
@@ -0,0 +1,35 @@
+-- Event phase schedule (RSVP I, RSVP II, PS release, PS lock window, final submission).
+-- city = '' is the default row for a phase; a row with a city code (BLR, PUNE, ...) overrides it for that city.
+-- mode applies between opens_at and closes_at (NULL = unbounded); outside the window the phase is closed.
+-- override_mode, when set, wins over the schedule (admin kill-switch / early open).
+CREATE TABLE IF NOT EXISTS event_phases (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    phase VARCHAR(50) NOT NULL,
+    city VARCHAR(50) NOT NULL DEFAULT '',
+    mode VARCHAR(10) NOT NULL DEFAULT 'closed' CHECK (mode IN ('open', 'closed', 'pin')),
+    opens_at TIMESTAMPTZ,
+    closes_at TIMESTAMPTZ,
+    override_mode VARCHAR(10) CHECK (override_mode IN ('open', 'closed', 'pin')),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(phase, city)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_phases_phase ON event_phases(phase);
+
+-- PS release: carry over the ps_released_at early-release setting when one exists. Without it no row
+-- is seeded and ps_release stays closed until an admin schedules it for the edition.
+INSERT INTO event_phases (phase, city, mode, opens_at)
+SELECT 'ps_release', '', 'open', value::timestamptz
+FROM settings
+WHERE key = 'ps_released_at' AND value <> ''
+ON CONFLICT (phase, city) DO NOTHING;
+
+-- PS lock window and final submission portal: carry over the manual toggles from settings.
+INSERT INTO event_phases (phase, city, mode)
+SELECT 'ps_lock', '', CASE WHEN (SELECT value FROM settings WHERE key = 'ps_submission_open') = 'true' THEN 'open' ELSE 'closed' END
+ON CONFLICT (phase, city) DO NOTHING;
+
+INSERT INTO event_phases (phase, city, mode)
+SELECT 'final_submission', '', CASE WHEN (SELECT value FROM settings WHERE key = 'ps_final_submission_open') = 'true' THEN 'open' ELSE 'closed' END
+ON CONFLICT (phase, city) DO NOTHING;
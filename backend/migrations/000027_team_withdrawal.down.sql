@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_otps_team_purpose;
+ALTER TABLE otps DROP COLUMN IF EXISTS purpose;
+
+-- Restore withdrawn / no-show teams to their previous status (enum values cannot be dropped)
+UPDATE teams SET status = previous_status WHERE status IN ('withdrawn', 'no_show') AND previous_status IS NOT NULL;
+
+ALTER TABLE teams
+DROP COLUMN IF EXISTS inactive_by,
+DROP COLUMN IF EXISTS inactive_reason,
+DROP COLUMN IF EXISTS inactive_at,
+DROP COLUMN IF EXISTS previous_status;
@@ -0,0 +1,19 @@
+-- Migration 000027: Team withdrawal and no-show handling
+
+-- New terminal statuses (reversible by admins via previous_status)
+ALTER TYPE team_status ADD VALUE IF NOT EXISTS 'withdrawn';
+ALTER TYPE team_status ADD VALUE IF NOT EXISTS 'no_show';
+
+-- previous_status is restored when an admin reinstates the team
+ALTER TABLE teams
+ADD COLUMN IF NOT EXISTS previous_status team_status,
+ADD COLUMN IF NOT EXISTS inactive_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN IF NOT EXISTS inactive_reason TEXT,
+ADD COLUMN IF NOT EXISTS inactive_by VARCHAR(255);
+
+-- Login codes and withdrawal confirmation codes share the otps table; a code is only accepted for
+-- the purpose it was issued for, so a login code can't confirm a withdrawal or the other way round.
+ALTER TABLE otps ADD COLUMN IF NOT EXISTS purpose VARCHAR(32) NOT NULL DEFAULT 'login'
+    CHECK (purpose IN ('login', 'withdrawal'));
+
+CREATE INDEX IF NOT EXISTS idx_otps_team_purpose ON otps(team_id, purpose, created_at DESC);
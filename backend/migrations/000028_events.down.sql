@@ -0,0 +1,38 @@
+-- Only safe while a single event exists (per-event uniqueness collapses back to global)
+ALTER TABLE settings DROP CONSTRAINT IF EXISTS settings_pkey;
+ALTER TABLE settings ADD CONSTRAINT settings_pkey PRIMARY KEY (key);
+
+ALTER TABLE event_phases DROP CONSTRAINT IF EXISTS event_phases_event_phase_city_key;
+ALTER TABLE event_phases ADD CONSTRAINT event_phases_phase_city_key UNIQUE (phase, city);
+
+ALTER TABLE certificates DROP CONSTRAINT IF EXISTS certificates_event_email_cert_type_key;
+ALTER TABLE certificates ADD CONSTRAINT certificates_participant_email_cert_type_key UNIQUE (participant_email, cert_type);
+
+ALTER TABLE venues DROP CONSTRAINT IF EXISTS venues_event_city_key;
+ALTER TABLE venues ADD CONSTRAINT venues_city_key UNIQUE (city);
+
+ALTER TABLE event_tables DROP CONSTRAINT IF EXISTS event_tables_event_table_number_key;
+ALTER TABLE event_tables ADD CONSTRAINT event_tables_table_number_key UNIQUE (table_number);
+
+ALTER TABLE volunteer_admins DROP CONSTRAINT IF EXISTS volunteer_admins_event_email_key;
+ALTER TABLE volunteer_admins ADD CONSTRAINT volunteer_admins_email_key UNIQUE (email);
+
+ALTER TABLE volunteers DROP CONSTRAINT IF EXISTS volunteers_event_email_key;
+ALTER TABLE volunteers ADD CONSTRAINT volunteers_email_key UNIQUE (email);
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'teams', 'volunteers', 'volunteer_admins', 'event_tables', 'venues', 'problem_statements',
+        'ps_selections', 'ps_submissions', 'announcements', 'certificates', 'event_phases', 'settings', 'email_logs'
+    ] LOOP
+        EXECUTE format('DROP INDEX IF EXISTS %I', 'idx_' || t || '_event');
+        EXECUTE format('ALTER TABLE %I DROP COLUMN IF EXISTS event_id', t);
+    END LOOP;
+END $$;
+
+DROP FUNCTION IF EXISTS current_event_id();
+DROP INDEX IF EXISTS idx_events_single_current;
+DROP TABLE IF EXISTS events;
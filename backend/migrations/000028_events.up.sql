@@ -0,0 +1,65 @@
+-- Migration 000028: Multi-event support
+-- An event (edition) scopes teams, volunteers, tables, venues, problem statements, submissions,
+-- announcements, certificates, phases and settings. Exactly one event is current; new rows default to it.
+
+CREATE TABLE IF NOT EXISTS events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    slug VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    is_current BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- At most one current event
+CREATE UNIQUE INDEX IF NOT EXISTS idx_events_single_current ON events(is_current) WHERE is_current;
+
+INSERT INTO events (slug, name, starts_at, ends_at, is_current)
+VALUES ('rift26', 'RIFT ''26', '2026-02-19 00:00:00+05:30', '2026-02-20 23:59:59+05:30', TRUE)
+ON CONFLICT (slug) DO NOTHING;
+
+-- Used as column default so existing inserts land in the current event
+CREATE OR REPLACE FUNCTION current_event_id() RETURNS UUID AS $$
+    SELECT id FROM events WHERE is_current LIMIT 1
+$$ LANGUAGE SQL STABLE;
+
+-- Scope tables to an event and backfill existing rows with the current event
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'teams', 'volunteers', 'volunteer_admins', 'event_tables', 'venues', 'problem_statements',
+        'ps_selections', 'ps_submissions', 'announcements', 'certificates', 'event_phases', 'settings', 'email_logs'
+    ] LOOP
+        EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS event_id UUID REFERENCES events(id)', t);
+        EXECUTE format('UPDATE %I SET event_id = current_event_id() WHERE event_id IS NULL', t);
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN event_id SET DEFAULT current_event_id()', t);
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN event_id SET NOT NULL', t);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I(event_id)', 'idx_' || t || '_event', t);
+    END LOOP;
+END $$;
+
+-- Per-event uniqueness
+ALTER TABLE volunteers DROP CONSTRAINT IF EXISTS volunteers_email_key;
+ALTER TABLE volunteers ADD CONSTRAINT volunteers_event_email_key UNIQUE (event_id, email);
+
+ALTER TABLE volunteer_admins DROP CONSTRAINT IF EXISTS volunteer_admins_email_key;
+ALTER TABLE volunteer_admins ADD CONSTRAINT volunteer_admins_event_email_key UNIQUE (event_id, email);
+
+ALTER TABLE event_tables DROP CONSTRAINT IF EXISTS event_tables_table_number_key;
+ALTER TABLE event_tables ADD CONSTRAINT event_tables_event_table_number_key UNIQUE (event_id, table_number);
+
+ALTER TABLE venues DROP CONSTRAINT IF EXISTS venues_city_key;
+ALTER TABLE venues ADD CONSTRAINT venues_event_city_key UNIQUE (event_id, city);
+
+ALTER TABLE certificates DROP CONSTRAINT IF EXISTS certificates_participant_email_cert_type_key;
+ALTER TABLE certificates ADD CONSTRAINT certificates_event_email_cert_type_key UNIQUE (event_id, participant_email, cert_type);
+
+ALTER TABLE event_phases DROP CONSTRAINT IF EXISTS event_phases_phase_city_key;
+ALTER TABLE event_phases ADD CONSTRAINT event_phases_event_phase_city_key UNIQUE (event_id, phase, city);
+
+ALTER TABLE settings DROP CONSTRAINT IF EXISTS settings_pkey;
+ALTER TABLE settings ADD CONSTRAINT settings_pkey PRIMARY KEY (event_id, key);
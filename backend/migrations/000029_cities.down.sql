@@ -0,0 +1,9 @@
+CREATE TYPE city_enum AS ENUM ('BLR', 'PUNE', 'NOIDA', 'LKO');
+
+ALTER TABLE venues DROP CONSTRAINT IF EXISTS venues_city_fkey;
+ALTER TABLE venues ALTER COLUMN city TYPE city_enum USING city::city_enum;
+
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_city_fkey;
+ALTER TABLE teams ALTER COLUMN city TYPE city_enum USING city::city_enum;
+
+DROP TABLE IF EXISTS cities;
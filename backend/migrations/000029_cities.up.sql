@@ -0,0 +1,38 @@
+-- Migration 000029: Configurable cities
+-- Replaces the hardcoded city_enum. Aliases are matched case-insensitively when importing teams
+-- and when filtering tables / volunteers that store free-text city names.
+
+CREATE TABLE IF NOT EXISTS cities (
+    code VARCHAR(20) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    aliases TEXT[] NOT NULL DEFAULT '{}',
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Kolkata',
+    venue_name VARCHAR(255),
+    venue_address TEXT,
+    venue_maps_url TEXT,
+    seat_allocation BOOLEAN NOT NULL DEFAULT FALSE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+INSERT INTO cities (code, name, aliases, seat_allocation, sort_order, venue_name, venue_address, venue_maps_url)
+SELECT c.code, c.name, c.aliases, c.seat_allocation, c.sort_order, v.venue_name, v.address, v.google_maps_embed
+FROM (VALUES
+    ('BLR', 'Bengaluru', ARRAY['bengaluru', 'bangalore', 'blr'], TRUE, 1),
+    ('PUNE', 'Pune', ARRAY['pune'], FALSE, 2),
+    ('NOIDA', 'Noida', ARRAY['noida'], FALSE, 3),
+    ('LKO', 'Lucknow', ARRAY['lucknow', 'lko'], FALSE, 4)
+) AS c(code, name, aliases, seat_allocation, sort_order)
+LEFT JOIN venues v ON v.city::text = c.code AND v.event_id = current_event_id()
+ON CONFLICT (code) DO NOTHING;
+
+-- Teams and venues reference cities instead of the enum
+ALTER TABLE teams ALTER COLUMN city TYPE VARCHAR(20) USING city::text;
+ALTER TABLE teams ADD CONSTRAINT teams_city_fkey FOREIGN KEY (city) REFERENCES cities(code) ON UPDATE CASCADE;
+
+ALTER TABLE venues ALTER COLUMN city TYPE VARCHAR(20) USING city::text;
+ALTER TABLE venues ADD CONSTRAINT venues_city_fkey FOREIGN KEY (city) REFERENCES cities(code) ON UPDATE CASCADE;
+
+DROP TYPE IF EXISTS city_enum;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_team_members_name_trgm;
+DROP INDEX IF EXISTS idx_teams_team_name_trgm;
+-- pg_trgm is left installed; other objects may depend on it
@@ -0,0 +1,7 @@
+-- Migration 000030: Trigram indexes for the public team search
+-- Search matches team names and member (including leader) names by word similarity.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_teams_team_name_trgm ON teams USING GIN (LOWER(team_name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_team_members_name_trgm ON team_members USING GIN (LOWER(name) gin_trgm_ops);
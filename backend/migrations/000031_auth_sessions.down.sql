@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS auth_revocations;
+DROP TABLE IF EXISTS auth_sessions;
@@ -0,0 +1,28 @@
+-- Migration 000031: Server-side sessions with rotating refresh tokens
+-- Access tokens carry the session id (sid); revoking a session invalidates its access tokens too.
+
+CREATE TABLE IF NOT EXISTS auth_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subject_id UUID NOT NULL, -- users.id, volunteers.id, volunteer_admins.id or teams.id (participants)
+    role VARCHAR(32) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    team_id UUID,
+    city VARCHAR(50) NOT NULL DEFAULT '',
+    refresh_token_hash VARCHAR(64) NOT NULL UNIQUE,
+    previous_token_hash VARCHAR(64), -- last rotated-out token; presenting it again revokes the session
+    user_agent TEXT,
+    ip VARCHAR(64),
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_subject ON auth_sessions(subject_id);
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_previous ON auth_sessions(previous_token_hash);
+
+-- Tokens without a session (issued before sessions existed) are rejected if issued before revoked_before
+CREATE TABLE IF NOT EXISTS auth_revocations (
+    subject_id UUID PRIMARY KEY,
+    revoked_before TIMESTAMPTZ NOT NULL
+);
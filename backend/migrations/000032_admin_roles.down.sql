@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_admin_roles;
+DROP TABLE IF EXISTS admin_roles;
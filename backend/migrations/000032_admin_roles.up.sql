@@ -0,0 +1,40 @@
+-- Migration 000032: Granular admin permissions
+-- Admin staff get one or more roles; each role grants a set of permissions ("*" grants all).
+-- users.role stays 'admin' for staff and still gates the admin API as a whole.
+
+CREATE TABLE IF NOT EXISTS admin_roles (
+    key VARCHAR(50) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    is_system BOOLEAN NOT NULL DEFAULT FALSE, -- built-in roles cannot be deleted
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS user_admin_roles (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role_key VARCHAR(50) NOT NULL REFERENCES admin_roles(key) ON DELETE CASCADE ON UPDATE CASCADE,
+    granted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    granted_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (user_id, role_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_admin_roles_role ON user_admin_roles(role_key);
+
+INSERT INTO admin_roles (key, name, description, permissions, is_system) VALUES
+    ('super_admin', 'Super admin', 'Full access, including destructive actions and role management', ARRAY['*'], TRUE),
+    ('comms', 'Communications', 'Announcements, bulk email and certificates',
+        ARRAY['teams.read', 'announcements.manage', 'email.send', 'certificates.send'], TRUE),
+    ('ops', 'Operations', 'Teams, check-in, registration desks, seating, volunteers and the phase schedule',
+        ARRAY['teams.read', 'teams.write', 'checkin.manage', 'seating.manage', 'volunteers.manage', 'phases.manage', 'sessions.manage'], TRUE),
+    ('judging_admin', 'Judging admin', 'Problem statements, submissions and semi-finalists',
+        ARRAY['teams.read', 'judging.manage'], TRUE),
+    ('support', 'Support', 'Support tickets',
+        ARRAY['teams.read', 'tickets.manage'], TRUE)
+ON CONFLICT (key) DO NOTHING;
+
+-- Existing admins keep full access
+INSERT INTO user_admin_roles (user_id, role_key)
+SELECT id, 'super_admin' FROM users WHERE role = 'admin'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS judge_notes;
+DROP TABLE IF EXISTS judge_assignments;
+-- Judge users lose access (enum values cannot be dropped)
+DELETE FROM users WHERE role = 'judge';
@@ -0,0 +1,31 @@
+-- Migration 000033: Judge role, assignments and notes
+-- Judges are users with role 'judge'. They see only submissions assigned to them directly or
+-- through a problem statement track, and keep one private note per submission.
+
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'judge';
+
+CREATE TABLE IF NOT EXISTS judge_assignments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID NOT NULL DEFAULT current_event_id() REFERENCES events(id),
+    judge_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    track VARCHAR(255),                                                 -- every submission of this track
+    submission_id UUID REFERENCES ps_submissions(id) ON DELETE CASCADE, -- or a single submission
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT judge_assignments_target CHECK ((track IS NULL) <> (submission_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_judge_assignments_track ON judge_assignments(event_id, judge_id, LOWER(track)) WHERE track IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_judge_assignments_submission ON judge_assignments(judge_id, submission_id) WHERE submission_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS judge_notes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    judge_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    submission_id UUID NOT NULL REFERENCES ps_submissions(id) ON DELETE CASCADE,
+    note TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (judge_id, submission_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_judge_notes_submission ON judge_notes(submission_id);
@@ -0,0 +1,39 @@
+-- Migration 000034: TOTP two-factor authentication for admins and volunteer admins
+-- subject_id is users.id (admins) or volunteer_admins.id. Secrets are stored AES-GCM encrypted.
+
+CREATE TABLE IF NOT EXISTS two_factor (
+    subject_id UUID PRIMARY KEY,
+    role VARCHAR(20) NOT NULL,
+    secret_encrypted TEXT NOT NULL,
+    enabled_at TIMESTAMP,     -- NULL while enrollment is pending confirmation
+    last_counter BIGINT,      -- last accepted TOTP time step; older or equal steps are replays
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS two_factor_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subject_id UUID NOT NULL REFERENCES two_factor(subject_id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_two_factor_recovery_subject ON two_factor_recovery_codes(subject_id);
+
+-- Second login step: issued after the password check, exchanged for a session after the TOTP check
+CREATE TABLE IF NOT EXISTS two_factor_challenges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    subject_id UUID NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    city VARCHAR(20),
+    setup BOOLEAN NOT NULL DEFAULT FALSE, -- enrollment is enforced and must be completed first
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    consumed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_two_factor_challenges_expires ON two_factor_challenges(expires_at);
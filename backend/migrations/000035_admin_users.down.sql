@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS password_tokens;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS invited_by,
+DROP COLUMN IF EXISTS disabled_at,
+DROP COLUMN IF EXISTS is_active;
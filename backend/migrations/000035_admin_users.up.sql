@@ -0,0 +1,21 @@
+-- Migration 000035: Admin user management
+-- Admins are invited by email and set their own password through a one-time link.
+-- Disabled users cannot log in and their sessions are revoked.
+
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS is_active BOOLEAN NOT NULL DEFAULT TRUE,
+ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN IF NOT EXISTS invited_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS password_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    purpose VARCHAR(20) NOT NULL CHECK (purpose IN ('invite', 'reset')),
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_tokens_user ON password_tokens(user_id);
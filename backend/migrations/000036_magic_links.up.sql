@@ -0,0 +1,15 @@
+-- Migration 000036: Magic-link login for team leaders
+-- Each emailed link carries a signed token whose ID is recorded here so it can be used once.
+
+CREATE TABLE IF NOT EXISTS magic_links (
+    id UUID PRIMARY KEY,
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    requested_ip VARCHAR(64),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_magic_links_email_created ON magic_links(LOWER(email), created_at);
+CREATE INDEX IF NOT EXISTS idx_magic_links_team ON magic_links(team_id);
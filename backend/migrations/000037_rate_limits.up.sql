@@ -0,0 +1,24 @@
+-- Migration 000037: Persistent rate limiting and OTP lockouts
+-- Rate limit counters are shared by every API instance and survive deploys.
+-- Each key keeps a counter per fixed window; the limiter weights the previous window to slide.
+
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    key TEXT NOT NULL,
+    window_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (key, window_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_expires ON rate_limit_buckets(expires_at);
+
+-- Failed OTP verifications per team. After too many failures the team is locked out,
+-- for longer each time (lockout_count drives the exponential backoff).
+CREATE TABLE IF NOT EXISTS otp_lockouts (
+    team_id UUID PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+    failed_attempts INTEGER NOT NULL DEFAULT 0,
+    lockout_count INTEGER NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    last_failed_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
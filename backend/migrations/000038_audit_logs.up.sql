@@ -0,0 +1,28 @@
+-- Migration 000038: Audit log of privileged actions
+-- Every admin and volunteer-admin mutation is recorded with its actor, request origin,
+-- target and, where the handler provides them, the state before and after the change.
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID REFERENCES events(id) ON DELETE SET NULL,
+    actor_id UUID,
+    actor_email VARCHAR(255),
+    actor_role VARCHAR(30) NOT NULL,
+    ip VARCHAR(64),
+    user_agent TEXT,
+    method VARCHAR(10) NOT NULL,
+    path TEXT NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    target_type VARCHAR(50),
+    target_id TEXT,
+    before_state JSONB,
+    after_state JSONB,
+    diff JSONB,
+    status_code INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created ON audit_logs(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor ON audit_logs(actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_action ON audit_logs(action, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_target ON audit_logs(target_type, target_id);
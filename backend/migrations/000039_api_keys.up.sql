@@ -0,0 +1,18 @@
+-- Migration 000039: Scoped API keys for read-only integrations
+-- Display screens and bots authenticate with a key instead of an admin login.
+-- Only the SHA-256 hash of a key is stored; the prefix identifies it in the admin list.
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    city VARCHAR(50),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    last_used_ip VARCHAR(64),
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
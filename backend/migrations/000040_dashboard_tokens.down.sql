@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS dashboard_access_logs;
+DROP TABLE IF EXISTS dashboard_tokens;
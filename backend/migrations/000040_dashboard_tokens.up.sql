@@ -0,0 +1,38 @@
+-- Migration 000040: Expiring, revocable dashboard links with access logs
+-- teams.dashboard_token stays the team's current full-access link (leaders are redirected to it);
+-- every link, including read-only member links, has a row here keyed by its SHA-256 hash.
+
+CREATE TABLE IF NOT EXISTS dashboard_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    prefix VARCHAR(16) NOT NULL,
+    access VARCHAR(20) NOT NULL DEFAULT 'full' CHECK (access IN ('full', 'read_only')),
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_dashboard_tokens_team ON dashboard_tokens(team_id, created_at DESC);
+
+-- Existing links keep working for 30 days
+INSERT INTO dashboard_tokens (team_id, token_hash, prefix, access, created_by, expires_at)
+SELECT id, encode(sha256(dashboard_token::bytea), 'hex'), LEFT(dashboard_token, 8), 'full', 'migration', NOW() + INTERVAL '30 days'
+FROM teams
+WHERE dashboard_token IS NOT NULL
+ON CONFLICT (token_hash) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS dashboard_access_logs (
+    id BIGSERIAL PRIMARY KEY,
+    token_id UUID NOT NULL REFERENCES dashboard_tokens(id) ON DELETE CASCADE,
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    ip VARCHAR(64),
+    user_agent TEXT,
+    method VARCHAR(10) NOT NULL,
+    path TEXT NOT NULL,
+    accessed_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_dashboard_access_logs_token ON dashboard_access_logs(token_id, accessed_at DESC);
@@ -0,0 +1,2 @@
+ALTER TABLE problem_statements DROP COLUMN IF EXISTS city_max_teams;
+ALTER TABLE problem_statements DROP COLUMN IF EXISTS max_teams;
@@ -0,0 +1,6 @@
+-- Migration 000041: Problem statement capacity limits
+-- max_teams caps how many teams can lock a problem statement overall; city_max_teams caps it
+-- per city code (e.g. {"BLR": 10}). NULL / a missing city means no limit.
+
+ALTER TABLE problem_statements ADD COLUMN IF NOT EXISTS max_teams INTEGER CHECK (max_teams IS NULL OR max_teams >= 0);
+ALTER TABLE problem_statements ADD COLUMN IF NOT EXISTS city_max_teams JSONB NOT NULL DEFAULT '{}'::jsonb;
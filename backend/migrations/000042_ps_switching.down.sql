@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS ps_selection_history;
+ALTER TABLE ps_selections DROP COLUMN IF EXISTS switch_count;
@@ -0,0 +1,26 @@
+-- Migration 000042: Problem statement switching and selection history
+-- A team may switch its locked PS a limited number of times within a grace period after its
+-- first lock (ps_selections.created_at). Every lock, switch and admin reassignment is recorded.
+
+ALTER TABLE ps_selections ADD COLUMN IF NOT EXISTS switch_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS ps_selection_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID REFERENCES events(id) ON DELETE CASCADE,
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    from_problem_statement_id UUID REFERENCES problem_statements(id) ON DELETE SET NULL,
+    to_problem_statement_id UUID REFERENCES problem_statements(id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('lock', 'switch', 'reassign')),
+    actor_type VARCHAR(20) NOT NULL CHECK (actor_type IN ('team', 'admin')),
+    actor VARCHAR(255),
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ps_selection_history_team ON ps_selection_history(team_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_ps_selection_history_event ON ps_selection_history(event_id, created_at DESC);
+
+-- Existing selections start the history as their first lock
+INSERT INTO ps_selection_history (event_id, team_id, to_problem_statement_id, action, actor_type, actor, created_at)
+SELECT event_id, team_id, problem_statement_id, 'lock', 'team', leader_email, locked_at
+FROM ps_selections;
@@ -0,0 +1,5 @@
+DROP VIEW IF EXISTS ps_submission_judged;
+DROP TABLE IF EXISTS ps_submission_versions;
+ALTER TABLE ps_submissions DROP COLUMN IF EXISTS submitted_by;
+ALTER TABLE ps_submissions DROP COLUMN IF EXISTS is_late;
+ALTER TABLE ps_submissions DROP COLUMN IF EXISTS version;
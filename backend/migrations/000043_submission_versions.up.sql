@@ -0,0 +1,43 @@
+-- Migration 000043: Versioned project submissions
+-- ps_submissions keeps the latest revision of a team's project; every save is also kept in
+-- ps_submission_versions with its submitter and whether it arrived after the portal's scheduled close.
+
+ALTER TABLE ps_submissions
+    ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS is_late BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS submitted_by VARCHAR(255);
+
+CREATE TABLE IF NOT EXISTS ps_submission_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    submission_id UUID NOT NULL REFERENCES ps_submissions(id) ON DELETE CASCADE,
+    event_id UUID REFERENCES events(id) ON DELETE CASCADE,
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    problem_statement_id UUID NOT NULL REFERENCES problem_statements(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    linkedin_url TEXT,
+    github_url TEXT,
+    live_url TEXT,
+    extra_notes TEXT,
+    custom_fields JSONB,
+    submitted_by VARCHAR(255),
+    is_late BOOLEAN NOT NULL DEFAULT FALSE,
+    deadline TIMESTAMPTZ, -- scheduled close of the portal when the version was saved
+    submitted_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (submission_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ps_submission_versions_team ON ps_submission_versions(team_id, submitted_at DESC);
+
+-- Existing submissions become their first version
+INSERT INTO ps_submission_versions (submission_id, event_id, team_id, problem_statement_id, version,
+    linkedin_url, github_url, live_url, extra_notes, custom_fields, submitted_at)
+SELECT id, event_id, team_id, problem_statement_id, 1,
+    linkedin_url, github_url, live_url, extra_notes, custom_fields, submitted_at
+FROM ps_submissions
+ON CONFLICT (submission_id, version) DO NOTHING;
+
+-- The version judges see: the latest on-time revision, or the latest late one when nothing came in on time
+CREATE OR REPLACE VIEW ps_submission_judged AS
+SELECT DISTINCT ON (v.submission_id) v.*
+FROM ps_submission_versions v
+ORDER BY v.submission_id, v.is_late, v.version DESC;
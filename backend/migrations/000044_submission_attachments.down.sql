@@ -0,0 +1 @@
+DROP TABLE IF EXISTS ps_submission_attachments;
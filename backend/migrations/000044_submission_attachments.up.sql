@@ -0,0 +1,20 @@
+-- Migration 000044: Project submission attachments
+-- Files uploaded for "file" submission fields. The field's value in custom_fields is the attachment
+-- id, so every submission version keeps pointing at the files it was saved with.
+
+CREATE TABLE IF NOT EXISTS ps_submission_attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID REFERENCES events(id) ON DELETE CASCADE,
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    problem_statement_id UUID NOT NULL REFERENCES problem_statements(id) ON DELETE CASCADE,
+    field_key VARCHAR(64) NOT NULL,
+    filename VARCHAR(255) NOT NULL,     -- as uploaded
+    content_type VARCHAR(255) NOT NULL, -- sniffed from the content
+    size_bytes BIGINT NOT NULL,
+    sha256 VARCHAR(64) NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    uploaded_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ps_submission_attachments_team ON ps_submission_attachments(team_id, problem_statement_id);
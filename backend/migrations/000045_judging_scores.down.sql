@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS judge_scores;
+DROP TABLE IF EXISTS judging_rubrics;
+DROP TABLE IF EXISTS judging_rounds;
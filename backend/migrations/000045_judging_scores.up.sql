@@ -0,0 +1,44 @@
+-- Migration 000045: Rubric-based judging scores
+-- A judging round holds one rubric per track (weighted criteria with a max score each). Judges
+-- score their assigned submissions against the rubric of the submission's track; once an admin
+-- finalizes the round its rubrics and scores are locked.
+
+CREATE TABLE IF NOT EXISTS judging_rounds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID NOT NULL DEFAULT current_event_id() REFERENCES events(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    normalize BOOLEAN NOT NULL DEFAULT FALSE, -- leaderboard z-score normalizes each judge's totals
+    finalized_at TIMESTAMP WITH TIME ZONE,
+    finalized_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_judging_rounds_name ON judging_rounds(event_id, LOWER(name));
+
+CREATE TABLE IF NOT EXISTS judging_rubrics (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    round_id UUID NOT NULL REFERENCES judging_rounds(id) ON DELETE CASCADE,
+    track VARCHAR(255) NOT NULL,
+    criteria JSONB NOT NULL, -- [{"key","label","description","weight","max_score"}]
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_judging_rubrics_track ON judging_rubrics(round_id, LOWER(track));
+
+CREATE TABLE IF NOT EXISTS judge_scores (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    round_id UUID NOT NULL REFERENCES judging_rounds(id) ON DELETE CASCADE,
+    judge_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    submission_id UUID NOT NULL REFERENCES ps_submissions(id) ON DELETE CASCADE,
+    scores JSONB NOT NULL,                  -- criterion key -> score
+    comments JSONB NOT NULL DEFAULT '{}',   -- criterion key -> comment
+    comment TEXT NOT NULL DEFAULT '',       -- overall comment
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (round_id, judge_id, submission_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_judge_scores_submission ON judge_scores(submission_id);
@@ -2,6 +2,7 @@ package email
 
 import (
 	"fmt"
+	"html"
 	"net/smtp"
 	"strings"
 )
@@ -14,8 +15,12 @@ type EmailService struct {
 	smtpPassword string
 	fromEmail    string
 	fromName     string
+	eventName    func() string
 }
 
+// defaultEventName is used in subjects and templates until an event name source is set.
+const defaultEventName = "RIFT '26"
+
 // NewEmailService creates a new email service instance
 func NewEmailService(host, port, username, password, fromEmail, fromName string) *EmailService {
 	return &EmailService{
@@ -28,9 +33,25 @@ func NewEmailService(host, port, username, password, fromEmail, fromName string)
 	}
 }
 
+// SetEventNameSource sets the function used to resolve the current event name (e.g. "RIFT '26") for emails.
+func (s *EmailService) SetEventNameSource(fn func() string) {
+	s.eventName = fn
+}
+
+// event returns the current event name for subjects and templates.
+func (s *EmailService) event() string {
+	if s.eventName == nil {
+		return defaultEventName
+	}
+	if name := s.eventName(); name != "" {
+		return name
+	}
+	return defaultEventName
+}
+
 // SendOTP sends an OTP email to the specified recipient
 func (s *EmailService) SendOTP(toEmail, otpCode, teamName string) error {
-	subject := "Your RIFT '26 OTP Code"
+	subject := fmt.Sprintf("Your %s OTP Code", s.event())
 	body := s.generateOTPEmailBody(otpCode, teamName)
 
 	return s.sendEmail(toEmail, subject, body)
@@ -44,7 +65,7 @@ func (s *EmailService) generateOTPEmailBody(otpCode, teamName string) string {
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>RIFT '26 OTP</title>
+    <title>%[1]s OTP</title>
 </head>
 <body style="margin: 0; padding: 0; font-family: Arial, sans-serif; background-color: #f4f4f4;">
     <table width="100%%" cellpadding="0" cellspacing="0" border="0" style="background-color: #f4f4f4; padding: 20px;">
@@ -54,7 +75,7 @@ func (s *EmailService) generateOTPEmailBody(otpCode, teamName string) string {
                     <!-- Header -->
                     <tr>
                         <td style="background: linear-gradient(135deg, #c0211f 0%%, #8b0000 100%%); padding: 30px; text-align: center;">
-                            <h1 style="color: #ffffff; margin: 0; font-size: 36px; font-weight: bold; letter-spacing: 2px;">RIFT '26</h1>
+                            <h1 style="color: #ffffff; margin: 0; font-size: 36px; font-weight: bold; letter-spacing: 2px;">%[1]s</h1>
                             <p style="color: #ffffff; margin: 5px 0 0 0; font-size: 14px;">Hackathon Registration</p>
                         </td>
                     </tr>
@@ -64,7 +85,7 @@ func (s *EmailService) generateOTPEmailBody(otpCode, teamName string) string {
                         <td style="padding: 40px 30px;">
                             <h2 style="color: #333333; margin: 0 0 20px 0; font-size: 24px;">Your OTP Code</h2>
                             <p style="color: #666666; font-size: 16px; line-height: 1.5; margin: 0 0 30px 0;">
-                                Hi there! You've requested to authenticate for team <strong>%s</strong>.
+                                Hi there! You've requested to authenticate for team <strong>%[2]s</strong>.
                             </p>
                             
                             <!-- OTP Box -->
@@ -73,7 +94,7 @@ func (s *EmailService) generateOTPEmailBody(otpCode, teamName string) string {
                                     <td align="center" style="padding: 20px 0;">
                                         <div style="background-color: #f8f8f8; border: 2px dashed #c0211f; border-radius: 8px; padding: 20px; display: inline-block;">
                                             <p style="margin: 0 0 10px 0; color: #666666; font-size: 14px;">Your OTP Code:</p>
-                                            <h1 style="margin: 0; color: #c0211f; font-size: 48px; font-weight: bold; letter-spacing: 8px;">%s</h1>
+                                            <h1 style="margin: 0; color: #c0211f; font-size: 48px; font-weight: bold; letter-spacing: 8px;">%[3]s</h1>
                                         </div>
                                     </td>
                                 </tr>
@@ -102,7 +123,7 @@ func (s *EmailService) generateOTPEmailBody(otpCode, teamName string) string {
     </table>
 </body>
 </html>
-`, teamName, otpCode)
+`, s.event(), teamName, otpCode)
 }
 
 // sendEmail sends an email using SMTP
@@ -193,7 +214,7 @@ func MaskEmail(email string) string {
 
 // SendTicketCreatedEmail sends notification when a ticket is created
 func (s *EmailService) SendTicketCreatedEmail(to, teamName, subject, ticketID string) error {
-	emailSubject := "Ticket Submitted - RIFT '26"
+	emailSubject := fmt.Sprintf("Ticket Submitted - %s", s.event())
 	body := fmt.Sprintf(`
 <!DOCTYPE html>
 <html>
@@ -213,15 +234,15 @@ func (s *EmailService) SendTicketCreatedEmail(to, teamName, subject, ticketID st
 <body>
 	<div class="container">
 		<div class="header">
-			<h1>🎫 RIFT '26 Support Ticket</h1>
+			<h1>🎫 %[1]s Support Ticket</h1>
 		</div>
 		<div class="content">
-			<p>Hi <strong>%s</strong>,</p>
-			<p>Your support ticket has been successfully submitted to the RIFT '26 team.</p>
+			<p>Hi <strong>%[2]s</strong>,</p>
+			<p>Your support ticket has been successfully submitted to the %[1]s team.</p>
 			
 			<div class="ticket-box">
-				<strong>Ticket ID:</strong> %s<br>
-				<strong>Subject:</strong> %s
+				<strong>Ticket ID:</strong> %[3]s<br>
+				<strong>Subject:</strong> %[4]s
 			</div>
 			
 			<p>Our team will review your request and respond as soon as possible. You'll receive an email notification when there's an update.</p>
@@ -229,20 +250,156 @@ func (s *EmailService) SendTicketCreatedEmail(to, teamName, subject, ticketID st
 			<p style="margin-top: 30px; color: #aaa; font-size: 14px;">Thank you for your patience!</p>
 		</div>
 		<div class="footer">
-			<strong>RIFT '26 Hackathon Team</strong><br>
+			<strong>%[1]s Hackathon Team</strong><br>
 			This is an automated email. Please monitor your inbox for updates.
 		</div>
 	</div>
 </body>
 </html>
-	`, teamName, ticketID, subject)
+	`, s.event(), teamName, ticketID, subject)
+
+	return s.sendEmail(to, emailSubject, body)
+}
+
+// SendPasswordLinkEmail sends an admin invite or password reset link
+func (s *EmailService) SendPasswordLinkEmail(to, name, link string, invite bool, validFor string) error {
+	emailSubject := fmt.Sprintf("Reset your %s admin password", s.event())
+	heading := "Reset your password"
+	intro := "A password reset was requested for your admin account. Use the button below to choose a new password."
+	if invite {
+		emailSubject = fmt.Sprintf("You're invited to the %s admin panel", s.event())
+		heading = "You're invited"
+		intro = "An admin account has been created for you. Use the button below to set your password and sign in."
+	}
+	body := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="UTF-8">
+	<style>
+		body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; background: #060010; color: #fff; padding: 0; margin: 0; }
+		.container { max-width: 600px; margin: 40px auto; background: linear-gradient(135deg, #1a0420 0%%, #060010 100%%); border: 1px solid #c0211f30; border-radius: 12px; overflow: hidden; }
+		.header { background: linear-gradient(90deg, #c0211f 0%%, #8a1816 100%%); padding: 30px; text-align: center; }
+		.header h1 { margin: 0; font-size: 28px; color: #fff; text-shadow: 0 2px 4px rgba(0,0,0,0.3); }
+		.content { padding: 30px; }
+		.button { display: inline-block; background: #c0211f; color: #fff !important; text-decoration: none; padding: 14px 28px; border-radius: 8px; font-weight: bold; }
+		.footer { padding: 20px 30px; background: rgba(255,255,255,0.03); border-top: 1px solid rgba(255,255,255,0.1); font-size: 12px; color: #888; text-align: center; }
+	</style>
+</head>
+<body>
+	<div class="container">
+		<div class="header">
+			<h1>%[1]s Admin</h1>
+		</div>
+		<div class="content">
+			<p>Hi <strong>%[2]s</strong>,</p>
+			<h2>%[3]s</h2>
+			<p>%[4]s</p>
+			<p style="text-align: center; margin: 30px 0;"><a class="button" href="%[5]s">Set password</a></p>
+			<p style="color: #aaa; font-size: 14px;">This link works once and expires in %[6]s. If the button does not work, copy this address into your browser:<br>%[5]s</p>
+		</div>
+		<div class="footer">
+			<strong>%[1]s Hackathon Team</strong><br>
+			If you did not expect this email, you can ignore it.
+		</div>
+	</div>
+</body>
+</html>
+	`, s.event(), html.EscapeString(name), heading, intro, html.EscapeString(link), validFor)
+
+	return s.sendEmail(to, emailSubject, body)
+}
+
+// SendWithdrawalOTP sends the leader the code that confirms withdrawing their team.
+// It is worded differently from the login code so nobody enters it thinking they are signing in.
+func (s *EmailService) SendWithdrawalOTP(to, otpCode, teamName string) error {
+	emailSubject := fmt.Sprintf("Confirm withdrawing %s from %s", stripCRLF(teamName), s.event())
+	body := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="UTF-8">
+	<style>
+		body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; background: #060010; color: #fff; padding: 0; margin: 0; }
+		.container { max-width: 600px; margin: 40px auto; background: linear-gradient(135deg, #1a0420 0%%, #060010 100%%); border: 1px solid #c0211f30; border-radius: 12px; overflow: hidden; }
+		.header { background: linear-gradient(90deg, #c0211f 0%%, #8a1816 100%%); padding: 30px; text-align: center; }
+		.header h1 { margin: 0; font-size: 28px; color: #fff; text-shadow: 0 2px 4px rgba(0,0,0,0.3); }
+		.content { padding: 30px; }
+		.warning { background: rgba(192,33,31,0.15); border-left: 4px solid #c0211f; padding: 15px; margin: 20px 0; border-radius: 4px; }
+		.code { text-align: center; font-size: 40px; font-weight: bold; letter-spacing: 8px; color: #ff4d4a; margin: 30px 0; }
+		.footer { padding: 20px 30px; background: rgba(255,255,255,0.03); border-top: 1px solid rgba(255,255,255,0.1); font-size: 12px; color: #888; text-align: center; }
+	</style>
+</head>
+<body>
+	<div class="container">
+		<div class="header">
+			<h1>%[1]s</h1>
+		</div>
+		<div class="content">
+			<p>Hi,</p>
+			<p>Someone asked to <strong>withdraw team %[2]s</strong> from %[1]s. Enter this code only if you want to withdraw the team:</p>
+			<div class="code">%[3]s</div>
+			<div class="warning">
+				<strong>Withdrawal is irreversible.</strong> Your team gives up its place in the event, and its registration desk and seat are released to other teams.
+			</div>
+			<p style="color: #aaa; font-size: 14px;">This code expires in 5 minutes and cannot be used to sign in. Do not share it with anyone.</p>
+		</div>
+		<div class="footer">
+			<strong>%[1]s Hackathon Team</strong><br>
+			If you did not ask to withdraw, ignore this email and your team stays registered.
+		</div>
+	</div>
+</body>
+</html>
+	`, s.event(), html.EscapeString(teamName), otpCode)
+
+	return s.sendEmail(to, emailSubject, body)
+}
+
+// SendMagicLinkEmail sends a team leader a one-time link that signs them in to the dashboard
+func (s *EmailService) SendMagicLinkEmail(to, teamName, link, validFor string) error {
+	emailSubject := fmt.Sprintf("Your %s sign-in link", s.event())
+	body := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="UTF-8">
+	<style>
+		body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; background: #060010; color: #fff; padding: 0; margin: 0; }
+		.container { max-width: 600px; margin: 40px auto; background: linear-gradient(135deg, #1a0420 0%%, #060010 100%%); border: 1px solid #c0211f30; border-radius: 12px; overflow: hidden; }
+		.header { background: linear-gradient(90deg, #c0211f 0%%, #8a1816 100%%); padding: 30px; text-align: center; }
+		.header h1 { margin: 0; font-size: 28px; color: #fff; text-shadow: 0 2px 4px rgba(0,0,0,0.3); }
+		.content { padding: 30px; }
+		.button { display: inline-block; background: #c0211f; color: #fff !important; text-decoration: none; padding: 14px 28px; border-radius: 8px; font-weight: bold; }
+		.footer { padding: 20px 30px; background: rgba(255,255,255,0.03); border-top: 1px solid rgba(255,255,255,0.1); font-size: 12px; color: #888; text-align: center; }
+	</style>
+</head>
+<body>
+	<div class="container">
+		<div class="header">
+			<h1>%[1]s</h1>
+		</div>
+		<div class="content">
+			<p>Hi <strong>%[2]s</strong>,</p>
+			<p>Use the button below to sign in to your team dashboard. No code needed.</p>
+			<p style="text-align: center; margin: 30px 0;"><a class="button" href="%[3]s">Open dashboard</a></p>
+			<p style="color: #aaa; font-size: 14px;">This link works once and expires in %[4]s. If the button does not work, copy this address into your browser:<br>%[3]s</p>
+		</div>
+		<div class="footer">
+			<strong>%[1]s Hackathon Team</strong><br>
+			If you did not request this link, you can ignore this email.
+		</div>
+	</div>
+</body>
+</html>
+	`, s.event(), html.EscapeString(teamName), html.EscapeString(link), validFor)
 
 	return s.sendEmail(to, emailSubject, body)
 }
 
 // SendTicketResolvedEmail sends notification when a ticket is resolved
 func (s *EmailService) SendTicketResolvedEmail(to, teamName, subject, resolution string, editAllowed bool, editMinutes int) error {
-	emailSubject := "Ticket Resolved - RIFT '26"
+	emailSubject := fmt.Sprintf("Ticket Resolved - %s", s.event())
 
 	editInfo := ""
 	if editAllowed {
@@ -293,12 +450,12 @@ func (s *EmailService) SendTicketResolvedEmail(to, teamName, subject, resolution
 			</p>
 		</div>
 		<div class="footer">
-			<strong>RIFT '26 Hackathon Team</strong>
+			<strong>%s Hackathon Team</strong>
 		</div>
 	</div>
 </body>
 </html>
-	`, teamName, subject, resolution, editInfo)
+	`, teamName, subject, resolution, editInfo, s.event())
 
 	return s.sendEmail(to, emailSubject, body)
 }
@@ -404,26 +561,27 @@ func (s *EmailService) SendCertificateEmail(
 	issuedAt string, // formatted date e.g. "February 2026"
 	verifyURL string,
 ) error {
+	eventName := s.event()
 	var certTitle, certDescription string
 	switch certType {
 	case "winner":
 		certTitle = "Winner"
-		certDescription = fmt.Sprintf("Won at RIFT '26 Hackathon, representing team <strong>%s</strong>.", teamName)
+		certDescription = fmt.Sprintf("Won at %s Hackathon, representing team <strong>%s</strong>.", eventName, teamName)
 	case "semi_finalist":
 		certTitle = "Semi-Finalist"
-		certDescription = fmt.Sprintf("Was a Semi-Finalist at RIFT '26 Hackathon, representing team <strong>%s</strong>.", teamName)
+		certDescription = fmt.Sprintf("Was a Semi-Finalist at %s Hackathon, representing team <strong>%s</strong>.", eventName, teamName)
 	case "volunteer":
 		certTitle = "Volunteer"
-		certDescription = fmt.Sprintf("Served as a valued Volunteer at RIFT '26 Hackathon.")
+		certDescription = fmt.Sprintf("Served as a valued Volunteer at %s Hackathon.", eventName)
 	case "hod":
 		certTitle = "Head of Department"
-		certDescription = fmt.Sprintf("Served as Head of Department at RIFT '26 Hackathon.")
+		certDescription = fmt.Sprintf("Served as Head of Department at %s Hackathon.", eventName)
 	case "custom":
 		certTitle = ""
-		certDescription = fmt.Sprintf("Contributed to RIFT '26 Hackathon.")
+		certDescription = fmt.Sprintf("Contributed to %s Hackathon.", eventName)
 	default:
 		certTitle = "Participant"
-		certDescription = fmt.Sprintf("Participated in RIFT '26 Hackathon as a member of team <strong>%s</strong>.", teamName)
+		certDescription = fmt.Sprintf("Participated in %s Hackathon as a member of team <strong>%s</strong>.", eventName, teamName)
 	}
 
 	// LinkedIn Add-to-Profile deep link (no API key required)
@@ -435,7 +593,7 @@ func (s *EmailService) SendCertificateEmail(
 	// 	certID,
 	// )
 
-	subject := fmt.Sprintf("Your %s Certificate – RIFT '26 Hackathon", certTitle)
+	subject := fmt.Sprintf("Your %s Certificate – %s Hackathon", certTitle, eventName)
 
 	body := fmt.Sprintf(`<!DOCTYPE html>
 <html lang="en">
@@ -452,7 +610,7 @@ func (s *EmailService) SendCertificateEmail(
         <!-- Header -->
         <tr>
           <td style="background:linear-gradient(135deg,#c0211f 0%%,#8b0000 100%%);padding:36px 40px;text-align:center;">
-            <p style="margin:0 0 8px;color:rgba(255,255,255,0.7);font-size:13px;letter-spacing:3px;text-transform:uppercase;">RIFT '26 Hackathon</p>
+            <p style="margin:0 0 8px;color:rgba(255,255,255,0.7);font-size:13px;letter-spacing:3px;text-transform:uppercase;">%s Hackathon</p>
             <h1 style="margin:0;color:#fff;font-size:30px;font-weight:800;letter-spacing:1px;">%s</h1>
           </td>
         </tr>
@@ -513,6 +671,7 @@ func (s *EmailService) SendCertificateEmail(
 </body>
 </html>`,
 		certTitle,       // page title
+		eventName,       // header eyebrow
 		certTitle,       // email header h1
 		participantName, // "This is to certify that"
 		certDescription, // description paragraph
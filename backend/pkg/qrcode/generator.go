@@ -70,3 +70,13 @@ func DecodeQR(qrDataString string) (*QRData, error) {
 
 	return &data, nil
 }
+
+// GenerateTextQR encodes arbitrary text (e.g. an otpauth:// URI) as a Base64-encoded PNG
+func GenerateTextQR(text string) (string, error) {
+	pngBytes, err := qrcode.Encode(text, qrcode.Medium, 256)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate QR code: %w", err)
+	}
+
+	return fmt.Sprintf("data:image/png;base64,%s", base64.StdEncoding.EncodeToString(pngBytes)), nil
+}
@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalPathPrefix is the API route that serves the local backend's signed URLs.
+const LocalPathPrefix = "/api/v1/files/"
+
+// Local keeps files under a directory. Its signed URLs point at the API (LocalPathPrefix),
+// which checks them with VerifySignedURL and streams the file.
+type Local struct {
+	dir       string
+	publicURL string
+	secret    []byte
+}
+
+// NewLocal returns a store rooted at dir; publicURL is the API's public base URL.
+func NewLocal(dir, publicURL, secret string) (*Local, error) {
+	if dir == "" {
+		dir = filepath.Join(".", "uploads")
+	}
+	if secret == "" {
+		return nil, errors.New("storage: local signed URLs need a signing secret")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("storage: create %s: %w", dir, err)
+	}
+	return &Local{dir: dir, publicURL: strings.TrimSuffix(publicURL, "/"), secret: []byte(secret)}, nil
+}
+
+// Name describes the backend for logs.
+func (l *Local) Name() string {
+	return "local " + l.dir
+}
+
+// Dir returns the root directory.
+func (l *Local) Dir() string {
+	return l.dir
+}
+
+func (l *Local) path(key string) string {
+	return filepath.Join(l.dir, filepath.FromSlash(key))
+}
+
+// Put writes the file through a temporary file so readers never see a partial object.
+func (l *Local) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	dst := l.path(key)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("storage: create directory for %s: %w", key, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("storage: put %s: %w", key, err)
+	}
+	n, err := io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && size >= 0 && n != size {
+		err = fmt.Errorf("wrote %d of %d bytes", n, size)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), dst)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("storage: put %s: %w", key, err)
+	}
+	return nil
+}
+
+// Open returns the file's content.
+func (l *Local) Open(ctx context.Context, key string) (io.ReadCloser, *Object, error) {
+	if err := checkKey(key); err != nil {
+		return nil, nil, err
+	}
+	f, err := os.Open(l.path(key))
+	if os.IsNotExist(err) {
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("storage: open %s: %w", key, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("storage: stat %s: %w", key, err)
+	}
+	return f, l.object(key, info), nil
+}
+
+// Stat returns the file's metadata. The local backend keeps no content type, so it is guessed from the extension.
+func (l *Local) Stat(ctx context.Context, key string) (*Object, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(l.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("storage: stat %s: %w", key, err)
+	}
+	return l.object(key, info), nil
+}
+
+func (l *Local) object(key string, info os.FileInfo) *Object {
+	return &Object{Key: key, Size: info.Size(), ContentType: TypeByKey(key), ModTime: info.ModTime()}
+}
+
+// Delete removes the file.
+func (l *Local) Delete(ctx context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	if err := os.Remove(l.path(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("storage: delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// SignedURL returns an API URL carrying the expiry, download name and type, and an HMAC over all of them.
+func (l *Local) SignedURL(ctx context.Context, key string, ttl time.Duration, d Download) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+	expires := time.Now().Add(clampTTL(ttl)).Unix()
+	q := url.Values{}
+	q.Set("expires", strconv.FormatInt(expires, 10))
+	if d.Filename != "" {
+		q.Set("name", d.Filename)
+	}
+	if d.ContentType != "" {
+		q.Set("type", d.ContentType)
+	}
+	q.Set("signature", l.sign(key, expires, d))
+	return l.publicURL + LocalPathPrefix + escapeRFC3986(key, true) + "?" + q.Encode(), nil
+}
+
+// VerifySignedURL checks a signed URL's query for key and returns how to present the download.
+func (l *Local) VerifySignedURL(key string, q url.Values) (Download, error) {
+	return l.verifySignedURL(key, q, time.Now())
+}
+
+func (l *Local) verifySignedURL(key string, q url.Values, now time.Time) (Download, error) {
+	d := Download{Filename: q.Get("name"), ContentType: q.Get("type")}
+	expires, err := strconv.ParseInt(q.Get("expires"), 10, 64)
+	if err != nil || !ValidKey(key) {
+		return d, errors.New("invalid download link")
+	}
+	if !hmac.Equal([]byte(q.Get("signature")), []byte(l.sign(key, expires, d))) {
+		return d, errors.New("invalid download link")
+	}
+	if now.Unix() > expires {
+		return d, errors.New("download link has expired")
+	}
+	return d, nil
+}
+
+func (l *Local) sign(key string, expires int64, d Download) string {
+	mac := hmac.New(sha256.New, l.secret)
+	fmt.Fprintf(mac, "%s\n%d\n%s\n%s", key, expires, d.Filename, d.ContentType)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLocal(t *testing.T, secret string) *Local {
+	t.Helper()
+	l, err := NewLocal(t.TempDir(), "https://api.example.com/", secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+// signedQuery signs key and returns the URL's query after checking its path.
+func signedQuery(t *testing.T, l *Local, key string, ttl time.Duration, d Download) url.Values {
+	t.Helper()
+	raw, err := l.SignedURL(context.Background(), key, ttl, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "https://api.example.com" + LocalPathPrefix + key; u.Scheme+"://"+u.Host+u.Path != want {
+		t.Fatalf("signed URL %s, want it under %s", raw, want)
+	}
+	return u.Query()
+}
+
+func TestLocalSignedURLRoundTrip(t *testing.T) {
+	l := newTestLocal(t, "secret")
+	d := Download{Filename: "Pitch deck.pdf", ContentType: "application/pdf"}
+	q := signedQuery(t, l, "submissions/deck.pdf", time.Hour, d)
+	got, err := l.VerifySignedURL("submissions/deck.pdf", q)
+	if err != nil {
+		t.Fatalf("VerifySignedURL: %v", err)
+	}
+	if got != d {
+		t.Errorf("download = %+v, want %+v", got, d)
+	}
+
+	plain := signedQuery(t, l, "ps/brief.pdf", time.Hour, Download{})
+	if got, err := l.VerifySignedURL("ps/brief.pdf", plain); err != nil || got != (Download{}) {
+		t.Errorf("VerifySignedURL without download options = %+v, %v", got, err)
+	}
+}
+
+func TestLocalSignedURLExpiry(t *testing.T) {
+	l := newTestLocal(t, "secret")
+	q := signedQuery(t, l, "ps/brief.pdf", time.Minute, Download{})
+	expires := time.Now().Add(time.Minute)
+	if _, err := l.verifySignedURL("ps/brief.pdf", q, expires.Add(-5*time.Second)); err != nil {
+		t.Errorf("link rejected before it expired: %v", err)
+	}
+	_, err := l.verifySignedURL("ps/brief.pdf", q, expires.Add(5*time.Second))
+	if err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("expired link: err = %v, want expired", err)
+	}
+
+	// A zero TTL falls back to the default rather than a link that is already dead
+	q = signedQuery(t, l, "ps/brief.pdf", 0, Download{})
+	if _, err := l.VerifySignedURL("ps/brief.pdf", q); err != nil {
+		t.Errorf("default TTL link rejected: %v", err)
+	}
+}
+
+func TestLocalSignedURLTampering(t *testing.T) {
+	l := newTestLocal(t, "secret")
+	key := "submissions/deck.pdf"
+	signed := signedQuery(t, l, key, time.Hour, Download{Filename: "deck.pdf", ContentType: "application/pdf"})
+
+	tamper := func(name string, change func(q url.Values)) {
+		q := url.Values{}
+		for k, v := range signed {
+			q[k] = append([]string(nil), v...)
+		}
+		change(q)
+		if _, err := l.VerifySignedURL(key, q); err == nil || err.Error() != "invalid download link" {
+			t.Errorf("%s: err = %v, want invalid download link", name, err)
+		}
+	}
+	tamper("later expiry", func(q url.Values) {
+		q.Set("expires", q.Get("expires")+"0")
+	})
+	tamper("bad expiry", func(q url.Values) { q.Set("expires", "soon") })
+	tamper("renamed download", func(q url.Values) { q.Set("name", "invoice.pdf") })
+	tamper("changed type", func(q url.Values) { q.Set("type", "text/html") })
+	tamper("dropped type", func(q url.Values) { q.Del("type") })
+	tamper("missing signature", func(q url.Values) { q.Del("signature") })
+	tamper("flipped signature", func(q url.Values) {
+		sig := []byte(q.Get("signature"))
+		if sig[0] == '0' {
+			sig[0] = '1'
+		} else {
+			sig[0] = '0'
+		}
+		q.Set("signature", string(sig))
+	})
+
+	if _, err := l.VerifySignedURL("submissions/other.pdf", signed); err == nil {
+		t.Error("signature for one key accepted for another")
+	}
+	if _, err := l.VerifySignedURL("../secret.pdf", signed); err == nil {
+		t.Error("invalid key accepted")
+	}
+	if _, err := newTestLocal(t, "other-secret").VerifySignedURL(key, signed); err == nil {
+		t.Error("link signed with another secret accepted")
+	}
+}
+
+func TestNewLocalNeedsSecret(t *testing.T) {
+	if _, err := NewLocal(t.TempDir(), "https://api.example.com", ""); err == nil {
+		t.Error("NewLocal accepted an empty signing secret")
+	}
+}
+
+func TestNewRejectsUnknownBackend(t *testing.T) {
+	if _, err := New(Config{Backend: "gcs", LocalDir: t.TempDir(), SigningSecret: "secret"}); err == nil {
+		t.Error("New accepted an unknown backend")
+	}
+	s, err := New(Config{Backend: "LOCAL", LocalDir: t.TempDir(), SigningSecret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.(*Local); !ok {
+		t.Errorf("New(LOCAL) = %T, want *Local", s)
+	}
+}
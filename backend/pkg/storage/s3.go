@@ -0,0 +1,282 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Config configures an S3-compatible bucket. For MinIO set Endpoint (e.g. http://minio:9000) and PathStyle.
+type S3Config struct {
+	Endpoint        string // default https://s3.<region>.amazonaws.com
+	Region          string // default us-east-1
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PathStyle       bool // bucket in the path (endpoint/bucket/key) instead of the host name
+}
+
+// S3 stores objects in a bucket, signing requests with AWS Signature Version 4.
+type S3 struct {
+	cfg    S3Config
+	base   *url.URL
+	client *http.Client
+}
+
+// unsignedPayload skips hashing request bodies; S3 and MinIO accept it and TLS protects the body.
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// NewS3 returns a store for the bucket.
+func NewS3(cfg S3Config) (*S3, error) {
+	if cfg.Bucket == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("storage: s3 needs a bucket, access key ID and secret access key")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	base, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("storage: invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	return &S3{cfg: cfg, base: base, client: &http.Client{Timeout: 5 * time.Minute}}, nil
+}
+
+// Name describes the backend for logs.
+func (s *S3) Name() string {
+	return "s3 " + s.cfg.Bucket + " at " + s.base.Host
+}
+
+// objectURL returns the object's URL; the path is already escaped the way SigV4 canonicalizes it.
+func (s *S3) objectURL(key string) *url.URL {
+	u := *s.base
+	escaped := escapeRFC3986(key, true)
+	if s.cfg.PathStyle {
+		u.Path = u.Path + "/" + s.cfg.Bucket + "/" + key
+		u.RawPath = strings.TrimSuffix(s.base.EscapedPath(), "/") + "/" + escapeRFC3986(s.cfg.Bucket, false) + "/" + escaped
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+		u.Path = u.Path + "/" + key
+		u.RawPath = strings.TrimSuffix(s.base.EscapedPath(), "/") + "/" + escaped
+	}
+	return &u
+}
+
+// Put uploads the object in a single request.
+func (s *S3) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	if contentType == "" {
+		contentType = TypeByKey(key)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key).String(), body)
+	if err != nil {
+		return fmt.Errorf("storage: put %s: %w", key, err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	resp, err := s.do(req)
+	if err != nil {
+		return fmt.Errorf("storage: put %s: %w", key, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Open downloads the object.
+func (s *S3) Open(ctx context.Context, key string) (io.ReadCloser, *Object, error) {
+	resp, err := s.request(ctx, http.MethodGet, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Body, s.object(key, resp), nil
+}
+
+// Stat reads the object's metadata with a HEAD request.
+func (s *S3) Stat(ctx context.Context, key string) (*Object, error) {
+	resp, err := s.request(ctx, http.MethodHead, key)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return s.object(key, resp), nil
+}
+
+// Delete removes the object (S3 reports success for missing objects too).
+func (s *S3) Delete(ctx context.Context, key string) error {
+	resp, err := s.request(ctx, http.MethodDelete, key)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3) request(ctx context.Context, method, key string) (*http.Response, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("storage: %s %s: %w", strings.ToLower(method), key, err)
+	}
+	resp, err := s.do(req)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("storage: %s %s: %w", strings.ToLower(method), key, err)
+	}
+	return resp, err
+}
+
+// do signs and sends the request; non-2xx answers become errors (404 is ErrNotFound).
+func (s *S3) do(req *http.Request) (*http.Response, error) {
+	s.sign(req, time.Now().UTC())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+}
+
+func (s *S3) object(key string, resp *http.Response) *Object {
+	obj := &Object{Key: key, Size: resp.ContentLength, ContentType: resp.Header.Get("Content-Type")}
+	if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		obj.ModTime = t
+	}
+	return obj
+}
+
+// SignedURL presigns a GET that also tells S3 which download name and type to answer with.
+func (s *S3) SignedURL(ctx context.Context, key string, ttl time.Duration, d Download) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+	return s.presign(key, ttl, d, time.Now().UTC()), nil
+}
+
+// presign builds the SigV4 query-string signed GET URL for key as of now.
+func (s *S3) presign(key string, ttl time.Duration, d Download, now time.Time) string {
+	u := s.objectURL(key)
+	q := url.Values{}
+	q.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	q.Set("X-Amz-Credential", s.cfg.AccessKeyID+"/"+s.scope(now))
+	q.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	q.Set("X-Amz-Expires", strconv.Itoa(int(clampTTL(ttl)/time.Second)))
+	q.Set("X-Amz-SignedHeaders", "host")
+	if d.Filename != "" {
+		q.Set("response-content-disposition", ContentDisposition(d.Filename))
+	}
+	if d.ContentType != "" {
+		q.Set("response-content-type", d.ContentType)
+	}
+	canonical := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		canonicalQuery(q),
+		"host:" + u.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+	q.Set("X-Amz-Signature", s.signature(now, canonical))
+	u.RawQuery = canonicalQuery(q)
+	return u.String()
+}
+
+// sign adds SigV4 authorization headers to a request.
+func (s *S3) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": unsignedPayload,
+		"x-amz-date":           amzDate,
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "" {
+		headers["content-type"] = ct
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKeyID, s.scope(now), signedHeaders, s.signature(now, canonical)))
+}
+
+func (s *S3) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.cfg.Region + "/s3/aws4_request"
+}
+
+// signature signs a canonical request with the key derived for the request's day and region.
+func (s *S3) signature(now time.Time, canonical string) string {
+	sum := sha256.Sum256([]byte(canonical))
+	stringToSign := "AWS4-HMAC-SHA256\n" + now.Format("20060102T150405Z") + "\n" + s.scope(now) + "\n" + hex.EncodeToString(sum[:])
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery sorts parameters and escapes them as SigV4 requires.
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		values := append([]string(nil), q[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, escapeRFC3986(k, false)+"="+escapeRFC3986(v, false))
+		}
+	}
+	return strings.Join(parts, "&")
+}
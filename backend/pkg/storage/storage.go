@@ -0,0 +1,141 @@
+// Package storage keeps uploaded files (problem statement PDFs, submission attachments) in a local
+// directory or an S3-compatible bucket (AWS S3, MinIO, R2) and hands out signed, expiring download URLs.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when an object doesn't exist.
+var ErrNotFound = errors.New("storage: object not found")
+
+// MaxURLTTL is the longest a signed URL may stay valid (the S3 presign limit).
+const MaxURLTTL = 7 * 24 * time.Hour
+
+// Object describes a stored file.
+type Object struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Download is how a signed URL presents the file to the browser.
+type Download struct {
+	Filename    string // suggested name; empty = the key's base name
+	ContentType string // empty = the stored type
+}
+
+// Store is a flat namespace of files addressed by slash-separated keys (e.g. "submissions/<id>.pdf").
+type Store interface {
+	// Put writes the object, replacing any existing one. size is the exact body length.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Open returns the object's content; the caller closes it.
+	Open(ctx context.Context, key string) (io.ReadCloser, *Object, error)
+	// Stat returns the object's metadata, or ErrNotFound.
+	Stat(ctx context.Context, key string) (*Object, error)
+	// Delete removes the object; deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL anyone can download the object from until ttl passes.
+	SignedURL(ctx context.Context, key string, ttl time.Duration, d Download) (string, error)
+	// Name describes the backend for logs ("local ./uploads", "s3 bucket").
+	Name() string
+}
+
+// Config selects and configures the backend.
+type Config struct {
+	Backend string // "local" (default) or "s3"
+	// Local backend: root directory, API public URL and secret for signed /api/v1/files URLs
+	LocalDir      string
+	PublicURL     string
+	SigningSecret string
+	S3            S3Config
+}
+
+// New returns the configured store.
+func New(cfg Config) (Store, error) {
+	switch strings.ToLower(cfg.Backend) {
+	case "", "local":
+		return NewLocal(cfg.LocalDir, cfg.PublicURL, cfg.SigningSecret)
+	case "s3":
+		return NewS3(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (use local or s3)", cfg.Backend)
+	}
+}
+
+var keySegment = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidKey reports whether key is a relative slash-separated path of plain segments
+// (letters, digits, dot, dash, underscore; no "..", no empty segments).
+func ValidKey(key string) bool {
+	if key == "" || len(key) > 512 {
+		return false
+	}
+	for _, seg := range strings.Split(key, "/") {
+		if !keySegment.MatchString(seg) || seg == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+func checkKey(key string) error {
+	if !ValidKey(key) {
+		return fmt.Errorf("storage: invalid key %q", key)
+	}
+	return nil
+}
+
+// TypeByKey guesses a content type from the key's extension.
+func TypeByKey(key string) string {
+	if t := mime.TypeByExtension(strings.ToLower(path.Ext(key))); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// ContentDisposition renders an attachment header with an ASCII fallback and the UTF-8 name.
+func ContentDisposition(filename string) string {
+	fallback := strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, filename)
+	return mime.FormatMediaType("attachment", map[string]string{"filename": fallback}) +
+		"; filename*=UTF-8''" + escapeRFC3986(filename, false)
+}
+
+// escapeRFC3986 percent-encodes everything but unreserved characters (and "/" when keepSlash).
+func escapeRFC3986(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (keepSlash && c == '/') {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func clampTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return 15 * time.Minute
+	}
+	if ttl > MaxURLTTL {
+		return MaxURLTTL
+	}
+	return ttl
+}
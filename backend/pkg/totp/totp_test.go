@@ -0,0 +1,142 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the RFC 6238 Appendix B SHA-1 seed "12345678901234567890", base32-encoded.
+var rfcSecret = b32.EncodeToString([]byte("12345678901234567890"))
+
+// TestCodeAtRFC6238 checks the Appendix B SHA-1 vectors. The RFC lists 8-digit codes; a 6-digit
+// code is the same value mod 10^6, i.e. its last six digits.
+func TestCodeAtRFC6238(t *testing.T) {
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, v := range vectors {
+		got, err := CodeAt(rfcSecret, Counter(time.Unix(v.unix, 0)))
+		if err != nil {
+			t.Fatalf("CodeAt(%d): %v", v.unix, err)
+		}
+		if want := v.code[2:]; got != want {
+			t.Errorf("CodeAt(%d) = %s, want %s", v.unix, got, want)
+		}
+	}
+}
+
+func TestCodeAtAcceptsLowercaseSecret(t *testing.T) {
+	upper, err := CodeAt(rfcSecret, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lower, err := CodeAt(" "+strings.ToLower(rfcSecret)+" ", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upper != lower {
+		t.Errorf("lowercase secret gave %s, want %s", lower, upper)
+	}
+	if _, err := CodeAt("not base32!", 1); err == nil {
+		t.Error("CodeAt accepted an invalid secret")
+	}
+}
+
+func TestValidateWindow(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	step := Counter(now)
+	for _, offset := range []int64{-1, 0, 1} {
+		code, err := CodeAt(rfcSecret, step+offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, ok := Validate(rfcSecret, code, now, 1)
+		if !ok {
+			t.Errorf("code of step %+d rejected with skew 1", offset)
+			continue
+		}
+		if got != step+offset {
+			t.Errorf("code of step %+d matched step %d, want %d", offset, got, step+offset)
+		}
+	}
+	for _, offset := range []int64{-2, 2} {
+		code, err := CodeAt(rfcSecret, step+offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := Validate(rfcSecret, code, now, 1); ok {
+			t.Errorf("code of step %+d accepted with skew 1", offset)
+		}
+	}
+	if _, ok := Validate(rfcSecret, "005924", now, 1); !ok {
+		t.Error("RFC code rejected")
+	}
+	if _, ok := Validate(rfcSecret, " 005 924 ", now, 1); !ok {
+		t.Error("code with spaces rejected")
+	}
+	for _, code := range []string{"", "00592", "0059240", "abcdef"} {
+		if _, ok := Validate(rfcSecret, code, now, 1); ok {
+			t.Errorf("Validate accepted %q", code)
+		}
+	}
+}
+
+// TestValidateReplayCounter checks that a code keeps matching the same step for as long as it is
+// valid, so a caller that stores the last accepted step rejects it the second time.
+func TestValidateReplayCounter(t *testing.T) {
+	issued := time.Unix(1111111111, 0)
+	code, err := CodeAt(rfcSecret, Counter(issued))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, ok := Validate(rfcSecret, code, issued, 1)
+	if !ok {
+		t.Fatal("fresh code rejected")
+	}
+	again, ok := Validate(rfcSecret, code, issued.Add(Period*time.Second), 1)
+	if !ok {
+		t.Fatal("code rejected one step later")
+	}
+	if again != first {
+		t.Errorf("replayed code matched step %d, want %d", again, first)
+	}
+
+	// A caller accepting only steps after the last used one
+	last := first
+	accept := func(step int64) bool {
+		if step <= last {
+			return false
+		}
+		last = step
+		return true
+	}
+	if accept(again) {
+		t.Error("replayed code was accepted")
+	}
+	next, err := CodeAt(rfcSecret, first+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	step, ok := Validate(rfcSecret, next, issued.Add(Period*time.Second), 1)
+	if !ok || !accept(step) {
+		t.Error("code of the next step was not accepted")
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("RIFT", "admin@example.com", "JBSWY3DPEHPK3PXP")
+	for _, part := range []string{"otpauth://totp/RIFT:admin@example.com?", "secret=JBSWY3DPEHPK3PXP", "issuer=RIFT", "digits=6", "period=30", "algorithm=SHA1"} {
+		if !strings.Contains(uri, part) {
+			t.Errorf("%s does not contain %s", uri, part)
+		}
+	}
+}
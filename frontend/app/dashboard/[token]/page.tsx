@@ -23,11 +23,11 @@ export default function DashboardPage() {
     const [psSelection, setPsSelection] = useState<{ problem_statement_id: string; locked_at: string } | null>(null)
     const [showLockPSModal, setShowLockPSModal] = useState(false)
     const [selectedPSId, setSelectedPSId] = useState('')
-    const [leaderEmail, setLeaderEmail] = useState('')
     const [lockingPS, setLockingPS] = useState(false)
     const [qrCodeData, setQRCodeData] = useState('')
     const [submissionAllowed, setSubmissionAllowed] = useState(false)
     const [submissionPortalOpen, setSubmissionPortalOpen] = useState(false)
+    const [submissionLate, setSubmissionLate] = useState(false) // deadline passed; saving now is marked late
     const [submission, setSubmission] = useState<{ 
         linkedin_url?: string; 
         github_url?: string; 
@@ -40,8 +40,25 @@ export default function DashboardPage() {
         github?: boolean;
         live?: boolean;
         extra_notes?: boolean;
-        custom_fields?: Array<{ key: string; label: string }>;
+        custom_fields?: Array<{
+            key: string;
+            label: string;
+            type?: string;
+            required?: boolean;
+            help_text?: string;
+            options?: string[];
+        }>;
     }>({})
+    const [submissionFieldErrors, setSubmissionFieldErrors] = useState<Record<string, string>>({})
+    const [submissionFiles, setSubmissionFiles] = useState<Record<string, File>>({}) // new uploads for file fields
+    const [submissionAttachments, setSubmissionAttachments] = useState<Array<{
+        id: string;
+        field_key: string;
+        filename: string;
+        size_bytes: number;
+        download_url?: string;
+        signed_url?: string;
+    }>>([])
     const [showSubmissionModal, setShowSubmissionModal] = useState(false)
     const [projectSubmissionSaved, setProjectSubmissionSaved] = useState(false) // true only after server confirms save (not from typing)
     const [submittingProject, setSubmittingProject] = useState(false)
@@ -118,8 +135,11 @@ export default function DashboardPage() {
 
             // After basic dashboard load, fetch submission form for this team
             try {
-                const subRes = await axios.get(`${process.env.NEXT_PUBLIC_API_URL}/teams/${teamData.id}/submission`)
+                const subRes = await axios.get(`${process.env.NEXT_PUBLIC_API_URL}/teams/${teamData.id}/submission`, {
+                    headers: { 'X-Dashboard-Token': token },
+                })
                 setSubmissionPortalOpen(subRes.data.portal_open === true)
+                setSubmissionLate(subRes.data.late === true)
                 setSubmissionAllowed(subRes.data.allowed === true)
                 setProjectSubmissionSaved(subRes.data.submitted === true)
                 if (subRes.data) {
@@ -135,6 +155,7 @@ export default function DashboardPage() {
                         extra_notes: subRes.data.extra_notes || '',
                         custom_field_values: customFieldValues,
                     })
+                    setSubmissionAttachments(subRes.data.attachments || [])
                 }
             } catch {
                 // ignore submission fetch errors on dashboard
@@ -175,6 +196,8 @@ export default function DashboardPage() {
                 team_id: team?.id,
                 subject: ticketSubject,
                 message: ticketMessage
+            }, {
+                headers: { 'X-Dashboard-Token': token },
             })
             alert('Ticket submitted successfully! We will respond via email.')
             setShowTicketModal(false)
@@ -188,20 +211,20 @@ export default function DashboardPage() {
     }
 
     const handleLockPS = async () => {
-        if (!selectedPSId || !leaderEmail.trim()) {
-            alert('Please select a problem statement and enter your leader email')
+        if (!selectedPSId) {
+            alert('Please select a problem statement')
             return
         }
         setLockingPS(true)
         try {
             await axios.post(`${process.env.NEXT_PUBLIC_API_URL}/teams/${team?.id}/lock-ps`, {
                 problem_statement_id: selectedPSId,
-                leader_email: leaderEmail.trim()
+            }, {
+                headers: { 'X-Dashboard-Token': token },
             })
             alert('Problem statement locked successfully!')
             setShowLockPSModal(false)
             setSelectedPSId('')
-            setLeaderEmail('')
             fetchDashboard()
         } catch (err: any) {
             alert(err.response?.data?.error || 'Failed to lock problem statement')
@@ -213,6 +236,7 @@ export default function DashboardPage() {
     const handleSubmitProject = async () => {
         if (!team?.id) return
         setSubmittingProject(true)
+        setSubmissionFieldErrors({})
         try {
             const payload: Record<string, unknown> = {
                 linkedin_url: submission.linkedin_url ?? '',
@@ -224,15 +248,33 @@ export default function DashboardPage() {
                 payload.custom_field_values = submission.custom_field_values
             }
             const apiUrl = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080/api/v1'
-            const res = await axios.post(`${apiUrl}/teams/${team.id}/submission`, payload, {
-                headers: { 'Content-Type': 'application/json' },
+            let body: Record<string, unknown> | FormData = payload
+            let contentType = 'application/json'
+            if (Object.keys(submissionFiles).length > 0) {
+                // Files go as multipart; custom_field_values travels as a JSON string
+                const form = new FormData()
+                for (const [k, v] of Object.entries(payload)) {
+                    form.append(k, typeof v === 'string' ? v : JSON.stringify(v))
+                }
+                for (const [key, file] of Object.entries(submissionFiles)) {
+                    form.append(key, file)
+                }
+                body = form
+                contentType = 'multipart/form-data'
+            }
+            const res = await axios.post(`${apiUrl}/teams/${team.id}/submission`, body, {
+                headers: { 'Content-Type': contentType, 'X-Dashboard-Token': token },
             })
             if (res.data?.message) {
+                setSubmissionFiles({})
                 setProjectSubmissionSaved(true)
                 alert('Submission saved successfully!')
                 setShowSubmissionModal(false)
             }
         } catch (err: any) {
+            if (err.response?.data?.field_errors) {
+                setSubmissionFieldErrors(err.response.data.field_errors)
+            }
             const msg = err.response?.data?.error ?? err.message ?? 'Failed to save submission'
             alert(msg)
         } finally {
@@ -240,6 +282,29 @@ export default function DashboardPage() {
         }
     }
 
+    // Signed links open directly; otherwise the download needs the dashboard token header, so fetch it as a blob
+    const downloadAttachment = async (a: { filename: string; download_url?: string; signed_url?: string }) => {
+        if (a.signed_url) {
+            window.open(a.signed_url, '_blank', 'noopener')
+            return
+        }
+        if (!a.download_url) return
+        try {
+            const res = await axios.get(a.download_url, {
+                headers: { 'X-Dashboard-Token': token },
+                responseType: 'blob',
+            })
+            const url = URL.createObjectURL(res.data)
+            const link = document.createElement('a')
+            link.href = url
+            link.download = a.filename
+            link.click()
+            URL.revokeObjectURL(url)
+        } catch {
+            alert('Failed to download file')
+        }
+    }
+
     const addToGoogleCalendar = () => {
         // Start: 19 Feb 2026, 09:00
         const startDate = '20260219'
@@ -560,20 +625,9 @@ export default function DashboardPage() {
                                     ))}
                                 </select>
                             </div>
-                            <div>
-                                <label className="text-gray-300 text-sm mb-2 block">Leader Email</label>
-                                <input
-                                    type="email"
-                                    value={leaderEmail}
-                                    onChange={(e) => setLeaderEmail(e.target.value)}
-                                    placeholder="Enter your leader email"
-                                    className="w-full bg-white/5 border border-white/10 rounded-lg px-4 py-3 text-white placeholder-gray-500 focus:outline-none focus:border-purple-500/50"
-                                />
-                                <p className="text-gray-400 text-xs mt-1">Must match the team leader's email</p>
-                            </div>
                             <button
                                 onClick={handleLockPS}
-                                disabled={lockingPS || !selectedPSId || !leaderEmail.trim()}
+                                disabled={lockingPS || !selectedPSId}
                                 className="w-full bg-purple-600 hover:bg-purple-700 disabled:bg-gray-600 text-white py-3 px-4 rounded-lg transition font-medium"
                             >
                                 {lockingPS ? 'Locking...' : 'Lock Problem Statement'}
@@ -621,6 +675,9 @@ export default function DashboardPage() {
                                         placeholder="https://www.linkedin.com/..."
                                         className="w-full bg-white/5 border border-white/10 rounded-lg px-4 py-3 text-white placeholder-gray-500 focus:outline-none focus:border-emerald-500/50 disabled:opacity-80"
                                     />
+                                    {submissionFieldErrors.linkedin_url && (
+                                        <p className="text-red-400 text-xs mt-1">{submissionFieldErrors.linkedin_url}</p>
+                                    )}
                                 </div>
                             )}
                             {submissionFields.github && (
@@ -634,6 +691,9 @@ export default function DashboardPage() {
                                         placeholder="https://github.com/..."
                                         className="w-full bg-white/5 border border-white/10 rounded-lg px-4 py-3 text-white placeholder-gray-500 focus:outline-none focus:border-emerald-500/50 disabled:opacity-80"
                                     />
+                                    {submissionFieldErrors.github_url && (
+                                        <p className="text-red-400 text-xs mt-1">{submissionFieldErrors.github_url}</p>
+                                    )}
                                 </div>
                             )}
                             {submissionFields.live && (
@@ -647,6 +707,9 @@ export default function DashboardPage() {
                                         placeholder="https://..."
                                         className="w-full bg-white/5 border border-white/10 rounded-lg px-4 py-3 text-white placeholder-gray-500 focus:outline-none focus:border-emerald-500/50 disabled:opacity-80"
                                     />
+                                    {submissionFieldErrors.live_url && (
+                                        <p className="text-red-400 text-xs mt-1">{submissionFieldErrors.live_url}</p>
+                                    )}
                                 </div>
                             )}
                             {submissionFields.extra_notes && (
@@ -664,25 +727,99 @@ export default function DashboardPage() {
                             )}
                             {submissionFields.custom_fields && submissionFields.custom_fields.length > 0 && (
                                 <>
-                                    {submissionFields.custom_fields.map((field) => (
+                                    {submissionFields.custom_fields.map((field) => {
+                                        const value = submission.custom_field_values?.[field.key] || ''
+                                        const setValue = (v: string) => !isReadOnly && setSubmission((prev) => ({
+                                            ...prev,
+                                            custom_field_values: {
+                                                ...(prev.custom_field_values || {}),
+                                                [field.key]: v,
+                                            },
+                                        }))
+                                        const inputClass = "w-full bg-white/5 border border-white/10 rounded-lg px-4 py-3 text-white placeholder-gray-500 focus:outline-none focus:border-emerald-500/50 disabled:opacity-80"
+                                        const inputType = ({ url: 'url', 'github-repo': 'url', number: 'number', email: 'email' } as Record<string, string>)[field.type || ''] || 'text'
+                                        return (
                                         <div key={field.key}>
-                                            <label className="text-gray-300 text-sm mb-2 block">{field.label}</label>
-                                            <input
-                                                type="text"
-                                                readOnly={isReadOnly}
-                                                value={submission.custom_field_values?.[field.key] || ''}
-                                                onChange={(e) => !isReadOnly && setSubmission((prev) => ({
-                                                    ...prev,
-                                                    custom_field_values: {
-                                                        ...(prev.custom_field_values || {}),
-                                                        [field.key]: e.target.value,
-                                                    },
-                                                }))}
-                                                placeholder={`Enter ${field.label.toLowerCase()}...`}
-                                                className="w-full bg-white/5 border border-white/10 rounded-lg px-4 py-3 text-white placeholder-gray-500 focus:outline-none focus:border-emerald-500/50 disabled:opacity-80"
-                                            />
+                                            {field.type === 'checkbox' ? (
+                                                <label className="flex items-center gap-2 text-gray-300 text-sm">
+                                                    <input
+                                                        type="checkbox"
+                                                        disabled={isReadOnly}
+                                                        checked={value === 'true'}
+                                                        onChange={(e) => setValue(e.target.checked ? 'true' : 'false')}
+                                                    />
+                                                    {field.label}{field.required && ' *'}
+                                                </label>
+                                            ) : (
+                                                <label className="text-gray-300 text-sm mb-2 block">{field.label}{field.required && ' *'}</label>
+                                            )}
+                                            {field.type === 'long-text' ? (
+                                                <textarea
+                                                    rows={4}
+                                                    readOnly={isReadOnly}
+                                                    value={value}
+                                                    onChange={(e) => setValue(e.target.value)}
+                                                    className={`${inputClass} resize-none`}
+                                                />
+                                            ) : field.type === 'select' ? (
+                                                <select
+                                                    disabled={isReadOnly}
+                                                    value={value}
+                                                    onChange={(e) => setValue(e.target.value)}
+                                                    className={inputClass}
+                                                >
+                                                    <option value="">Select...</option>
+                                                    {(field.options || []).map((o) => (
+                                                        <option key={o} value={o}>{o}</option>
+                                                    ))}
+                                                </select>
+                                            ) : field.type === 'file' ? (
+                                                <div className="space-y-2">
+                                                    {(() => {
+                                                        const current = submissionAttachments.find((a) => a.id === value)
+                                                        return current && (
+                                                            <button
+                                                                type="button"
+                                                                onClick={() => downloadAttachment(current)}
+                                                                className="text-emerald-400 text-sm underline"
+                                                            >
+                                                                {current.filename} ({Math.ceil(current.size_bytes / 1024)} KB)
+                                                            </button>
+                                                        )
+                                                    })()}
+                                                    {!isReadOnly && (
+                                                        <input
+                                                            type="file"
+                                                            onChange={(e) => {
+                                                                const file = e.target.files?.[0]
+                                                                setSubmissionFiles((prev) => {
+                                                                    const next = { ...prev }
+                                                                    if (file) next[field.key] = file
+                                                                    else delete next[field.key]
+                                                                    return next
+                                                                })
+                                                            }}
+                                                            className="text-gray-300 text-sm"
+                                                        />
+                                                    )}
+                                                </div>
+                                            ) : field.type !== 'checkbox' && (
+                                                <input
+                                                    type={inputType}
+                                                    readOnly={isReadOnly}
+                                                    value={value}
+                                                    onChange={(e) => setValue(e.target.value)}
+                                                    placeholder={`Enter ${field.label.toLowerCase()}...`}
+                                                    className={inputClass}
+                                                />
+                                            )}
+                                            {field.help_text && <p className="text-gray-500 text-xs mt-1">{field.help_text}</p>}
+                                            {submissionFieldErrors[field.key] && (
+                                                <p className="text-red-400 text-xs mt-1">{submissionFieldErrors[field.key]}</p>
+                                            )}
                                         </div>
-                                    ))}
+                                        )
+                                    })}
                                 </>
                             )}
                         </div>
@@ -871,6 +1008,11 @@ export default function DashboardPage() {
                                     <p className="text-zinc-400 text-sm">
                                         Submit your LinkedIn video, GitHub repo and live demo link for your locked problem statement.
                                     </p>
+                                    {submissionLate && (
+                                        <p className="text-amber-400 text-sm mt-1">
+                                            The submission deadline has passed. Anything you submit now is marked late.
+                                        </p>
+                                    )}
                                 </div>
                                 <button
                                     onClick={() => setShowSubmissionModal(true)}
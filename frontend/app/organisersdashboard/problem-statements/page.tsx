@@ -5,11 +5,31 @@ import axios from 'axios'
 import { getAdminToken } from '@/src/lib/admin-auth'
 import { FileText, Plus, Trash2, Upload, Zap, Lock, Unlock } from 'lucide-react'
 
+type CustomFieldType = 'url' | 'github-repo' | 'text' | 'long-text' | 'number' | 'email' | 'select' | 'checkbox' | 'file'
+
 interface CustomField {
   key: string
   label: string
+  type?: CustomFieldType
+  required?: boolean
+  help_text?: string
+  options?: string[]
+  allowed_types?: string[] // file fields; empty = PDF, slides and images
+  max_bytes?: number
 }
 
+const CUSTOM_FIELD_TYPES: { value: CustomFieldType; label: string }[] = [
+  { value: 'text', label: 'Text' },
+  { value: 'long-text', label: 'Long text' },
+  { value: 'url', label: 'URL' },
+  { value: 'github-repo', label: 'GitHub repo' },
+  { value: 'number', label: 'Number' },
+  { value: 'email', label: 'Email' },
+  { value: 'select', label: 'Select' },
+  { value: 'checkbox', label: 'Checkbox' },
+  { value: 'file', label: 'File upload' },
+]
+
 interface PSItem {
   id: string
   track: string
@@ -38,8 +58,13 @@ export default function ProblemStatementsPage() {
   const [success, setSuccess] = useState('')
   const [form, setForm] = useState({ track: '', name: '', link: '', linkedin: true, github: true, live: true, extra_notes: true })
   const [customFields, setCustomFields] = useState<CustomField[]>([])
+  const [pdfFile, setPdfFile] = useState<File | null>(null) // uploaded instead of a link
   const [newCustomFieldLabel, setNewCustomFieldLabel] = useState('')
 
+  const updateCustomField = (idx: number, patch: Partial<CustomField>) => {
+    setCustomFields((fields) => fields.map((f, i) => (i === idx ? { ...f, ...patch } : f)))
+  }
+
   const api = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080/api/v1'
 
   const fetchList = async () => {
@@ -71,8 +96,8 @@ export default function ProblemStatementsPage() {
 
   const handleSubmit = async (e: React.FormEvent) => {
     e.preventDefault()
-    if (!form.track.trim() || !form.name.trim() || !form.link.trim()) {
-      setError('Track, name and Google Drive link are required')
+    if (!form.track.trim() || !form.name.trim() || (!form.link.trim() && !pdfFile)) {
+      setError('Track, name and a Google Drive link or PDF are required')
       return
     }
     setError('')
@@ -84,6 +109,7 @@ export default function ProblemStatementsPage() {
       fd.append('track', form.track.trim())
       fd.append('name', form.name.trim())
       fd.append('link', form.link.trim())
+      if (pdfFile) fd.append('file', pdfFile)
       // Encode submission fields config as JSON for backend
       const submissionFields: any = {
         linkedin: form.linkedin,
@@ -102,6 +128,7 @@ export default function ProblemStatementsPage() {
       setForm({ track: '', name: '', link: '', linkedin: true, github: true, live: true, extra_notes: true })
       setCustomFields([])
       setNewCustomFieldLabel('')
+      setPdfFile(null)
       fetchList()
       setTimeout(() => setSuccess(''), 3000)
     } catch (err: any) {
@@ -308,6 +335,13 @@ export default function ProblemStatementsPage() {
               placeholder="https://drive.google.com/..."
               className="w-full px-4 py-2 bg-zinc-950 border border-zinc-800 rounded-lg text-white"
             />
+            <label className="block text-zinc-400 text-sm mt-2 mb-1">Or upload the PDF (max 20 MB)</label>
+            <input
+              type="file"
+              accept="application/pdf"
+              onChange={(e) => setPdfFile(e.target.files?.[0] ?? null)}
+              className="text-zinc-300 text-sm"
+            />
           </div>
           <div>
             <label className="block text-zinc-400 text-sm mb-2">Submission fields for this PS</label>
@@ -395,8 +429,60 @@ export default function ProblemStatementsPage() {
                 {customFields.length > 0 && (
                   <div className="space-y-2">
                     {customFields.map((field, idx) => (
-                      <div key={field.key} className="flex items-center justify-between p-2 bg-zinc-950 rounded border border-zinc-800">
-                        <span className="text-zinc-300 text-sm">{field.label}</span>
+                      <div key={field.key} className="flex flex-wrap items-center gap-2 p-2 bg-zinc-950 rounded border border-zinc-800">
+                        <span className="text-zinc-300 text-sm flex-1">{field.label}</span>
+                        <select
+                          value={field.type || 'text'}
+                          onChange={(e) => updateCustomField(idx, { type: e.target.value as CustomFieldType })}
+                          className="px-2 py-1 bg-zinc-900 border border-zinc-800 rounded text-white text-xs"
+                        >
+                          {CUSTOM_FIELD_TYPES.map((t) => (
+                            <option key={t.value} value={t.value}>{t.label}</option>
+                          ))}
+                        </select>
+                        <label className="flex items-center gap-1 text-zinc-400 text-xs">
+                          <input
+                            type="checkbox"
+                            checked={!!field.required}
+                            onChange={(e) => updateCustomField(idx, { required: e.target.checked })}
+                          />
+                          Required
+                        </label>
+                        {field.type === 'select' && (
+                          <input
+                            type="text"
+                            value={(field.options || []).join(', ')}
+                            onChange={(e) => updateCustomField(idx, { options: e.target.value.split(',').map((o) => o.trim()) })}
+                            placeholder="Options, comma-separated"
+                            className="w-full px-2 py-1 bg-zinc-900 border border-zinc-800 rounded text-white text-xs"
+                          />
+                        )}
+                        {field.type === 'file' && (
+                          <>
+                            <input
+                              type="text"
+                              value={(field.allowed_types || []).join(', ')}
+                              onChange={(e) => updateCustomField(idx, { allowed_types: e.target.value.split(',').map((t) => t.trim()).filter(Boolean) })}
+                              placeholder="Allowed types, e.g. application/pdf, image/png (default: PDF, slides, images)"
+                              className="w-full px-2 py-1 bg-zinc-900 border border-zinc-800 rounded text-white text-xs"
+                            />
+                            <input
+                              type="number"
+                              min={1}
+                              value={field.max_bytes ? Math.round(field.max_bytes / (1 << 20)) : ''}
+                              onChange={(e) => updateCustomField(idx, { max_bytes: e.target.value ? Number(e.target.value) * (1 << 20) : undefined })}
+                              placeholder="Max size in MB (default: server limit)"
+                              className="w-full px-2 py-1 bg-zinc-900 border border-zinc-800 rounded text-white text-xs"
+                            />
+                          </>
+                        )}
+                        <input
+                          type="text"
+                          value={field.help_text || ''}
+                          onChange={(e) => updateCustomField(idx, { help_text: e.target.value })}
+                          placeholder="Help text (optional)"
+                          className="w-full px-2 py-1 bg-zinc-900 border border-zinc-800 rounded text-white text-xs"
+                        />
                         <button
                           type="button"
                           onClick={() => {
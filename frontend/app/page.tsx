@@ -46,6 +46,7 @@ export default function Home() {
     const [error, setError] = useState('');
     const [isRSVPLocked, setIsRSVPLocked] = useState(false);
     const [otpEnabled, setOtpEnabled] = useState(true); // Track if OTP is enabled on backend
+    const [magicLinkSent, setMagicLinkSent] = useState(false);
     const [rsvpOpenMode, setRsvpOpenMode] = useState<string>('false'); // from backend config only: 'true' | 'false' | 'pin'
     const [showRSVPClosedModal, setShowRSVPClosedModal] = useState(false);
     const [showPinModal, setShowPinModal] = useState(false);
@@ -74,7 +75,7 @@ export default function Home() {
     // Debounced autocomplete with loading state
     useEffect(() => {
         const timer = setTimeout(async () => {
-            if (searchQuery.trim().length >= 2) {
+            if (searchQuery.trim().length >= 3) {
                 setSearchingTeams(true);
                 try {
                     const response = await axios.get(`${process.env.NEXT_PUBLIC_API_URL}/teams/search`, {
@@ -103,6 +104,7 @@ export default function Home() {
         setEmail('');
         setSearchQuery(team.team_name);
         setShowSuggestions(false);
+        setMagicLinkSent(false);
         setStep('email');
         setIsRSVPLocked(team.rsvp_locked || false);
     };
@@ -181,6 +183,48 @@ export default function Home() {
         }
     };
 
+    // Emails the leader a single-use sign-in link; opening it lands on /rsvp/[id], which exchanges the token
+    const handleSendMagicLink = async () => {
+        if (!selectedTeam) return;
+
+        const emailRegex = /^[^\s@]+@[^\s@]+\.[^\s@]+$/;
+        if (!emailRegex.test(email)) {
+            setError('Please enter a valid email address');
+            return;
+        }
+
+        setLoading(true);
+        setError('');
+
+        try {
+            const configResponse = await axios.get(`${process.env.NEXT_PUBLIC_API_URL}/config`);
+            const mode = configResponse.data.rsvp_open === 'pin' ? 'pin' : configResponse.data.rsvp_open === true || configResponse.data.rsvp_open === 'true' ? 'true' : 'false';
+            setRsvpOpenMode(mode);
+
+            if (mode === 'false' && !selectedTeam.rsvp_locked) {
+                setShowRSVPClosedModal(true);
+                return;
+            }
+
+            const response = await axios.post(`${process.env.NEXT_PUBLIC_API_URL}/auth/send-magic-link`, {
+                team_id: selectedTeam.id,
+                email: email
+            });
+
+            if (response.data.magic_link_enabled === false) {
+                // Email sign-in is off on the backend: the email is verified, sign in directly
+                await handleDirectAuth();
+                return;
+            }
+            setMagicLinkSent(true);
+        } catch (err: any) {
+            setError(err.response?.data?.error || err.message || 'Failed to send sign-in link');
+            console.error('Magic Link Error:', err);
+        } finally {
+            setLoading(false);
+        }
+    };
+
     const handleDirectAuth = async () => {
         if (!selectedTeam) return;
 
@@ -508,7 +552,7 @@ export default function Home() {
 
 
                                 {/* Loading Animation - Positioned Absolutely Above */}
-                                {searchingTeams && searchQuery.trim().length >= 2 && (
+                                {searchingTeams && searchQuery.trim().length >= 3 && (
                                     <div className="relative top-12 left-1/2 transform -translate-x-1/2 z-20">
                                         <div className="loader-wrapper">
                                             <div className="loader"></div>
@@ -663,7 +707,7 @@ export default function Home() {
                                 )}
 
 
-                                {showSuggestions && suggestions.length === 0 && !searchingTeams && searchQuery.length >= 2 && (
+                                {showSuggestions && suggestions.length === 0 && !searchingTeams && searchQuery.trim().length >= 3 && (
                                     <div className="absolute top-full mt-4 left-0 right-0 bg-black/40 backdrop-blur-xl border border-white/10 rounded-xl p-4 text-center text-gray-400 animate-fade-in z-10">
                                         No teams found
                                     </div>
@@ -705,7 +749,7 @@ export default function Home() {
                                     <input
                                         type="email"
                                         value={email}
-                                        onChange={(e) => setEmail(e.target.value)}
+                                        onChange={(e) => { setEmail(e.target.value); setMagicLinkSent(false); }}
                                         placeholder={`${selectedTeam?.masked_email}`}
                                         autoComplete="email"
                                         className="text-center"
@@ -720,6 +764,22 @@ export default function Home() {
                             >
                                 {loading ? 'Processing...' : 'Continue'}
                             </button>
+
+                            {magicLinkSent ? (
+                                <div className="bg-green-500/20 border border-green-500/50 p-4 rounded-lg">
+                                    <p className="text-green-200 text-sm text-center">
+                                        ✓ Sign-in link sent to {email}. It expires in 15 minutes.
+                                    </p>
+                                </div>
+                            ) : (
+                                <button
+                                    onClick={handleSendMagicLink}
+                                    disabled={loading || !email}
+                                    className="w-full text-sm text-gray-400 hover:text-white cursor-pointer transition-all disabled:opacity-50 disabled:cursor-not-allowed"
+                                >
+                                    Email me a sign-in link instead
+                                </button>
+                            )}
                         </div>
                     )}
 
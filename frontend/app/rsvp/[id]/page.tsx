@@ -1,7 +1,7 @@
 'use client'
 
-import { useState, useEffect } from 'react'
-import { useParams, useRouter } from 'next/navigation'
+import { useState, useEffect, useRef } from 'react'
+import { useParams, useRouter, useSearchParams } from 'next/navigation'
 import axios from 'axios'
 import { Team, TeamMember } from '@/types'
 import RIFTBackground from '@/components/RIFTBackground'
@@ -12,8 +12,10 @@ import { useAuthStore } from '@/store/auth-store'
 export default function RSVP2Page() {
     const params = useParams()
     const router = useRouter()
+    const searchParams = useSearchParams()
     const teamId = params.id as string
-    const { token: authToken } = useAuthStore()
+    const magicToken = searchParams.get('magic_token')
+    const { token: authToken, setAuth } = useAuthStore()
 
     const [team, setTeam] = useState<Team | null>(null)
     const [selectedMembers, setSelectedMembers] = useState<Set<string>>(new Set())
@@ -22,14 +24,54 @@ export default function RSVP2Page() {
     const [error, setError] = useState('')
     const [showInfoModal, setShowInfoModal] = useState(true)
     const [agreedToTerms, setAgreedToTerms] = useState(false)
+    // Sign-in links are single-use; never post the same token twice
+    const exchangedToken = useRef<string | null>(null)
 
     useEffect(() => {
+        // Emailed sign-in links land here with ?magic_token=; exchange it for a session first
+        if (magicToken) {
+            if (exchangedToken.current !== magicToken) {
+                exchangedToken.current = magicToken
+                verifyMagicLink(magicToken)
+            }
+            return
+        }
         // Wait a bit for auth to be available
         const timer = setTimeout(() => {
             fetchTeamDetails()
         }, 100)
         return () => clearTimeout(timer)
-    }, [teamId, authToken])
+    }, [teamId, authToken, magicToken])
+
+    const verifyMagicLink = async (linkToken: string) => {
+        try {
+            const response = await axios.post(`${process.env.NEXT_PUBLIC_API_URL}/auth/verify-magic-link`, {
+                token: linkToken
+            })
+
+            // Store auth data BEFORE redirect, same as the OTP sign-in
+            const token = response.data.token
+            const teamData = response.data.team
+            localStorage.setItem('auth_token', token)
+            // phone_number carries the signed-in email, as with verify-email-otp
+            if (response.data.phone_number) {
+                localStorage.setItem('user_email', response.data.phone_number)
+            }
+            setAuth(token, teamData)
+
+            // Teams past RSVP II go straight to the dashboard; the rest continue on this page
+            if (teamData.status !== 'rsvp_done' && teamData.dashboard_token) {
+                router.replace(`/dashboard/${teamData.dashboard_token}`)
+            } else {
+                // Drop the single-use token from the URL; this re-runs the effect with the new session
+                router.replace(`/rsvp/${teamData.id}`)
+            }
+        } catch (err: any) {
+            console.error('Magic link verification error:', err)
+            setError(err.response?.data?.error || 'This sign-in link is invalid or has expired. Please request a new one.')
+            setLoading(false)
+        }
+    }
 
     const fetchTeamDetails = async () => {
         try {